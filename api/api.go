@@ -0,0 +1,10 @@
+package api
+
+import _ "embed"
+
+//go:generate protoc --go_out=notevaultpb --go_opt=paths=source_relative --go-grpc_out=notevaultpb --go-grpc_opt=paths=source_relative notevault.proto
+
+// OpenAPI - спецификация API, ее отдает сервер на /api/v1/openapi.json и по ней генерируется пакет client
+//
+//go:embed openapi.json
+var OpenAPI []byte
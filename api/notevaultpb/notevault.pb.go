@@ -0,0 +1,1738 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: notevault.proto
+
+package notevaultpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type NoteStatus int32
+
+const (
+	// То же, что NOTE_STATUS_ACTIVE.
+	NoteStatus_NOTE_STATUS_UNSPECIFIED NoteStatus = 0
+	NoteStatus_NOTE_STATUS_ACTIVE      NoteStatus = 1
+	NoteStatus_NOTE_STATUS_ARCHIVED    NoteStatus = 2
+	NoteStatus_NOTE_STATUS_TRASHED     NoteStatus = 3
+	NoteStatus_NOTE_STATUS_ALL         NoteStatus = 4
+)
+
+// Enum value maps for NoteStatus.
+var (
+	NoteStatus_name = map[int32]string{
+		0: "NOTE_STATUS_UNSPECIFIED",
+		1: "NOTE_STATUS_ACTIVE",
+		2: "NOTE_STATUS_ARCHIVED",
+		3: "NOTE_STATUS_TRASHED",
+		4: "NOTE_STATUS_ALL",
+	}
+	NoteStatus_value = map[string]int32{
+		"NOTE_STATUS_UNSPECIFIED": 0,
+		"NOTE_STATUS_ACTIVE":      1,
+		"NOTE_STATUS_ARCHIVED":    2,
+		"NOTE_STATUS_TRASHED":     3,
+		"NOTE_STATUS_ALL":         4,
+	}
+)
+
+func (x NoteStatus) Enum() *NoteStatus {
+	p := new(NoteStatus)
+	*p = x
+	return p
+}
+
+func (x NoteStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NoteStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_notevault_proto_enumTypes[0].Descriptor()
+}
+
+func (NoteStatus) Type() protoreflect.EnumType {
+	return &file_notevault_proto_enumTypes[0]
+}
+
+func (x NoteStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NoteStatus.Descriptor instead.
+func (NoteStatus) EnumDescriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{0}
+}
+
+// DeleteMode - что сделать с заметками блокнота, как mode в DELETE /notebooks/{id}.
+type DeleteMode int32
+
+const (
+	// То же, что DELETE_MODE_UNLINK.
+	DeleteMode_DELETE_MODE_UNSPECIFIED DeleteMode = 0
+	DeleteMode_DELETE_MODE_UNLINK      DeleteMode = 1
+	DeleteMode_DELETE_MODE_MOVE        DeleteMode = 2
+	DeleteMode_DELETE_MODE_TRASH       DeleteMode = 3
+	DeleteMode_DELETE_MODE_RESTRICT    DeleteMode = 4
+)
+
+// Enum value maps for DeleteMode.
+var (
+	DeleteMode_name = map[int32]string{
+		0: "DELETE_MODE_UNSPECIFIED",
+		1: "DELETE_MODE_UNLINK",
+		2: "DELETE_MODE_MOVE",
+		3: "DELETE_MODE_TRASH",
+		4: "DELETE_MODE_RESTRICT",
+	}
+	DeleteMode_value = map[string]int32{
+		"DELETE_MODE_UNSPECIFIED": 0,
+		"DELETE_MODE_UNLINK":      1,
+		"DELETE_MODE_MOVE":        2,
+		"DELETE_MODE_TRASH":       3,
+		"DELETE_MODE_RESTRICT":    4,
+	}
+)
+
+func (x DeleteMode) Enum() *DeleteMode {
+	p := new(DeleteMode)
+	*p = x
+	return p
+}
+
+func (x DeleteMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeleteMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_notevault_proto_enumTypes[1].Descriptor()
+}
+
+func (DeleteMode) Type() protoreflect.EnumType {
+	return &file_notevault_proto_enumTypes[1]
+}
+
+func (x DeleteMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeleteMode.Descriptor instead.
+func (DeleteMode) EnumDescriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{1}
+}
+
+type LoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_notevault_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LoginRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type LoginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginResponse) Reset() {
+	*x = LoginResponse{}
+	mi := &file_notevault_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResponse) ProtoMessage() {}
+
+func (x *LoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
+func (*LoginResponse) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LoginResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type Note struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Text        string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	Color       string                 `protobuf:"bytes,4,opt,name=color,proto3" json:"color,omitempty"`
+	Order       int32                  `protobuf:"varint,5,opt,name=order,proto3" json:"order,omitempty"`
+	Rank        string                 `protobuf:"bytes,6,opt,name=rank,proto3" json:"rank,omitempty"`
+	IsDeleted   bool                   `protobuf:"varint,7,opt,name=is_deleted,json=isDeleted,proto3" json:"is_deleted,omitempty"`
+	IsArchived  bool                   `protobuf:"varint,8,opt,name=is_archived,json=isArchived,proto3" json:"is_archived,omitempty"`
+	IsPinned    bool                   `protobuf:"varint,9,opt,name=is_pinned,json=isPinned,proto3" json:"is_pinned,omitempty"`
+	IsFavourite bool                   `protobuf:"varint,10,opt,name=is_favourite,json=isFavourite,proto3" json:"is_favourite,omitempty"`
+	NotebookId  string                 `protobuf:"bytes,11,opt,name=notebook_id,json=notebookId,proto3" json:"notebook_id,omitempty"`
+	TagIds      []string               `protobuf:"bytes,12,rep,name=tag_ids,json=tagIds,proto3" json:"tag_ids,omitempty"`
+	// Пустой у общих заметок без владельца.
+	UserId        string `protobuf:"bytes,13,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreatedAt     string `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	JournalDate   string `protobuf:"bytes,16,opt,name=journal_date,json=journalDate,proto3" json:"journal_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Note) Reset() {
+	*x = Note{}
+	mi := &file_notevault_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Note) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Note) ProtoMessage() {}
+
+func (x *Note) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Note.ProtoReflect.Descriptor instead.
+func (*Note) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Note) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Note) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Note) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Note) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Note) GetOrder() int32 {
+	if x != nil {
+		return x.Order
+	}
+	return 0
+}
+
+func (x *Note) GetRank() string {
+	if x != nil {
+		return x.Rank
+	}
+	return ""
+}
+
+func (x *Note) GetIsDeleted() bool {
+	if x != nil {
+		return x.IsDeleted
+	}
+	return false
+}
+
+func (x *Note) GetIsArchived() bool {
+	if x != nil {
+		return x.IsArchived
+	}
+	return false
+}
+
+func (x *Note) GetIsPinned() bool {
+	if x != nil {
+		return x.IsPinned
+	}
+	return false
+}
+
+func (x *Note) GetIsFavourite() bool {
+	if x != nil {
+		return x.IsFavourite
+	}
+	return false
+}
+
+func (x *Note) GetNotebookId() string {
+	if x != nil {
+		return x.NotebookId
+	}
+	return ""
+}
+
+func (x *Note) GetTagIds() []string {
+	if x != nil {
+		return x.TagIds
+	}
+	return nil
+}
+
+func (x *Note) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Note) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Note) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Note) GetJournalDate() string {
+	if x != nil {
+		return x.JournalDate
+	}
+	return ""
+}
+
+type GetNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNoteRequest) Reset() {
+	*x = GetNoteRequest{}
+	mi := &file_notevault_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNoteRequest) ProtoMessage() {}
+
+func (x *GetNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNoteRequest.ProtoReflect.Descriptor instead.
+func (*GetNoteRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListNotesRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Status     NoteStatus             `protobuf:"varint,1,opt,name=status,proto3,enum=notevault.v1.NoteStatus" json:"status,omitempty"`
+	NotebookId string                 `protobuf:"bytes,2,opt,name=notebook_id,json=notebookId,proto3" json:"notebook_id,omitempty"`
+	// Заметки со всеми тегами из списка.
+	TagIds []string `protobuf:"bytes,3,rep,name=tag_ids,json=tagIds,proto3" json:"tag_ids,omitempty"`
+	// Выражение по именам тегов, как query в POST /notes/tag.
+	TagQuery           string `protobuf:"bytes,4,opt,name=tag_query,json=tagQuery,proto3" json:"tag_query,omitempty"`
+	IncludeDescendants bool   `protobuf:"varint,5,opt,name=include_descendants,json=includeDescendants,proto3" json:"include_descendants,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ListNotesRequest) Reset() {
+	*x = ListNotesRequest{}
+	mi := &file_notevault_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotesRequest) ProtoMessage() {}
+
+func (x *ListNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotesRequest.ProtoReflect.Descriptor instead.
+func (*ListNotesRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListNotesRequest) GetStatus() NoteStatus {
+	if x != nil {
+		return x.Status
+	}
+	return NoteStatus_NOTE_STATUS_UNSPECIFIED
+}
+
+func (x *ListNotesRequest) GetNotebookId() string {
+	if x != nil {
+		return x.NotebookId
+	}
+	return ""
+}
+
+func (x *ListNotesRequest) GetTagIds() []string {
+	if x != nil {
+		return x.TagIds
+	}
+	return nil
+}
+
+func (x *ListNotesRequest) GetTagQuery() string {
+	if x != nil {
+		return x.TagQuery
+	}
+	return ""
+}
+
+func (x *ListNotesRequest) GetIncludeDescendants() bool {
+	if x != nil {
+		return x.IncludeDescendants
+	}
+	return false
+}
+
+type CreateNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Color         string                 `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	NotebookId    string                 `protobuf:"bytes,4,opt,name=notebook_id,json=notebookId,proto3" json:"notebook_id,omitempty"`
+	TagIds        []string               `protobuf:"bytes,5,rep,name=tag_ids,json=tagIds,proto3" json:"tag_ids,omitempty"`
+	IsPinned      *bool                  `protobuf:"varint,6,opt,name=is_pinned,json=isPinned,proto3,oneof" json:"is_pinned,omitempty"`
+	IsFavourite   *bool                  `protobuf:"varint,7,opt,name=is_favourite,json=isFavourite,proto3,oneof" json:"is_favourite,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateNoteRequest) Reset() {
+	*x = CreateNoteRequest{}
+	mi := &file_notevault_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNoteRequest) ProtoMessage() {}
+
+func (x *CreateNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNoteRequest.ProtoReflect.Descriptor instead.
+func (*CreateNoteRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateNoteRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetNotebookId() string {
+	if x != nil {
+		return x.NotebookId
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetTagIds() []string {
+	if x != nil {
+		return x.TagIds
+	}
+	return nil
+}
+
+func (x *CreateNoteRequest) GetIsPinned() bool {
+	if x != nil && x.IsPinned != nil {
+		return *x.IsPinned
+	}
+	return false
+}
+
+func (x *CreateNoteRequest) GetIsFavourite() bool {
+	if x != nil && x.IsFavourite != nil {
+		return *x.IsFavourite
+	}
+	return false
+}
+
+// TagIDs отличает "не менять теги" (поле не задано) от "снять все теги" (пустой список).
+type TagIDs struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagIDs) Reset() {
+	*x = TagIDs{}
+	mi := &file_notevault_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagIDs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagIDs) ProtoMessage() {}
+
+func (x *TagIDs) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagIDs.ProtoReflect.Descriptor instead.
+func (*TagIDs) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TagIDs) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type UpdateNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Text          *string                `protobuf:"bytes,3,opt,name=text,proto3,oneof" json:"text,omitempty"`
+	Color         *string                `protobuf:"bytes,4,opt,name=color,proto3,oneof" json:"color,omitempty"`
+	Order         *int32                 `protobuf:"varint,5,opt,name=order,proto3,oneof" json:"order,omitempty"`
+	NotebookId    *string                `protobuf:"bytes,6,opt,name=notebook_id,json=notebookId,proto3,oneof" json:"notebook_id,omitempty"`
+	TagIds        *TagIDs                `protobuf:"bytes,7,opt,name=tag_ids,json=tagIds,proto3" json:"tag_ids,omitempty"`
+	IsDeleted     *bool                  `protobuf:"varint,8,opt,name=is_deleted,json=isDeleted,proto3,oneof" json:"is_deleted,omitempty"`
+	IsArchived    *bool                  `protobuf:"varint,9,opt,name=is_archived,json=isArchived,proto3,oneof" json:"is_archived,omitempty"`
+	IsPinned      *bool                  `protobuf:"varint,10,opt,name=is_pinned,json=isPinned,proto3,oneof" json:"is_pinned,omitempty"`
+	IsFavourite   *bool                  `protobuf:"varint,11,opt,name=is_favourite,json=isFavourite,proto3,oneof" json:"is_favourite,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateNoteRequest) Reset() {
+	*x = UpdateNoteRequest{}
+	mi := &file_notevault_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateNoteRequest) ProtoMessage() {}
+
+func (x *UpdateNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateNoteRequest.ProtoReflect.Descriptor instead.
+func (*UpdateNoteRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetColor() string {
+	if x != nil && x.Color != nil {
+		return *x.Color
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetOrder() int32 {
+	if x != nil && x.Order != nil {
+		return *x.Order
+	}
+	return 0
+}
+
+func (x *UpdateNoteRequest) GetNotebookId() string {
+	if x != nil && x.NotebookId != nil {
+		return *x.NotebookId
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetTagIds() *TagIDs {
+	if x != nil {
+		return x.TagIds
+	}
+	return nil
+}
+
+func (x *UpdateNoteRequest) GetIsDeleted() bool {
+	if x != nil && x.IsDeleted != nil {
+		return *x.IsDeleted
+	}
+	return false
+}
+
+func (x *UpdateNoteRequest) GetIsArchived() bool {
+	if x != nil && x.IsArchived != nil {
+		return *x.IsArchived
+	}
+	return false
+}
+
+func (x *UpdateNoteRequest) GetIsPinned() bool {
+	if x != nil && x.IsPinned != nil {
+		return *x.IsPinned
+	}
+	return false
+}
+
+func (x *UpdateNoteRequest) GetIsFavourite() bool {
+	if x != nil && x.IsFavourite != nil {
+		return *x.IsFavourite
+	}
+	return false
+}
+
+type DeleteNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteNoteRequest) Reset() {
+	*x = DeleteNoteRequest{}
+	mi := &file_notevault_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteNoteRequest) ProtoMessage() {}
+
+func (x *DeleteNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteNoteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteNoteRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type NoteBook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	IsActive      bool                   `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteBook) Reset() {
+	*x = NoteBook{}
+	mi := &file_notevault_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteBook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteBook) ProtoMessage() {}
+
+func (x *NoteBook) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteBook.ProtoReflect.Descriptor instead.
+func (*NoteBook) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *NoteBook) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NoteBook) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NoteBook) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *NoteBook) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+type GetNoteBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNoteBookRequest) Reset() {
+	*x = GetNoteBookRequest{}
+	mi := &file_notevault_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNoteBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNoteBookRequest) ProtoMessage() {}
+
+func (x *GetNoteBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNoteBookRequest.ProtoReflect.Descriptor instead.
+func (*GetNoteBookRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetNoteBookRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListNoteBooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNoteBooksRequest) Reset() {
+	*x = ListNoteBooksRequest{}
+	mi := &file_notevault_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNoteBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNoteBooksRequest) ProtoMessage() {}
+
+func (x *ListNoteBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNoteBooksRequest.ProtoReflect.Descriptor instead.
+func (*ListNoteBooksRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{11}
+}
+
+type CreateNoteBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	IsActive      *bool                  `protobuf:"varint,3,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateNoteBookRequest) Reset() {
+	*x = CreateNoteBookRequest{}
+	mi := &file_notevault_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNoteBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNoteBookRequest) ProtoMessage() {}
+
+func (x *CreateNoteBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNoteBookRequest.ProtoReflect.Descriptor instead.
+func (*CreateNoteBookRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreateNoteBookRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateNoteBookRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateNoteBookRequest) GetIsActive() bool {
+	if x != nil && x.IsActive != nil {
+		return *x.IsActive
+	}
+	return false
+}
+
+type UpdateNoteBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	IsActive      *bool                  `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateNoteBookRequest) Reset() {
+	*x = UpdateNoteBookRequest{}
+	mi := &file_notevault_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateNoteBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateNoteBookRequest) ProtoMessage() {}
+
+func (x *UpdateNoteBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateNoteBookRequest.ProtoReflect.Descriptor instead.
+func (*UpdateNoteBookRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateNoteBookRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateNoteBookRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateNoteBookRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateNoteBookRequest) GetIsActive() bool {
+	if x != nil && x.IsActive != nil {
+		return *x.IsActive
+	}
+	return false
+}
+
+type DeleteNoteBookRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Mode  DeleteMode             `protobuf:"varint,2,opt,name=mode,proto3,enum=notevault.v1.DeleteMode" json:"mode,omitempty"`
+	// Блокнот, куда переносятся заметки при DELETE_MODE_MOVE.
+	TargetId      string `protobuf:"bytes,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteNoteBookRequest) Reset() {
+	*x = DeleteNoteBookRequest{}
+	mi := &file_notevault_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteNoteBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteNoteBookRequest) ProtoMessage() {}
+
+func (x *DeleteNoteBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteNoteBookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteNoteBookRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteNoteBookRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteNoteBookRequest) GetMode() DeleteMode {
+	if x != nil {
+		return x.Mode
+	}
+	return DeleteMode_DELETE_MODE_UNSPECIFIED
+}
+
+func (x *DeleteNoteBookRequest) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+type Tag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Color         string                 `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tag) Reset() {
+	*x = Tag{}
+	mi := &file_notevault_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tag) ProtoMessage() {}
+
+func (x *Tag) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tag.ProtoReflect.Descriptor instead.
+func (*Tag) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Tag) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Tag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Tag) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+type GetTagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTagRequest) Reset() {
+	*x = GetTagRequest{}
+	mi := &file_notevault_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTagRequest) ProtoMessage() {}
+
+func (x *GetTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTagRequest.ProtoReflect.Descriptor instead.
+func (*GetTagRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetTagRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsRequest) Reset() {
+	*x = ListTagsRequest{}
+	mi := &file_notevault_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsRequest) ProtoMessage() {}
+
+func (x *ListTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsRequest.ProtoReflect.Descriptor instead.
+func (*ListTagsRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{17}
+}
+
+type CreateTagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Color         string                 `protobuf:"bytes,2,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTagRequest) Reset() {
+	*x = CreateTagRequest{}
+	mi := &file_notevault_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTagRequest) ProtoMessage() {}
+
+func (x *CreateTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTagRequest.ProtoReflect.Descriptor instead.
+func (*CreateTagRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CreateTagRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateTagRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+type UpdateTagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Color         string                 `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTagRequest) Reset() {
+	*x = UpdateTagRequest{}
+	mi := &file_notevault_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTagRequest) ProtoMessage() {}
+
+func (x *UpdateTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTagRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTagRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpdateTagRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateTagRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateTagRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+type DeleteTagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTagRequest) Reset() {
+	*x = DeleteTagRequest{}
+	mi := &file_notevault_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTagRequest) ProtoMessage() {}
+
+func (x *DeleteTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notevault_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTagRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTagRequest) Descriptor() ([]byte, []int) {
+	return file_notevault_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteTagRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_notevault_proto protoreflect.FileDescriptor
+
+const file_notevault_proto_rawDesc = "" +
+	"\n" +
+	"\x0fnotevault.proto\x12\fnotevault.v1\x1a\x1bgoogle/protobuf/empty.proto\"@\n" +
+	"\fLoginRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\">\n" +
+	"\rLoginResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xb2\x03\n" +
+	"\x04Note\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x12\x14\n" +
+	"\x05color\x18\x04 \x01(\tR\x05color\x12\x14\n" +
+	"\x05order\x18\x05 \x01(\x05R\x05order\x12\x12\n" +
+	"\x04rank\x18\x06 \x01(\tR\x04rank\x12\x1d\n" +
+	"\n" +
+	"is_deleted\x18\a \x01(\bR\tisDeleted\x12\x1f\n" +
+	"\vis_archived\x18\b \x01(\bR\n" +
+	"isArchived\x12\x1b\n" +
+	"\tis_pinned\x18\t \x01(\bR\bisPinned\x12!\n" +
+	"\fis_favourite\x18\n" +
+	" \x01(\bR\visFavourite\x12\x1f\n" +
+	"\vnotebook_id\x18\v \x01(\tR\n" +
+	"notebookId\x12\x17\n" +
+	"\atag_ids\x18\f \x03(\tR\x06tagIds\x12\x17\n" +
+	"\auser_id\x18\r \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x0f \x01(\tR\tupdatedAt\x12!\n" +
+	"\fjournal_date\x18\x10 \x01(\tR\vjournalDate\" \n" +
+	"\x0eGetNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xcc\x01\n" +
+	"\x10ListNotesRequest\x120\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x18.notevault.v1.NoteStatusR\x06status\x12\x1f\n" +
+	"\vnotebook_id\x18\x02 \x01(\tR\n" +
+	"notebookId\x12\x17\n" +
+	"\atag_ids\x18\x03 \x03(\tR\x06tagIds\x12\x1b\n" +
+	"\ttag_query\x18\x04 \x01(\tR\btagQuery\x12/\n" +
+	"\x13include_descendants\x18\x05 \x01(\bR\x12includeDescendants\"\xf4\x01\n" +
+	"\x11CreateNoteRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\tR\x05color\x12\x1f\n" +
+	"\vnotebook_id\x18\x04 \x01(\tR\n" +
+	"notebookId\x12\x17\n" +
+	"\atag_ids\x18\x05 \x03(\tR\x06tagIds\x12 \n" +
+	"\tis_pinned\x18\x06 \x01(\bH\x00R\bisPinned\x88\x01\x01\x12&\n" +
+	"\fis_favourite\x18\a \x01(\bH\x01R\visFavourite\x88\x01\x01B\f\n" +
+	"\n" +
+	"_is_pinnedB\x0f\n" +
+	"\r_is_favourite\"\x1a\n" +
+	"\x06TagIDs\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"\xe8\x03\n" +
+	"\x11UpdateNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12\x17\n" +
+	"\x04text\x18\x03 \x01(\tH\x01R\x04text\x88\x01\x01\x12\x19\n" +
+	"\x05color\x18\x04 \x01(\tH\x02R\x05color\x88\x01\x01\x12\x19\n" +
+	"\x05order\x18\x05 \x01(\x05H\x03R\x05order\x88\x01\x01\x12$\n" +
+	"\vnotebook_id\x18\x06 \x01(\tH\x04R\n" +
+	"notebookId\x88\x01\x01\x12-\n" +
+	"\atag_ids\x18\a \x01(\v2\x14.notevault.v1.TagIDsR\x06tagIds\x12\"\n" +
+	"\n" +
+	"is_deleted\x18\b \x01(\bH\x05R\tisDeleted\x88\x01\x01\x12$\n" +
+	"\vis_archived\x18\t \x01(\bH\x06R\n" +
+	"isArchived\x88\x01\x01\x12 \n" +
+	"\tis_pinned\x18\n" +
+	" \x01(\bH\aR\bisPinned\x88\x01\x01\x12&\n" +
+	"\fis_favourite\x18\v \x01(\bH\bR\visFavourite\x88\x01\x01B\a\n" +
+	"\x05_nameB\a\n" +
+	"\x05_textB\b\n" +
+	"\x06_colorB\b\n" +
+	"\x06_orderB\x0e\n" +
+	"\f_notebook_idB\r\n" +
+	"\v_is_deletedB\x0e\n" +
+	"\f_is_archivedB\f\n" +
+	"\n" +
+	"_is_pinnedB\x0f\n" +
+	"\r_is_favourite\"#\n" +
+	"\x11DeleteNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"m\n" +
+	"\bNoteBook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tis_active\x18\x04 \x01(\bR\bisActive\"$\n" +
+	"\x12GetNoteBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x16\n" +
+	"\x14ListNoteBooksRequest\"}\n" +
+	"\x15CreateNoteBookRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12 \n" +
+	"\tis_active\x18\x03 \x01(\bH\x00R\bisActive\x88\x01\x01B\f\n" +
+	"\n" +
+	"_is_active\"\x8d\x01\n" +
+	"\x15UpdateNoteBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12 \n" +
+	"\tis_active\x18\x04 \x01(\bH\x00R\bisActive\x88\x01\x01B\f\n" +
+	"\n" +
+	"_is_active\"r\n" +
+	"\x15DeleteNoteBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12,\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x18.notevault.v1.DeleteModeR\x04mode\x12\x1b\n" +
+	"\ttarget_id\x18\x03 \x01(\tR\btargetId\"?\n" +
+	"\x03Tag\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\tR\x05color\"\x1f\n" +
+	"\rGetTagRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x11\n" +
+	"\x0fListTagsRequest\"<\n" +
+	"\x10CreateTagRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x02 \x01(\tR\x05color\"L\n" +
+	"\x10UpdateTagRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\tR\x05color\"\"\n" +
+	"\x10DeleteTagRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id*\x89\x01\n" +
+	"\n" +
+	"NoteStatus\x12\x1b\n" +
+	"\x17NOTE_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12NOTE_STATUS_ACTIVE\x10\x01\x12\x18\n" +
+	"\x14NOTE_STATUS_ARCHIVED\x10\x02\x12\x17\n" +
+	"\x13NOTE_STATUS_TRASHED\x10\x03\x12\x13\n" +
+	"\x0fNOTE_STATUS_ALL\x10\x04*\x88\x01\n" +
+	"\n" +
+	"DeleteMode\x12\x1b\n" +
+	"\x17DELETE_MODE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12DELETE_MODE_UNLINK\x10\x01\x12\x14\n" +
+	"\x10DELETE_MODE_MOVE\x10\x02\x12\x15\n" +
+	"\x11DELETE_MODE_TRASH\x10\x03\x12\x18\n" +
+	"\x14DELETE_MODE_RESTRICT\x10\x042\xde\b\n" +
+	"\tNoteVault\x12@\n" +
+	"\x05Login\x12\x1a.notevault.v1.LoginRequest\x1a\x1b.notevault.v1.LoginResponse\x12;\n" +
+	"\aGetNote\x12\x1c.notevault.v1.GetNoteRequest\x1a\x12.notevault.v1.Note\x12A\n" +
+	"\tListNotes\x12\x1e.notevault.v1.ListNotesRequest\x1a\x12.notevault.v1.Note0\x01\x12A\n" +
+	"\n" +
+	"CreateNote\x12\x1f.notevault.v1.CreateNoteRequest\x1a\x12.notevault.v1.Note\x12A\n" +
+	"\n" +
+	"UpdateNote\x12\x1f.notevault.v1.UpdateNoteRequest\x1a\x12.notevault.v1.Note\x12E\n" +
+	"\n" +
+	"DeleteNote\x12\x1f.notevault.v1.DeleteNoteRequest\x1a\x16.google.protobuf.Empty\x12G\n" +
+	"\vGetNoteBook\x12 .notevault.v1.GetNoteBookRequest\x1a\x16.notevault.v1.NoteBook\x12M\n" +
+	"\rListNoteBooks\x12\".notevault.v1.ListNoteBooksRequest\x1a\x16.notevault.v1.NoteBook0\x01\x12M\n" +
+	"\x0eCreateNoteBook\x12#.notevault.v1.CreateNoteBookRequest\x1a\x16.notevault.v1.NoteBook\x12M\n" +
+	"\x0eUpdateNoteBook\x12#.notevault.v1.UpdateNoteBookRequest\x1a\x16.notevault.v1.NoteBook\x12M\n" +
+	"\x0eDeleteNoteBook\x12#.notevault.v1.DeleteNoteBookRequest\x1a\x16.google.protobuf.Empty\x128\n" +
+	"\x06GetTag\x12\x1b.notevault.v1.GetTagRequest\x1a\x11.notevault.v1.Tag\x12>\n" +
+	"\bListTags\x12\x1d.notevault.v1.ListTagsRequest\x1a\x11.notevault.v1.Tag0\x01\x12>\n" +
+	"\tCreateTag\x12\x1e.notevault.v1.CreateTagRequest\x1a\x11.notevault.v1.Tag\x12>\n" +
+	"\tUpdateTag\x12\x1e.notevault.v1.UpdateTagRequest\x1a\x11.notevault.v1.Tag\x12C\n" +
+	"\tDeleteTag\x12\x1e.notevault.v1.DeleteTagRequest\x1a\x16.google.protobuf.EmptyB3Z1github.com/LoL-KeKovich/NoteVault/api/notevaultpbb\x06proto3"
+
+var (
+	file_notevault_proto_rawDescOnce sync.Once
+	file_notevault_proto_rawDescData []byte
+)
+
+func file_notevault_proto_rawDescGZIP() []byte {
+	file_notevault_proto_rawDescOnce.Do(func() {
+		file_notevault_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_notevault_proto_rawDesc), len(file_notevault_proto_rawDesc)))
+	})
+	return file_notevault_proto_rawDescData
+}
+
+var file_notevault_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_notevault_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_notevault_proto_goTypes = []any{
+	(NoteStatus)(0),               // 0: notevault.v1.NoteStatus
+	(DeleteMode)(0),               // 1: notevault.v1.DeleteMode
+	(*LoginRequest)(nil),          // 2: notevault.v1.LoginRequest
+	(*LoginResponse)(nil),         // 3: notevault.v1.LoginResponse
+	(*Note)(nil),                  // 4: notevault.v1.Note
+	(*GetNoteRequest)(nil),        // 5: notevault.v1.GetNoteRequest
+	(*ListNotesRequest)(nil),      // 6: notevault.v1.ListNotesRequest
+	(*CreateNoteRequest)(nil),     // 7: notevault.v1.CreateNoteRequest
+	(*TagIDs)(nil),                // 8: notevault.v1.TagIDs
+	(*UpdateNoteRequest)(nil),     // 9: notevault.v1.UpdateNoteRequest
+	(*DeleteNoteRequest)(nil),     // 10: notevault.v1.DeleteNoteRequest
+	(*NoteBook)(nil),              // 11: notevault.v1.NoteBook
+	(*GetNoteBookRequest)(nil),    // 12: notevault.v1.GetNoteBookRequest
+	(*ListNoteBooksRequest)(nil),  // 13: notevault.v1.ListNoteBooksRequest
+	(*CreateNoteBookRequest)(nil), // 14: notevault.v1.CreateNoteBookRequest
+	(*UpdateNoteBookRequest)(nil), // 15: notevault.v1.UpdateNoteBookRequest
+	(*DeleteNoteBookRequest)(nil), // 16: notevault.v1.DeleteNoteBookRequest
+	(*Tag)(nil),                   // 17: notevault.v1.Tag
+	(*GetTagRequest)(nil),         // 18: notevault.v1.GetTagRequest
+	(*ListTagsRequest)(nil),       // 19: notevault.v1.ListTagsRequest
+	(*CreateTagRequest)(nil),      // 20: notevault.v1.CreateTagRequest
+	(*UpdateTagRequest)(nil),      // 21: notevault.v1.UpdateTagRequest
+	(*DeleteTagRequest)(nil),      // 22: notevault.v1.DeleteTagRequest
+	(*emptypb.Empty)(nil),         // 23: google.protobuf.Empty
+}
+var file_notevault_proto_depIdxs = []int32{
+	0,  // 0: notevault.v1.ListNotesRequest.status:type_name -> notevault.v1.NoteStatus
+	8,  // 1: notevault.v1.UpdateNoteRequest.tag_ids:type_name -> notevault.v1.TagIDs
+	1,  // 2: notevault.v1.DeleteNoteBookRequest.mode:type_name -> notevault.v1.DeleteMode
+	2,  // 3: notevault.v1.NoteVault.Login:input_type -> notevault.v1.LoginRequest
+	5,  // 4: notevault.v1.NoteVault.GetNote:input_type -> notevault.v1.GetNoteRequest
+	6,  // 5: notevault.v1.NoteVault.ListNotes:input_type -> notevault.v1.ListNotesRequest
+	7,  // 6: notevault.v1.NoteVault.CreateNote:input_type -> notevault.v1.CreateNoteRequest
+	9,  // 7: notevault.v1.NoteVault.UpdateNote:input_type -> notevault.v1.UpdateNoteRequest
+	10, // 8: notevault.v1.NoteVault.DeleteNote:input_type -> notevault.v1.DeleteNoteRequest
+	12, // 9: notevault.v1.NoteVault.GetNoteBook:input_type -> notevault.v1.GetNoteBookRequest
+	13, // 10: notevault.v1.NoteVault.ListNoteBooks:input_type -> notevault.v1.ListNoteBooksRequest
+	14, // 11: notevault.v1.NoteVault.CreateNoteBook:input_type -> notevault.v1.CreateNoteBookRequest
+	15, // 12: notevault.v1.NoteVault.UpdateNoteBook:input_type -> notevault.v1.UpdateNoteBookRequest
+	16, // 13: notevault.v1.NoteVault.DeleteNoteBook:input_type -> notevault.v1.DeleteNoteBookRequest
+	18, // 14: notevault.v1.NoteVault.GetTag:input_type -> notevault.v1.GetTagRequest
+	19, // 15: notevault.v1.NoteVault.ListTags:input_type -> notevault.v1.ListTagsRequest
+	20, // 16: notevault.v1.NoteVault.CreateTag:input_type -> notevault.v1.CreateTagRequest
+	21, // 17: notevault.v1.NoteVault.UpdateTag:input_type -> notevault.v1.UpdateTagRequest
+	22, // 18: notevault.v1.NoteVault.DeleteTag:input_type -> notevault.v1.DeleteTagRequest
+	3,  // 19: notevault.v1.NoteVault.Login:output_type -> notevault.v1.LoginResponse
+	4,  // 20: notevault.v1.NoteVault.GetNote:output_type -> notevault.v1.Note
+	4,  // 21: notevault.v1.NoteVault.ListNotes:output_type -> notevault.v1.Note
+	4,  // 22: notevault.v1.NoteVault.CreateNote:output_type -> notevault.v1.Note
+	4,  // 23: notevault.v1.NoteVault.UpdateNote:output_type -> notevault.v1.Note
+	23, // 24: notevault.v1.NoteVault.DeleteNote:output_type -> google.protobuf.Empty
+	11, // 25: notevault.v1.NoteVault.GetNoteBook:output_type -> notevault.v1.NoteBook
+	11, // 26: notevault.v1.NoteVault.ListNoteBooks:output_type -> notevault.v1.NoteBook
+	11, // 27: notevault.v1.NoteVault.CreateNoteBook:output_type -> notevault.v1.NoteBook
+	11, // 28: notevault.v1.NoteVault.UpdateNoteBook:output_type -> notevault.v1.NoteBook
+	23, // 29: notevault.v1.NoteVault.DeleteNoteBook:output_type -> google.protobuf.Empty
+	17, // 30: notevault.v1.NoteVault.GetTag:output_type -> notevault.v1.Tag
+	17, // 31: notevault.v1.NoteVault.ListTags:output_type -> notevault.v1.Tag
+	17, // 32: notevault.v1.NoteVault.CreateTag:output_type -> notevault.v1.Tag
+	17, // 33: notevault.v1.NoteVault.UpdateTag:output_type -> notevault.v1.Tag
+	23, // 34: notevault.v1.NoteVault.DeleteTag:output_type -> google.protobuf.Empty
+	19, // [19:35] is the sub-list for method output_type
+	3,  // [3:19] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_notevault_proto_init() }
+func file_notevault_proto_init() {
+	if File_notevault_proto != nil {
+		return
+	}
+	file_notevault_proto_msgTypes[5].OneofWrappers = []any{}
+	file_notevault_proto_msgTypes[7].OneofWrappers = []any{}
+	file_notevault_proto_msgTypes[12].OneofWrappers = []any{}
+	file_notevault_proto_msgTypes[13].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_notevault_proto_rawDesc), len(file_notevault_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   21,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_notevault_proto_goTypes,
+		DependencyIndexes: file_notevault_proto_depIdxs,
+		EnumInfos:         file_notevault_proto_enumTypes,
+		MessageInfos:      file_notevault_proto_msgTypes,
+	}.Build()
+	File_notevault_proto = out.File
+	file_notevault_proto_goTypes = nil
+	file_notevault_proto_depIdxs = nil
+}
@@ -0,0 +1,722 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: notevault.proto
+
+package notevaultpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NoteVault_Login_FullMethodName          = "/notevault.v1.NoteVault/Login"
+	NoteVault_GetNote_FullMethodName        = "/notevault.v1.NoteVault/GetNote"
+	NoteVault_ListNotes_FullMethodName      = "/notevault.v1.NoteVault/ListNotes"
+	NoteVault_CreateNote_FullMethodName     = "/notevault.v1.NoteVault/CreateNote"
+	NoteVault_UpdateNote_FullMethodName     = "/notevault.v1.NoteVault/UpdateNote"
+	NoteVault_DeleteNote_FullMethodName     = "/notevault.v1.NoteVault/DeleteNote"
+	NoteVault_GetNoteBook_FullMethodName    = "/notevault.v1.NoteVault/GetNoteBook"
+	NoteVault_ListNoteBooks_FullMethodName  = "/notevault.v1.NoteVault/ListNoteBooks"
+	NoteVault_CreateNoteBook_FullMethodName = "/notevault.v1.NoteVault/CreateNoteBook"
+	NoteVault_UpdateNoteBook_FullMethodName = "/notevault.v1.NoteVault/UpdateNoteBook"
+	NoteVault_DeleteNoteBook_FullMethodName = "/notevault.v1.NoteVault/DeleteNoteBook"
+	NoteVault_GetTag_FullMethodName         = "/notevault.v1.NoteVault/GetTag"
+	NoteVault_ListTags_FullMethodName       = "/notevault.v1.NoteVault/ListTags"
+	NoteVault_CreateTag_FullMethodName      = "/notevault.v1.NoteVault/CreateTag"
+	NoteVault_UpdateTag_FullMethodName      = "/notevault.v1.NoteVault/UpdateTag"
+	NoteVault_DeleteTag_FullMethodName      = "/notevault.v1.NoteVault/DeleteTag"
+)
+
+// NoteVaultClient is the client API for NoteVault service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NoteVault - gRPC-доступ к заметкам, блокнотам и тегам для других сервисов. Все методы, кроме Login,
+// требуют токен в метаданных: "authorization: Bearer <token>".
+// Ошибки приходят с кодами gRPC: INVALID_ARGUMENT, UNAUTHENTICATED, NOT_FOUND, FAILED_PRECONDITION и т.д.,
+// ошибки полей - в деталях google.rpc.BadRequest.
+type NoteVaultClient interface {
+	// Login выдает токен - тот же JWT, что POST /users/login кладет в cookie auth_token.
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	GetNote(ctx context.Context, in *GetNoteRequest, opts ...grpc.CallOption) (*Note, error)
+	// ListNotes отдает заметки пользователя и общие заметки потоком, по одной в сообщении.
+	ListNotes(ctx context.Context, in *ListNotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Note], error)
+	CreateNote(ctx context.Context, in *CreateNoteRequest, opts ...grpc.CallOption) (*Note, error)
+	// UpdateNote меняет только переданные поля, пустая строка в text, color и notebook_id очищает поле.
+	UpdateNote(ctx context.Context, in *UpdateNoteRequest, opts ...grpc.CallOption) (*Note, error)
+	DeleteNote(ctx context.Context, in *DeleteNoteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetNoteBook(ctx context.Context, in *GetNoteBookRequest, opts ...grpc.CallOption) (*NoteBook, error)
+	ListNoteBooks(ctx context.Context, in *ListNoteBooksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NoteBook], error)
+	CreateNoteBook(ctx context.Context, in *CreateNoteBookRequest, opts ...grpc.CallOption) (*NoteBook, error)
+	// UpdateNoteBook меняет только непустые поля, как PUT /notebooks/{id}.
+	UpdateNoteBook(ctx context.Context, in *UpdateNoteBookRequest, opts ...grpc.CallOption) (*NoteBook, error)
+	DeleteNoteBook(ctx context.Context, in *DeleteNoteBookRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetTag(ctx context.Context, in *GetTagRequest, opts ...grpc.CallOption) (*Tag, error)
+	ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Tag], error)
+	CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*Tag, error)
+	// UpdateTag меняет только непустые поля, как PUT /tags/{id}.
+	UpdateTag(ctx context.Context, in *UpdateTagRequest, opts ...grpc.CallOption) (*Tag, error)
+	DeleteTag(ctx context.Context, in *DeleteTagRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type noteVaultClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNoteVaultClient(cc grpc.ClientConnInterface) NoteVaultClient {
+	return &noteVaultClient{cc}
+}
+
+func (c *noteVaultClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, NoteVault_Login_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) GetNote(ctx context.Context, in *GetNoteRequest, opts ...grpc.CallOption) (*Note, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Note)
+	err := c.cc.Invoke(ctx, NoteVault_GetNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) ListNotes(ctx context.Context, in *ListNotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Note], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NoteVault_ServiceDesc.Streams[0], NoteVault_ListNotes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListNotesRequest, Note]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NoteVault_ListNotesClient = grpc.ServerStreamingClient[Note]
+
+func (c *noteVaultClient) CreateNote(ctx context.Context, in *CreateNoteRequest, opts ...grpc.CallOption) (*Note, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Note)
+	err := c.cc.Invoke(ctx, NoteVault_CreateNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) UpdateNote(ctx context.Context, in *UpdateNoteRequest, opts ...grpc.CallOption) (*Note, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Note)
+	err := c.cc.Invoke(ctx, NoteVault_UpdateNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) DeleteNote(ctx context.Context, in *DeleteNoteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, NoteVault_DeleteNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) GetNoteBook(ctx context.Context, in *GetNoteBookRequest, opts ...grpc.CallOption) (*NoteBook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NoteBook)
+	err := c.cc.Invoke(ctx, NoteVault_GetNoteBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) ListNoteBooks(ctx context.Context, in *ListNoteBooksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NoteBook], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NoteVault_ServiceDesc.Streams[1], NoteVault_ListNoteBooks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListNoteBooksRequest, NoteBook]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NoteVault_ListNoteBooksClient = grpc.ServerStreamingClient[NoteBook]
+
+func (c *noteVaultClient) CreateNoteBook(ctx context.Context, in *CreateNoteBookRequest, opts ...grpc.CallOption) (*NoteBook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NoteBook)
+	err := c.cc.Invoke(ctx, NoteVault_CreateNoteBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) UpdateNoteBook(ctx context.Context, in *UpdateNoteBookRequest, opts ...grpc.CallOption) (*NoteBook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NoteBook)
+	err := c.cc.Invoke(ctx, NoteVault_UpdateNoteBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) DeleteNoteBook(ctx context.Context, in *DeleteNoteBookRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, NoteVault_DeleteNoteBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) GetTag(ctx context.Context, in *GetTagRequest, opts ...grpc.CallOption) (*Tag, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tag)
+	err := c.cc.Invoke(ctx, NoteVault_GetTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Tag], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NoteVault_ServiceDesc.Streams[2], NoteVault_ListTags_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListTagsRequest, Tag]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NoteVault_ListTagsClient = grpc.ServerStreamingClient[Tag]
+
+func (c *noteVaultClient) CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*Tag, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tag)
+	err := c.cc.Invoke(ctx, NoteVault_CreateTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) UpdateTag(ctx context.Context, in *UpdateTagRequest, opts ...grpc.CallOption) (*Tag, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tag)
+	err := c.cc.Invoke(ctx, NoteVault_UpdateTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *noteVaultClient) DeleteTag(ctx context.Context, in *DeleteTagRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, NoteVault_DeleteTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NoteVaultServer is the server API for NoteVault service.
+// All implementations must embed UnimplementedNoteVaultServer
+// for forward compatibility.
+//
+// NoteVault - gRPC-доступ к заметкам, блокнотам и тегам для других сервисов. Все методы, кроме Login,
+// требуют токен в метаданных: "authorization: Bearer <token>".
+// Ошибки приходят с кодами gRPC: INVALID_ARGUMENT, UNAUTHENTICATED, NOT_FOUND, FAILED_PRECONDITION и т.д.,
+// ошибки полей - в деталях google.rpc.BadRequest.
+type NoteVaultServer interface {
+	// Login выдает токен - тот же JWT, что POST /users/login кладет в cookie auth_token.
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	GetNote(context.Context, *GetNoteRequest) (*Note, error)
+	// ListNotes отдает заметки пользователя и общие заметки потоком, по одной в сообщении.
+	ListNotes(*ListNotesRequest, grpc.ServerStreamingServer[Note]) error
+	CreateNote(context.Context, *CreateNoteRequest) (*Note, error)
+	// UpdateNote меняет только переданные поля, пустая строка в text, color и notebook_id очищает поле.
+	UpdateNote(context.Context, *UpdateNoteRequest) (*Note, error)
+	DeleteNote(context.Context, *DeleteNoteRequest) (*emptypb.Empty, error)
+	GetNoteBook(context.Context, *GetNoteBookRequest) (*NoteBook, error)
+	ListNoteBooks(*ListNoteBooksRequest, grpc.ServerStreamingServer[NoteBook]) error
+	CreateNoteBook(context.Context, *CreateNoteBookRequest) (*NoteBook, error)
+	// UpdateNoteBook меняет только непустые поля, как PUT /notebooks/{id}.
+	UpdateNoteBook(context.Context, *UpdateNoteBookRequest) (*NoteBook, error)
+	DeleteNoteBook(context.Context, *DeleteNoteBookRequest) (*emptypb.Empty, error)
+	GetTag(context.Context, *GetTagRequest) (*Tag, error)
+	ListTags(*ListTagsRequest, grpc.ServerStreamingServer[Tag]) error
+	CreateTag(context.Context, *CreateTagRequest) (*Tag, error)
+	// UpdateTag меняет только непустые поля, как PUT /tags/{id}.
+	UpdateTag(context.Context, *UpdateTagRequest) (*Tag, error)
+	DeleteTag(context.Context, *DeleteTagRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedNoteVaultServer()
+}
+
+// UnimplementedNoteVaultServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNoteVaultServer struct{}
+
+func (UnimplementedNoteVaultServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedNoteVaultServer) GetNote(context.Context, *GetNoteRequest) (*Note, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNote not implemented")
+}
+func (UnimplementedNoteVaultServer) ListNotes(*ListNotesRequest, grpc.ServerStreamingServer[Note]) error {
+	return status.Errorf(codes.Unimplemented, "method ListNotes not implemented")
+}
+func (UnimplementedNoteVaultServer) CreateNote(context.Context, *CreateNoteRequest) (*Note, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNote not implemented")
+}
+func (UnimplementedNoteVaultServer) UpdateNote(context.Context, *UpdateNoteRequest) (*Note, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNote not implemented")
+}
+func (UnimplementedNoteVaultServer) DeleteNote(context.Context, *DeleteNoteRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteNote not implemented")
+}
+func (UnimplementedNoteVaultServer) GetNoteBook(context.Context, *GetNoteBookRequest) (*NoteBook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNoteBook not implemented")
+}
+func (UnimplementedNoteVaultServer) ListNoteBooks(*ListNoteBooksRequest, grpc.ServerStreamingServer[NoteBook]) error {
+	return status.Errorf(codes.Unimplemented, "method ListNoteBooks not implemented")
+}
+func (UnimplementedNoteVaultServer) CreateNoteBook(context.Context, *CreateNoteBookRequest) (*NoteBook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNoteBook not implemented")
+}
+func (UnimplementedNoteVaultServer) UpdateNoteBook(context.Context, *UpdateNoteBookRequest) (*NoteBook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNoteBook not implemented")
+}
+func (UnimplementedNoteVaultServer) DeleteNoteBook(context.Context, *DeleteNoteBookRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteNoteBook not implemented")
+}
+func (UnimplementedNoteVaultServer) GetTag(context.Context, *GetTagRequest) (*Tag, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTag not implemented")
+}
+func (UnimplementedNoteVaultServer) ListTags(*ListTagsRequest, grpc.ServerStreamingServer[Tag]) error {
+	return status.Errorf(codes.Unimplemented, "method ListTags not implemented")
+}
+func (UnimplementedNoteVaultServer) CreateTag(context.Context, *CreateTagRequest) (*Tag, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTag not implemented")
+}
+func (UnimplementedNoteVaultServer) UpdateTag(context.Context, *UpdateTagRequest) (*Tag, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTag not implemented")
+}
+func (UnimplementedNoteVaultServer) DeleteTag(context.Context, *DeleteTagRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTag not implemented")
+}
+func (UnimplementedNoteVaultServer) mustEmbedUnimplementedNoteVaultServer() {}
+func (UnimplementedNoteVaultServer) testEmbeddedByValue()                   {}
+
+// UnsafeNoteVaultServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NoteVaultServer will
+// result in compilation errors.
+type UnsafeNoteVaultServer interface {
+	mustEmbedUnimplementedNoteVaultServer()
+}
+
+func RegisterNoteVaultServer(s grpc.ServiceRegistrar, srv NoteVaultServer) {
+	// If the following call pancis, it indicates UnimplementedNoteVaultServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NoteVault_ServiceDesc, srv)
+}
+
+func _NoteVault_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_GetNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).GetNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_GetNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).GetNote(ctx, req.(*GetNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_ListNotes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListNotesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NoteVaultServer).ListNotes(m, &grpc.GenericServerStream[ListNotesRequest, Note]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NoteVault_ListNotesServer = grpc.ServerStreamingServer[Note]
+
+func _NoteVault_CreateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).CreateNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_CreateNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).CreateNote(ctx, req.(*CreateNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_UpdateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).UpdateNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_UpdateNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).UpdateNote(ctx, req.(*UpdateNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_DeleteNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).DeleteNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_DeleteNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).DeleteNote(ctx, req.(*DeleteNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_GetNoteBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNoteBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).GetNoteBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_GetNoteBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).GetNoteBook(ctx, req.(*GetNoteBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_ListNoteBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListNoteBooksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NoteVaultServer).ListNoteBooks(m, &grpc.GenericServerStream[ListNoteBooksRequest, NoteBook]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NoteVault_ListNoteBooksServer = grpc.ServerStreamingServer[NoteBook]
+
+func _NoteVault_CreateNoteBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNoteBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).CreateNoteBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_CreateNoteBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).CreateNoteBook(ctx, req.(*CreateNoteBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_UpdateNoteBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNoteBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).UpdateNoteBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_UpdateNoteBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).UpdateNoteBook(ctx, req.(*UpdateNoteBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_DeleteNoteBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNoteBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).DeleteNoteBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_DeleteNoteBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).DeleteNoteBook(ctx, req.(*DeleteNoteBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_GetTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).GetTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_GetTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).GetTag(ctx, req.(*GetTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_ListTags_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListTagsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NoteVaultServer).ListTags(m, &grpc.GenericServerStream[ListTagsRequest, Tag]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NoteVault_ListTagsServer = grpc.ServerStreamingServer[Tag]
+
+func _NoteVault_CreateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).CreateTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_CreateTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).CreateTag(ctx, req.(*CreateTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_UpdateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).UpdateTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_UpdateTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).UpdateTag(ctx, req.(*UpdateTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NoteVault_DeleteTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NoteVaultServer).DeleteTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NoteVault_DeleteTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NoteVaultServer).DeleteTag(ctx, req.(*DeleteTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NoteVault_ServiceDesc is the grpc.ServiceDesc for NoteVault service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NoteVault_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "notevault.v1.NoteVault",
+	HandlerType: (*NoteVaultServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Login",
+			Handler:    _NoteVault_Login_Handler,
+		},
+		{
+			MethodName: "GetNote",
+			Handler:    _NoteVault_GetNote_Handler,
+		},
+		{
+			MethodName: "CreateNote",
+			Handler:    _NoteVault_CreateNote_Handler,
+		},
+		{
+			MethodName: "UpdateNote",
+			Handler:    _NoteVault_UpdateNote_Handler,
+		},
+		{
+			MethodName: "DeleteNote",
+			Handler:    _NoteVault_DeleteNote_Handler,
+		},
+		{
+			MethodName: "GetNoteBook",
+			Handler:    _NoteVault_GetNoteBook_Handler,
+		},
+		{
+			MethodName: "CreateNoteBook",
+			Handler:    _NoteVault_CreateNoteBook_Handler,
+		},
+		{
+			MethodName: "UpdateNoteBook",
+			Handler:    _NoteVault_UpdateNoteBook_Handler,
+		},
+		{
+			MethodName: "DeleteNoteBook",
+			Handler:    _NoteVault_DeleteNoteBook_Handler,
+		},
+		{
+			MethodName: "GetTag",
+			Handler:    _NoteVault_GetTag_Handler,
+		},
+		{
+			MethodName: "CreateTag",
+			Handler:    _NoteVault_CreateTag_Handler,
+		},
+		{
+			MethodName: "UpdateTag",
+			Handler:    _NoteVault_UpdateTag_Handler,
+		},
+		{
+			MethodName: "DeleteTag",
+			Handler:    _NoteVault_DeleteTag_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListNotes",
+			Handler:       _NoteVault_ListNotes_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListNoteBooks",
+			Handler:       _NoteVault_ListNoteBooks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListTags",
+			Handler:       _NoteVault_ListTags_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "notevault.proto",
+}
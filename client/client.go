@@ -0,0 +1,174 @@
+// Package client - типизированный клиент API NoteVault.
+// Методы и типы в client_gen.go генерируются по api/openapi.json, здесь только транспорт
+package client
+
+//go:generate go run ../cmd/clientgen -spec ../api/openapi.json -out client_gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+)
+
+// Client ходит в API NoteVault. BaseURL включает префикс API, например http://localhost:8082/api/v1.
+// Авторизация хранится в cookie, поэтому HTTPClient должен иметь Jar, чтобы LoginUser действовал на следующие запросы
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// New создает клиент. Если httpClient nil, создается клиент со своим хранилищем cookie
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		jar, _ := cookiejar.New(nil)
+		httpClient = &http.Client{Jar: jar}
+	}
+
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: httpClient,
+	}
+}
+
+// Error - ответ API со статусом не 2xx
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Fields     []FieldError
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("notevault: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Nullable - поле запроса JSON Merge Patch. Нулевое значение не отправляется,
+// Value задает новое значение, Null отправляет null и очищает поле
+type Nullable[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+func Value[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, set: true}
+}
+
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{set: true, null: true}
+}
+
+func (n Nullable[T]) IsZero() bool {
+	return !n.set
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.null {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.value)
+}
+
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	contentType string
+	body        any
+}
+
+// call выполняет запрос и разбирает поле data из ответа
+func (c *Client) call(ctx context.Context, req request, out any) error {
+	envelope := struct {
+		Data any `json:"data"`
+	}{Data: out}
+
+	return c.callRaw(ctx, req, &envelope)
+}
+
+func (c *Client) callRaw(ctx context.Context, req request, out any) error {
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) callText(ctx context.Context, req request) (string, error) {
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	text, err := io.ReadAll(resp.Body)
+	return string(text), err
+}
+
+// stream возвращает тело ответа как есть, закрыть его должен вызывающий
+func (c *Client) stream(ctx context.Context, req request) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+func (c *Client) do(ctx context.Context, req request) (*http.Response, error) {
+	target := c.BaseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	var body io.Reader
+	switch b := req.body.(type) {
+	case nil:
+	case io.Reader:
+		body = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("notevault: encoding request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+
+		var errResp ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&errResp)
+
+		return nil, &Error{
+			StatusCode: resp.StatusCode,
+			Code:       errResp.Code,
+			Message:    errResp.Error,
+			Fields:     errResp.Fields,
+		}
+	}
+
+	return resp, nil
+}
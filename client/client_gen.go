@@ -0,0 +1,899 @@
+// Code generated by clientgen from api/openapi.json. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+type Note struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Text  string `json:"text,omitempty"`
+	Color string `json:"color,omitempty"`
+	Order int    `json:"order,omitempty"`
+	// Ключ сортировки внутри блокнота
+	Rank        string   `json:"rank,omitempty"`
+	IsDeleted   *bool    `json:"is_deleted,omitempty"`
+	IsArchived  *bool    `json:"is_archived,omitempty"`
+	IsPinned    *bool    `json:"is_pinned,omitempty"`
+	IsFavourite *bool    `json:"is_favourite,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+	NotebookID  string   `json:"notebook_id,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	UserID      string   `json:"user_id,omitempty"`
+	// Дата ежедневной заметки, YYYY-MM-DD
+	JournalDate string `json:"journal_date,omitempty"`
+}
+
+type NoteTag struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+// NoteView - заметка с развернутыми тегами
+type NoteView struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Text  string `json:"text,omitempty"`
+	Color string `json:"color,omitempty"`
+	Order int    `json:"order,omitempty"`
+	// Ключ сортировки внутри блокнота
+	Rank        string    `json:"rank,omitempty"`
+	IsDeleted   *bool     `json:"is_deleted,omitempty"`
+	IsArchived  *bool     `json:"is_archived,omitempty"`
+	IsPinned    *bool     `json:"is_pinned,omitempty"`
+	IsFavourite *bool     `json:"is_favourite,omitempty"`
+	CreatedAt   string    `json:"created_at"`
+	UpdatedAt   string    `json:"updated_at"`
+	NotebookID  string    `json:"notebook_id,omitempty"`
+	Tags        []NoteTag `json:"tags,omitempty"`
+	UserID      string    `json:"user_id,omitempty"`
+	// Дата ежедневной заметки, YYYY-MM-DD
+	JournalDate string `json:"journal_date,omitempty"`
+}
+
+type NoteBook struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	IsActive    *bool  `json:"is_active,omitempty"`
+}
+
+type Tag struct {
+	ID string `json:"id,omitempty"`
+	// Иерархия задается через /: work/clientA
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+// TagUsage - тег с числом заметок, ответ GET /tags?with_counts=true
+type TagUsage struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Color     string `json:"color,omitempty"`
+	NoteCount int    `json:"note_count"`
+}
+
+type Template struct {
+	ID         string   `json:"id,omitempty"`
+	UserID     string   `json:"user_id"`
+	Name       string   `json:"name,omitempty"`
+	Text       string   `json:"text,omitempty"`
+	Color      string   `json:"color,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	NotebookID string   `json:"notebook_id,omitempty"`
+}
+
+type User struct {
+	ID        string `json:"id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+}
+
+type JournalCalendar struct {
+	Month string   `json:"month"`
+	Days  []string `json:"days"`
+}
+
+type BulkResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type NoteBulkResult struct {
+	Total     int          `json:"total"`
+	Succeeded int          `json:"succeeded"`
+	Items     []BulkResult `json:"items"`
+}
+
+type ImportItem struct {
+	File   string `json:"file"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ImportResult struct {
+	Total    int          `json:"total"`
+	Imported int          `json:"imported"`
+	Items    []ImportItem `json:"items"`
+}
+
+// NoteRequest - создание и изменение заметки. В PUT пустое поле не меняется
+type NoteRequest struct {
+	// Обязательно при создании, если не задан template_id
+	Name string `json:"name,omitempty"`
+	Text string `json:"text,omitempty"`
+	// Цвет в формате #rgb или #rrggbb
+	Color      string `json:"color,omitempty"`
+	Order      int    `json:"order,omitempty"`
+	IsDeleted  *bool  `json:"is_deleted,omitempty"`
+	IsArchived *bool  `json:"is_archived,omitempty"`
+	NotebookID string `json:"notebook_id,omitempty"`
+	TagID      string `json:"tag_id,omitempty"`
+	TagName    string `json:"tag_name,omitempty"`
+	TemplateID string `json:"template_id,omitempty"`
+}
+
+// NotePatchRequest - JSON Merge Patch (RFC 7396): отсутствующее поле не меняется, null очищает его
+type NotePatchRequest struct {
+	// null недопустим: имя нельзя очистить
+	Name Nullable[string] `json:"name,omitzero"`
+	Text Nullable[string] `json:"text,omitzero"`
+	// Цвет в формате #rgb или #rrggbb
+	Color Nullable[string] `json:"color,omitzero"`
+	Order Nullable[int]    `json:"order,omitzero"`
+	// null убирает заметку из блокнота
+	NotebookID Nullable[string] `json:"notebook_id,omitzero"`
+	// Заменяет список тегов целиком
+	Tags Nullable[[]string] `json:"tags,omitzero"`
+}
+
+// NoteReorderRequest - ровно одно из полей: заметка, перед или после которой встать
+type NoteReorderRequest struct {
+	BeforeID string `json:"before_id,omitempty"`
+	AfterID  string `json:"after_id,omitempty"`
+}
+
+type NoteTagsRequest struct {
+	TagIDs []string `json:"tag_ids,omitempty"`
+	// Имена тегов
+	Tags   []string `json:"tags,omitempty"`
+	AnyOf  []string `json:"any_of,omitempty"`
+	AllOf  []string `json:"all_of,omitempty"`
+	NoneOf []string `json:"none_of,omitempty"`
+	// Выражение над тегами: work AND (urgent OR NOT done)
+	Query              string `json:"query,omitempty"`
+	IncludeDescendants *bool  `json:"include_descendants,omitempty"`
+	NotebookID         string `json:"notebook_id,omitempty"`
+	Status             string `json:"status,omitempty"`
+}
+
+// NoteBulkRequest - нужно одно из ids и filter, не больше 1000 заметок
+type NoteBulkRequest struct {
+	Action     string           `json:"action"`
+	IDs        []string         `json:"ids,omitempty"`
+	Filter     *NoteTagsRequest `json:"filter,omitempty"`
+	NotebookID string           `json:"notebook_id,omitempty"`
+	TagID      string           `json:"tag_id,omitempty"`
+	TagName    string           `json:"tag_name,omitempty"`
+	// Цвет в формате #rgb или #rrggbb
+	Color string `json:"color,omitempty"`
+}
+
+// NoteBookRequest - в PUT name не обязателен
+type NoteBookRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	IsActive    *bool  `json:"is_active,omitempty"`
+}
+
+// TagRequest - в PUT name не обязателен
+type TagRequest struct {
+	Name string `json:"name"`
+	// Цвет в формате #rgb или #rrggbb
+	Color string `json:"color,omitempty"`
+}
+
+type TagMergeRequest struct {
+	TargetID string `json:"target_id"`
+}
+
+type RegisterRequest struct {
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// TemplateRequest - в PUT name не обязателен
+type TemplateRequest struct {
+	Name string `json:"name"`
+	// Подстановки: {{date}}, {{time}}, {{datetime}}, {{weekday}}, {{user}}
+	Text string `json:"text,omitempty"`
+	// Цвет в формате #rgb или #rrggbb
+	Color      string   `json:"color,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	NotebookID string   `json:"notebook_id,omitempty"`
+}
+
+// Event - изменение сущности, клиент перечитывает ее по id
+type Event struct {
+	Kind   string `json:"kind"`
+	Action string `json:"action"`
+	ID     string `json:"id"`
+}
+
+type Webhook struct {
+	ID     string `json:"id,omitempty"`
+	UserID string `json:"user_id"`
+	URL    string `json:"url"`
+	// Ключ подписи X-NoteVault-Signature
+	Secret string `json:"secret"`
+	// Пустой список - все события
+	Events []string `json:"events,omitempty"`
+	// false - события не отправляются, по умолчанию true
+	IsActive  *bool  `json:"is_active,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// WebhookDelivery - одна попытка доставки. Попытки одного события имеют общий delivery_id
+type WebhookDelivery struct {
+	ID        string `json:"id"`
+	WebhookID string `json:"webhook_id"`
+	// Значение заголовка X-NoteVault-Delivery и id в теле
+	DeliveryID string `json:"delivery_id"`
+	Event      string `json:"event"`
+	Attempt    int    `json:"attempt"`
+	// Статус ответа, нет если ответа не было
+	StatusCode int  `json:"status_code,omitempty"`
+	Success    bool `json:"success"`
+	// Ошибка соединения или таймаут
+	Error      string `json:"error,omitempty"`
+	DurationMs int    `json:"duration_ms"`
+	// Отправленное тело WebhookPayload
+	Payload   string `json:"payload"`
+	CreatedAt string `json:"created_at"`
+}
+
+// WebhookRequest - в PUT url не обязателен, пустые поля не меняются, а пустой список events подписывает на все события
+type WebhookRequest struct {
+	// Адрес http или https
+	URL string `json:"url"`
+	// Без secret при создании сервер генерирует его сам
+	Secret   string   `json:"secret,omitempty"`
+	Events   []string `json:"events,omitempty"`
+	IsActive *bool    `json:"is_active,omitempty"`
+}
+
+// WebhookPayload - тело запроса на адрес вебхука. note нет у удаленной заметки и у события ping
+type WebhookPayload struct {
+	// Совпадает с X-NoteVault-Delivery и не меняется между повторами
+	ID    string `json:"id"`
+	Event string `json:"event"`
+	// Время события, RFC 3339
+	CreatedAt string `json:"created_at"`
+	NoteID    string `json:"note_id,omitempty"`
+	Note      *Note  `json:"note,omitempty"`
+}
+
+// SyncPull - изменения после token. full - в ответе все данные пользователя, клиент заменяет ими локальную копию. has_more - изменения не поместились в ответ, их нужно сразу дочитать с новым token
+type SyncPull struct {
+	// Передается в следующий GET /sync
+	Token     string          `json:"token"`
+	Full      bool            `json:"full"`
+	HasMore   bool            `json:"has_more"`
+	Notes     []SyncNote      `json:"notes"`
+	Notebooks []SyncNoteBook  `json:"notebooks"`
+	Tags      []SyncTag       `json:"tags"`
+	Deleted   []SyncTombstone `json:"deleted"`
+}
+
+type SyncNote struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Text  string `json:"text,omitempty"`
+	Color string `json:"color,omitempty"`
+	Order int    `json:"order,omitempty"`
+	// Ключ сортировки внутри блокнота
+	Rank        string   `json:"rank,omitempty"`
+	IsDeleted   *bool    `json:"is_deleted,omitempty"`
+	IsArchived  *bool    `json:"is_archived,omitempty"`
+	IsPinned    *bool    `json:"is_pinned,omitempty"`
+	IsFavourite *bool    `json:"is_favourite,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+	NotebookID  string   `json:"notebook_id,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	UserID      string   `json:"user_id,omitempty"`
+	// Дата ежедневной заметки, YYYY-MM-DD
+	JournalDate string `json:"journal_date,omitempty"`
+	// Ревизия сущности: передается в SyncChange.rev при изменении
+	Rev int `json:"rev"`
+}
+
+type SyncNoteBook struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	IsActive    *bool  `json:"is_active,omitempty"`
+	// Ревизия сущности: передается в SyncChange.rev при изменении
+	Rev int `json:"rev"`
+}
+
+type SyncTag struct {
+	ID string `json:"id,omitempty"`
+	// Иерархия задается через /: work/clientA
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+	// Ревизия сущности: передается в SyncChange.rev при изменении
+	Rev int `json:"rev"`
+}
+
+// SyncTombstone - удаленная сущность
+type SyncTombstone struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	Rev  int    `json:"rev"`
+}
+
+type SyncPushRequest struct {
+	Changes []SyncChange `json:"changes"`
+}
+
+// SyncChange - локальное изменение клиента. Для update и delete id - ID на сервере, rev - ревизия, с которой клиент начинал изменение. В note, notebook или tag - полное новое состояние сущности
+type SyncChange struct {
+	Kind string `json:"kind,omitempty"`
+	Op   string `json:"op,omitempty"`
+	ID   string `json:"id,omitempty"`
+	// Локальный ID клиента, возвращается в результате
+	ClientID string           `json:"client_id,omitempty"`
+	Rev      int              `json:"rev,omitempty"`
+	Note     *SyncNoteData    `json:"note,omitempty"`
+	Notebook *NoteBookRequest `json:"notebook,omitempty"`
+	Tag      *TagRequest      `json:"tag,omitempty"`
+}
+
+// SyncNoteData - состояние заметки с клиента. Не переданные флаги не меняются
+type SyncNoteData struct {
+	Name        string   `json:"name"`
+	Text        string   `json:"text,omitempty"`
+	Color       string   `json:"color,omitempty"`
+	NotebookID  string   `json:"notebook_id,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	IsDeleted   *bool    `json:"is_deleted,omitempty"`
+	IsArchived  *bool    `json:"is_archived,omitempty"`
+	IsPinned    *bool    `json:"is_pinned,omitempty"`
+	IsFavourite *bool    `json:"is_favourite,omitempty"`
+}
+
+// SyncResult - результат одного изменения. При конфликте в note, notebook или tag - текущее состояние сущности на сервере, deleted - сущность на сервере уже удалена
+type SyncResult struct {
+	Kind     string        `json:"kind"`
+	ID       string        `json:"id,omitempty"`
+	ClientID string        `json:"client_id,omitempty"`
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Rev      int           `json:"rev,omitempty"`
+	Deleted  *bool         `json:"deleted,omitempty"`
+	Note     *SyncNote     `json:"note,omitempty"`
+	Notebook *SyncNoteBook `json:"notebook,omitempty"`
+	Tag      *SyncTag      `json:"tag,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type ErrorResponse struct {
+	// Частичный результат, например ImportResult при ошибке импорта
+	Data   json.RawMessage `json:"data,omitempty"`
+	Error  string          `json:"error"`
+	Code   string          `json:"code"`
+	Fields []FieldError    `json:"fields,omitempty"`
+}
+
+type GraphQLRequest struct {
+	// Документ GraphQL
+	Query string `json:"query"`
+	// Операция для выполнения, если в документе их несколько
+	OperationName string          `json:"operationName,omitempty"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+	// Принимается и не используется
+	Extensions json.RawMessage `json:"extensions,omitempty"`
+}
+
+type GraphQLResponse struct {
+	// null, если ошибка в non-null поле верхнего уровня; нет, если запрос не дошел до выполнения
+	Data   json.RawMessage `json:"data,omitempty"`
+	Errors []GraphQLError  `json:"errors,omitempty"`
+}
+
+type GraphQLError struct {
+	Message   string            `json:"message"`
+	Locations []GraphQLLocation `json:"locations,omitempty"`
+	// Путь до поля с ошибкой в data
+	Path []json.RawMessage `json:"path,omitempty"`
+	// code - код ошибки как в ErrorResponse, fields - ошибки полей входного объекта
+	Extensions json.RawMessage `json:"extensions,omitempty"`
+}
+
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Health - GET /health. Проверка работоспособности
+func (c *Client) Health(ctx context.Context) (string, error) {
+	return c.callText(ctx, request{method: http.MethodGet, path: "/health"})
+}
+
+// GetOpenAPI - GET /openapi.json. Эта спецификация
+func (c *Client) GetOpenAPI(ctx context.Context) (json.RawMessage, error) {
+	var out json.RawMessage
+	err := c.callRaw(ctx, request{method: http.MethodGet, path: "/openapi.json"}, &out)
+	return out, err
+}
+
+// GetNotes - GET /notes. Активные заметки
+func (c *Client) GetNotes(ctx context.Context) ([]NoteView, error) {
+	var out []NoteView
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notes"}, &out)
+	return out, err
+}
+
+// CreateNote - POST /notes. Создать заметку
+func (c *Client) CreateNote(ctx context.Context, body NoteRequest) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodPost, path: "/notes", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// GetNoteByID - GET /notes/{id}. Заметка по ID
+func (c *Client) GetNoteByID(ctx context.Context, id string) (NoteView, error) {
+	var out NoteView
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notes/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// UpdateNote - PUT /notes/{id}. Изменить имя, текст, цвет или порядок
+func (c *Client) UpdateNote(ctx context.Context, id string, body NoteRequest) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodPut, path: "/notes/" + url.PathEscape(id), contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// PatchNote - PATCH /notes/{id}. Изменить заметку через JSON Merge Patch
+func (c *Client) PatchNote(ctx context.Context, id string, body NotePatchRequest) (NoteView, error) {
+	var out NoteView
+	err := c.call(ctx, request{method: http.MethodPatch, path: "/notes/" + url.PathEscape(id), contentType: "application/merge-patch+json", body: body}, &out)
+	return out, err
+}
+
+// DeleteNote - DELETE /notes/{id}. Удалить заметку
+func (c *Client) DeleteNote(ctx context.Context, id string) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodDelete, path: "/notes/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// GetTrashedNotes - GET /notes/trash. Заметки в корзине
+func (c *Client) GetTrashedNotes(ctx context.Context) ([]NoteView, error) {
+	var out []NoteView
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notes/trash"}, &out)
+	return out, err
+}
+
+// GetArchivedNotes - GET /notes/archive. Заметки в архиве
+func (c *Client) GetArchivedNotes(ctx context.Context) ([]NoteView, error) {
+	var out []NoteView
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notes/archive"}, &out)
+	return out, err
+}
+
+// GetFavouriteNotes - GET /notes/favourites. Избранные заметки
+func (c *Client) GetFavouriteNotes(ctx context.Context) ([]NoteView, error) {
+	var out []NoteView
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notes/favourites"}, &out)
+	return out, err
+}
+
+// RestoreNoteFromTrash - GET /notes/trash/{id}. Вернуть заметку из корзины
+func (c *Client) RestoreNoteFromTrash(ctx context.Context, id string) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notes/trash/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// MoveNoteToTrash - DELETE /notes/trash/{id}. Переместить заметку в корзину
+func (c *Client) MoveNoteToTrash(ctx context.Context, id string) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodDelete, path: "/notes/trash/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// RestoreNoteFromArchive - GET /notes/archive/{id}. Вернуть заметку из архива
+func (c *Client) RestoreNoteFromArchive(ctx context.Context, id string) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notes/archive/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// MoveNoteToArchive - DELETE /notes/archive/{id}. Переместить заметку в архив
+func (c *Client) MoveNoteToArchive(ctx context.Context, id string) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodDelete, path: "/notes/archive/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// GetNotesByNoteBookID - GET /notes/group/{id}. Заметки блокнота
+func (c *Client) GetNotesByNoteBookID(ctx context.Context, id string) ([]NoteView, error) {
+	var out []NoteView
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notes/group/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// GetNotesByTags - POST /notes/tag. Поиск заметок по тегам
+func (c *Client) GetNotesByTags(ctx context.Context, body NoteTagsRequest) ([]NoteView, error) {
+	var out []NoteView
+	err := c.call(ctx, request{method: http.MethodPost, path: "/notes/tag", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// BulkNotes - POST /notes/bulk. Массовое действие над заметками
+func (c *Client) BulkNotes(ctx context.Context, body NoteBulkRequest) (NoteBulkResult, error) {
+	var out NoteBulkResult
+	err := c.call(ctx, request{method: http.MethodPost, path: "/notes/bulk", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// UpdateNoteNoteBook - PUT /notes/notebook/{id}. Переместить заметку в блокнот из notebook_id
+func (c *Client) UpdateNoteNoteBook(ctx context.Context, id string, body NoteRequest) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodPut, path: "/notes/notebook/" + url.PathEscape(id), contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// RemoveNoteBookFromNote - DELETE /notes/notebook/{id}. Убрать заметку из блокнота
+func (c *Client) RemoveNoteBookFromNote(ctx context.Context, id string) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodDelete, path: "/notes/notebook/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// ReorderNote - PUT /notes/order/{id}. Переставить заметку внутри блокнота
+func (c *Client) ReorderNote(ctx context.Context, id string, body NoteReorderRequest) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodPut, path: "/notes/order/" + url.PathEscape(id), contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// PinNote - PUT /notes/pin/{id}. Закрепить заметку
+func (c *Client) PinNote(ctx context.Context, id string) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodPut, path: "/notes/pin/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// UnpinNote - DELETE /notes/pin/{id}. Открепить заметку
+func (c *Client) UnpinNote(ctx context.Context, id string) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodDelete, path: "/notes/pin/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// AddNoteToFavourites - PUT /notes/favourite/{id}. Добавить заметку в избранное
+func (c *Client) AddNoteToFavourites(ctx context.Context, id string) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodPut, path: "/notes/favourite/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// RemoveNoteFromFavourites - DELETE /notes/favourite/{id}. Убрать заметку из избранного
+func (c *Client) RemoveNoteFromFavourites(ctx context.Context, id string) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodDelete, path: "/notes/favourite/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// AddTagToNote - PUT /notes/tag/{id}. Добавить тег по tag_id или tag_name
+func (c *Client) AddTagToNote(ctx context.Context, id string, body NoteRequest) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodPut, path: "/notes/tag/" + url.PathEscape(id), contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// RemoveTagFromNote - PATCH /notes/tag/{id}. Снять тег по tag_id или tag_name
+func (c *Client) RemoveTagFromNote(ctx context.Context, id string, body NoteRequest) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodPatch, path: "/notes/tag/" + url.PathEscape(id), contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// GetDailyNote - GET /notes/daily/{date}. Ежедневная заметка, создается при первом запросе
+func (c *Client) GetDailyNote(ctx context.Context, date string) (NoteView, error) {
+	var out NoteView
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notes/daily/" + url.PathEscape(date)}, &out)
+	return out, err
+}
+
+// GetJournalCalendar - GET /notes/daily/calendar/{month}. Дни месяца с ежедневными заметками
+func (c *Client) GetJournalCalendar(ctx context.Context, month string) (JournalCalendar, error) {
+	var out JournalCalendar
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notes/daily/calendar/" + url.PathEscape(month)}, &out)
+	return out, err
+}
+
+// GetNoteBooks - GET /notebooks. Все блокноты
+func (c *Client) GetNoteBooks(ctx context.Context) ([]NoteBook, error) {
+	var out []NoteBook
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notebooks"}, &out)
+	return out, err
+}
+
+// CreateNoteBook - POST /notebooks. Создать блокнот
+func (c *Client) CreateNoteBook(ctx context.Context, body NoteBookRequest) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodPost, path: "/notebooks", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// GetNoteBookByID - GET /notebooks/{id}. Блокнот по ID
+func (c *Client) GetNoteBookByID(ctx context.Context, id string) (NoteBook, error) {
+	var out NoteBook
+	err := c.call(ctx, request{method: http.MethodGet, path: "/notebooks/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// UpdateNoteBook - PUT /notebooks/{id}. Изменить блокнот
+func (c *Client) UpdateNoteBook(ctx context.Context, id string, body NoteBookRequest) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodPut, path: "/notebooks/" + url.PathEscape(id), contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// DeleteNoteBook - DELETE /notebooks/{id}. Удалить блокнот
+func (c *Client) DeleteNoteBook(ctx context.Context, id string, mode string, targetID string) (int, error) {
+	query := url.Values{}
+	if mode != "" {
+		query.Set("mode", mode)
+	}
+	if targetID != "" {
+		query.Set("target_id", targetID)
+	}
+
+	var out int
+	err := c.call(ctx, request{method: http.MethodDelete, path: "/notebooks/" + url.PathEscape(id), query: query}, &out)
+	return out, err
+}
+
+// GetTags - GET /tags. Все теги
+func (c *Client) GetTags(ctx context.Context, withCounts bool) ([]TagUsage, error) {
+	query := url.Values{}
+	if withCounts {
+		query.Set("with_counts", "true")
+	}
+
+	var out []TagUsage
+	err := c.call(ctx, request{method: http.MethodGet, path: "/tags", query: query}, &out)
+	return out, err
+}
+
+// CreateTag - POST /tags. Создать тег
+func (c *Client) CreateTag(ctx context.Context, body TagRequest) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodPost, path: "/tags", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// GetTagByID - GET /tags/{id}. Тег по ID
+func (c *Client) GetTagByID(ctx context.Context, id string) (Tag, error) {
+	var out Tag
+	err := c.call(ctx, request{method: http.MethodGet, path: "/tags/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// UpdateTag - PUT /tags/{id}. Изменить тег, переименование переносит потомков
+func (c *Client) UpdateTag(ctx context.Context, id string, body TagRequest) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodPut, path: "/tags/" + url.PathEscape(id), contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// DeleteTag - DELETE /tags/{id}. Удалить тег и снять его с заметок
+func (c *Client) DeleteTag(ctx context.Context, id string) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodDelete, path: "/tags/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// MergeTag - POST /tags/{id}/merge. Слить тег в target_id вместе с потомками
+func (c *Client) MergeTag(ctx context.Context, id string, body TagMergeRequest) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodPost, path: "/tags/" + url.PathEscape(id) + "/merge", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// RegisterUser - POST /users/register. Регистрация
+func (c *Client) RegisterUser(ctx context.Context, body RegisterRequest) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodPost, path: "/users/register", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// LoginUser - POST /users/login. Вход, ставит cookie auth_token
+func (c *Client) LoginUser(ctx context.Context, body LoginRequest) (User, error) {
+	var out User
+	err := c.call(ctx, request{method: http.MethodPost, path: "/users/login", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// GetProfile - GET /users/profile. Текущий пользователь
+func (c *Client) GetProfile(ctx context.Context) (User, error) {
+	var out User
+	err := c.call(ctx, request{method: http.MethodGet, path: "/users/profile"}, &out)
+	return out, err
+}
+
+// DeleteProfile - DELETE /users/profile. Удалить пользователя, его заметки, напоминания, шаблоны и вебхуки
+func (c *Client) DeleteProfile(ctx context.Context) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodDelete, path: "/users/profile"}, &out)
+	return out, err
+}
+
+// GetTemplates - GET /templates. Шаблоны пользователя
+func (c *Client) GetTemplates(ctx context.Context) ([]Template, error) {
+	var out []Template
+	err := c.call(ctx, request{method: http.MethodGet, path: "/templates"}, &out)
+	return out, err
+}
+
+// CreateTemplate - POST /templates. Создать шаблон
+func (c *Client) CreateTemplate(ctx context.Context, body TemplateRequest) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodPost, path: "/templates", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// GetTemplateByID - GET /templates/{id}. Шаблон по ID
+func (c *Client) GetTemplateByID(ctx context.Context, id string) (Template, error) {
+	var out Template
+	err := c.call(ctx, request{method: http.MethodGet, path: "/templates/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// UpdateTemplate - PUT /templates/{id}. Изменить шаблон
+func (c *Client) UpdateTemplate(ctx context.Context, id string, body TemplateRequest) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodPut, path: "/templates/" + url.PathEscape(id), contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// DeleteTemplate - DELETE /templates/{id}. Удалить шаблон
+func (c *Client) DeleteTemplate(ctx context.Context, id string) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodDelete, path: "/templates/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// GetWebhooks - GET /webhooks. Вебхуки пользователя
+func (c *Client) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	var out []Webhook
+	err := c.call(ctx, request{method: http.MethodGet, path: "/webhooks"}, &out)
+	return out, err
+}
+
+// CreateWebhook - POST /webhooks. Подписать адрес на события заметок
+func (c *Client) CreateWebhook(ctx context.Context, body WebhookRequest) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: http.MethodPost, path: "/webhooks", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// GetWebhookByID - GET /webhooks/{id}. Вебхук по ID
+func (c *Client) GetWebhookByID(ctx context.Context, id string) (Webhook, error) {
+	var out Webhook
+	err := c.call(ctx, request{method: http.MethodGet, path: "/webhooks/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// UpdateWebhook - PUT /webhooks/{id}. Изменить вебхук
+func (c *Client) UpdateWebhook(ctx context.Context, id string, body WebhookRequest) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodPut, path: "/webhooks/" + url.PathEscape(id), contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// DeleteWebhook - DELETE /webhooks/{id}. Удалить вебхук вместе с журналом доставок
+func (c *Client) DeleteWebhook(ctx context.Context, id string) (int, error) {
+	var out int
+	err := c.call(ctx, request{method: http.MethodDelete, path: "/webhooks/" + url.PathEscape(id)}, &out)
+	return out, err
+}
+
+// GetWebhookDeliveries - GET /webhooks/{id}/deliveries. Журнал доставок, новые попытки первыми
+func (c *Client) GetWebhookDeliveries(ctx context.Context, id string) ([]WebhookDelivery, error) {
+	var out []WebhookDelivery
+	err := c.call(ctx, request{method: http.MethodGet, path: "/webhooks/" + url.PathEscape(id) + "/deliveries"}, &out)
+	return out, err
+}
+
+// TestWebhook - POST /webhooks/{id}/test. Отправить пробное событие ping
+func (c *Client) TestWebhook(ctx context.Context, id string) (WebhookDelivery, error) {
+	var out WebhookDelivery
+	err := c.call(ctx, request{method: http.MethodPost, path: "/webhooks/" + url.PathEscape(id) + "/test"}, &out)
+	return out, err
+}
+
+// GetEvents - GET /events. Поток изменений заметок, блокнотов и тегов (server-sent events)
+func (c *Client) GetEvents(ctx context.Context) (io.ReadCloser, error) {
+	return c.stream(ctx, request{method: http.MethodGet, path: "/events"})
+}
+
+// Export - GET /export. Выгрузить все заметки в ZIP с Markdown
+func (c *Client) Export(ctx context.Context) (io.ReadCloser, error) {
+	return c.stream(ctx, request{method: http.MethodGet, path: "/export"})
+}
+
+// Import - POST /import. Импорт из Markdown ZIP, Evernote ENEX или Google Keep
+func (c *Client) Import(ctx context.Context, format string, notebook string, body io.Reader) (ImportResult, error) {
+	query := url.Values{}
+	if format != "" {
+		query.Set("format", format)
+	}
+	if notebook != "" {
+		query.Set("notebook", notebook)
+	}
+
+	var out ImportResult
+	err := c.call(ctx, request{method: http.MethodPost, path: "/import", query: query, contentType: "application/octet-stream", body: body}, &out)
+	return out, err
+}
+
+// GetSync - GET /sync. Изменения после последней синхронизации
+func (c *Client) GetSync(ctx context.Context, token string) (SyncPull, error) {
+	query := url.Values{}
+	if token != "" {
+		query.Set("token", token)
+	}
+
+	var out SyncPull
+	err := c.call(ctx, request{method: http.MethodGet, path: "/sync", query: query}, &out)
+	return out, err
+}
+
+// PushSync - POST /sync. Отправить локальные изменения
+func (c *Client) PushSync(ctx context.Context, body SyncPushRequest) ([]SyncResult, error) {
+	var out []SyncResult
+	err := c.call(ctx, request{method: http.MethodPost, path: "/sync", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// GraphQL - POST /graphql. Выполнить GraphQL-запрос
+func (c *Client) GraphQL(ctx context.Context, body GraphQLRequest) (json.RawMessage, error) {
+	var out json.RawMessage
+	err := c.callRaw(ctx, request{method: http.MethodPost, path: "/graphql", contentType: "application/json", body: body}, &out)
+	return out, err
+}
+
+// GetGraphQLSchema - GET /graphql/schema. GraphQL-схема в SDL
+func (c *Client) GetGraphQLSchema(ctx context.Context) (string, error) {
+	return c.callText(ctx, request{method: http.MethodGet, path: "/graphql/schema"})
+}
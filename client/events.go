@@ -0,0 +1,42 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// ReadEvents разбирает поток из GetEvents и вызывает fn для каждого события, пока поток не закончится
+// или fn не вернет ошибку. Комментарии-пинги пропускаются
+func ReadEvents(stream io.Reader, fn func(Event) error) error {
+	scanner := bufio.NewScanner(stream)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+
+			var e Event
+			err := json.Unmarshal([]byte(data.String()), &e)
+			if err != nil {
+				return err
+			}
+			data.Reset()
+
+			err = fn(e)
+			if err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+
+	return scanner.Err()
+}
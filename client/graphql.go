@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// GraphQLErrors - ошибки из ответа GraphQL. Query возвращает их вместе с частичным data
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Message)
+	}
+
+	return "graphql: " + strings.Join(messages, "; ")
+}
+
+// Code - код ошибки из extensions, как Code в ErrorResponse. Пустой у ошибок разбора и проверки запроса
+func (e GraphQLError) Code() string {
+	var extensions struct {
+		Code string `json:"code"`
+	}
+	json.Unmarshal(e.Extensions, &extensions)
+
+	return extensions.Code
+}
+
+// Query выполняет GraphQL-запрос и раскладывает data в out. Если в ответе есть errors, возвращается
+// GraphQLErrors, а out все равно заполняется полями, которые удалось вычислить
+func (c *Client) Query(ctx context.Context, query string, variables map[string]any, out any) error {
+	req := GraphQLRequest{Query: query}
+
+	if variables != nil {
+		vars, err := json.Marshal(variables)
+		if err != nil {
+			return err
+		}
+		req.Variables = vars
+	}
+
+	raw, err := c.GraphQL(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	var res GraphQLResponse
+	err = json.Unmarshal(raw, &res)
+	if err != nil {
+		return err
+	}
+
+	if out != nil && len(res.Data) > 0 && string(res.Data) != "null" {
+		err = json.Unmarshal(res.Data, out)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(res.Errors) > 0 {
+		return GraphQLErrors(res.Errors)
+	}
+
+	return nil
+}
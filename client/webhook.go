@@ -0,0 +1,30 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// SignatureHeader - заголовок с подписью тела запроса вебхука
+const SignatureHeader = "X-NoteVault-Signature"
+
+// VerifySignature проверяет заголовок X-NoteVault-Signature у запроса, пришедшего на адрес вебхука.
+// body - тело запроса без изменений, secret - секрет вебхука
+func VerifySignature(secret string, body []byte, signature string) bool {
+	sum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+
+	want, err := hex.DecodeString(sum)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hmac.Equal(mac.Sum(nil), want)
+}
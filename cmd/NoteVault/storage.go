@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/app"
+	"github.com/LoL-KeKovich/NoteVault/internal/config"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository/mongodb"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository/sqlite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// setupStorage подключает хранилище из конфига и возвращает его репозитории и функцию закрытия
+func setupStorage(cfg *config.Config, log *slog.Logger) (app.Repos, func()) {
+	switch cfg.Storage {
+	case config.StorageMongoDB:
+		return setupMongo(cfg, log)
+	case config.StorageSQLite:
+		return setupSQLite(cfg, log)
+	default:
+		log.Error("Unknown storage", slog.String("storage", cfg.Storage))
+		os.Exit(1)
+		return app.Repos{}, nil
+	}
+}
+
+func setupSQLite(cfg *config.Config, log *slog.Logger) (app.Repos, func()) {
+	db, err := sqlite.Open(cfg.StoragePath)
+	if err != nil {
+		log.Error("Failed to open sqlite database", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	log.Info("Successfully opened sqlite database!", slog.String("path", cfg.StoragePath))
+
+	client := sqlite.SQLiteClient{DB: db, Timeout: cfg.QueryTimeout}
+
+	repos := app.Repos{
+		Notes:      client,
+		NoteBooks:  client,
+		Tags:       client,
+		Users:      client,
+		Templates:  client,
+		Webhooks:   client,
+		Sync:       client,
+		Reminders:  client,
+		UnitOfWork: sqlite.SQLiteUnitOfWork{DB: db, Timeout: cfg.QueryTimeout},
+	}
+
+	return repos, func() { db.Close() }
+}
+
+func setupMongo(cfg *config.Config, log *slog.Logger) (app.Repos, func()) {
+	mongoClient, ctx := mongoConnect(cfg, log)
+
+	noteCollection := mongoClient.Database(cfg.Database).Collection(cfg.Collections.Notes)
+	noteBookCollection := mongoClient.Database(cfg.Database).Collection(cfg.Collections.NoteBooks)
+	tagCollection := mongoClient.Database(cfg.Database).Collection(cfg.Collections.Tags)
+	userCollection := mongoClient.Database(cfg.Database).Collection(cfg.Collections.Users)
+	templateCollection := mongoClient.Database(cfg.Database).Collection(cfg.Collections.Templates)
+	webhookCollection := mongoClient.Database(cfg.Database).Collection(cfg.Collections.Webhooks)
+	syncCollection := mongoClient.Database(cfg.Database).Collection(cfg.Collections.Sync)
+	reminderCollection := mongoClient.Database(cfg.Database).Collection(cfg.Collections.Reminders)
+
+	indexEmail := mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := userCollection.Indexes().CreateOne(context.Background(), indexEmail)
+	if err != nil {
+		log.Error("Failed to create unique index for email", slog.String("error", err.Error()))
+	}
+
+	indexName := mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err = tagCollection.Indexes().CreateOne(context.Background(), indexName)
+	if err != nil {
+		log.Error("Failed to create unique index for tag name", slog.String("error", err.Error()))
+	}
+
+	_, err = noteBookCollection.Indexes().CreateOne(context.Background(), indexName)
+	if err != nil {
+		log.Error("Failed to create unique index for notebook name", slog.String("error", err.Error()))
+	}
+
+	indexJournal := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "journal_date", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.D{{Key: "journal_date", Value: bson.D{{Key: "$exists", Value: true}}}}),
+	}
+
+	_, err = noteCollection.Indexes().CreateOne(context.Background(), indexJournal)
+	if err != nil {
+		log.Error("Failed to create unique index for journal notes", slog.String("error", err.Error()))
+	}
+
+	indexWebhookUser := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}},
+	}
+
+	_, err = webhookCollection.Indexes().CreateOne(context.Background(), indexWebhookUser)
+	if err != nil {
+		log.Error("Failed to create index for webhook owner", slog.String("error", err.Error()))
+	}
+
+	indexSync := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "seq", Value: 1}},
+	}
+
+	_, err = syncCollection.Indexes().CreateOne(context.Background(), indexSync)
+	if err != nil {
+		log.Error("Failed to create index for sync changes", slog.String("error", err.Error()))
+	}
+
+	indexReminderNote := mongo.IndexModel{
+		Keys: bson.D{{Key: "note_id", Value: 1}},
+	}
+
+	_, err = reminderCollection.Indexes().CreateOne(context.Background(), indexReminderNote)
+	if err != nil {
+		log.Error("Failed to create index for note reminders", slog.String("error", err.Error()))
+	}
+
+	migrated, err := mongodb.MigrateNoteTags(
+		context.Background(),
+		mongodb.MongoClient{Client: *noteCollection},
+		mongodb.MongoClient{Client: *tagCollection},
+	)
+	if err != nil {
+		log.Error("Failed to migrate note tags", slog.String("error", err.Error()))
+	} else if migrated > 0 {
+		log.Info("Migrated note tags to ids", slog.Int("notes", migrated))
+	}
+
+	ranked, err := mongodb.MigrateNoteRanks(context.Background(), mongodb.MongoClient{Client: *noteCollection})
+	if err != nil {
+		log.Error("Failed to migrate note ranks", slog.String("error", err.Error()))
+	} else if ranked > 0 {
+		log.Info("Assigned ranks to notes", slog.Int("notes", ranked))
+	}
+
+	repos := app.Repos{
+		Notes:     mongodb.MongoClient{Client: *noteCollection, Timeout: cfg.QueryTimeout},
+		NoteBooks: mongodb.MongoClient{Client: *noteBookCollection, Timeout: cfg.QueryTimeout},
+		Tags:      mongodb.MongoClient{Client: *tagCollection, Timeout: cfg.QueryTimeout},
+		Users:     mongodb.MongoClient{Client: *userCollection, Timeout: cfg.QueryTimeout},
+		Templates: mongodb.MongoClient{Client: *templateCollection, Timeout: cfg.QueryTimeout},
+		Webhooks:  mongodb.MongoClient{Client: *webhookCollection, Timeout: cfg.QueryTimeout},
+		Sync:      mongodb.MongoClient{Client: *syncCollection, Timeout: cfg.QueryTimeout},
+		Reminders: mongodb.MongoClient{Client: *reminderCollection, Timeout: cfg.QueryTimeout},
+		UnitOfWork: mongodb.MongoUnitOfWork{
+			Client:    mongoClient,
+			Timeout:   cfg.QueryTimeout,
+			Notes:     *noteCollection,
+			NoteBooks: *noteBookCollection,
+			Tags:      *tagCollection,
+			Users:     *userCollection,
+			Templates: *templateCollection,
+			Webhooks:  *webhookCollection,
+			Reminders: *reminderCollection,
+		},
+	}
+
+	return repos, func() { mongoClient.Disconnect(ctx) }
+}
+
+func mongoConnect(cfg *config.Config, log *slog.Logger) (*mongo.Client, context.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	clientOptions := options.Client().ApplyURI(cfg.StoragePath)
+
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		panic(err)
+	}
+
+	err = client.Ping(ctx, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	log.Info("Successfully connected to mongo!")
+
+	return client, ctx
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/config"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const usage = `Usage:
+  backup dump -o <file>     dump all collections to a JSON-lines archive
+  backup restore -i <file>  restore an archive into an empty database
+
+Files ending with .gz are compressed. The config is read from CONFIG_PATH.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+
+	var err error
+	switch os.Args[1] {
+	case "dump":
+		err = dump(cfg, os.Args[2:])
+	case "restore":
+		err = restore(cfg, os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		slog.Error("Backup command failed", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
+
+func dump(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("dump", flag.ExitOnError)
+	output := flags.String("o", "", "archive file")
+	flags.Parse(args)
+
+	if *output == "" {
+		return fmt.Errorf("no output file")
+	}
+
+	client, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(context.Background())
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var w io.Writer = file
+	var gz *gzip.Writer
+	if strings.HasSuffix(*output, ".gz") {
+		gz = gzip.NewWriter(file)
+		w = gz
+	}
+
+	counts, err := mongodb.DumpDatabase(context.Background(), client.Database(cfg.Database), cfg.Collections.Names(), w)
+	if err != nil {
+		os.Remove(*output)
+		return err
+	}
+
+	if gz != nil {
+		err = gz.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	for name, count := range counts {
+		slog.Info("Collection dumped", slog.String("collection", name), slog.Int("documents", count))
+	}
+
+	return file.Close()
+}
+
+func restore(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	input := flags.String("i", "", "archive file")
+	flags.Parse(args)
+
+	if *input == "" {
+		return fmt.Errorf("no input file")
+	}
+
+	client, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(context.Background())
+
+	open := func() (io.ReadCloser, error) {
+		file, err := os.Open(*input)
+		if err != nil {
+			return nil, err
+		}
+
+		if !strings.HasSuffix(*input, ".gz") {
+			return file, nil
+		}
+
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		return gzipFile{Reader: gz, file: file}, nil
+	}
+
+	counts, err := mongodb.RestoreDatabase(context.Background(), client.Database(cfg.Database), cfg.Collections.Names(), open)
+	if err != nil {
+		return err
+	}
+
+	for name, count := range counts {
+		slog.Info("Collection restored", slog.String("collection", name), slog.Int("documents", count))
+	}
+
+	return nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g gzipFile) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
+
+func connect(cfg *config.Config) (*mongo.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.StoragePath))
+	if err != nil {
+		return nil, err
+	}
+
+	return client, client.Ping(ctx, nil)
+}
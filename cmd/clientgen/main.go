@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/clientgen"
+)
+
+// clientgen генерирует пакет client по спецификации OpenAPI, запускается через go generate ./client
+func main() {
+	specPath := flag.String("spec", "api/openapi.json", "path to the OpenAPI spec")
+	outPath := flag.String("out", "client/client_gen.go", "path to the generated file")
+	flag.Parse()
+
+	spec, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := clientgen.Generate(spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = os.WriteFile(*outPath, code, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,236 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/LoL-KeKovich/NoteVault/api"
+	"github.com/LoL-KeKovich/NoteVault/api/notevaultpb"
+	"github.com/LoL-KeKovich/NoteVault/internal/config"
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/internal/service"
+	"github.com/LoL-KeKovich/NoteVault/internal/webhook"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/cors"
+	"google.golang.org/grpc"
+)
+
+// Repos - реализации репозиториев выбранного хранилища
+type Repos struct {
+	Notes      repository.NoteRepo
+	NoteBooks  repository.NoteBookRepo
+	Tags       repository.TagRepo
+	Users      repository.UserRepo
+	Templates  repository.TemplateRepo
+	Webhooks   repository.WebhookRepo
+	Sync       repository.SyncRepo
+	Reminders  repository.ReminderRepo
+	UnitOfWork repository.UnitOfWork
+}
+
+// NewRouter собирает сервисы поверх репозиториев и регистрирует все маршруты API
+func NewRouter(cfg *config.Config, repos Repos) http.Handler {
+	router, _ := NewServers(cfg, repos)
+	return router
+}
+
+// NewServers собирает сервисы один раз для HTTP API и gRPC-сервера, чтобы изменения через gRPC
+// тоже попадали в поток событий, вебхуки и журнал синхронизации
+func NewServers(cfg *config.Config, repos Repos) (http.Handler, *grpc.Server) {
+	bus := events.NewBus()
+
+	noteService := service.NoteService{
+		DBClient:             repos.Notes,
+		HelperNoteBookClient: repos.NoteBooks,
+		HelperTagClient:      repos.Tags,
+		HelperTemplateClient: repos.Templates,
+		HelperUserClient:     repos.Users,
+		Journal:              cfg.Journal,
+		Events:               bus,
+	}
+
+	noteBookService := service.NoteBookService{
+		DBClient:         repos.NoteBooks,
+		HelperNoteClient: repos.Notes,
+		TxClient:         repos.UnitOfWork,
+		Events:           bus,
+	}
+
+	tagService := service.TagService{
+		DBClient:         repos.Tags,
+		HelperNoteClient: repos.Notes,
+		TxClient:         repos.UnitOfWork,
+		Events:           bus,
+	}
+
+	userService := service.UserService{
+		DBClient: repos.Users,
+		TxClient: repos.UnitOfWork,
+	}
+
+	templateService := service.TemplateService{
+		DBClient:             repos.Templates,
+		HelperNoteBookClient: repos.NoteBooks,
+		HelperTagClient:      repos.Tags,
+	}
+
+	exportService := service.ExportService{
+		DBClient:             repos.Notes,
+		HelperNoteBookClient: repos.NoteBooks,
+		HelperTagClient:      repos.Tags,
+	}
+
+	importService := service.ImportService{
+		DBClient:             repos.Notes,
+		HelperNoteBookClient: repos.NoteBooks,
+		HelperTagClient:      repos.Tags,
+		Events:               bus,
+	}
+
+	eventService := service.EventService{
+		Bus: bus,
+	}
+
+	dispatcher := webhook.Dispatcher{
+		Webhooks: repos.Webhooks,
+		Notes:    repos.Notes,
+		Client:   webhook.NewClient(cfg.Webhooks),
+		Config:   cfg.Webhooks,
+	}
+	bus.Listen(dispatcher.Handle)
+
+	syncService := service.SyncService{
+		DBClient:  repos.Sync,
+		Recorder:  &service.SyncRecorder{DBClient: repos.Sync},
+		Notes:     noteService,
+		NoteBooks: noteBookService,
+		Tags:      tagService,
+	}
+	bus.Listen(syncService.Recorder.Record)
+
+	graphQLService := service.GraphQLService{
+		Notes:     noteService,
+		NoteBooks: noteBookService,
+		Tags:      tagService,
+		Reminders: repos.Reminders,
+	}
+
+	grpcService := service.GRPCService{
+		Notes:     noteService,
+		NoteBooks: noteBookService,
+		Tags:      tagService,
+		Users:     userService,
+	}
+
+	webhookService := service.WebhookService{
+		DBClient:   repos.Webhooks,
+		Dispatcher: dispatcher,
+	}
+
+	router := chi.NewRouter()
+	router.Use(middleware.Logger)
+	router.Use(middleware.SetHeader("CONTENT-TYPE", "application/json"))
+	router.Use(cors.Handler(cors.Options{
+		AllowedOrigins:   []string{"http://localhost:3000"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		ExposedHeaders:   []string{"Link"},
+		AllowCredentials: true,
+		MaxAge:           300,
+	}))
+
+	router.Route("/api/v1", func(router chi.Router) {
+		router.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("NoteVault is OK!"))
+		})
+		router.Get("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(api.OpenAPI)
+		})
+
+		router.Get("/notes/{id}", noteService.HandleGetNoteByID)
+		router.Get("/notes", noteService.HandleGetNotes)
+		router.Get("/notes/trash", noteService.HandleGetTrashedNotes)
+		router.Get("/notes/archive", noteService.HandleGetArchivedNotes)
+		router.Get("/notes/favourites", noteService.HandleGetFavouriteNotes)
+		router.Get("/notes/trash/{id}", noteService.HandleRestoreNoteFromTrash)
+		router.Get("/notes/archive/{id}", noteService.HandleRestoreNoteFromArchive)
+		router.Get("/notes/group/{id}", noteService.HandleGetNotesByNoteBookID)
+		router.With(userService.OptionalAuthMiddleware).Post("/notes", noteService.HandleCreateNote)
+		router.Post("/notes/tag", noteService.HandleGetNotesByTags)
+		router.Post("/notes/bulk", noteService.HandleBulkNotes)
+		router.Put("/notes/{id}", noteService.HandleUpdateNote)
+		router.Patch("/notes/{id}", noteService.HandlePatchNote)
+		router.Put("/notes/notebook/{id}", noteService.HandleUpdateNoteNoteBook)
+		router.Put("/notes/order/{id}", noteService.HandleReorderNote)
+		router.Put("/notes/pin/{id}", noteService.HandlePinNote)
+		router.Put("/notes/favourite/{id}", noteService.HandleAddNoteToFavourites)
+		router.Put("/notes/tag/{id}", noteService.HandleAddTagToNote)
+		router.Patch("/notes/tag/{id}", noteService.HandleRemoveTagFromNote)
+		router.Delete("/notes/{id}", noteService.HandleDeleteNote)
+		router.Delete("/notes/trash/{id}", noteService.HandleMoveNoteToTrash)
+		router.Delete("/notes/archive/{id}", noteService.HandleMoveNoteToArchive)
+		router.Delete("/notes/notebook/{id}", noteService.HandleRemoveNoteBookFromNote)
+		router.Delete("/notes/pin/{id}", noteService.HandleUnpinNote)
+		router.Delete("/notes/favourite/{id}", noteService.HandleRemoveNoteFromFavourites)
+
+		router.Get("/notebooks/{id}", noteBookService.HandleGetNoteBookByID)
+		router.Get("/notebooks", noteBookService.HandleGetNoteBooks)
+		router.Post("/notebooks", noteBookService.HandleCreateNoteBook)
+		router.Put("/notebooks/{id}", noteBookService.HandleUpdateNoteBook)
+		router.Delete("/notebooks/{id}", noteBookService.HandleDeleteNoteBook)
+
+		router.Get("/tags/{id}", tagService.HandleGetTagByID)
+		router.Get("/tags", tagService.HandleGetTags)
+		router.Post("/tags", tagService.HandleCreateTag)
+		router.Post("/tags/{id}/merge", tagService.HandleMergeTag)
+		router.Put("/tags/{id}", tagService.HandleUpdateTag)
+		router.Delete("/tags/{id}", tagService.HandleDeleteTag)
+
+		router.Get("/graphql/schema", graphQLService.HandleGraphQLSchema)
+
+		router.Post("/users/register", userService.HandleRegisterUser)
+		router.Post("/users/login", userService.HandleLoginUser)
+
+		router.Group(func(router chi.Router) {
+			router.Use(userService.AuthMiddleware)
+			router.Get("/users/profile", userService.HandleGetProfile)
+			router.Delete("/users/profile", userService.HandleDeleteProfile)
+
+			router.Get("/notes/daily/{date}", noteService.HandleGetDailyNote)
+			router.Get("/notes/daily/calendar/{month}", noteService.HandleGetJournalCalendar)
+
+			router.Get("/events", eventService.HandleEvents)
+
+			router.Get("/sync", syncService.HandlePull)
+			router.Post("/sync", syncService.HandlePush)
+
+			router.Post("/graphql", graphQLService.HandleGraphQL)
+
+			router.Get("/export", exportService.HandleExport)
+			router.Post("/import", importService.HandleImport)
+
+			router.Get("/templates/{id}", templateService.HandleGetTemplateByID)
+			router.Get("/templates", templateService.HandleGetTemplates)
+			router.Post("/templates", templateService.HandleCreateTemplate)
+			router.Put("/templates/{id}", templateService.HandleUpdateTemplate)
+			router.Delete("/templates/{id}", templateService.HandleDeleteTemplate)
+
+			router.Get("/webhooks/{id}", webhookService.HandleGetWebhookByID)
+			router.Get("/webhooks/{id}/deliveries", webhookService.HandleGetWebhookDeliveries)
+			router.Get("/webhooks", webhookService.HandleGetWebhooks)
+			router.Post("/webhooks", webhookService.HandleCreateWebhook)
+			router.Post("/webhooks/{id}/test", webhookService.HandleTestWebhook)
+			router.Put("/webhooks/{id}", webhookService.HandleUpdateWebhook)
+			router.Delete("/webhooks/{id}", webhookService.HandleDeleteWebhook)
+		})
+	})
+
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcService.UnaryInterceptor),
+		grpc.StreamInterceptor(grpcService.StreamInterceptor),
+	)
+	notevaultpb.RegisterNoteVaultServer(grpcServer, grpcService)
+
+	return router, grpcServer
+}
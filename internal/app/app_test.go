@@ -0,0 +1,336 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/app"
+	"github.com/LoL-KeKovich/NoteVault/internal/config"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository/memory"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+)
+
+// covered собирает маршруты, вызванные тестами; TestMain сверяет их со всеми маршрутами роутера
+var covered = struct {
+	sync.Mutex
+	routes map[string]bool
+}{routes: map[string]bool{}}
+
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	middleware.DefaultLogger = middleware.RequestLogger(&middleware.DefaultLogFormatter{Logger: log.New(io.Discard, "", 0)})
+
+	code := m.Run()
+
+	// при запуске части тестов через -run проверять покрытие маршрутов бессмысленно
+	if code == 0 && flag.Lookup("test.run").Value.String() == "" {
+		if missing := uncoveredRoutes(); len(missing) > 0 {
+			fmt.Println("routes without tests:")
+			for _, route := range missing {
+				fmt.Println("  " + route)
+			}
+			code = 1
+		}
+	}
+
+	os.Exit(code)
+}
+
+func uncoveredRoutes() []string {
+	router := app.NewRouter(testConfig(), newRepos())
+
+	var missing []string
+
+	chi.Walk(router.(chi.Routes), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if !covered.routes[method+" "+route] {
+			missing = append(missing, method+" "+route)
+		}
+		return nil
+	})
+
+	sort.Strings(missing)
+
+	return missing
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Journal: config.Journal{
+			NoteBook: "Journal",
+			Template: "Journal",
+			Text:     "# {{date}}",
+		},
+		Webhooks: config.Webhooks{
+			Timeout:      2 * time.Second,
+			MaxAttempts:  3,
+			RetryDelay:   10 * time.Millisecond,
+			AllowPrivate: true,
+		},
+	}
+}
+
+func newRepos() app.Repos {
+	store := memory.NewStore()
+	client := memory.MemoryClient{Store: store}
+
+	return app.Repos{
+		Notes:      client,
+		NoteBooks:  client,
+		Tags:       client,
+		Users:      client,
+		Templates:  client,
+		Webhooks:   client,
+		Sync:       client,
+		Reminders:  client,
+		UnitOfWork: memory.MemoryUnitOfWork{Store: store},
+	}
+}
+
+type testAPI struct {
+	t      *testing.T
+	server *httptest.Server
+	client *http.Client
+}
+
+type envelope struct {
+	Data   json.RawMessage `json:"data"`
+	Error  string          `json:"error"`
+	Code   string          `json:"code"`
+	Fields []struct {
+		Field string `json:"field"`
+		Rule  string `json:"rule"`
+	} `json:"fields"`
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+
+	return newTestAPIWith(t, newRepos())
+}
+
+// newTestAPIWith поднимает API поверх переданных репозиториев, например с подсчетом запросов к базе
+func newTestAPIWith(t *testing.T, repos app.Repos) *testAPI {
+	t.Helper()
+
+	return newTestAPIConfig(t, testConfig(), repos)
+}
+
+func newTestAPIConfig(t *testing.T, cfg *config.Config, repos app.Repos) *testAPI {
+	t.Helper()
+
+	router := app.NewRouter(cfg, repos)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rctx := chi.NewRouteContext()
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		router.ServeHTTP(w, r)
+
+		covered.Lock()
+		covered.routes[r.Method+" "+rctx.RoutePattern()] = true
+		covered.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	jar, _ := cookiejar.New(nil)
+
+	return &testAPI{t: t, server: server, client: &http.Client{Jar: jar}}
+}
+
+// raw выполняет запрос и возвращает статус и тело как есть. body - строка, []byte или значение для JSON
+func (api *testAPI) raw(method, path string, body any) (int, []byte) {
+	api.t.Helper()
+
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewBufferString(b)
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			api.t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, api.server.URL+"/api/v1"+path, reader)
+	if err != nil {
+		api.t.Fatal(err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		api.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		api.t.Fatal(err)
+	}
+
+	return resp.StatusCode, data
+}
+
+func (api *testAPI) do(method, path string, body any) (int, envelope) {
+	api.t.Helper()
+
+	status, data := api.raw(method, path, body)
+
+	var env envelope
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &env); err != nil {
+			api.t.Fatalf("%s %s: decoding %q: %v", method, path, data, err)
+		}
+	}
+
+	return status, env
+}
+
+// ok выполняет запрос, ожидает 200 и раскладывает data в out
+func (api *testAPI) ok(method, path string, body any, out any) {
+	api.t.Helper()
+
+	status, env := api.do(method, path, body)
+	if status != http.StatusOK {
+		api.t.Fatalf("%s %s: status %d, error %q", method, path, status, env.Error)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			api.t.Fatalf("%s %s: decoding data %s: %v", method, path, env.Data, err)
+		}
+	}
+}
+
+func (api *testAPI) expect(method, path string, body any, want int) envelope {
+	api.t.Helper()
+
+	status, env := api.do(method, path, body)
+	if status != want {
+		api.t.Fatalf("%s %s: status %d, want %d (error %q)", method, path, status, want, env.Error)
+	}
+
+	return env
+}
+
+// expectCode проверяет и статус, и машинный код ошибки
+func (api *testAPI) expectCode(method, path string, body any, want int, code string) envelope {
+	api.t.Helper()
+
+	env := api.expect(method, path, body, want)
+	if env.Code != code {
+		api.t.Fatalf("%s %s: code %q, want %q (error %q)", method, path, env.Code, code, env.Error)
+	}
+
+	return env
+}
+
+func (api *testAPI) create(path string, body any) string {
+	api.t.Helper()
+
+	var id string
+	api.ok(http.MethodPost, path, body, &id)
+
+	return id
+}
+
+type noteView struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Text        string `json:"text"`
+	Color       string `json:"color"`
+	Order       int    `json:"order"`
+	Rank        string `json:"rank"`
+	IsDeleted   bool   `json:"is_deleted"`
+	IsArchived  bool   `json:"is_archived"`
+	IsPinned    bool   `json:"is_pinned"`
+	IsFavourite bool   `json:"is_favourite"`
+	NoteBookID  string `json:"notebook_id"`
+	JournalDate string `json:"journal_date"`
+	Tags        []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+func (api *testAPI) notes(path string) []noteView {
+	api.t.Helper()
+
+	var notes []noteView
+	api.ok(http.MethodGet, path, nil, &notes)
+
+	return notes
+}
+
+func (api *testAPI) note(id string) noteView {
+	api.t.Helper()
+
+	var note noteView
+	api.ok(http.MethodGet, "/notes/"+id, nil, &note)
+
+	return note
+}
+
+func names(notes []noteView) []string {
+	result := []string{}
+	for _, note := range notes {
+		result = append(result, note.Name)
+	}
+
+	return result
+}
+
+func assertNames(t *testing.T, what string, notes []noteView, want ...string) {
+	t.Helper()
+
+	got := names(notes)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+}
+
+func (api *testAPI) login(email string) string {
+	api.t.Helper()
+
+	id := api.create("/users/register", map[string]string{
+		"email":      email,
+		"password":   "secret-password",
+		"first_name": "Test",
+		"last_name":  "User",
+	})
+	api.ok(http.MethodPost, "/users/login", map[string]string{"email": email, "password": "secret-password"}, nil)
+
+	return id
+}
+
+func (api *testAPI) logout() {
+	api.client.Jar, _ = cookiejar.New(nil)
+}
+
+func TestHealth(t *testing.T) {
+	api := newTestAPI(t)
+
+	status, body := api.raw(http.MethodGet, "/health", nil)
+	if status != http.StatusOK || string(body) != "NoteVault is OK!" {
+		t.Fatalf("health: %d %q", status, body)
+	}
+}
@@ -0,0 +1,163 @@
+package app_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/client"
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+)
+
+// subscribe открывает поток событий клиента и пересылает события в канал
+func subscribe(t *testing.T, ctx context.Context, c *client.Client) <-chan client.Event {
+	t.Helper()
+
+	stream, err := c.GetEvents(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan client.Event, 16)
+	go func() {
+		defer stream.Close()
+		client.ReadEvents(stream, func(e client.Event) error {
+			ch <- e
+			return nil
+		})
+	}()
+
+	return ch
+}
+
+func expectEvent(t *testing.T, who string, ch <-chan client.Event, kind, action, id string) {
+	t.Helper()
+
+	select {
+	case e := <-ch:
+		if e.Kind != kind || e.Action != action || e.ID != id {
+			t.Fatalf("%s: event %+v, want %s.%s %s", who, e, kind, action, id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s: no %s.%s event", who, kind, action)
+	}
+}
+
+func loginClient(t *testing.T, api *testAPI, email string) *client.Client {
+	t.Helper()
+
+	c := client.New(api.server.URL+"/api/v1", nil)
+	ctx := context.Background()
+
+	_, err := c.RegisterUser(ctx, client.RegisterRequest{Email: email, Password: "secret-password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.LoginUser(ctx, client.LoginRequest{Email: email, Password: "secret-password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestEvents(t *testing.T) {
+	api := newTestAPI(t)
+
+	api.expectCode(http.MethodGet, "/events", nil, http.StatusUnauthorized, dto.ErrorCodeUnauthorized)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	alice := loginClient(t, api, "alice@example.com")
+	bob := loginClient(t, api, "bob@example.com")
+
+	aliceEvents := subscribe(t, ctx, alice)
+	bobEvents := subscribe(t, ctx, bob)
+
+	//Заметка Алисы не доходит до Боба, а тег общий и приходит обоим
+	noteID, err := alice.CreateNote(ctx, client.NoteRequest{Name: "Private"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, "alice", aliceEvents, "note", "created", noteID)
+
+	tagID, err := alice.CreateTag(ctx, client.TagRequest{Name: "shared"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, "alice", aliceEvents, "tag", "created", tagID)
+	expectEvent(t, "bob", bobEvents, "tag", "created", tagID)
+
+	api.ok(http.MethodPatch, "/notes/"+noteID, map[string]any{"text": "updated"}, nil)
+	expectEvent(t, "alice", aliceEvents, "note", "updated", noteID)
+
+	api.ok(http.MethodDelete, "/notes/trash/"+noteID, nil, nil)
+	expectEvent(t, "alice", aliceEvents, "note", "trashed", noteID)
+
+	api.ok(http.MethodDelete, "/notes/"+noteID, nil, nil)
+	expectEvent(t, "alice", aliceEvents, "note", "deleted", noteID)
+
+	//Заметка без владельца видна всем, как и в GET /notes
+	anonID := api.create("/notes", map[string]any{"name": "Anonymous"})
+	expectEvent(t, "bob", bobEvents, "note", "created", anonID)
+	expectEvent(t, "alice", aliceEvents, "note", "created", anonID)
+
+	api.ok(http.MethodPost, "/notes/bulk", map[string]any{"action": "archive", "ids": []string{anonID}}, nil)
+	expectEvent(t, "bob", bobEvents, "note", "archived", anonID)
+
+	noteBookID := api.create("/notebooks", map[string]any{"name": "Work"})
+	expectEvent(t, "bob", bobEvents, "notebook", "created", noteBookID)
+	api.ok(http.MethodDelete, "/notebooks/"+noteBookID, nil, nil)
+	expectEvent(t, "bob", bobEvents, "notebook", "deleted", noteBookID)
+}
+
+// countingNotes считает чтения заметок по одной и пачкой
+type countingNotes struct {
+	repository.NoteRepo
+	byID  *atomic.Int64
+	byIDs *atomic.Int64
+}
+
+func (c countingNotes) GetNoteByID(ctx context.Context, id string) (model.Note, error) {
+	c.byID.Add(1)
+	return c.NoteRepo.GetNoteByID(ctx, id)
+}
+
+func (c countingNotes) GetNotesByIDs(ctx context.Context, ids []string) ([]model.Note, error) {
+	c.byIDs.Add(1)
+	return c.NoteRepo.GetNotesByIDs(ctx, ids)
+}
+
+// TestBulkEventOwners - владельцы заметок для событий массовой операции читаются одним запросом
+func TestBulkEventOwners(t *testing.T) {
+	var byID, byIDs atomic.Int64
+	repos := newRepos()
+	repos.Notes = countingNotes{NoteRepo: repos.Notes, byID: &byID, byIDs: &byIDs}
+
+	api := newTestAPIWith(t, repos)
+	api.login("alice@example.com")
+
+	ids := []string{"not-an-id"}
+	for range 50 {
+		ids = append(ids, api.create("/notes", map[string]any{"name": "Note"}))
+	}
+
+	byID.Store(0)
+	byIDs.Store(0)
+
+	var res struct {
+		Succeeded int `json:"succeeded"`
+	}
+	api.ok(http.MethodPost, "/notes/bulk", map[string]any{"action": "archive", "ids": ids}, &res)
+	if res.Succeeded != 50 {
+		t.Fatalf("bulk archive: %+v", res)
+	}
+	if byID.Load() != 0 || byIDs.Load() != 1 {
+		t.Fatalf("note lookups: %d by id, %d by ids", byID.Load(), byIDs.Load())
+	}
+}
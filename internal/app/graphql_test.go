@@ -0,0 +1,328 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LoL-KeKovich/NoteVault/client"
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+)
+
+// countingTags и countingReminders считают запросы, которые должны выполняться одним вызовом на весь ответ
+type countingTags struct {
+	repository.TagRepo
+	calls *atomic.Int64
+}
+
+func (c countingTags) GetTagsByIDs(ctx context.Context, ids []string) ([]model.Tag, error) {
+	c.calls.Add(1)
+	return c.TagRepo.GetTagsByIDs(ctx, ids)
+}
+
+type countingReminders struct {
+	repository.ReminderRepo
+	calls *atomic.Int64
+}
+
+func (c countingReminders) GetRemindersByNotes(ctx context.Context, ids []string) ([]model.Reminder, error) {
+	c.calls.Add(1)
+	return c.ReminderRepo.GetRemindersByNotes(ctx, ids)
+}
+
+func gqlQuery(t *testing.T, c *client.Client, query string, variables map[string]any, out any) {
+	t.Helper()
+
+	err := c.Query(context.Background(), query, variables, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// expectGraphQLError проверяет код первой ошибки из errors
+func expectGraphQLError(t *testing.T, err error, code string) client.GraphQLError {
+	t.Helper()
+
+	var gqlErrs client.GraphQLErrors
+	if !errors.As(err, &gqlErrs) || len(gqlErrs) == 0 {
+		t.Fatalf("error %v, want graphql errors", err)
+	}
+	if gqlErrs[0].Code() != code {
+		t.Fatalf("error %v: code %q, want %q", err, gqlErrs[0].Code(), code)
+	}
+
+	return gqlErrs[0]
+}
+
+type gqlNote struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Text       string `json:"text"`
+	IsArchived bool   `json:"isArchived"`
+	Notebook   *struct {
+		Name string `json:"name"`
+	} `json:"notebook"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	Reminders []struct {
+		Name string `json:"name"`
+	} `json:"reminders"`
+}
+
+func TestGraphQL(t *testing.T) {
+	var tagCalls, reminderCalls atomic.Int64
+
+	repos := newRepos()
+	repos.Tags = countingTags{TagRepo: repos.Tags, calls: &tagCalls}
+	repos.Reminders = countingReminders{ReminderRepo: repos.Reminders, calls: &reminderCalls}
+
+	api := newTestAPIWith(t, repos)
+
+	status, body := api.raw(http.MethodGet, "/graphql/schema", nil)
+	if status != http.StatusOK || !strings.Contains(string(body), "type Query {") || !strings.Contains(string(body), "createNote(input: NoteInput!): Note!") {
+		t.Fatalf("schema: %d %s", status, body)
+	}
+
+	api.expectCode(http.MethodPost, "/graphql", map[string]string{"query": "{ me { id } }"}, http.StatusUnauthorized, dto.ErrorCodeUnauthorized)
+
+	alice := loginClient(t, api, "alice@example.com")
+	bob := loginClient(t, api, "bob@example.com")
+
+	var me struct {
+		Me struct {
+			Email string `json:"email"`
+		} `json:"me"`
+	}
+	gqlQuery(t, alice, `{ me { email } }`, nil, &me)
+	if me.Me.Email != "alice@example.com" {
+		t.Fatalf("me: %+v", me)
+	}
+
+	var created struct {
+		Notebook struct {
+			ID string `json:"id"`
+		} `json:"createNotebook"`
+		Work struct {
+			ID string `json:"id"`
+		} `json:"work"`
+		Home struct {
+			ID string `json:"id"`
+		} `json:"home"`
+	}
+	gqlQuery(t, alice, `mutation {
+		createNotebook(input: {name: "Projects"}) { id }
+		work: createTag(input: {name: "work"}) { id }
+		home: createTag(input: {name: "home"}) { id }
+	}`, nil, &created)
+
+	const createNote = `mutation Create($input: NoteInput!) { createNote(input: $input) { id } }`
+
+	var noteIDs []string
+	for _, input := range []map[string]any{
+		{"name": "First", "notebookId": created.Notebook.ID, "tagIds": []string{created.Work.ID}},
+		{"name": "Second", "tagIds": []string{created.Work.ID, created.Home.ID}},
+		{"name": "Third", "tagIds": []string{created.Home.ID}},
+	} {
+		var res struct {
+			CreateNote struct {
+				ID string `json:"id"`
+			} `json:"createNote"`
+		}
+		gqlQuery(t, alice, createNote, map[string]any{"input": input}, &res)
+		noteIDs = append(noteIDs, res.CreateNote.ID)
+	}
+
+	gqlQuery(t, bob, createNote, map[string]any{"input": map[string]any{"name": "Bob's"}}, nil)
+
+	var reminder struct {
+		CreateReminder struct {
+			ID       string `json:"id"`
+			RemindAt string `json:"remindAt"`
+			Repeat   string `json:"repeat"`
+			Note     struct {
+				Name string `json:"name"`
+			} `json:"note"`
+		} `json:"createReminder"`
+	}
+	gqlQuery(t, alice, `mutation($note: ID!) {
+		createReminder(input: {noteId: $note, name: "Call", remindAt: "2030-01-02T10:00:00+03:00", repeat: WEEKLY}) {
+			id remindAt repeat note { name }
+		}
+	}`, map[string]any{"note": noteIDs[0]}, &reminder)
+	if r := reminder.CreateReminder; r.RemindAt != "2030-01-02T07:00:00Z" || r.Repeat != "WEEKLY" || r.Note.Name != "First" {
+		t.Fatalf("created reminder: %+v", r)
+	}
+
+	tagCalls.Store(0)
+	reminderCalls.Store(0)
+
+	var list struct {
+		Notes []gqlNote `json:"notes"`
+		Same  []struct {
+			Title string `json:"title"`
+		} `json:"same"`
+	}
+	gqlQuery(t, alice, `
+		query List($status: NoteStatus) {
+			notes(status: $status) { ...full }
+			same: notes { title: name }
+		}
+		fragment full on Note { id name notebook { name } tags { name } reminders { name } }
+	`, map[string]any{"status": "ACTIVE"}, &list)
+
+	if len(list.Notes) != 3 || len(list.Same) != 3 || list.Same[0].Title != "First" {
+		t.Fatalf("notes: %+v", list)
+	}
+	first := list.Notes[0]
+	if first.Name != "First" || first.Notebook == nil || first.Notebook.Name != "Projects" || len(first.Tags) != 1 || first.Tags[0].Name != "work" || len(first.Reminders) != 1 {
+		t.Fatalf("first note: %+v", first)
+	}
+	if second := list.Notes[1]; second.Notebook != nil || len(second.Tags) != 2 || len(second.Reminders) != 0 {
+		t.Fatalf("second note: %+v", second)
+	}
+	if tagCalls.Load() != 1 || reminderCalls.Load() != 1 {
+		t.Fatalf("batching: %d tag queries, %d reminder queries, want 1 and 1", tagCalls.Load(), reminderCalls.Load())
+	}
+
+	var byTag struct {
+		Tags []struct {
+			Name  string `json:"name"`
+			Notes []struct {
+				Name string `json:"name"`
+			} `json:"notes"`
+		} `json:"tags"`
+		Notebook struct {
+			Notes []struct {
+				Name string `json:"name"`
+			} `json:"notes"`
+		} `json:"notebook"`
+	}
+	gqlQuery(t, alice, `query($id: ID!) { tags { name notes { name } } notebook(id: $id) { notes { name } } }`, map[string]any{"id": created.Notebook.ID}, &byTag)
+	if len(byTag.Tags) != 2 || len(byTag.Tags[0].Notes) != 2 || len(byTag.Tags[1].Notes) != 2 || len(byTag.Notebook.Notes) != 1 {
+		t.Fatalf("tags and notebook notes: %+v", byTag)
+	}
+
+	var filtered struct {
+		Notes []gqlNote `json:"notes"`
+	}
+	gqlQuery(t, alice, `{ notes(tagQuery: "work AND home") { name } }`, nil, &filtered)
+	if len(filtered.Notes) != 1 || filtered.Notes[0].Name != "Second" {
+		t.Fatalf("tag query: %+v", filtered)
+	}
+
+	var updated struct {
+		UpdateNote gqlNote `json:"updateNote"`
+	}
+	gqlQuery(t, alice, `mutation($id: ID!) { updateNote(id: $id, input: {text: "archived", isArchived: true}) { name text isArchived } }`, map[string]any{"id": noteIDs[2]}, &updated)
+	if u := updated.UpdateNote; u.Name != "Third" || u.Text != "archived" || !u.IsArchived {
+		t.Fatalf("updated note: %+v", u)
+	}
+
+	gqlQuery(t, alice, `{ notes(status: ARCHIVED) { name } }`, nil, &filtered)
+	if len(filtered.Notes) != 1 || filtered.Notes[0].Name != "Third" {
+		t.Fatalf("archived notes: %+v", filtered)
+	}
+
+	var active struct {
+		Reminders []struct {
+			ID string `json:"id"`
+		} `json:"reminders"`
+	}
+	gqlQuery(t, alice, `mutation($id: ID!) { updateReminder(id: $id, input: {isActive: false}) { id } }`, map[string]any{"id": reminder.CreateReminder.ID}, nil)
+	gqlQuery(t, alice, `{ reminders(active: true) { id } }`, nil, &active)
+	if len(active.Reminders) != 0 {
+		t.Fatalf("active reminders: %+v", active)
+	}
+	gqlQuery(t, alice, `mutation($id: ID!) { deleteReminder(id: $id) }`, map[string]any{"id": reminder.CreateReminder.ID}, nil)
+
+	gqlQuery(t, alice, `mutation($id: ID!) {
+		updateNotebook(id: $id, input: {description: "work stuff"}) { id }
+		deleteNotebook(id: $id)
+	}`, map[string]any{"id": created.Notebook.ID}, nil)
+	gqlQuery(t, alice, `mutation($id: ID!) {
+		updateTag(id: $id, input: {color: "#00ff00"}) { id }
+		deleteTag(id: $id)
+	}`, map[string]any{"id": created.Home.ID}, nil)
+
+	gqlQuery(t, alice, `mutation($id: ID!) { deleteNote(id: $id) }`, map[string]any{"id": noteIDs[0]}, nil)
+
+	var gone struct {
+		Note *gqlNote `json:"note"`
+		Me   struct {
+			Email string `json:"email"`
+		} `json:"me"`
+	}
+	err := alice.Query(context.Background(), `query($id: ID!) { me { email } note(id: $id) { id } }`, map[string]any{"id": noteIDs[0]}, &gone)
+	gqlErr := expectGraphQLError(t, err, dto.ErrorCodeNotFound)
+	if gone.Note != nil || gone.Me.Email != "alice@example.com" || len(gqlErr.Path) != 1 {
+		t.Fatalf("partial data: %+v, error %+v", gone, gqlErr)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	api := newTestAPI(t)
+	ctx := context.Background()
+
+	alice := loginClient(t, api, "alice@example.com")
+	bob := loginClient(t, api, "bob@example.com")
+
+	var data map[string]any
+
+	err := alice.Query(ctx, `{ me { id `, nil, &data)
+	expectGraphQLError(t, err, "")
+	if data != nil {
+		t.Fatalf("syntax error: data %v", data)
+	}
+
+	err = alice.Query(ctx, `{ me { id password } }`, nil, &data)
+	gqlErr := expectGraphQLError(t, err, "")
+	if !strings.Contains(gqlErr.Message, "password") || len(gqlErr.Locations) != 1 || data != nil {
+		t.Fatalf("unknown field: %+v, data %v", gqlErr, data)
+	}
+
+	err = alice.Query(ctx, `query($id: ID!) { note(id: $id) { id } }`, nil, &data)
+	expectGraphQLError(t, err, "")
+
+	err = alice.Query(ctx, `mutation { createNote(input: {name: "Bad", color: "blue"}) { id } }`, nil, &data)
+	expectGraphQLError(t, err, dto.ErrorCodeInvalidFields)
+
+	err = alice.Query(ctx, `{ note(id: "bad") { id } }`, nil, &data)
+	expectGraphQLError(t, err, dto.ErrorCodeInvalidID)
+
+	var res struct {
+		CreateNote struct {
+			ID string `json:"id"`
+		} `json:"createNote"`
+	}
+	gqlQuery(t, alice, `mutation { createNote(input: {name: "Private"}) { id } }`, nil, &res)
+
+	vars := map[string]any{"id": res.CreateNote.ID}
+
+	err = bob.Query(ctx, `query($id: ID!) { note(id: $id) { id } }`, vars, &data)
+	expectGraphQLError(t, err, dto.ErrorCodeNotFound)
+
+	err = bob.Query(ctx, `mutation($id: ID!) { updateNote(id: $id, input: {name: "Mine"}) { id } }`, vars, &data)
+	expectGraphQLError(t, err, dto.ErrorCodeNotFound)
+
+	var notes struct {
+		Notes []gqlNote `json:"notes"`
+	}
+	gqlQuery(t, bob, `{ notes { id } }`, nil, &notes)
+	if len(notes.Notes) != 0 {
+		t.Fatalf("bob sees notes: %+v", notes)
+	}
+
+	raw, err := alice.GraphQL(ctx, client.GraphQLRequest{
+		Query:         `query A { me { id } } query B { notes { name } }`,
+		OperationName: "B",
+	})
+	if err != nil || !strings.Contains(string(raw), "Private") || strings.Contains(string(raw), `"me"`) {
+		t.Fatalf("operation name: %s, %v", raw, err)
+	}
+}
@@ -0,0 +1,297 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/LoL-KeKovich/NoteVault/api/notevaultpb"
+	"github.com/LoL-KeKovich/NoteVault/internal/app"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newGRPCClient поднимает gRPC-сервер поверх repos в памяти процесса, без сетевого порта
+func newGRPCClient(t *testing.T, repos app.Repos) notevaultpb.NoteVaultClient {
+	t.Helper()
+
+	_, server := app.NewServers(testConfig(), repos)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///notevault",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return notevaultpb.NewNoteVaultClient(conn)
+}
+
+// grpcLogin входит через Login и возвращает контекст с токеном для остальных вызовов
+func grpcLogin(t *testing.T, c notevaultpb.NoteVaultClient, email string) context.Context {
+	t.Helper()
+
+	res, err := c.Login(context.Background(), &notevaultpb.LoginRequest{Email: email, Password: "secret-password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+res.GetToken())
+}
+
+// expectGRPCError проверяет код статуса и, если field не пустой, поле в google.rpc.BadRequest
+func expectGRPCError(t *testing.T, err error, code codes.Code, field string) {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != code {
+		t.Fatalf("error %v, want %s", err, code)
+	}
+
+	if field == "" {
+		return
+	}
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok && len(badRequest.FieldViolations) > 0 && badRequest.FieldViolations[0].Field == field {
+			return
+		}
+	}
+	t.Fatalf("error %v: details %v, want field %s", err, st.Details(), field)
+}
+
+func receiveAll[T any](t *testing.T, stream grpc.ServerStreamingClient[T], err error) []*T {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var items []*T
+	for {
+		item, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return items
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, item)
+	}
+}
+
+func grpcNoteNames(notes []*notevaultpb.Note) []string {
+	result := []string{}
+	for _, note := range notes {
+		result = append(result, note.GetName())
+	}
+
+	return result
+}
+
+func TestGRPC(t *testing.T) {
+	repos := newRepos()
+	api := newTestAPIWith(t, repos)
+	c := newGRPCClient(t, repos)
+
+	api.login("bob@example.com")
+	aliceID := api.login("alice@example.com")
+
+	_, err := c.GetNote(context.Background(), &notevaultpb.GetNoteRequest{Id: aliceID})
+	expectGRPCError(t, err, codes.Unauthenticated, "")
+
+	stream, err := c.ListNotes(context.Background(), &notevaultpb.ListNotesRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	expectGRPCError(t, err, codes.Unauthenticated, "")
+
+	_, err = c.Login(context.Background(), &notevaultpb.LoginRequest{Email: "alice@example.com", Password: "wrong"})
+	expectGRPCError(t, err, codes.Unauthenticated, "")
+
+	alice := grpcLogin(t, c, "alice@example.com")
+	bob := grpcLogin(t, c, "bob@example.com")
+
+	listNotes := func(ctx context.Context, req *notevaultpb.ListNotesRequest) []*notevaultpb.Note {
+		stream, err := c.ListNotes(ctx, req)
+		return receiveAll(t, stream, err)
+	}
+	listNoteBooks := func(ctx context.Context) []*notevaultpb.NoteBook {
+		stream, err := c.ListNoteBooks(ctx, &notevaultpb.ListNoteBooksRequest{})
+		return receiveAll(t, stream, err)
+	}
+	listTags := func(ctx context.Context) []*notevaultpb.Tag {
+		stream, err := c.ListTags(ctx, &notevaultpb.ListTagsRequest{})
+		return receiveAll(t, stream, err)
+	}
+
+	noteBook, err := c.CreateNoteBook(alice, &notevaultpb.CreateNoteBookRequest{Name: "Projects"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := c.CreateNoteBook(alice, &notevaultpb.CreateNoteBookRequest{Name: "Other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	work, err := c.CreateTag(alice, &notevaultpb.CreateTagRequest{Name: "work", Color: "#ff0000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	home, err := c.CreateTag(alice, &notevaultpb.CreateTagRequest{Name: "home"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.CreateTag(alice, &notevaultpb.CreateTagRequest{Name: "bad", Color: "red"})
+	expectGRPCError(t, err, codes.InvalidArgument, "color")
+	_, err = c.CreateNote(alice, &notevaultpb.CreateNoteRequest{})
+	expectGRPCError(t, err, codes.InvalidArgument, "name")
+	_, err = c.CreateNote(alice, &notevaultpb.CreateNoteRequest{Name: "Lost", NotebookId: aliceID})
+	expectGRPCError(t, err, codes.InvalidArgument, "")
+
+	first, err := c.CreateNote(alice, &notevaultpb.CreateNoteRequest{Name: "First", Text: "text", NotebookId: noteBook.GetId(), TagIds: []string{work.GetId()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.GetUserId() != aliceID || first.GetNotebookId() != noteBook.GetId() || len(first.GetTagIds()) != 1 || first.GetRank() == "" {
+		t.Fatalf("created note: %v", first)
+	}
+
+	second, err := c.CreateNote(alice, &notevaultpb.CreateNoteRequest{Name: "Second", TagIds: []string{work.GetId(), home.GetId(), home.GetId()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.GetTagIds()) != 2 {
+		t.Fatalf("duplicate tags: %v", second)
+	}
+
+	_, err = c.CreateNote(bob, &notevaultpb.CreateNoteRequest{Name: "Bob's"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Заметка, созданная через gRPC, видна в HTTP API
+	if note := api.note(first.GetId()); note.Name != "First" || note.NoteBookID != noteBook.GetId() {
+		t.Fatalf("note over HTTP: %+v", note)
+	}
+
+	notes := listNotes(alice, &notevaultpb.ListNotesRequest{})
+	if len(notes) != 2 {
+		t.Fatalf("alice notes: %v", grpcNoteNames(notes))
+	}
+	notes = listNotes(alice, &notevaultpb.ListNotesRequest{TagQuery: "work AND home"})
+	if len(notes) != 1 || notes[0].GetName() != "Second" {
+		t.Fatalf("notes by tag query: %v", grpcNoteNames(notes))
+	}
+	notes = listNotes(alice, &notevaultpb.ListNotesRequest{NotebookId: noteBook.GetId()})
+	if len(notes) != 1 || notes[0].GetName() != "First" {
+		t.Fatalf("notes by notebook: %v", grpcNoteNames(notes))
+	}
+	notes = listNotes(bob, &notevaultpb.ListNotesRequest{Status: notevaultpb.NoteStatus_NOTE_STATUS_ALL})
+	if len(notes) != 1 || notes[0].GetName() != "Bob's" {
+		t.Fatalf("bob notes: %v", grpcNoteNames(notes))
+	}
+
+	_, err = c.GetNote(bob, &notevaultpb.GetNoteRequest{Id: first.GetId()})
+	expectGRPCError(t, err, codes.NotFound, "")
+	_, err = c.UpdateNote(bob, &notevaultpb.UpdateNoteRequest{Id: first.GetId(), Name: ptr("Mine")})
+	expectGRPCError(t, err, codes.NotFound, "")
+	_, err = c.GetNote(alice, &notevaultpb.GetNoteRequest{Id: "bad"})
+	expectGRPCError(t, err, codes.InvalidArgument, "")
+
+	_, err = c.UpdateNote(alice, &notevaultpb.UpdateNoteRequest{Id: first.GetId(), Name: ptr(" ")})
+	expectGRPCError(t, err, codes.InvalidArgument, "name")
+
+	updated, err := c.UpdateNote(alice, &notevaultpb.UpdateNoteRequest{
+		Id:         first.GetId(),
+		Text:       ptr(""),
+		NotebookId: ptr(""),
+		TagIds:     &notevaultpb.TagIDs{},
+		IsArchived: ptr(true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.GetName() != "First" || updated.GetText() != "" || updated.GetNotebookId() != "" || len(updated.GetTagIds()) != 0 || !updated.GetIsArchived() {
+		t.Fatalf("updated note: %v", updated)
+	}
+
+	notes = listNotes(alice, &notevaultpb.ListNotesRequest{Status: notevaultpb.NoteStatus_NOTE_STATUS_ARCHIVED})
+	if len(notes) != 1 || notes[0].GetName() != "First" {
+		t.Fatalf("archived notes: %v", grpcNoteNames(notes))
+	}
+
+	got, err := c.GetNote(alice, &notevaultpb.GetNoteRequest{Id: second.GetId()})
+	if err != nil || got.GetName() != "Second" {
+		t.Fatalf("get note: %v, %v", got, err)
+	}
+
+	noteBooks := listNoteBooks(alice)
+	tags := listTags(alice)
+	if len(noteBooks) != 2 || len(tags) != 2 {
+		t.Fatalf("notebooks %v, tags %v", noteBooks, tags)
+	}
+
+	renamed, err := c.UpdateNoteBook(alice, &notevaultpb.UpdateNoteBookRequest{Id: noteBook.GetId(), Description: "work stuff", IsActive: ptr(true)})
+	if err != nil || renamed.GetName() != "Projects" || renamed.GetDescription() != "work stuff" || !renamed.GetIsActive() {
+		t.Fatalf("updated notebook: %v, %v", renamed, err)
+	}
+	recolored, err := c.UpdateTag(alice, &notevaultpb.UpdateTagRequest{Id: home.GetId(), Color: "#00ff00"})
+	if err != nil || recolored.GetName() != "home" || recolored.GetColor() != "#00ff00" {
+		t.Fatalf("updated tag: %v, %v", recolored, err)
+	}
+
+	_, err = c.UpdateNote(alice, &notevaultpb.UpdateNoteRequest{Id: second.GetId(), NotebookId: ptr(noteBook.GetId())})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.DeleteNoteBook(alice, &notevaultpb.DeleteNoteBookRequest{Id: noteBook.GetId(), Mode: notevaultpb.DeleteMode_DELETE_MODE_RESTRICT})
+	expectGRPCError(t, err, codes.FailedPrecondition, "")
+	_, err = c.DeleteNoteBook(alice, &notevaultpb.DeleteNoteBookRequest{Id: noteBook.GetId(), Mode: notevaultpb.DeleteMode_DELETE_MODE_MOVE})
+	expectGRPCError(t, err, codes.InvalidArgument, "")
+
+	_, err = c.DeleteNoteBook(alice, &notevaultpb.DeleteNoteBookRequest{Id: noteBook.GetId(), Mode: notevaultpb.DeleteMode_DELETE_MODE_MOVE, TargetId: other.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := c.GetNote(alice, &notevaultpb.GetNoteRequest{Id: second.GetId()}); got.GetNotebookId() != other.GetId() {
+		t.Fatalf("moved note: %v", got)
+	}
+	_, err = c.GetNoteBook(alice, &notevaultpb.GetNoteBookRequest{Id: noteBook.GetId()})
+	expectGRPCError(t, err, codes.NotFound, "")
+
+	_, err = c.DeleteTag(alice, &notevaultpb.DeleteTagRequest{Id: home.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.GetTag(alice, &notevaultpb.GetTagRequest{Id: home.GetId()})
+	expectGRPCError(t, err, codes.NotFound, "")
+
+	_, err = c.DeleteNote(bob, &notevaultpb.DeleteNoteRequest{Id: second.GetId()})
+	expectGRPCError(t, err, codes.NotFound, "")
+	_, err = c.DeleteNote(alice, &notevaultpb.DeleteNoteRequest{Id: second.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	api.expectCode(http.MethodGet, "/notes/"+second.GetId(), nil, http.StatusNotFound, "not_found")
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
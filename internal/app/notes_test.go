@@ -0,0 +1,456 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+)
+
+func TestNoteCRUD(t *testing.T) {
+	api := newTestAPI(t)
+
+	id := api.create("/notes", map[string]any{"name": "First", "text": "hello", "color": "#ff0000"})
+
+	note := api.note(id)
+	if note.Name != "First" || note.Text != "hello" || note.Color != "#ff0000" {
+		t.Fatalf("created note: %+v", note)
+	}
+	if note.Rank == "" {
+		t.Fatal("created note has no rank")
+	}
+
+	var modified int
+	api.ok(http.MethodPut, "/notes/"+id, map[string]any{"name": "Renamed", "text": "world"}, &modified)
+	if modified != 1 {
+		t.Fatalf("update modified %d notes", modified)
+	}
+
+	note = api.note(id)
+	if note.Name != "Renamed" || note.Text != "world" || note.Color != "#ff0000" {
+		t.Fatalf("updated note: %+v", note)
+	}
+
+	api.create("/notes", map[string]any{"name": "Second"})
+	assertNames(t, "notes", api.notes("/notes"), "Renamed", "Second")
+
+	var deleted int
+	api.ok(http.MethodDelete, "/notes/"+id, nil, &deleted)
+	if deleted != 1 {
+		t.Fatalf("delete removed %d notes", deleted)
+	}
+
+	api.expectCode(http.MethodGet, "/notes/"+id, nil, http.StatusNotFound, dto.ErrorCodeNotFound)
+	api.expectCode(http.MethodGet, "/notes/not-an-id", nil, http.StatusBadRequest, dto.ErrorCodeInvalidID)
+	api.expectCode(http.MethodPost, "/notes", "{", http.StatusBadRequest, dto.ErrorCodeValidation)
+	api.expect(http.MethodPut, "/notes/"+id, "{", http.StatusBadRequest)
+	assertNames(t, "notes after delete", api.notes("/notes"), "Second")
+}
+
+func TestNotePatch(t *testing.T) {
+	api := newTestAPI(t)
+
+	noteBook := api.create("/notebooks", map[string]any{"name": "Work"})
+	work := api.create("/tags", map[string]any{"name": "work"})
+	home := api.create("/tags", map[string]any{"name": "home"})
+	id := api.create("/notes", map[string]any{"name": "Plan", "text": "draft", "color": "#ff0000", "order": 3})
+
+	var patched noteView
+	api.ok(http.MethodPatch, "/notes/"+id, map[string]any{
+		"text":        "final",
+		"notebook_id": noteBook,
+		"tags":        []string{work, home, work},
+	}, &patched)
+	if patched.Name != "Plan" || patched.Text != "final" || patched.Color != "#ff0000" || patched.Order != 3 ||
+		patched.NoteBookID != noteBook || len(patched.Tags) != 2 {
+		t.Fatalf("patched note: %+v", patched)
+	}
+
+	//null очищает поле, пустая строка и пустой список - тоже значения
+	var cleared noteView
+	api.ok(http.MethodPatch, "/notes/"+id, `{"color": null, "order": null, "notebook_id": null, "tags": [], "text": ""}`, &cleared)
+	if cleared.Name != "Plan" || cleared.Color != "" || cleared.Order != 0 || cleared.NoteBookID == noteBook || len(cleared.Tags) != 0 || cleared.Text != "" {
+		t.Fatalf("cleared note: %+v", cleared)
+	}
+	if note := api.note(id); note.Name != "Plan" || len(note.Tags) != 0 {
+		t.Fatalf("stored note: %+v", note)
+	}
+
+	//Ошибка в любом поле отменяет весь патч
+	env := api.expectCode(http.MethodPatch, "/notes/"+id, `{"name": null, "text": "lost"}`, http.StatusUnprocessableEntity, dto.ErrorCodeInvalidFields)
+	assertFields(t, env, "name:required")
+	api.expect(http.MethodPatch, "/notes/"+id, map[string]any{"text": "lost", "tags": []string{"000000000000000000000001"}}, http.StatusBadRequest)
+	api.expect(http.MethodPatch, "/notes/"+id, map[string]any{"text": "lost", "notebook_id": "000000000000000000000001"}, http.StatusBadRequest)
+	api.expect(http.MethodPatch, "/notes/"+id, map[string]any{"text": "lost", "color": "red"}, http.StatusUnprocessableEntity)
+	api.expect(http.MethodPatch, "/notes/"+id, map[string]any{"text": "lost", "rank": "a"}, http.StatusBadRequest)
+	api.expect(http.MethodPatch, "/notes/"+id, `["text"]`, http.StatusBadRequest)
+	if note := api.note(id); note.Text != "" {
+		t.Fatalf("rejected patch changed note: %+v", note)
+	}
+
+	api.expectCode(http.MethodPatch, "/notes/000000000000000000000001", map[string]any{"text": "x"}, http.StatusNotFound, dto.ErrorCodeNotFound)
+
+	req, _ := http.NewRequest(http.MethodPatch, api.server.URL+"/api/v1/notes/"+id, strings.NewReader(`{"text": "x"}`))
+	req.Header.Set("Content-Type", "text/plain")
+	resp, err := api.client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Fatalf("text/plain patch: status %d", resp.StatusCode)
+	}
+
+	req, _ = http.NewRequest(http.MethodPatch, api.server.URL+"/api/v1/notes/"+id, strings.NewReader(`{"text": "merged"}`))
+	req.Header.Set("Content-Type", dto.MergePatchContentType)
+	resp, err = api.client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if note := api.note(id); resp.StatusCode != http.StatusOK || note.Text != "merged" {
+		t.Fatalf("merge-patch+json: status %d, note %+v", resp.StatusCode, note)
+	}
+}
+
+func TestNoteTrashAndArchive(t *testing.T) {
+	api := newTestAPI(t)
+
+	trashed := api.create("/notes", map[string]any{"name": "Trashed"})
+	archived := api.create("/notes", map[string]any{"name": "Archived"})
+	api.create("/notes", map[string]any{"name": "Active"})
+
+	api.ok(http.MethodDelete, "/notes/trash/"+trashed, nil, nil)
+	api.ok(http.MethodDelete, "/notes/archive/"+archived, nil, nil)
+
+	assertNames(t, "active", api.notes("/notes"), "Active")
+	assertNames(t, "trash", api.notes("/notes/trash"), "Trashed")
+	assertNames(t, "archive", api.notes("/notes/archive"), "Archived")
+
+	api.ok(http.MethodGet, "/notes/trash/"+trashed, nil, nil)
+	api.ok(http.MethodGet, "/notes/archive/"+archived, nil, nil)
+
+	assertNames(t, "active after restore", api.notes("/notes"), "Trashed", "Archived", "Active")
+	assertNames(t, "trash after restore", api.notes("/notes/trash"))
+	assertNames(t, "archive after restore", api.notes("/notes/archive"))
+}
+
+func TestNotePinAndFavourite(t *testing.T) {
+	api := newTestAPI(t)
+
+	api.create("/notes", map[string]any{"name": "A"})
+	b := api.create("/notes", map[string]any{"name": "B"})
+
+	api.ok(http.MethodPut, "/notes/pin/"+b, nil, nil)
+	api.ok(http.MethodPut, "/notes/favourite/"+b, nil, nil)
+
+	assertNames(t, "pinned first", api.notes("/notes"), "B", "A")
+	assertNames(t, "favourites", api.notes("/notes/favourites"), "B")
+	if note := api.note(b); !note.IsPinned || !note.IsFavourite {
+		t.Fatalf("pinned favourite note: %+v", note)
+	}
+
+	api.ok(http.MethodDelete, "/notes/pin/"+b, nil, nil)
+	api.ok(http.MethodDelete, "/notes/favourite/"+b, nil, nil)
+
+	if note := api.note(b); note.IsPinned || note.IsFavourite {
+		t.Fatalf("unpinned note: %+v", note)
+	}
+	assertNames(t, "no favourites", api.notes("/notes/favourites"))
+}
+
+// TestNoteFlagsNotFound - флаги несуществующей заметки не меняются молча, а повторная установка не считается ошибкой
+func TestNoteFlagsNotFound(t *testing.T) {
+	api := newTestAPI(t)
+
+	id := api.create("/notes", map[string]any{"name": "A"})
+	const missing = "000000000000000000000001"
+
+	routes := []struct{ method, path string }{
+		{http.MethodDelete, "/notes/trash/"},
+		{http.MethodGet, "/notes/trash/"},
+		{http.MethodDelete, "/notes/archive/"},
+		{http.MethodGet, "/notes/archive/"},
+		{http.MethodPut, "/notes/pin/"},
+		{http.MethodDelete, "/notes/pin/"},
+		{http.MethodPut, "/notes/favourite/"},
+		{http.MethodDelete, "/notes/favourite/"},
+	}
+
+	for _, route := range routes {
+		api.expectCode(route.method, route.path+missing, nil, http.StatusNotFound, dto.ErrorCodeNotFound)
+		api.ok(route.method, route.path+id, nil, nil)
+		api.ok(route.method, route.path+id, nil, nil)
+	}
+}
+
+func TestNoteNoteBook(t *testing.T) {
+	api := newTestAPI(t)
+
+	noteBook := api.create("/notebooks", map[string]any{"name": "Work", "is_active": true})
+	id := api.create("/notes", map[string]any{"name": "Plan"})
+	api.create("/notes", map[string]any{"name": "Loose"})
+
+	api.ok(http.MethodPut, "/notes/notebook/"+id, map[string]any{"notebook_id": noteBook}, nil)
+	if note := api.note(id); note.NoteBookID != noteBook {
+		t.Fatalf("note notebook %q, want %q", note.NoteBookID, noteBook)
+	}
+	assertNames(t, "group", api.notes("/notes/group/"+noteBook), "Plan")
+
+	api.expect(http.MethodPut, "/notes/notebook/"+id, map[string]any{"notebook_id": "000000000000000000000001"}, http.StatusBadRequest)
+	api.expect(http.MethodGet, "/notes/group/not-an-id", nil, http.StatusBadRequest)
+
+	api.ok(http.MethodDelete, "/notes/notebook/"+id, nil, nil)
+	assertNames(t, "group after unlink", api.notes("/notes/group/"+noteBook))
+}
+
+// TestNoteMoveRank проверяет, что заметка, перенесенная в блокнот любым способом, встает в его конец
+func TestNoteMoveRank(t *testing.T) {
+	api := newTestAPI(t)
+
+	a := api.create("/notes", map[string]any{"name": "A"})
+	b := api.create("/notes", map[string]any{"name": "B"})
+	c := api.create("/notes", map[string]any{"name": "C"})
+	d := api.create("/notes", map[string]any{"name": "D"})
+
+	noteBook := api.create("/notebooks", map[string]any{"name": "Work"})
+	api.create("/notes", map[string]any{"name": "X", "notebook_id": noteBook})
+	api.create("/notes", map[string]any{"name": "Y", "notebook_id": noteBook})
+
+	api.ok(http.MethodPut, "/notes/notebook/"+a, map[string]any{"notebook_id": noteBook}, nil)
+	assertNames(t, "after PUT", api.notes("/notes/group/"+noteBook), "X", "Y", "A")
+
+	api.ok(http.MethodPatch, "/notes/"+b, map[string]any{"notebook_id": noteBook}, nil)
+	assertNames(t, "after PATCH", api.notes("/notes/group/"+noteBook), "X", "Y", "A", "B")
+
+	api.ok(http.MethodPost, "/notes/bulk", map[string]any{"action": "move", "ids": []string{c, d, a}, "notebook_id": noteBook}, nil)
+	assertNames(t, "after bulk move", api.notes("/notes/group/"+noteBook), "X", "Y", "A", "B", "C", "D")
+
+	other := api.create("/notebooks", map[string]any{"name": "Other"})
+	api.create("/notes", map[string]any{"name": "O1", "notebook_id": other})
+	api.create("/notes", map[string]any{"name": "O2", "notebook_id": other})
+	api.ok(http.MethodDelete, "/notebooks/"+other+"?mode=move&target_id="+noteBook, nil, nil)
+	assertNames(t, "after notebook delete", api.notes("/notes/group/"+noteBook), "X", "Y", "A", "B", "C", "D", "O1", "O2")
+
+	e := api.create("/notes", map[string]any{"name": "E"})
+	api.ok(http.MethodDelete, "/notes/notebook/"+a, nil, nil)
+	if rankA, rankE := api.note(a).Rank, api.note(e).Rank; rankA <= rankE {
+		t.Fatalf("unlinked note rank %q, want after %q", rankA, rankE)
+	}
+}
+
+func TestNoteReorder(t *testing.T) {
+	api := newTestAPI(t)
+
+	a := api.create("/notes", map[string]any{"name": "A"})
+	b := api.create("/notes", map[string]any{"name": "B"})
+	c := api.create("/notes", map[string]any{"name": "C"})
+
+	api.ok(http.MethodPut, "/notes/order/"+c, map[string]any{"before_id": a}, nil)
+	assertNames(t, "C before A", api.notes("/notes"), "C", "A", "B")
+
+	api.ok(http.MethodPut, "/notes/order/"+c, map[string]any{"after_id": a}, nil)
+	assertNames(t, "C after A", api.notes("/notes"), "A", "C", "B")
+
+	api.ok(http.MethodPut, "/notes/order/"+a, map[string]any{"after_id": b}, nil)
+	assertNames(t, "A last", api.notes("/notes"), "C", "B", "A")
+
+	api.expect(http.MethodPut, "/notes/order/"+a, map[string]any{"before_id": b, "after_id": c}, http.StatusBadRequest)
+	api.expect(http.MethodPut, "/notes/order/"+a, map[string]any{"after_id": a}, http.StatusBadRequest)
+	api.expect(http.MethodPut, "/notes/order/000000000000000000000001", map[string]any{"after_id": a}, http.StatusNotFound)
+
+	noteBook := api.create("/notebooks", map[string]any{"name": "Other"})
+	api.ok(http.MethodPut, "/notes/notebook/"+b, map[string]any{"notebook_id": noteBook}, nil)
+	api.expect(http.MethodPut, "/notes/order/"+a, map[string]any{"after_id": b}, http.StatusBadRequest)
+}
+
+func TestNoteTags(t *testing.T) {
+	api := newTestAPI(t)
+
+	work := api.create("/tags", map[string]any{"name": "work"})
+	api.create("/tags", map[string]any{"name": "work/clientA"})
+	api.create("/tags", map[string]any{"name": "home"})
+
+	a := api.create("/notes", map[string]any{"name": "A"})
+	b := api.create("/notes", map[string]any{"name": "B"})
+	c := api.create("/notes", map[string]any{"name": "C"})
+
+	api.ok(http.MethodPut, "/notes/tag/"+a, map[string]any{"tag_id": work}, nil)
+	api.ok(http.MethodPut, "/notes/tag/"+b, map[string]any{"tag_name": "work/clientA"}, nil)
+	api.ok(http.MethodPut, "/notes/tag/"+b, map[string]any{"tag_name": "home"}, nil)
+	api.ok(http.MethodPut, "/notes/tag/"+c, map[string]any{"tag_name": "home"}, nil)
+
+	note := api.note(b)
+	if len(note.Tags) != 2 || note.Tags[0].Name != "work/clientA" || note.Tags[1].Name != "home" {
+		t.Fatalf("note tags: %+v", note.Tags)
+	}
+
+	var found []noteView
+	api.ok(http.MethodPost, "/notes/tag", map[string]any{"tags": []string{"work"}}, &found)
+	assertNames(t, "work", found, "A")
+
+	api.ok(http.MethodPost, "/notes/tag", map[string]any{"tags": []string{"work"}, "include_descendants": true}, &found)
+	assertNames(t, "work with descendants", found, "A", "B")
+
+	api.ok(http.MethodPost, "/notes/tag", map[string]any{"all_of": []string{"home"}, "none_of": []string{"work/clientA"}}, &found)
+	assertNames(t, "home without clientA", found, "C")
+
+	api.ok(http.MethodPost, "/notes/tag", map[string]any{"query": "work OR home"}, &found)
+	assertNames(t, "query", found, "A", "B", "C")
+
+	deep := strings.Repeat("(", 200000) + "work" + strings.Repeat(")", 200000)
+	api.expect(http.MethodPost, "/notes/tag", map[string]any{"query": deep}, http.StatusBadRequest)
+
+	api.expect(http.MethodPost, "/notes/tag", map[string]any{"status": "unknown"}, http.StatusBadRequest)
+	api.expect(http.MethodPut, "/notes/tag/"+a, map[string]any{}, http.StatusBadRequest)
+	api.expect(http.MethodPut, "/notes/tag/"+a, map[string]any{"tag_name": "missing"}, http.StatusBadRequest)
+
+	api.ok(http.MethodPatch, "/notes/tag/"+b, map[string]any{"tag_name": "home"}, nil)
+	if note := api.note(b); len(note.Tags) != 1 || note.Tags[0].Name != "work/clientA" {
+		t.Fatalf("note tags after removal: %+v", note.Tags)
+	}
+	api.expect(http.MethodPatch, "/notes/tag/"+b, map[string]any{}, http.StatusBadRequest)
+}
+
+// failingNotes - заметки, чтение заметки с id broken падает
+type failingNotes struct {
+	repository.NoteRepo
+	broken *atomic.Value
+}
+
+func (f failingNotes) GetNoteByID(ctx context.Context, id string) (model.Note, error) {
+	if id == f.broken.Load() {
+		return model.Note{}, errors.New("notes are unavailable")
+	}
+	return f.NoteRepo.GetNoteByID(ctx, id)
+}
+
+// TestNoteLookupErrors - ошибка базы при поиске тега или якоря не выдается за неверный запрос
+func TestNoteLookupErrors(t *testing.T) {
+	var failTags atomic.Bool
+	var broken atomic.Value
+	repos := newRepos()
+	repos.Tags = failingTags{TagRepo: repos.Tags, fail: &failTags}
+	repos.Notes = failingNotes{NoteRepo: repos.Notes, broken: &broken}
+	api := newTestAPIWith(t, repos)
+
+	tag := api.create("/tags", map[string]any{"name": "work"})
+	a := api.create("/notes", map[string]any{"name": "A"})
+	b := api.create("/notes", map[string]any{"name": "B"})
+
+	api.expect(http.MethodPut, "/notes/tag/"+a, map[string]any{"tag_id": "not-an-id"}, http.StatusBadRequest)
+	api.expect(http.MethodPut, "/notes/tag/"+a, map[string]any{"tag_id": "000000000000000000000001"}, http.StatusBadRequest)
+	api.expect(http.MethodPatch, "/notes/tag/"+a, map[string]any{"tag_id": "000000000000000000000001"}, http.StatusBadRequest)
+	api.expect(http.MethodPut, "/notes/order/"+a, map[string]any{"after_id": "not-an-id"}, http.StatusBadRequest)
+	api.expect(http.MethodPut, "/notes/order/"+a, map[string]any{"after_id": "000000000000000000000001"}, http.StatusBadRequest)
+
+	failTags.Store(true)
+	api.expect(http.MethodPut, "/notes/tag/"+a, map[string]any{"tag_id": tag}, http.StatusInternalServerError)
+	api.expect(http.MethodPatch, "/notes/tag/"+a, map[string]any{"tag_id": tag}, http.StatusInternalServerError)
+	failTags.Store(false)
+
+	broken.Store(b)
+	api.expect(http.MethodPut, "/notes/order/"+a, map[string]any{"after_id": b}, http.StatusInternalServerError)
+	broken.Store("")
+
+	api.ok(http.MethodPut, "/notes/tag/"+a, map[string]any{"tag_id": tag}, nil)
+	api.ok(http.MethodPut, "/notes/order/"+a, map[string]any{"after_id": b}, nil)
+	assertNames(t, "A after B", api.notes("/notes"), "B", "A")
+}
+
+func TestNoteBulk(t *testing.T) {
+	api := newTestAPI(t)
+
+	noteBook := api.create("/notebooks", map[string]any{"name": "Inbox"})
+	api.create("/tags", map[string]any{"name": "todo"})
+
+	a := api.create("/notes", map[string]any{"name": "A"})
+	b := api.create("/notes", map[string]any{"name": "B"})
+	api.create("/notes", map[string]any{"name": "C"})
+
+	var res struct {
+		Total     int `json:"total"`
+		Succeeded int `json:"succeeded"`
+	}
+
+	api.ok(http.MethodPost, "/notes/bulk", map[string]any{"action": "move", "ids": []string{a, b}, "notebook_id": noteBook}, &res)
+	if res.Total != 2 || res.Succeeded != 2 {
+		t.Fatalf("bulk move: %+v", res)
+	}
+	assertNames(t, "moved", api.notes("/notes/group/"+noteBook), "A", "B")
+
+	api.ok(http.MethodPost, "/notes/bulk", map[string]any{"action": "add_tag", "ids": []string{a}, "tag_name": "todo"}, nil)
+
+	api.ok(http.MethodPost, "/notes/bulk", map[string]any{"action": "archive", "filter": map[string]any{"tags": []string{"todo"}}}, &res)
+	if res.Total != 1 || res.Succeeded != 1 {
+		t.Fatalf("bulk archive by filter: %+v", res)
+	}
+	assertNames(t, "archived", api.notes("/notes/archive"), "A")
+
+	api.ok(http.MethodPost, "/notes/bulk", map[string]any{"action": "recolor", "ids": []string{b, "000000000000000000000001"}, "color": "#0000ff"}, &res)
+	if res.Total != 2 || res.Succeeded != 1 {
+		t.Fatalf("bulk recolor with missing note: %+v", res)
+	}
+	if note := api.note(b); note.Color != "#0000ff" {
+		t.Fatalf("recolored note: %+v", note)
+	}
+
+	api.expect(http.MethodPost, "/notes/bulk", map[string]any{"action": "explode", "ids": []string{a}}, http.StatusBadRequest)
+	api.expect(http.MethodPost, "/notes/bulk", map[string]any{"action": "trash"}, http.StatusBadRequest)
+	api.expect(http.MethodPost, "/notes/bulk", map[string]any{"action": "recolor", "ids": []string{a}}, http.StatusBadRequest)
+}
+
+// limitedNotes запоминает, сколько заметок вернул последний FindNotes
+type limitedNotes struct {
+	repository.NoteRepo
+	found *atomic.Int64
+}
+
+func (l limitedNotes) FindNotes(ctx context.Context, filter repository.NoteFilter) ([]model.Note, error) {
+	notes, err := l.NoteRepo.FindNotes(ctx, filter)
+	l.found.Store(int64(len(notes)))
+	return notes, err
+}
+
+func TestNoteBulkLimits(t *testing.T) {
+	var found atomic.Int64
+	repos := newRepos()
+	repos.Notes = limitedNotes{NoteRepo: repos.Notes, found: &found}
+	api := newTestAPIWith(t, repos)
+
+	var ids []string
+	for range 1001 {
+		id, err := repos.Notes.CreateNote(context.Background(), model.Note{Name: "Note"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	api.expect(http.MethodPost, "/notes/bulk", map[string]any{"action": "trash", "ids": ids}, http.StatusBadRequest)
+	api.expect(http.MethodPost, "/notes/bulk", map[string]any{"action": "trash", "filter": map[string]any{"status": "unknown"}}, http.StatusBadRequest)
+
+	api.expect(http.MethodPost, "/notes/bulk", map[string]any{"action": "trash", "filter": map[string]any{"status": "all"}}, http.StatusBadRequest)
+	if found.Load() != 1001 {
+		t.Fatalf("filter loaded %d notes, want the limit plus one", found.Load())
+	}
+
+	api.ok(http.MethodDelete, "/notes/"+ids[0], nil, nil)
+	var res struct {
+		Total int `json:"total"`
+		Items []struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"items"`
+	}
+	api.ok(http.MethodPost, "/notes/bulk", map[string]any{"action": "trash", "filter": map[string]any{"status": "all"}}, &res)
+	if res.Total != 1000 || len(res.Items) != 1000 || res.Items[0].Status != "ok" {
+		t.Fatalf("bulk trash of 1000 notes: total %d, %d items", res.Total, len(res.Items))
+	}
+}
@@ -0,0 +1,277 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/LoL-KeKovich/NoteVault/api"
+	"github.com/LoL-KeKovich/NoteVault/client"
+	"github.com/LoL-KeKovich/NoteVault/internal/app"
+	"github.com/LoL-KeKovich/NoteVault/internal/clientgen"
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/lib/graphql"
+	"github.com/go-chi/chi"
+)
+
+type openAPISpec struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadSpec(t *testing.T) openAPISpec {
+	t.Helper()
+
+	var spec openAPISpec
+	if err := json.Unmarshal(api.OpenAPI, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	return spec
+}
+
+// TestOpenAPIRoutes сверяет маршруты роутера с путями спецификации в обе стороны
+func TestOpenAPIRoutes(t *testing.T) {
+	spec := loadSpec(t)
+
+	var inSpec []string
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if method != "parameters" {
+				inSpec = append(inSpec, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+
+	var inRouter []string
+	chi.Walk(app.NewRouter(testConfig(), newRepos()).(chi.Routes), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		inRouter = append(inRouter, method+" "+strings.TrimPrefix(strings.TrimSuffix(route, "/"), "/api/v1"))
+		return nil
+	})
+
+	for _, route := range inRouter {
+		if !slices.Contains(inSpec, route) {
+			t.Errorf("route %s is not described in api/openapi.json", route)
+		}
+	}
+	for _, route := range inSpec {
+		if !slices.Contains(inRouter, route) {
+			t.Errorf("api/openapi.json describes %s, but the router has no such route", route)
+		}
+	}
+}
+
+// TestOpenAPISchemas сверяет свойства схем с JSON-полями структур, которые API принимает и отдает
+func TestOpenAPISchemas(t *testing.T) {
+	spec := loadSpec(t)
+
+	types := map[string]any{
+		"Note":               model.Note{},
+		"NoteTag":            dto.NoteTag{},
+		"NoteView":           dto.NoteView{},
+		"NoteBook":           model.NoteBook{},
+		"Tag":                model.Tag{},
+		"TagUsage":           dto.TagUsage{},
+		"Template":           model.Template{},
+		"User":               model.User{},
+		"JournalCalendar":    dto.JournalCalendar{},
+		"BulkResult":         dto.BulkResult{},
+		"NoteBulkResult":     dto.NoteBulkResult{},
+		"ImportItem":         dto.ImportItem{},
+		"ImportResult":       dto.ImportResult{},
+		"NoteRequest":        dto.NoteRequest{},
+		"NotePatchRequest":   dto.NotePatchRequest{},
+		"NoteReorderRequest": dto.NoteReorderRequest{},
+		"NoteTagsRequest":    dto.NoteTagsRequest{},
+		"NoteBulkRequest":    dto.NoteBulkRequest{},
+		"NoteBookRequest":    dto.NoteBookRequest{},
+		"TagRequest":         dto.TagRequest{},
+		"TagMergeRequest":    dto.TagMergeRequest{},
+		"RegisterRequest":    dto.RegisterRequest{},
+		"LoginRequest":       dto.LoginRequest{},
+		"TemplateRequest":    dto.TemplateRequest{},
+		"Event":              events.Event{},
+		"Webhook":            model.Webhook{},
+		"WebhookDelivery":    model.WebhookDelivery{},
+		"WebhookRequest":     dto.WebhookRequest{},
+		"WebhookPayload":     dto.WebhookPayload{},
+		"SyncPull":           dto.SyncPull{},
+		"SyncNote":           dto.SyncNote{},
+		"SyncNoteBook":       dto.SyncNoteBook{},
+		"SyncTag":            dto.SyncTag{},
+		"SyncTombstone":      dto.SyncTombstone{},
+		"SyncPushRequest":    dto.SyncPushRequest{},
+		"SyncChange":         dto.SyncChange{},
+		"SyncNoteData":       dto.SyncNoteData{},
+		"SyncResult":         dto.SyncResult{},
+		"GraphQLRequest":     dto.GraphQLRequest{},
+		"GraphQLResponse":    graphql.Response{},
+		"GraphQLError":       graphql.Error{},
+		"GraphQLLocation":    graphql.Location{},
+		"FieldError":         dto.FieldError{},
+		"ErrorResponse":      dto.ErrorResponse{},
+	}
+
+	for name, schema := range spec.Components.Schemas {
+		v, ok := types[name]
+		if !ok {
+			t.Errorf("schema %s has no Go type in the test", name)
+			continue
+		}
+
+		var props []string
+		for prop := range schema.Properties {
+			props = append(props, prop)
+		}
+		sort.Strings(props)
+
+		//Поле внешней структуры перекрывает одноименное поле встроенной
+		fields := jsonFields(reflect.TypeOf(v))
+		sort.Strings(fields)
+		fields = slices.Compact(fields)
+
+		if !slices.Equal(props, fields) {
+			t.Errorf("schema %s: properties %v, %T fields %v", name, props, v, fields)
+		}
+	}
+
+	for name := range types {
+		if _, ok := spec.Components.Schemas[name]; !ok {
+			t.Errorf("api/openapi.json has no schema %s", name)
+		}
+	}
+}
+
+// jsonFields возвращает имена полей в JSON с учетом встроенных структур
+func jsonFields(rt reflect.Type) []string {
+	var fields []string
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		switch {
+		case name == "-" || !field.IsExported():
+		case field.Anonymous && name == "":
+			fields = append(fields, jsonFields(field.Type)...)
+		case name == "":
+			fields = append(fields, field.Name)
+		default:
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// TestClientGenerated проверяет, что client_gen.go перегенерирован после изменения спецификации
+func TestClientGenerated(t *testing.T) {
+	want, err := clientgen.Generate(api.OpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile("../../client/client_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Fatal("client/client_gen.go is out of date, run go generate ./client")
+	}
+}
+
+func TestClient(t *testing.T) {
+	testAPI := newTestAPI(t)
+	ctx := context.Background()
+	c := client.New(testAPI.server.URL+"/api/v1", nil)
+
+	spec, err := c.GetOpenAPI(ctx)
+	if err != nil || !bytes.Equal(spec, bytes.TrimSpace(api.OpenAPI)) {
+		t.Fatalf("openapi.json: %v", err)
+	}
+
+	_, err = c.RegisterUser(ctx, client.RegisterRequest{Email: "client@example.com", Password: "secret-password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.LoginUser(ctx, client.LoginRequest{Email: "client@example.com", Password: "secret-password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notebookID, err := c.CreateNoteBook(ctx, client.NoteBookRequest{Name: "Work"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagID, err := c.CreateTag(ctx, client.TagRequest{Name: "urgent", Color: "#ff0000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	noteID, err := c.CreateNote(ctx, client.NoteRequest{Name: "Plan", Text: "text", NotebookID: notebookID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.AddTagToNote(ctx, noteID, client.NoteRequest{TagID: tagID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	note, err := c.PatchNote(ctx, noteID, client.NotePatchRequest{
+		Name:       client.Value("Plan v2"),
+		Text:       client.Null[string](),
+		NotebookID: client.Null[string](),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Name != "Plan v2" || note.Text != "" || note.NotebookID == notebookID || len(note.Tags) != 1 || note.Tags[0].Name != "urgent" {
+		t.Fatalf("patched note: %+v", note)
+	}
+
+	usages, err := c.GetTags(ctx, true)
+	if err != nil || len(usages) != 1 || usages[0].NoteCount != 1 {
+		t.Fatalf("tags with counts: %+v, %v", usages, err)
+	}
+
+	_, err = c.CreateNote(ctx, client.NoteRequest{Color: "red"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Code != dto.ErrorCodeInvalidFields || len(apiErr.Fields) != 2 {
+		t.Fatalf("invalid note: %#v", err)
+	}
+
+	_, err = c.GetNoteByID(ctx, "665f1c2e8b3a4d0012345678")
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Code != dto.ErrorCodeNotFound {
+		t.Fatalf("missing note: %#v", err)
+	}
+
+	archive, err := c.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(archive)
+	archive.Close()
+	if err != nil || !bytes.HasPrefix(data, []byte("PK")) {
+		t.Fatalf("export: %d bytes, %v", len(data), err)
+	}
+
+	result, err := c.Import(ctx, "", "Imported", bytes.NewReader(data))
+	if err != nil || result.Imported != 1 {
+		t.Fatalf("import: %+v, %v", result, err)
+	}
+}
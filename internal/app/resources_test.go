@@ -0,0 +1,719 @@
+package app_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type noteBookView struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+type tagView struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	NoteCount int    `json:"note_count"`
+}
+
+func TestNoteBookCRUD(t *testing.T) {
+	api := newTestAPI(t)
+
+	id := api.create("/notebooks", map[string]any{"name": "Work", "description": "job", "is_active": true})
+
+	var noteBook noteBookView
+	api.ok(http.MethodGet, "/notebooks/"+id, nil, &noteBook)
+	if noteBook.Name != "Work" || noteBook.Description != "job" || noteBook.IsActive == nil || !*noteBook.IsActive {
+		t.Fatalf("created notebook: %+v", noteBook)
+	}
+
+	api.ok(http.MethodPut, "/notebooks/"+id, map[string]any{"name": "Job", "is_active": false}, nil)
+	api.ok(http.MethodGet, "/notebooks/"+id, nil, &noteBook)
+	if noteBook.Name != "Job" || noteBook.Description != "job" || *noteBook.IsActive {
+		t.Fatalf("updated notebook: %+v", noteBook)
+	}
+
+	api.create("/notebooks", map[string]any{"name": "Home"})
+
+	//Имена блокнотов уникальны, как и имена тегов
+	api.expectCode(http.MethodPost, "/notebooks", map[string]any{"name": "Home"}, http.StatusConflict, dto.ErrorCodeConflict)
+	api.expectCode(http.MethodPut, "/notebooks/"+id, map[string]any{"name": "Home"}, http.StatusConflict, dto.ErrorCodeConflict)
+	api.ok(http.MethodPut, "/notebooks/"+id, map[string]any{"name": "Job"}, nil)
+
+	var noteBooks []noteBookView
+	api.ok(http.MethodGet, "/notebooks", nil, &noteBooks)
+	if len(noteBooks) != 2 {
+		t.Fatalf("notebooks: %+v", noteBooks)
+	}
+
+	api.expectCode(http.MethodGet, "/notebooks/000000000000000000000001", nil, http.StatusNotFound, dto.ErrorCodeNotFound)
+	api.expect(http.MethodPost, "/notebooks", "{", http.StatusBadRequest)
+	api.expect(http.MethodPut, "/notebooks/"+id, "{", http.StatusBadRequest)
+}
+
+func TestNoteBookDeleteModes(t *testing.T) {
+	api := newTestAPI(t)
+
+	inNoteBook := func(noteBook string, names ...string) {
+		t.Helper()
+		for _, name := range names {
+			id := api.create("/notes", map[string]any{"name": name})
+			api.ok(http.MethodPut, "/notes/notebook/"+id, map[string]any{"notebook_id": noteBook}, nil)
+		}
+	}
+
+	unlinked := api.create("/notebooks", map[string]any{"name": "Unlinked"})
+	inNoteBook(unlinked, "U")
+	api.ok(http.MethodDelete, "/notebooks/"+unlinked, nil, nil)
+	if notes := api.notes("/notes"); len(notes) != 1 || notes[0].NoteBookID == unlinked {
+		t.Fatalf("unlinked notes: %+v", notes)
+	}
+
+	target := api.create("/notebooks", map[string]any{"name": "Target"})
+	moved := api.create("/notebooks", map[string]any{"name": "Moved"})
+	inNoteBook(moved, "M")
+	api.expect(http.MethodDelete, "/notebooks/"+moved+"?mode=move", nil, http.StatusBadRequest)
+	api.expect(http.MethodDelete, "/notebooks/"+moved+"?mode=move&target_id=000000000000000000000001", nil, http.StatusBadRequest)
+	api.ok(http.MethodDelete, "/notebooks/"+moved+"?mode=move&target_id="+target, nil, nil)
+	assertNames(t, "moved", api.notes("/notes/group/"+target), "M")
+
+	trashed := api.create("/notebooks", map[string]any{"name": "Trashed"})
+	inNoteBook(trashed, "T")
+	api.ok(http.MethodDelete, "/notebooks/"+trashed+"?mode=trash", nil, nil)
+	assertNames(t, "trashed", api.notes("/notes/trash"), "T")
+
+	api.expectCode(http.MethodDelete, "/notebooks/"+target+"?mode=restrict", nil, http.StatusConflict, dto.ErrorCodeConflict)
+	empty := api.create("/notebooks", map[string]any{"name": "Empty"})
+	api.ok(http.MethodDelete, "/notebooks/"+empty+"?mode=restrict", nil, nil)
+
+	api.expect(http.MethodDelete, "/notebooks/"+target+"?mode=explode", nil, http.StatusBadRequest)
+	api.expect(http.MethodDelete, "/notebooks/"+empty, nil, http.StatusNotFound)
+
+	var noteBooks []noteBookView
+	api.ok(http.MethodGet, "/notebooks", nil, &noteBooks)
+	if len(noteBooks) != 1 || noteBooks[0].Name != "Target" {
+		t.Fatalf("notebooks left: %+v", noteBooks)
+	}
+}
+
+// failingNoteBooks - блокноты в транзакции, чтение которых падает, пока fail выставлен
+type failingNoteBooks struct {
+	repository.NoteBookRepo
+	fail *atomic.Bool
+}
+
+func (f failingNoteBooks) GetNoteBookByID(ctx context.Context, id string) (model.NoteBook, error) {
+	if f.fail.Load() {
+		return model.NoteBook{}, errors.New("notebooks are unavailable")
+	}
+	return f.NoteBookRepo.GetNoteBookByID(ctx, id)
+}
+
+type failingNoteBooksUoW struct {
+	repository.UnitOfWork
+	fail *atomic.Bool
+}
+
+func (u failingNoteBooksUoW) Do(ctx context.Context, fn func(repository.Tx) error) error {
+	return u.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		tx.NoteBooks = failingNoteBooks{NoteBookRepo: tx.NoteBooks, fail: u.fail}
+		return fn(tx)
+	})
+}
+
+// TestNoteBookDeleteErrors - ошибка базы при удалении блокнота не выдается за "не найден"
+func TestNoteBookDeleteErrors(t *testing.T) {
+	var fail atomic.Bool
+	repos := newRepos()
+	repos.UnitOfWork = failingNoteBooksUoW{UnitOfWork: repos.UnitOfWork, fail: &fail}
+	api := newTestAPIWith(t, repos)
+
+	noteBook := api.create("/notebooks", map[string]any{"name": "Work"})
+	target := api.create("/notebooks", map[string]any{"name": "Target"})
+
+	api.expect(http.MethodDelete, "/notebooks/not-an-id", nil, http.StatusBadRequest)
+	api.expect(http.MethodDelete, "/notebooks/"+noteBook+"?mode=move&target_id=not-an-id", nil, http.StatusBadRequest)
+
+	fail.Store(true)
+	api.expect(http.MethodDelete, "/notebooks/"+noteBook, nil, http.StatusInternalServerError)
+	api.expect(http.MethodDelete, "/notebooks/"+noteBook+"?mode=move&target_id="+target, nil, http.StatusInternalServerError)
+
+	fail.Store(false)
+	api.ok(http.MethodDelete, "/notebooks/"+noteBook+"?mode=move&target_id="+target, nil, nil)
+}
+
+func TestTagCRUD(t *testing.T) {
+	api := newTestAPI(t)
+
+	work := api.create("/tags", map[string]any{"name": "work", "color": "#ff0000"})
+	client := api.create("/tags", map[string]any{"name": "work/clientA"})
+
+	var tag tagView
+	api.ok(http.MethodGet, "/tags/"+work, nil, &tag)
+	if tag.Name != "work" || tag.Color != "#ff0000" {
+		t.Fatalf("created tag: %+v", tag)
+	}
+
+	api.expectCode(http.MethodPost, "/tags", map[string]any{"name": "work"}, http.StatusConflict, dto.ErrorCodeConflict)
+	api.expect(http.MethodPost, "/tags", map[string]any{"name": "work//x"}, http.StatusBadRequest)
+
+	api.ok(http.MethodPut, "/tags/"+work, map[string]any{"name": "job"}, nil)
+	api.ok(http.MethodGet, "/tags/"+client, nil, &tag)
+	if tag.Name != "job/clientA" {
+		t.Fatalf("descendant after rename: %+v", tag)
+	}
+
+	api.expect(http.MethodPut, "/tags/000000000000000000000001", map[string]any{"color": "#0000ff"}, http.StatusNotFound)
+	api.expect(http.MethodPut, "/tags/"+work, map[string]any{"name": "/"}, http.StatusBadRequest)
+
+	note := api.create("/notes", map[string]any{"name": "A"})
+	api.ok(http.MethodPut, "/notes/tag/"+note, map[string]any{"tag_id": work}, nil)
+
+	var tags []tagView
+	api.ok(http.MethodGet, "/tags?with_counts=true", nil, &tags)
+	if len(tags) != 2 || tags[0].NoteCount != 1 || tags[1].NoteCount != 0 {
+		t.Fatalf("tags with counts: %+v", tags)
+	}
+
+	var deleted int
+	api.ok(http.MethodDelete, "/tags/"+client, nil, &deleted)
+	if deleted != 1 {
+		t.Fatalf("delete removed %d tags", deleted)
+	}
+	api.expect(http.MethodDelete, "/tags/"+client, nil, http.StatusNotFound)
+
+	api.ok(http.MethodGet, "/tags", nil, &tags)
+	if len(tags) != 1 || tags[0].Name != "job" {
+		t.Fatalf("tags after delete: %+v", tags)
+	}
+}
+
+func TestTagMerge(t *testing.T) {
+	api := newTestAPI(t)
+
+	source := api.create("/tags", map[string]any{"name": "todo"})
+	target := api.create("/tags", map[string]any{"name": "tasks"})
+
+	a := api.create("/notes", map[string]any{"name": "A"})
+	b := api.create("/notes", map[string]any{"name": "B"})
+	api.ok(http.MethodPut, "/notes/tag/"+a, map[string]any{"tag_id": source}, nil)
+	api.ok(http.MethodPut, "/notes/tag/"+b, map[string]any{"tag_id": source}, nil)
+	api.ok(http.MethodPut, "/notes/tag/"+b, map[string]any{"tag_id": target}, nil)
+
+	api.expect(http.MethodPost, "/tags/"+source+"/merge", map[string]any{"target_id": source}, http.StatusBadRequest)
+	api.expect(http.MethodPost, "/tags/"+source+"/merge", map[string]any{"target_id": "000000000000000000000001"}, http.StatusBadRequest)
+	api.expect(http.MethodPost, "/tags/000000000000000000000001/merge", map[string]any{"target_id": target}, http.StatusNotFound)
+
+	var modified int
+	api.ok(http.MethodPost, "/tags/"+source+"/merge", map[string]any{"target_id": target}, &modified)
+	if modified != 2 {
+		t.Fatalf("merge modified %d notes", modified)
+	}
+
+	for _, id := range []string{a, b} {
+		if note := api.note(id); len(note.Tags) != 1 || note.Tags[0].ID != target {
+			t.Fatalf("tags of %s after merge: %+v", note.Name, note.Tags)
+		}
+	}
+	api.expect(http.MethodGet, "/tags/"+source, nil, http.StatusNotFound)
+}
+
+// TestTagMergeDescendants - потомки переезжают под целевой тег, а совпавшие по имени сливаются
+func TestTagMergeDescendants(t *testing.T) {
+	api := newTestAPI(t)
+
+	source := api.create("/tags", map[string]any{"name": "todo"})
+	home := api.create("/tags", map[string]any{"name": "todo/home"})
+	work := api.create("/tags", map[string]any{"name": "todo/work"})
+	urgent := api.create("/tags", map[string]any{"name": "todo/work/urgent"})
+	target := api.create("/tags", map[string]any{"name": "tasks"})
+	targetWork := api.create("/tags", map[string]any{"name": "tasks/work"})
+
+	note := api.create("/notes", map[string]any{"name": "A"})
+	api.ok(http.MethodPut, "/notes/tag/"+note, map[string]any{"tag_id": work}, nil)
+	other := api.create("/notes", map[string]any{"name": "B"})
+	api.ok(http.MethodPut, "/notes/tag/"+other, map[string]any{"tag_id": urgent}, nil)
+
+	api.expect(http.MethodPost, "/tags/"+source+"/merge", map[string]any{"target_id": work}, http.StatusBadRequest)
+
+	var modified int
+	api.ok(http.MethodPost, "/tags/"+source+"/merge", map[string]any{"target_id": target}, &modified)
+	if modified != 1 {
+		t.Fatalf("merge modified %d notes", modified)
+	}
+
+	for id, name := range map[string]string{home: "tasks/home", urgent: "tasks/work/urgent", targetWork: "tasks/work"} {
+		var tag tagView
+		api.ok(http.MethodGet, "/tags/"+id, nil, &tag)
+		if tag.Name != name {
+			t.Fatalf("tag %s after merge: %+v", name, tag)
+		}
+	}
+	api.expect(http.MethodGet, "/tags/"+work, nil, http.StatusNotFound)
+
+	if tags := api.note(note).Tags; len(tags) != 1 || tags[0].ID != targetWork {
+		t.Fatalf("note tags after merge: %+v", tags)
+	}
+
+	var found []noteView
+	api.ok(http.MethodPost, "/notes/tag", map[string]any{"tags": []string{"tasks"}, "include_descendants": true}, &found)
+	assertNames(t, "notes under target", found, "A", "B")
+}
+
+// failingTags - теги в транзакции, чтение которых падает, пока fail выставлен
+type failingTags struct {
+	repository.TagRepo
+	fail *atomic.Bool
+}
+
+func (f failingTags) GetTagByID(ctx context.Context, id string) (model.Tag, error) {
+	if f.fail.Load() {
+		return model.Tag{}, errors.New("tags are unavailable")
+	}
+	return f.TagRepo.GetTagByID(ctx, id)
+}
+
+type failingTagsUoW struct {
+	repository.UnitOfWork
+	fail *atomic.Bool
+}
+
+func (u failingTagsUoW) Do(ctx context.Context, fn func(repository.Tx) error) error {
+	return u.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		tx.Tags = failingTags{TagRepo: tx.Tags, fail: u.fail}
+		return fn(tx)
+	})
+}
+
+// TestTagErrors - неверный id и ошибка базы при изменении тега не выдаются за "не найден"
+func TestTagErrors(t *testing.T) {
+	var fail atomic.Bool
+	repos := newRepos()
+	repos.UnitOfWork = failingTagsUoW{UnitOfWork: repos.UnitOfWork, fail: &fail}
+	api := newTestAPIWith(t, repos)
+
+	tag := api.create("/tags", map[string]any{"name": "work"})
+	target := api.create("/tags", map[string]any{"name": "job"})
+
+	api.expectCode(http.MethodPut, "/tags/not-an-id", map[string]any{"color": "#0000ff"}, http.StatusBadRequest, dto.ErrorCodeInvalidID)
+	api.expectCode(http.MethodDelete, "/tags/not-an-id", nil, http.StatusBadRequest, dto.ErrorCodeInvalidID)
+	api.expectCode(http.MethodPost, "/tags/not-an-id/merge", map[string]any{"target_id": target}, http.StatusBadRequest, dto.ErrorCodeInvalidID)
+
+	fail.Store(true)
+	api.expect(http.MethodPut, "/tags/"+tag, map[string]any{"color": "#0000ff"}, http.StatusInternalServerError)
+	api.expect(http.MethodDelete, "/tags/"+tag, nil, http.StatusInternalServerError)
+	api.expect(http.MethodPost, "/tags/"+tag+"/merge", map[string]any{"target_id": target}, http.StatusInternalServerError)
+
+	fail.Store(false)
+	api.ok(http.MethodDelete, "/tags/"+tag, nil, nil)
+}
+
+func TestUsers(t *testing.T) {
+	api := newTestAPI(t)
+
+	api.expect(http.MethodGet, "/users/profile", nil, http.StatusUnauthorized)
+	api.expect(http.MethodPost, "/users/register", map[string]any{"email": "a@example.com"}, http.StatusUnprocessableEntity)
+
+	id := api.login("a@example.com")
+
+	api.expect(http.MethodPost, "/users/register", map[string]any{"email": "a@example.com", "password": "x"}, http.StatusConflict)
+	api.expect(http.MethodPost, "/users/login", map[string]any{"email": "a@example.com", "password": "wrong"}, http.StatusUnauthorized)
+	api.expect(http.MethodPost, "/users/login", map[string]any{"email": "b@example.com", "password": "x"}, http.StatusUnauthorized)
+
+	var profile struct {
+		ID        string `json:"id"`
+		Email     string `json:"email"`
+		FirstName string `json:"first_name"`
+	}
+	api.ok(http.MethodGet, "/users/profile", nil, &profile)
+	if profile.ID != id || profile.Email != "a@example.com" || profile.FirstName != "Test" {
+		t.Fatalf("profile: %+v", profile)
+	}
+
+	api.ok(http.MethodDelete, "/users/profile", nil, nil)
+	api.expect(http.MethodGet, "/users/profile", nil, http.StatusUnauthorized)
+	api.expect(http.MethodPost, "/users/login", map[string]any{"email": "a@example.com", "password": "secret-password"}, http.StatusUnauthorized)
+}
+
+// TestDeleteProfileData - вместе с профилем удаляются заметки пользователя, его журнал и напоминания
+func TestDeleteProfileData(t *testing.T) {
+	repos := newRepos()
+	api := newTestAPIWith(t, repos)
+	ctx := context.Background()
+
+	shared := api.create("/notes", map[string]any{"name": "Shared"})
+
+	api.login("b@example.com")
+	kept := api.create("/notes", map[string]any{"name": "Other user"})
+
+	api.logout()
+	api.login("a@example.com")
+	own := api.create("/notes", map[string]any{"name": "Own"})
+	var daily noteView
+	api.ok(http.MethodGet, "/notes/daily/2025-03-14", nil, &daily)
+
+	for _, noteID := range []string{own, daily.ID, kept} {
+		docId, _ := primitive.ObjectIDFromHex(noteID)
+		if _, err := repos.Reminders.CreateReminder(ctx, model.Reminder{Name: "Call", NoteID: docId}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	api.ok(http.MethodDelete, "/users/profile", nil, nil)
+
+	notes, err := repos.Notes.GetNotesByIDs(ctx, []string{shared, kept, own, daily.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	for _, note := range notes {
+		left = append(left, note.Name)
+	}
+	if strings.Join(left, ",") != "Shared,Other user" {
+		t.Fatalf("notes after profile deletion: %v", left)
+	}
+
+	reminders, err := repos.Reminders.GetRemindersByNotes(ctx, []string{own, daily.ID, kept})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reminders) != 1 || reminders[0].NoteID.Hex() != kept {
+		t.Fatalf("reminders after profile deletion: %+v", reminders)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	api := newTestAPI(t)
+
+	api.expect(http.MethodGet, "/templates", nil, http.StatusUnauthorized)
+
+	api.login("a@example.com")
+
+	noteBook := api.create("/notebooks", map[string]any{"name": "Meetings"})
+	tag := api.create("/tags", map[string]any{"name": "meeting"})
+
+	id := api.create("/templates", map[string]any{
+		"name":        "Meeting",
+		"text":        "Agenda for {{date}}",
+		"color":       "#00ff00",
+		"tags":        []string{tag},
+		"notebook_id": noteBook,
+	})
+
+	var template struct {
+		Name string   `json:"name"`
+		Text string   `json:"text"`
+		Tags []string `json:"tags"`
+	}
+	api.ok(http.MethodGet, "/templates/"+id, nil, &template)
+	if template.Name != "Meeting" || len(template.Tags) != 1 || template.Tags[0] != tag {
+		t.Fatalf("created template: %+v", template)
+	}
+
+	api.expect(http.MethodPost, "/templates", map[string]any{"text": "no name"}, http.StatusUnprocessableEntity)
+	api.expect(http.MethodPost, "/templates", map[string]any{"name": "Bad", "tags": []string{"000000000000000000000001"}}, http.StatusBadRequest)
+
+	api.ok(http.MethodPut, "/templates/"+id, map[string]any{"text": "Notes for {{date}}"}, nil)
+	api.ok(http.MethodGet, "/templates/"+id, nil, &template)
+	if template.Name != "Meeting" || template.Text != "Notes for {{date}}" {
+		t.Fatalf("updated template: %+v", template)
+	}
+
+	note := api.note(api.create("/notes", map[string]any{"name": "Standup", "template_id": id}))
+	if note.Color != "#00ff00" || note.NoteBookID != noteBook || len(note.Tags) != 1 || note.Tags[0].ID != tag {
+		t.Fatalf("note from template: %+v", note)
+	}
+	if !strings.HasPrefix(note.Text, "Notes for ") || strings.Contains(note.Text, "{{") {
+		t.Fatalf("note text from template: %q", note.Text)
+	}
+
+	var templates []map[string]any
+	api.ok(http.MethodGet, "/templates", nil, &templates)
+	if len(templates) != 1 {
+		t.Fatalf("templates: %+v", templates)
+	}
+
+	//Шаблоны видны только владельцу
+	other := newTestAPI(t)
+	other.expect(http.MethodPost, "/notes", map[string]any{"template_id": id}, http.StatusUnauthorized)
+	api.logout()
+	api.expect(http.MethodGet, "/templates/"+id, nil, http.StatusUnauthorized)
+	api.login("b@example.com")
+	api.expect(http.MethodGet, "/templates/"+id, nil, http.StatusNotFound)
+	api.expect(http.MethodPost, "/notes", map[string]any{"template_id": id}, http.StatusBadRequest)
+	api.expect(http.MethodPut, "/templates/"+id, map[string]any{"name": "Mine"}, http.StatusNotFound)
+	api.expect(http.MethodDelete, "/templates/"+id, nil, http.StatusNotFound)
+}
+
+// TestTemplateReferences - удаление и слияние тегов и удаление блокнота меняют и шаблоны
+func TestTemplateReferences(t *testing.T) {
+	api := newTestAPI(t)
+	api.login("a@example.com")
+
+	noteBook := api.create("/notebooks", map[string]any{"name": "Meetings"})
+	source := api.create("/tags", map[string]any{"name": "meeting"})
+	target := api.create("/tags", map[string]any{"name": "call"})
+	other := api.create("/tags", map[string]any{"name": "work"})
+
+	id := api.create("/templates", map[string]any{
+		"name":        "Meeting",
+		"tags":        []string{source, other},
+		"notebook_id": noteBook,
+	})
+
+	var template struct {
+		Tags       []string `json:"tags"`
+		NoteBookID string   `json:"notebook_id"`
+	}
+
+	api.ok(http.MethodPost, "/tags/"+source+"/merge", map[string]any{"target_id": target}, nil)
+	api.ok(http.MethodGet, "/templates/"+id, nil, &template)
+	if strings.Join(template.Tags, ",") != other+","+target {
+		t.Fatalf("template tags after merge: %v", template.Tags)
+	}
+
+	api.ok(http.MethodDelete, "/tags/"+other, nil, nil)
+	api.ok(http.MethodDelete, "/notebooks/"+noteBook, nil, nil)
+
+	template.Tags, template.NoteBookID = nil, ""
+	api.ok(http.MethodGet, "/templates/"+id, nil, &template)
+	if strings.Join(template.Tags, ",") != target || template.NoteBookID == noteBook {
+		t.Fatalf("template after deleting tag and notebook: %+v", template)
+	}
+
+	note := api.note(api.create("/notes", map[string]any{"template_id": id}))
+	if note.NoteBookID == noteBook || len(note.Tags) != 1 || note.Tags[0].ID != target {
+		t.Fatalf("note from template: %+v", note)
+	}
+}
+
+// failingTemplates - шаблоны, чтение которых падает
+type failingTemplates struct {
+	repository.TemplateRepo
+}
+
+func (failingTemplates) GetTemplateByID(ctx context.Context, id string) (model.Template, error) {
+	return model.Template{}, errors.New("templates are unavailable")
+}
+
+// TestTemplateLookupError - ошибка базы при чтении шаблона не выдается за неверный template_id
+func TestTemplateLookupError(t *testing.T) {
+	repos := newRepos()
+	repos.Templates = failingTemplates{TemplateRepo: repos.Templates}
+	api := newTestAPIWith(t, repos)
+	api.login("a@example.com")
+
+	api.expect(http.MethodPost, "/notes", map[string]any{"template_id": "000000000000000000000001"}, http.StatusInternalServerError)
+}
+
+func TestTemplateDelete(t *testing.T) {
+	api := newTestAPI(t)
+	api.login("a@example.com")
+
+	id := api.create("/templates", map[string]any{"name": "Short"})
+
+	var deleted int
+	api.ok(http.MethodDelete, "/templates/"+id, nil, &deleted)
+	if deleted != 1 {
+		t.Fatalf("delete removed %d templates", deleted)
+	}
+	api.expect(http.MethodGet, "/templates/"+id, nil, http.StatusNotFound)
+}
+
+func TestDailyNotes(t *testing.T) {
+	api := newTestAPI(t)
+
+	api.expect(http.MethodGet, "/notes/daily/2025-03-14", nil, http.StatusUnauthorized)
+
+	api.login("a@example.com")
+
+	//Первые записи, открытые одновременно, попадают в один блокнот журнала
+	var wg sync.WaitGroup
+	for _, day := range []string{"2025-02-01", "2025-02-02", "2025-02-03", "2025-02-04"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if status, _ := api.raw(http.MethodGet, "/notes/daily/"+day, nil); status != http.StatusOK {
+				t.Errorf("daily note %s: status %d", day, status)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var first, second noteView
+	api.ok(http.MethodGet, "/notes/daily/2025-03-14", nil, &first)
+	api.ok(http.MethodGet, "/notes/daily/2025-03-14", nil, &second)
+	if first.ID == "" || first.ID != second.ID {
+		t.Fatalf("daily note is not reused: %q and %q", first.ID, second.ID)
+	}
+	if first.Name != "2025-03-14" || first.JournalDate != "2025-03-14" || first.Text != "# 2025-03-14" {
+		t.Fatalf("daily note: %+v", first)
+	}
+
+	api.ok(http.MethodGet, "/notes/daily/2025-03-02", nil, nil)
+	api.ok(http.MethodGet, "/notes/daily/2025-04-01", nil, nil)
+
+	var noteBooks []noteBookView
+	api.ok(http.MethodGet, "/notebooks", nil, &noteBooks)
+	if len(noteBooks) != 1 || noteBooks[0].Name != "Journal" || first.NoteBookID != noteBooks[0].ID {
+		t.Fatalf("journal notebook: %+v", noteBooks)
+	}
+
+	var calendar struct {
+		Month string   `json:"month"`
+		Days  []string `json:"days"`
+	}
+	api.ok(http.MethodGet, "/notes/daily/calendar/2025-03", nil, &calendar)
+	if calendar.Month != "2025-03" || strings.Join(calendar.Days, ",") != "2025-03-02,2025-03-14" {
+		t.Fatalf("calendar: %+v", calendar)
+	}
+
+	//Запись из корзины пропадает из календаря и возвращается, когда ее открывают снова
+	var trashed noteView
+	api.ok(http.MethodGet, "/notes/daily/2025-03-02", nil, &trashed)
+	api.ok(http.MethodDelete, "/notes/trash/"+trashed.ID, nil, nil)
+	api.ok(http.MethodGet, "/notes/daily/calendar/2025-03", nil, &calendar)
+	if strings.Join(calendar.Days, ",") != "2025-03-14" {
+		t.Fatalf("calendar with trashed note: %+v", calendar)
+	}
+
+	var restored noteView
+	api.ok(http.MethodGet, "/notes/daily/2025-03-02", nil, &restored)
+	if restored.ID != trashed.ID || restored.IsDeleted {
+		t.Fatalf("daily note from trash: %+v", restored)
+	}
+	api.ok(http.MethodGet, "/notes/daily/calendar/2025-03", nil, &calendar)
+	if strings.Join(calendar.Days, ",") != "2025-03-02,2025-03-14" {
+		t.Fatalf("calendar after restore: %+v", calendar)
+	}
+
+	api.expect(http.MethodGet, "/notes/daily/14-03-2025", nil, http.StatusBadRequest)
+	api.expect(http.MethodGet, "/notes/daily/calendar/2025-13", nil, http.StatusBadRequest)
+
+	//Журнал у каждого пользователя свой
+	api.login("b@example.com")
+	api.ok(http.MethodGet, "/notes/daily/calendar/2025-03", nil, &calendar)
+	if len(calendar.Days) != 0 {
+		t.Fatalf("calendar of another user: %+v", calendar)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	api := newTestAPI(t)
+
+	status, _ := api.raw(http.MethodGet, "/export", nil)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("export without auth: status %d", status)
+	}
+
+	api.login("a@example.com")
+
+	noteBook := api.create("/notebooks", map[string]any{"name": "Work", "is_active": true})
+	api.create("/tags", map[string]any{"name": "todo", "color": "#ff0000"})
+	a := api.create("/notes", map[string]any{"name": "Plan", "text": "step one"})
+	api.ok(http.MethodPut, "/notes/notebook/"+a, map[string]any{"notebook_id": noteBook}, nil)
+	api.ok(http.MethodPut, "/notes/tag/"+a, map[string]any{"tag_name": "todo"}, nil)
+	api.create("/notes", map[string]any{"name": "Loose", "text": "no notebook"})
+
+	status, archive := api.raw(http.MethodGet, "/export", nil)
+	if status != http.StatusOK {
+		t.Fatalf("export: status %d", status)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []string{}
+	for _, file := range reader.File {
+		files = append(files, file.Name)
+	}
+	if strings.Join(files, ",") != "notebooks.yaml,tags.yaml,Loose.md,Work/Plan.md" {
+		t.Fatalf("exported files: %v", files)
+	}
+
+	restored := newTestAPI(t)
+	restored.expect(http.MethodPost, "/import", archive, http.StatusUnauthorized)
+	restored.login("a@example.com")
+	restored.expect(http.MethodPost, "/import?format=pdf", archive, http.StatusBadRequest)
+	restored.expect(http.MethodPost, "/import", "not a zip", http.StatusBadRequest)
+
+	var result struct {
+		Total    int `json:"total"`
+		Imported int `json:"imported"`
+	}
+	restored.ok(http.MethodPost, "/import", archive, &result)
+	if result.Total != 2 || result.Imported != 2 {
+		t.Fatalf("import result: %+v", result)
+	}
+
+	notes := restored.notes("/notes")
+	assertNames(t, "imported", notes, "Loose", "Plan")
+	plan := notes[1]
+	if plan.Text != "step one" || len(plan.Tags) != 1 || plan.Tags[0].Name != "todo" {
+		t.Fatalf("imported note: %+v", plan)
+	}
+
+	var noteBookView noteBookView
+	restored.ok(http.MethodGet, "/notebooks/"+plan.NoteBookID, nil, &noteBookView)
+	if noteBookView.Name != "Work" {
+		t.Fatalf("imported notebook: %+v", noteBookView)
+	}
+
+	enex := `<?xml version="1.0" encoding="UTF-8"?>
+<en-export><note><title>Evernote</title><content><![CDATA[<en-note><div>from enex</div></en-note>]]></content><tag>todo</tag></note></en-export>`
+	restored.ok(http.MethodPost, "/import?format=enex&notebook=Inbox", enex, &result)
+	if result.Imported != 1 {
+		t.Fatalf("enex import result: %+v", result)
+	}
+
+	var found []noteView
+	restored.ok(http.MethodPost, "/notes/tag", map[string]any{"tags": []string{"todo"}}, &found)
+	assertNames(t, "imported by tag", found, "Plan", "Evernote")
+}
+
+// TestImportZipLimits - файл, который распаковывается больше допустимого, не читается целиком
+// и попадает в результат как ошибка этого файла
+func TestImportZipLimits(t *testing.T) {
+	api := newTestAPI(t)
+	api.login("a@example.com")
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, size := range map[string]int{"small.md": 10, "bomb.md": 5 << 20} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(bytes.Repeat([]byte("a"), size))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var result dto.ImportResult
+	api.ok(http.MethodPost, "/import", buf.Bytes(), &result)
+	if result.Total != 2 || result.Imported != 1 {
+		t.Fatalf("import result: %+v", result)
+	}
+	for _, item := range result.Items {
+		if item.File == "bomb.md" && (item.Status != dto.ImportStatusFailed || !strings.Contains(item.Error, "larger than 4 MB")) {
+			t.Fatalf("oversized file: %+v", item)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/client"
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+)
+
+func pullSync(t *testing.T, c *client.Client, token string) client.SyncPull {
+	t.Helper()
+
+	pull, err := c.GetSync(context.Background(), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pull
+}
+
+func pushSync(t *testing.T, c *client.Client, changes ...client.SyncChange) []client.SyncResult {
+	t.Helper()
+
+	results, err := c.PushSync(context.Background(), client.SyncPushRequest{Changes: changes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(changes) {
+		t.Fatalf("results %+v for %d changes", results, len(changes))
+	}
+
+	return results
+}
+
+func syncNote(pull client.SyncPull, id string) *client.SyncNote {
+	for i := range pull.Notes {
+		if pull.Notes[i].ID == id {
+			return &pull.Notes[i]
+		}
+	}
+
+	return nil
+}
+
+func TestSyncPull(t *testing.T) {
+	api := newTestAPI(t)
+	ctx := context.Background()
+
+	api.expectCode(http.MethodGet, "/sync", nil, http.StatusUnauthorized, dto.ErrorCodeUnauthorized)
+
+	alice := loginClient(t, api, "alice@example.com")
+	bob := loginClient(t, api, "bob@example.com")
+
+	noteBookID, err := alice.CreateNoteBook(ctx, client.NoteBookRequest{Name: "Work"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagID, err := alice.CreateTag(ctx, client.TagRequest{Name: "todo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	noteID, err := alice.CreateNote(ctx, client.NoteRequest{Name: "Plan", NotebookID: noteBookID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = alice.AddTagToNote(ctx, noteID, client.NoteRequest{TagID: tagID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobNoteID, err := bob.CreateNote(ctx, client.NoteRequest{Name: "Bob's"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Без token приходят все данные пользователя, чужие заметки не приходят
+	full := pullSync(t, alice, "")
+	if !full.Full || full.HasMore || len(full.Notes) != 1 || len(full.Notebooks) != 1 || len(full.Tags) != 1 || len(full.Deleted) != 0 {
+		t.Fatalf("full pull %+v", full)
+	}
+	note := syncNote(full, noteID)
+	if note == nil || note.Rev == 0 || len(note.Tags) != 1 || note.Tags[0] != tagID || note.NotebookID != noteBookID {
+		t.Fatalf("full pull note %+v", full.Notes)
+	}
+	if full.Notebooks[0].Rev == 0 || full.Tags[0].Rev == 0 {
+		t.Fatalf("full pull revs %+v %+v", full.Notebooks, full.Tags)
+	}
+
+	_, err = alice.GetSync(ctx, "not-a-token")
+	expectAPIError(t, err, http.StatusBadRequest, "")
+
+	//Token из другой базы больше последнего номера в журнале: клиент получает все данные заново
+	last, _ := strconv.Atoi(full.Token)
+	if stale := pullSync(t, alice, strconv.Itoa(last+1000)); !stale.Full || len(stale.Notes) != 1 {
+		t.Fatalf("pull with unknown token %+v", stale)
+	}
+
+	empty := pullSync(t, alice, full.Token)
+	if empty.Full || empty.Token != full.Token || len(empty.Notes)+len(empty.Notebooks)+len(empty.Tags)+len(empty.Deleted) != 0 {
+		t.Fatalf("pull without changes %+v", empty)
+	}
+
+	_, err = alice.UpdateNote(ctx, noteID, client.NoteRequest{Text: "updated"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = bob.UpdateNote(ctx, bobNoteID, client.NoteRequest{Text: "not for alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delta := pullSync(t, alice, full.Token)
+	note = syncNote(delta, noteID)
+	if delta.Full || len(delta.Notes) != 1 || note == nil || note.Text != "updated" || note.Rev <= full.Notes[0].Rev {
+		t.Fatalf("delta pull %+v", delta)
+	}
+
+	//Жесткое удаление приходит надгробием
+	_, err = alice.DeleteNote(ctx, noteID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleted := pullSync(t, alice, delta.Token)
+	if len(deleted.Notes) != 0 || len(deleted.Deleted) != 1 || deleted.Deleted[0].ID != noteID || deleted.Deleted[0].Kind != "note" {
+		t.Fatalf("pull after delete %+v", deleted)
+	}
+
+	//Удаление блокнота отвязывает его заметки: они приходят вместе с надгробием блокнота
+	movedID, err := alice.CreateNote(ctx, client.NoteRequest{Name: "In notebook", NotebookID: noteBookID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := pullSync(t, alice, deleted.Token).Token
+
+	_, err = alice.DeleteNoteBook(ctx, noteBookID, "unlink", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cascade := pullSync(t, alice, token)
+	moved := syncNote(cascade, movedID)
+	if moved == nil || moved.NotebookID == noteBookID || len(cascade.Deleted) != 1 || cascade.Deleted[0].Kind != "notebook" {
+		t.Fatalf("pull after notebook delete %+v", cascade)
+	}
+
+	//Удаление тега снимает его с заметок
+	_, err = alice.AddTagToNote(ctx, movedID, client.NoteRequest{TagID: tagID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token = pullSync(t, alice, cascade.Token).Token
+
+	_, err = alice.DeleteTag(ctx, tagID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	untagged := pullSync(t, alice, token)
+	moved = syncNote(untagged, movedID)
+	if moved == nil || len(moved.Tags) != 0 || len(untagged.Deleted) != 1 || untagged.Deleted[0].ID != tagID {
+		t.Fatalf("pull after tag delete %+v", untagged)
+	}
+}
+
+func TestSyncPullPages(t *testing.T) {
+	api := newTestAPI(t)
+
+	alice := loginClient(t, api, "alice@example.com")
+	token := pullSync(t, alice, "").Token
+
+	changes := make([]client.SyncChange, 500)
+	for i := range changes {
+		changes[i] = client.SyncChange{Kind: "note", Op: "create", Note: &client.SyncNoteData{Name: "Note " + strconv.Itoa(i)}}
+	}
+	pushSync(t, alice, changes...)
+	pushSync(t, alice, changes[0])
+
+	first := pullSync(t, alice, token)
+	if !first.HasMore || len(first.Notes) != 500 {
+		t.Fatalf("first page: has_more %v, %d notes", first.HasMore, len(first.Notes))
+	}
+
+	second := pullSync(t, alice, first.Token)
+	if second.HasMore || len(second.Notes) != 1 {
+		t.Fatalf("second page: has_more %v, %d notes", second.HasMore, len(second.Notes))
+	}
+}
+
+func TestSyncPush(t *testing.T) {
+	api := newTestAPI(t)
+	ctx := context.Background()
+
+	api.expectCode(http.MethodPost, "/sync", map[string]any{"changes": []any{}}, http.StatusUnauthorized, dto.ErrorCodeUnauthorized)
+
+	alice := loginClient(t, api, "alice@example.com")
+	bob := loginClient(t, api, "bob@example.com")
+
+	_, err := alice.PushSync(ctx, client.SyncPushRequest{})
+	expectAPIError(t, err, http.StatusUnprocessableEntity, "changes")
+
+	tagID, err := alice.CreateTag(ctx, client.TagRequest{Name: "synced"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobNoteID, err := bob.CreateNote(ctx, client.NoteRequest{Name: "Bob's"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pinned := true
+	results := pushSync(t, alice,
+		client.SyncChange{Kind: "notebook", Op: "create", ClientID: "nb-1", Notebook: &client.NoteBookRequest{Name: "Offline"}},
+		client.SyncChange{Kind: "note", Op: "create", ClientID: "n-1", Note: &client.SyncNoteData{
+			Name: "Written offline", Tags: []string{tagID, tagID}, IsPinned: &pinned,
+		}},
+		client.SyncChange{Kind: "note", Op: "create", ClientID: "n-2", Note: &client.SyncNoteData{Name: " "}},
+		client.SyncChange{Kind: "note", Op: "create", ClientID: "n-3", Note: &client.SyncNoteData{Name: "Bad tag", Tags: []string{"665f1c2e8b3a4d0012345678"}}},
+		client.SyncChange{Kind: "note", Op: "update", ID: bobNoteID, Note: &client.SyncNoteData{Name: "Stolen"}},
+		client.SyncChange{Kind: "reminder", Op: "create"},
+	)
+
+	noteBookID := results[0].ID
+	if r := results[0]; r.Status != "ok" || r.ClientID != "nb-1" || r.ID == "" || r.Rev == 0 {
+		t.Fatalf("notebook create %+v", r)
+	}
+	created := results[1]
+	if created.Status != "ok" || created.ClientID != "n-1" || created.ID == "" || created.Rev == 0 {
+		t.Fatalf("note create %+v", created)
+	}
+	for i, want := range []string{"invalid", "invalid", "not_found", "invalid"} {
+		if r := results[i+2]; r.Status != want || r.Error == "" {
+			t.Fatalf("result %d: %+v, want %s", i+2, r, want)
+		}
+	}
+
+	note := syncNote(pullSync(t, alice, ""), created.ID)
+	if note == nil || len(note.Tags) != 1 || note.IsPinned == nil || !*note.IsPinned || note.Rev != created.Rev {
+		t.Fatalf("created note %+v", note)
+	}
+
+	//Изменение с актуальной ревизией применяется и получает новую ревизию
+	trashed := true
+	results = pushSync(t, alice, client.SyncChange{
+		Kind: "note", Op: "update", ID: created.ID, Rev: created.Rev,
+		Note: &client.SyncNoteData{Name: "Edited offline", Text: "text", NotebookID: noteBookID, IsDeleted: &trashed},
+	})
+	updated := results[0]
+	if updated.Status != "ok" || updated.Rev <= created.Rev {
+		t.Fatalf("note update %+v", updated)
+	}
+
+	var view noteView
+	api.ok(http.MethodPost, "/users/login", map[string]any{"email": "alice@example.com", "password": "secret-password"}, nil)
+	api.ok(http.MethodGet, "/notes/"+created.ID, nil, &view)
+	if view.Name != "Edited offline" || view.NoteBookID != noteBookID || !view.IsDeleted || !view.IsPinned || len(view.Tags) != 0 {
+		t.Fatalf("note after update %+v", view)
+	}
+
+	//Заметку изменили на сервере после ревизии клиента: изменение не применяется, клиент получает текущую версию
+	_, err = alice.UpdateNote(ctx, created.ID, client.NoteRequest{Text: "server side"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results = pushSync(t, alice,
+		client.SyncChange{Kind: "note", Op: "update", ID: created.ID, Rev: updated.Rev, Note: &client.SyncNoteData{Name: "Lost"}},
+		client.SyncChange{Kind: "note", Op: "delete", ID: created.ID, Rev: updated.Rev},
+	)
+	for _, r := range results {
+		if r.Status != "conflict" || r.Note == nil || r.Note.Text != "server side" || r.Rev <= updated.Rev || r.Rev != r.Note.Rev {
+			t.Fatalf("conflict %+v", r)
+		}
+	}
+
+	removed := pushSync(t, alice, client.SyncChange{Kind: "note", Op: "delete", ID: created.ID, Rev: results[0].Rev})[0]
+	if removed.Status != "ok" || removed.Deleted == nil || !*removed.Deleted {
+		t.Fatalf("note delete %+v", removed)
+	}
+	api.expect(http.MethodGet, "/notes/"+created.ID, nil, http.StatusNotFound)
+
+	//Повторное удаление проходит, изменение удаленной заметки - конфликт
+	results = pushSync(t, alice,
+		client.SyncChange{Kind: "note", Op: "delete", ID: created.ID, Rev: created.Rev},
+		client.SyncChange{Kind: "note", Op: "update", ID: created.ID, Rev: removed.Rev, Note: &client.SyncNoteData{Name: "Zombie"}},
+	)
+	if results[0].Status != "ok" || results[1].Status != "conflict" || results[1].Deleted == nil || !*results[1].Deleted {
+		t.Fatalf("changes of deleted note %+v", results)
+	}
+
+	//Теги и блокноты меняются тем же способом
+	full := pullSync(t, alice, "")
+	results = pushSync(t, alice,
+		client.SyncChange{Kind: "tag", Op: "update", ID: tagID, Rev: full.Tags[0].Rev, Tag: &client.TagRequest{Name: "renamed"}},
+		client.SyncChange{Kind: "notebook", Op: "delete", ID: full.Notebooks[0].ID, Rev: full.Notebooks[0].Rev},
+	)
+	if results[0].Status != "ok" || results[1].Status != "ok" {
+		t.Fatalf("tag and notebook changes %+v", results)
+	}
+
+	delta := pullSync(t, alice, full.Token)
+	if len(delta.Tags) != 1 || delta.Tags[0].Name != "renamed" || len(delta.Deleted) != 1 || delta.Deleted[0].Kind != "notebook" {
+		t.Fatalf("pull after push %+v", delta)
+	}
+}
+
+// failingSync отказывает в записи журнала, пока установлен fail
+type failingSync struct {
+	repository.SyncRepo
+	fail *atomic.Bool
+}
+
+func (f failingSync) RecordChange(ctx context.Context, change model.SyncChange) (int64, error) {
+	if f.fail.Load() {
+		return 0, errors.New("sync log is unavailable")
+	}
+	return f.SyncRepo.RecordChange(ctx, change)
+}
+
+// TestSyncRecordFailure - изменение, которое не удалось записать в журнал, не пропадает: pull отвечает
+// ошибкой, пока запись не пройдет, а затем отдает изменение
+func TestSyncRecordFailure(t *testing.T) {
+	var fail atomic.Bool
+	repos := newRepos()
+	repos.Sync = failingSync{SyncRepo: repos.Sync, fail: &fail}
+
+	api := newTestAPIWith(t, repos)
+	ctx := context.Background()
+	alice := loginClient(t, api, "alice@example.com")
+
+	token := pullSync(t, alice, "").Token
+
+	fail.Store(true)
+	noteID, err := alice.CreateNote(ctx, client.NoteRequest{Name: "Plan"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = alice.GetSync(ctx, token)
+	expectAPIError(t, err, http.StatusInternalServerError, "")
+
+	fail.Store(false)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		pull, err := alice.GetSync(ctx, token)
+		if err == nil {
+			if syncNote(pull, noteID) == nil {
+				t.Fatalf("pull after recovery %+v", pull)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pull after recovery: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,108 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/app"
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+)
+
+// assertFields проверяет, что 422 перечисляет ровно эти поля с этими правилами
+func assertFields(t *testing.T, env envelope, want ...string) {
+	t.Helper()
+
+	var got []string
+	for _, f := range env.Fields {
+		got = append(got, f.Field+":"+f.Rule)
+	}
+
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("fields %v, want %v", got, want)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	api := newTestAPI(t)
+
+	env := api.expectCode(http.MethodPost, "/notes", map[string]any{"text": "no name", "color": "red"}, http.StatusUnprocessableEntity, dto.ErrorCodeInvalidFields)
+	assertFields(t, env, "name:required_without", "color:color")
+
+	env = api.expectCode(http.MethodPost, "/notes", map[string]any{"name": "Note", "colour": "#fff"}, http.StatusBadRequest, dto.ErrorCodeValidation)
+	if !strings.Contains(env.Error, "colour") {
+		t.Fatalf("unknown field error: %q", env.Error)
+	}
+
+	api.expectCode(http.MethodPost, "/notes", `{"name": "A"} {"name": "B"}`, http.StatusBadRequest, dto.ErrorCodeValidation)
+	api.expectCode(http.MethodPost, "/notes", map[string]any{"name": strings.Repeat("x", 2<<20)}, http.StatusRequestEntityTooLarge, dto.ErrorCodeTooLarge)
+
+	env = api.expectCode(http.MethodPost, "/notes", map[string]any{"name": strings.Repeat("я", 201), "text": strings.Repeat("x", 100001)}, http.StatusUnprocessableEntity, dto.ErrorCodeInvalidFields)
+	assertFields(t, env, "name:max", "text:max")
+
+	id := api.create("/notes", map[string]any{"name": strings.Repeat("я", 200), "color": "#1E90ff"})
+
+	//В частичном обновлении пустое имя означает "не менять", а формат по-прежнему проверяется
+	api.ok(http.MethodPut, "/notes/"+id, map[string]any{"text": "updated"}, nil)
+	env = api.expect(http.MethodPut, "/notes/"+id, map[string]any{"color": "#12345"}, http.StatusUnprocessableEntity)
+	assertFields(t, env, "color:color")
+
+	env = api.expect(http.MethodPost, "/notebooks", map[string]any{"name": "  ", "description": strings.Repeat("d", 1001)}, http.StatusUnprocessableEntity)
+	assertFields(t, env, "name:required", "description:max")
+	api.ok(http.MethodPut, "/notebooks/"+api.create("/notebooks", map[string]any{"name": "Work"}), map[string]any{"description": "desc"}, nil)
+
+	env = api.expect(http.MethodPost, "/tags", map[string]any{"color": "blue"}, http.StatusUnprocessableEntity)
+	assertFields(t, env, "name:required", "color:color")
+
+	env = api.expect(http.MethodPost, "/users/register", map[string]any{"email": "Alice <a@example.com>", "password": strings.Repeat("p", 73)}, http.StatusUnprocessableEntity)
+	assertFields(t, env, "email:email", "password:max")
+	api.expect(http.MethodPost, "/users/register", map[string]any{"email": "a@example.com", "password": strings.Repeat("я", 72)}, http.StatusBadRequest)
+}
+
+// TestRequestContext - запрос, который отменил клиент или у которого истек срок, не доходит до хранилища
+func TestRequestContext(t *testing.T) {
+	repos := newRepos()
+	router := app.NewRouter(testConfig(), repos)
+	api := newTestAPIWith(t, repos)
+
+	noteBook := api.create("/notebooks", map[string]any{"name": "Work"})
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		path   string
+		status int
+		code   string
+	}{
+		{"canceled read", canceled, http.MethodGet, "/notes", 499, dto.ErrorCodeCanceled},
+		{"expired read", expired, http.MethodGet, "/notes", http.StatusGatewayTimeout, dto.ErrorCodeTimeout},
+		{"canceled transaction", canceled, http.MethodDelete, "/notebooks/" + noteBook, 499, dto.ErrorCodeCanceled},
+		{"expired transaction", expired, http.MethodDelete, "/notebooks/" + noteBook, http.StatusGatewayTimeout, dto.ErrorCodeTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(tt.ctx, tt.method, "/api/v1"+tt.path, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			var env envelope
+			json.Unmarshal(rec.Body.Bytes(), &env)
+			if rec.Code != tt.status || env.Code != tt.code {
+				t.Fatalf("%s %s: status %d, code %q, want %d %q", tt.method, tt.path, rec.Code, env.Code, tt.status, tt.code)
+			}
+		})
+	}
+
+	api.ok(http.MethodGet, "/notebooks/"+noteBook, nil, nil)
+}
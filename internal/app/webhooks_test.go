@@ -0,0 +1,343 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/client"
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+)
+
+type hookRequest struct {
+	header  http.Header
+	body    []byte
+	payload client.WebhookPayload
+}
+
+// webhookReceiver - адрес вебхука, который отвечает статусами statuses по очереди, а затем 200
+func webhookReceiver(t *testing.T, statuses ...int) (string, <-chan hookRequest) {
+	t.Helper()
+
+	ch := make(chan hookRequest, 16)
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		req := hookRequest{header: r.Header, body: body}
+		json.Unmarshal(body, &req.payload)
+		ch <- req
+
+		mu.Lock()
+		status := http.StatusOK
+		if len(statuses) > 0 {
+			status, statuses = statuses[0], statuses[1:]
+		}
+		mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL, ch
+}
+
+func expectHook(t *testing.T, ch <-chan hookRequest, event string) hookRequest {
+	t.Helper()
+
+	select {
+	case req := <-ch:
+		if req.payload.Event != event {
+			t.Fatalf("webhook got %s, want %s", req.payload.Event, event)
+		}
+		return req
+	case <-time.After(5 * time.Second):
+		t.Fatalf("webhook got no %s", event)
+		return hookRequest{}
+	}
+}
+
+// waitDeliveries ждет, пока в журнале вебхука появится n попыток: журнал пишется после ответа адреса
+func waitDeliveries(t *testing.T, c *client.Client, id string, n int) []client.WebhookDelivery {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deliveries, err := c.GetWebhookDeliveries(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deliveries) >= n {
+			return deliveries
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("webhook %s: %d deliveries, want %d", id, len(deliveries), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func expectAPIError(t *testing.T, err error, status int, field string) {
+	t.Helper()
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+		t.Fatalf("error %v, want status %d", err, status)
+	}
+	if field != "" && (len(apiErr.Fields) == 0 || apiErr.Fields[0].Field != field) {
+		t.Fatalf("error fields %+v, want %s", apiErr.Fields, field)
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	api := newTestAPI(t)
+	ctx := context.Background()
+
+	api.expectCode(http.MethodGet, "/webhooks", nil, http.StatusUnauthorized, dto.ErrorCodeUnauthorized)
+
+	alice := loginClient(t, api, "alice@example.com")
+	bob := loginClient(t, api, "bob@example.com")
+
+	url, received := webhookReceiver(t)
+	secret := "0123456789abcdef"
+
+	_, err := alice.CreateWebhook(ctx, client.WebhookRequest{URL: url, Events: []string{"note.unknown"}})
+	expectAPIError(t, err, http.StatusUnprocessableEntity, "events")
+	_, err = alice.CreateWebhook(ctx, client.WebhookRequest{URL: "ftp://example.com"})
+	expectAPIError(t, err, http.StatusUnprocessableEntity, "url")
+	_, err = alice.CreateWebhook(ctx, client.WebhookRequest{URL: url, Secret: "short"})
+	expectAPIError(t, err, http.StatusUnprocessableEntity, "secret")
+
+	hookID, err := alice.CreateWebhook(ctx, client.WebhookRequest{
+		URL:    url,
+		Secret: secret,
+		Events: []string{"note.created", "note.tagged"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hooks, err := alice.GetWebhooks(ctx)
+	if err != nil || len(hooks) != 1 || hooks[0].ID != hookID || hooks[0].Secret != secret {
+		t.Fatalf("webhooks %+v, %v", hooks, err)
+	}
+
+	//Чужой вебхук не отличается от несуществующего
+	_, err = bob.GetWebhookByID(ctx, hookID)
+	expectAPIError(t, err, http.StatusNotFound, "")
+	_, err = bob.DeleteWebhook(ctx, hookID)
+	expectAPIError(t, err, http.StatusNotFound, "")
+	_, err = bob.TestWebhook(ctx, hookID)
+	expectAPIError(t, err, http.StatusNotFound, "")
+
+	noteID, err := alice.CreateNote(ctx, client.NoteRequest{Name: "Hooked"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := expectHook(t, received, "note.created")
+	if !client.VerifySignature(secret, req.body, req.header.Get(client.SignatureHeader)) {
+		t.Fatalf("bad signature %q", req.header.Get(client.SignatureHeader))
+	}
+	if client.VerifySignature("another-secret-16", req.body, req.header.Get(client.SignatureHeader)) {
+		t.Fatal("signature accepted with a wrong secret")
+	}
+	if req.header.Get("X-NoteVault-Event") != "note.created" || req.header.Get("X-NoteVault-Delivery") != req.payload.ID {
+		t.Fatalf("headers %v, payload id %s", req.header, req.payload.ID)
+	}
+	if req.payload.NoteID != noteID || req.payload.Note == nil || req.payload.Note.Name != "Hooked" {
+		t.Fatalf("payload %s", req.body)
+	}
+
+	//Изменение не входит в фильтр, а заметки Боба не уходят на вебхук Алисы: следующим приходит note.tagged
+	_, err = alice.UpdateNote(ctx, noteID, client.NoteRequest{Text: "filtered out"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = bob.CreateNote(ctx, client.NoteRequest{Name: "Bob's"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagID, err := alice.CreateTag(ctx, client.TagRequest{Name: "hooked"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = alice.AddTagToNote(ctx, noteID, client.NoteRequest{TagID: tagID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectHook(t, received, "note.tagged")
+
+	//Выключенный вебхук не получает события, но пробное событие отправляется. Пустой список events снимает фильтр
+	api.ok(http.MethodPost, "/users/login", map[string]any{"email": "alice@example.com", "password": "secret-password"}, nil)
+	api.ok(http.MethodPut, "/webhooks/"+hookID, map[string]any{"is_active": false, "events": []string{}}, nil)
+	_, err = alice.UpdateWebhook(ctx, hookID, client.WebhookRequest{Events: []string{"note.bogus"}})
+	expectAPIError(t, err, http.StatusUnprocessableEntity, "events")
+
+	_, err = alice.UpdateNote(ctx, noteID, client.NoteRequest{Text: "inactive"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delivery, err := alice.TestWebhook(ctx, hookID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !delivery.Success || delivery.StatusCode != http.StatusOK || delivery.Event != "ping" || delivery.Attempt != 1 {
+		t.Fatalf("test delivery %+v", delivery)
+	}
+	ping := expectHook(t, received, "ping")
+	if ping.payload.Note != nil || !client.VerifySignature(secret, ping.body, ping.header.Get(client.SignatureHeader)) {
+		t.Fatalf("ping %s", ping.body)
+	}
+
+	hook, err := alice.GetWebhookByID(ctx, hookID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hook.IsActive == nil || *hook.IsActive || len(hook.Events) != 0 {
+		t.Fatalf("webhook after update %+v", hook)
+	}
+
+	deliveries := waitDeliveries(t, alice, hookID, 3)
+	if len(deliveries) != 3 || deliveries[0].Event != "ping" || deliveries[2].Event != "note.created" {
+		t.Fatalf("deliveries %+v", deliveries)
+	}
+
+	api.ok(http.MethodDelete, "/webhooks/"+hookID, nil, nil)
+	api.expectCode(http.MethodGet, "/webhooks/"+hookID+"/deliveries", nil, http.StatusNotFound, dto.ErrorCodeNotFound)
+}
+
+func TestWebhookRetries(t *testing.T) {
+	api := newTestAPI(t)
+	ctx := context.Background()
+
+	alice := loginClient(t, api, "alice@example.com")
+
+	url, received := webhookReceiver(t, http.StatusInternalServerError, http.StatusBadGateway)
+
+	//Без secret сервер генерирует его сам
+	hookID, err := alice.CreateWebhook(ctx, client.WebhookRequest{URL: url})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook, err := alice.GetWebhookByID(ctx, hookID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.Secret) != 64 {
+		t.Fatalf("generated secret %q", hook.Secret)
+	}
+
+	_, err = alice.CreateNote(ctx, client.NoteRequest{Name: "Retried"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Повторы несут то же тело и тот же ID доставки
+	first := expectHook(t, received, "note.created")
+	for range 2 {
+		retry := expectHook(t, received, "note.created")
+		if retry.payload.ID != first.payload.ID || string(retry.body) != string(first.body) {
+			t.Fatalf("retry %s, first %s", retry.body, first.body)
+		}
+		if !client.VerifySignature(hook.Secret, retry.body, retry.header.Get(client.SignatureHeader)) {
+			t.Fatal("bad signature on retry")
+		}
+	}
+
+	deliveries := waitDeliveries(t, alice, hookID, 3)
+	want := []struct {
+		attempt, status int
+		success         bool
+	}{
+		{3, http.StatusOK, true},
+		{2, http.StatusBadGateway, false},
+		{1, http.StatusInternalServerError, false},
+	}
+	for i, w := range want {
+		d := deliveries[i]
+		if d.Attempt != w.attempt || d.StatusCode != w.status || d.Success != w.success || d.DeliveryID != first.payload.ID {
+			t.Fatalf("delivery %d: %+v, want %+v", i, d, w)
+		}
+	}
+
+	//Адрес, который всегда отвечает ошибкой, получает не больше max_attempts попыток
+	failing, failed := webhookReceiver(t, 500, 500, 500, 500, 500)
+	_, err = alice.UpdateWebhook(ctx, hookID, client.WebhookRequest{URL: failing})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = alice.CreateNote(ctx, client.NoteRequest{Name: "Failed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		expectHook(t, failed, "note.created")
+	}
+
+	deliveries = waitDeliveries(t, alice, hookID, 6)
+	if deliveries[0].Attempt != 3 || deliveries[0].Success {
+		t.Fatalf("last delivery %+v", deliveries[0])
+	}
+
+	select {
+	case req := <-failed:
+		t.Fatalf("unexpected attempt %s", req.body)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	delivery, err := alice.TestWebhook(ctx, hookID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Success || delivery.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("test delivery %+v", delivery)
+	}
+}
+
+// TestWebhookPrivateAddress - без allow_private вебхук не доставляется на localhost и внутренние адреса
+func TestWebhookPrivateAddress(t *testing.T) {
+	cfg := testConfig()
+	cfg.Webhooks.AllowPrivate = false
+
+	api := newTestAPIConfig(t, cfg, newRepos())
+	ctx := context.Background()
+	alice := loginClient(t, api, "alice@example.com")
+
+	hit := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit <- struct{}{}
+	}))
+	defer server.Close()
+
+	for _, url := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1), "http://169.254.169.254/latest/meta-data"} {
+		hookID, err := alice.CreateWebhook(ctx, client.WebhookRequest{URL: url})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		delivery, err := alice.TestWebhook(ctx, hookID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if delivery.Success || !strings.Contains(delivery.Error, "webhook address is not allowed") {
+			t.Fatalf("delivery to %s: %+v", url, delivery)
+		}
+	}
+
+	select {
+	case <-hit:
+		t.Fatal("webhook reached a loopback address")
+	default:
+	}
+}
@@ -0,0 +1,387 @@
+package clientgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Generate строит код пакета client по спецификации OpenAPI.
+// Поддерживается только то подмножество OpenAPI, которым описан API NoteVault
+func Generate(specJSON []byte) ([]byte, error) {
+	var s spec
+	err := json.Unmarshal(specJSON, &s)
+	if err != nil {
+		return nil, fmt.Errorf("parsing spec: %w", err)
+	}
+
+	g := generator{}
+	g.printf("// Code generated by clientgen from api/openapi.json. DO NOT EDIT.\n\n")
+	g.printf("package client\n\n")
+	g.printf("import (\n\"context\"\n\"encoding/json\"\n\"io\"\n\"net/http\"\n\"net/url\"\n)\n\n")
+
+	for _, name := range s.Components.Schemas.keys {
+		err := g.schemaType(name, s.Components.Schemas.values[name])
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+
+	for _, path := range s.Paths.keys {
+		operations := s.Paths.values[path]
+		for _, method := range operations.keys {
+			err := g.operation(path, method, operations.values[method])
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+		}
+	}
+
+	code, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, g.buf.Bytes())
+	}
+
+	return code, nil
+}
+
+type spec struct {
+	Paths      ordered[ordered[operation]] `json:"paths"`
+	Components struct {
+		Schemas ordered[*schema] `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Parameters  []parameter          `json:"parameters"`
+	RequestBody *body                `json:"requestBody"`
+	Responses   map[string]*response `json:"responses"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Schema      *schema `json:"schema"`
+}
+
+type body struct {
+	Content ordered[mediaType] `json:"content"`
+}
+
+type response struct {
+	Ref     string             `json:"$ref"`
+	Content ordered[mediaType] `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref         string           `json:"$ref"`
+	Type        string           `json:"type"`
+	Format      string           `json:"format"`
+	Description string           `json:"description"`
+	Nullable    bool             `json:"nullable"`
+	Required    []string         `json:"required"`
+	Properties  ordered[*schema] `json:"properties"`
+	Items       *schema          `json:"items"`
+}
+
+// ordered - JSON-объект с сохранением порядка ключей: от него зависит порядок полей и методов в коде
+type ordered[T any] struct {
+	keys   []string
+	values map[string]T
+}
+
+func (o *ordered[T]) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("expected object, got %v", tok)
+	}
+
+	o.values = map[string]T{}
+	for decoder.More() {
+		tok, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+
+		var value T
+		err = decoder.Decode(&value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+
+		o.keys = append(o.keys, key)
+		o.values[key] = value
+	}
+
+	return nil
+}
+
+type generator struct {
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) schemaType(name string, s *schema) error {
+	if s.Type != "object" {
+		return fmt.Errorf("only object schemas are supported")
+	}
+
+	g.printf("\n")
+	g.comment(name, s.Description)
+	g.printf("type %s struct {\n", name)
+
+	for _, prop := range s.Properties.keys {
+		ps := s.Properties.values[prop]
+		required := contains(s.Required, prop)
+
+		typ, err := goType(ps, required)
+		if err != nil {
+			return fmt.Errorf("%s: %w", prop, err)
+		}
+
+		tag := prop
+		switch {
+		case ps.Nullable:
+			typ = "Nullable[" + typ + "]"
+			tag += ",omitzero"
+		case !required:
+			tag += ",omitempty"
+		}
+
+		if ps.Description != "" {
+			g.printf("// %s\n", ps.Description)
+		}
+		g.printf("%s %s `json:%q`\n", goName(prop, true), typ, tag)
+	}
+
+	g.printf("}\n")
+
+	return nil
+}
+
+func (g *generator) comment(name, description string) {
+	if description != "" {
+		//Описание начинается с маленькой буквы, если это не аббревиатура вроде JSON
+		first, size := utf8.DecodeRuneInString(description)
+		second, _ := utf8.DecodeRuneInString(description[size:])
+		if !unicode.IsUpper(second) {
+			description = string(unicode.ToLower(first)) + description[size:]
+		}
+		g.printf("// %s - %s\n", name, description)
+	}
+}
+
+func goType(s *schema, required bool) (string, error) {
+	if s.Ref != "" {
+		if required {
+			return refName(s.Ref), nil
+		}
+		return "*" + refName(s.Ref), nil
+	}
+
+	switch s.Type {
+	case "string":
+		return "string", nil
+	case "integer":
+		return "int", nil
+	case "boolean":
+		//Необязательный флаг - указатель, чтобы false отличался от "не задан"
+		if required {
+			return "bool", nil
+		}
+		return "*bool", nil
+	case "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("array without items")
+		}
+		item, err := goType(s.Items, true)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "object", "":
+		if s.Properties.keys != nil {
+			return "", fmt.Errorf("inline object schemas are not supported, use components")
+		}
+		return "json.RawMessage", nil
+	default:
+		return "", fmt.Errorf("unsupported type %q", s.Type)
+	}
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func (g *generator) operation(path, method string, op operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("no operationId")
+	}
+
+	ok := op.Responses["200"]
+	if ok == nil || len(ok.Content.keys) != 1 {
+		return fmt.Errorf("expected a single 200 response content type")
+	}
+	resType := ok.Content.keys[0]
+	resSchema := ok.Content.values[resType].Schema
+
+	var args, pathExpr, queryCode []string
+
+	pathExpr = pathExpression(path)
+
+	params := append([]parameter{}, op.Parameters...)
+	sort.SliceStable(params, func(i, j int) bool { return params[i].In == "path" && params[j].In != "path" })
+
+	for _, p := range params {
+		arg := goName(p.Name, false)
+		switch {
+		case p.In == "path":
+			args = append(args, arg+" string")
+		case p.In == "query" && p.Schema.Type == "boolean":
+			args = append(args, arg+" bool")
+			queryCode = append(queryCode, fmt.Sprintf("if %s {\nquery.Set(%q, \"true\")\n}", arg, p.Name))
+		case p.In == "query" && p.Schema.Type == "string":
+			args = append(args, arg+" string")
+			queryCode = append(queryCode, fmt.Sprintf("if %s != \"\" {\nquery.Set(%q, %s)\n}", arg, p.Name, arg))
+		default:
+			return fmt.Errorf("unsupported parameter %s in %s", p.Name, p.In)
+		}
+	}
+
+	contentType := ""
+	if op.RequestBody != nil {
+		if len(op.RequestBody.Content.keys) != 1 {
+			return fmt.Errorf("expected a single request content type")
+		}
+		contentType = op.RequestBody.Content.keys[0]
+		bodySchema := op.RequestBody.Content.values[contentType].Schema
+
+		if bodySchema.Format == "binary" {
+			args = append(args, "body io.Reader")
+		} else {
+			typ, err := goType(bodySchema, true)
+			if err != nil {
+				return err
+			}
+			args = append(args, "body "+typ)
+		}
+	}
+
+	g.printf("\n// %s - %s %s. %s\n", op.OperationID, strings.ToUpper(method), path, op.Summary)
+
+	signature := fmt.Sprintf("func (c *Client) %s(%s)", op.OperationID, strings.Join(append([]string{"ctx context.Context"}, args...), ", "))
+
+	fields := []string{"method: http.Method" + methodName(method), "path: " + strings.Join(pathExpr, " + ")}
+	queryInit := ""
+	if len(queryCode) > 0 {
+		fields = append(fields, "query: query")
+		queryInit = "query := url.Values{}\n" + strings.Join(queryCode, "\n") + "\n\n"
+	}
+	if contentType != "" {
+		fields = append(fields, fmt.Sprintf("contentType: %q, body: body", contentType))
+	}
+	request := "request{" + strings.Join(fields, ", ") + "}"
+
+	switch {
+	case resType == "text/plain":
+		g.printf("%s (string, error) {\n%sreturn c.callText(ctx, %s)\n}\n", signature, queryInit, request)
+	case resSchema.Format == "binary" || resType == "text/event-stream":
+		g.printf("%s (io.ReadCloser, error) {\n%sreturn c.stream(ctx, %s)\n}\n", signature, queryInit, request)
+	case resType == "application/json" && isEnvelope(resSchema):
+		typ, err := goType(resSchema.Properties.values["data"], true)
+		if err != nil {
+			return err
+		}
+		g.printf("%s (%s, error) {\n%svar out %s\nerr := c.call(ctx, %s, &out)\nreturn out, err\n}\n", signature, typ, queryInit, typ, request)
+	case resType == "application/json":
+		g.printf("%s (json.RawMessage, error) {\n%svar out json.RawMessage\nerr := c.callRaw(ctx, %s, &out)\nreturn out, err\n}\n", signature, queryInit, request)
+	default:
+		return fmt.Errorf("unsupported response content type %s", resType)
+	}
+
+	return nil
+}
+
+// isEnvelope - ответ вида {"data": ...}, в который API заворачивает результат
+func isEnvelope(s *schema) bool {
+	return s.Type == "object" && len(s.Properties.keys) == 1 && s.Properties.keys[0] == "data"
+}
+
+func pathExpression(path string) []string {
+	var parts []string
+
+	for path != "" {
+		start := strings.Index(path, "{")
+		if start < 0 {
+			parts = append(parts, fmt.Sprintf("%q", path))
+			break
+		}
+		end := strings.Index(path, "}")
+
+		if start > 0 {
+			parts = append(parts, fmt.Sprintf("%q", path[:start]))
+		}
+		parts = append(parts, "url.PathEscape("+goName(path[start+1:end], false)+")")
+
+		path = path[end+1:]
+	}
+
+	return parts
+}
+
+func methodName(method string) string {
+	return strings.ToUpper(method[:1]) + method[1:]
+}
+
+// initialisms - части имен, которые в Go пишутся заглавными
+var initialisms = map[string]string{"id": "ID", "ids": "IDs", "url": "URL"}
+
+// goName переводит snake_case в имя Go с учетом аббревиатур: notebook_id -> NotebookID, url -> URL
+func goName(name string, exported bool) string {
+	var b strings.Builder
+
+	for i, part := range strings.Split(name, "_") {
+		switch {
+		case i == 0 && !exported:
+			part = strings.ToLower(part[:1]) + part[1:]
+		case initialisms[part] != "":
+			part = initialisms[part]
+		case part != "":
+			part = strings.ToUpper(part[:1]) + part[1:]
+		}
+		b.WriteString(part)
+	}
+
+	return b.String()
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -8,12 +8,24 @@ import (
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+const (
+	StorageMongoDB = "mongodb"
+	StorageSQLite  = "sqlite"
+)
+
+// Storage выбирает хранилище: для mongodb StoragePath - строка подключения, для sqlite - путь к файлу базы.
+// QueryTimeout ограничивает каждый запрос к хранилищу, 0 - без ограничения
 type Config struct {
-	Env         string `yaml:"env" env-default:"local"`
-	StoragePath string `yaml:"storage_path" env-required:"true"`
-	Database    string `yaml:"database"`
-	Collections `yaml:"collections"`
-	HTTPServer  `yaml:"http_server"`
+	Env          string        `yaml:"env" env-default:"local"`
+	Storage      string        `yaml:"storage" env-default:"mongodb"`
+	StoragePath  string        `yaml:"storage_path" env-required:"true"`
+	QueryTimeout time.Duration `yaml:"query_timeout" env-default:"3s"`
+	Database     string        `yaml:"database"`
+	Collections  `yaml:"collections"`
+	HTTPServer   `yaml:"http_server"`
+	GRPCServer   GRPCServer `yaml:"grpc_server"`
+	Journal      `yaml:"journal"`
+	Webhooks     `yaml:"webhooks"`
 }
 
 type Collections struct {
@@ -21,6 +33,15 @@ type Collections struct {
 	NoteBooks string `yaml:"notebooks"`
 	Tags      string `yaml:"tags"`
 	Users     string `yaml:"users"`
+	Templates string `yaml:"templates" env-default:"templates"`
+	Webhooks  string `yaml:"webhooks" env-default:"webhooks"`
+	Sync      string `yaml:"sync" env-default:"sync"`
+	Reminders string `yaml:"reminders" env-default:"reminders"`
+}
+
+// Names возвращает имена всех коллекций приложения, например для резервного копирования
+func (c Collections) Names() []string {
+	return []string{c.Notes, c.NoteBooks, c.Tags, c.Users, c.Templates, c.Webhooks, c.Sync, c.Reminders}
 }
 
 type HTTPServer struct {
@@ -29,6 +50,31 @@ type HTTPServer struct {
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
 }
 
+// GRPCServer - gRPC-сервер для внутренних сервисов, работает рядом с HTTP API на своем порту.
+// Пустой Address отключает его
+type GRPCServer struct {
+	Address string `yaml:"address"`
+}
+
+// Journal - настройки ежедневных заметок. Template - имя шаблона пользователя,
+// Text используется, если такого шаблона у пользователя нет.
+type Journal struct {
+	NoteBook string `yaml:"notebook" env-default:"Journal"`
+	Template string `yaml:"template" env-default:"Journal"`
+	Text     string `yaml:"text" env-default:"# {{date}}"`
+}
+
+// Webhooks - доставка вебхуков. Timeout ограничивает одну попытку, после неудачи попытка повторяется
+// через RetryDelay, 2*RetryDelay, 4*RetryDelay... - всего не больше MaxAttempts попыток.
+// AllowPrivate разрешает адреса localhost и внутренней сети: без него вебхук нельзя направить
+// на сервисы рядом с сервером
+type Webhooks struct {
+	Timeout      time.Duration `yaml:"timeout" env-default:"10s"`
+	MaxAttempts  int           `yaml:"max_attempts" env-default:"5"`
+	RetryDelay   time.Duration `yaml:"retry_delay" env-default:"10s"`
+	AllowPrivate bool          `yaml:"allow_private"`
+}
+
 func Load() *Config {
 	configPath := os.Getenv("CONFIG_PATH")
 	if configPath == "" {
@@ -0,0 +1,29 @@
+package dto
+
+// Машиночитаемые коды ошибок: клиенту не нужно разбирать текст ошибки
+const (
+	ErrorCodeValidation    = "validation"
+	ErrorCodeInvalidFields = "invalid_fields"
+	ErrorCodeInvalidID     = "invalid_id"
+	ErrorCodeUnauthorized  = "unauthorized"
+	ErrorCodeNotFound      = "not_found"
+	ErrorCodeConflict      = "conflict"
+	ErrorCodeTooLarge      = "too_large"
+	ErrorCodeMediaType     = "unsupported_media_type"
+	ErrorCodeTimeout       = "timeout"
+	ErrorCodeCanceled      = "canceled"
+	ErrorCodeInternal      = "internal"
+)
+
+type ErrorResponse struct {
+	Data   interface{}  `json:"data,omitempty"`
+	Error  string       `json:"error"`
+	Code   string       `json:"code"`
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
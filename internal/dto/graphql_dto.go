@@ -0,0 +1,58 @@
+package dto
+
+// GraphQLRequest - тело POST /graphql по GraphQL over HTTP. Extensions принимается, чтобы не отклонять запросы
+// клиентов, которые его присылают, но не используется
+type GraphQLRequest struct {
+	Query         string         `json:"query" validate:"required"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	Extensions    map[string]any `json:"extensions,omitempty"`
+}
+
+// Входные объекты мутаций. Имена полей совпадают с GraphQL-схемой, поэтому ошибки валидации указывают
+// на поле так, как его написал клиент
+type GraphQLNoteInput struct {
+	Name        string   `json:"name" validate:"required,max=200"`
+	Text        string   `json:"text" validate:"max=100000"`
+	Color       string   `json:"color" validate:"color"`
+	NoteBookID  string   `json:"notebookId"`
+	TagIDs      []string `json:"tagIds" validate:"max=100"`
+	IsPinned    *bool    `json:"isPinned"`
+	IsFavourite *bool    `json:"isFavourite"`
+}
+
+type GraphQLNotePatchInput struct {
+	Name        *string  `json:"name" validate:"max=200"`
+	Text        *string  `json:"text" validate:"max=100000"`
+	Color       *string  `json:"color" validate:"color"`
+	Order       *int     `json:"order"`
+	NoteBookID  *string  `json:"notebookId"`
+	TagIDs      []string `json:"tagIds" validate:"max=100"`
+	IsDeleted   *bool    `json:"isDeleted"`
+	IsArchived  *bool    `json:"isArchived"`
+	IsPinned    *bool    `json:"isPinned"`
+	IsFavourite *bool    `json:"isFavourite"`
+}
+
+type GraphQLNoteBookInput struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+	IsActive    *bool  `json:"isActive"`
+}
+
+// RemindAt - время в RFC 3339
+type GraphQLReminderInput struct {
+	NoteID   string `json:"noteId" validate:"required"`
+	Name     string `json:"name" validate:"required,max=100"`
+	Message  string `json:"message" validate:"max=1000"`
+	RemindAt string `json:"remindAt" validate:"required"`
+	Repeat   string `json:"repeat"`
+	IsActive *bool  `json:"isActive"`
+}
+
+const (
+	ReminderRepeatDaily   = "daily"
+	ReminderRepeatWeekly  = "weekly"
+	ReminderRepeatMonthly = "monthly"
+	ReminderRepeatYearly  = "yearly"
+)
@@ -0,0 +1,18 @@
+package dto
+
+// Запросы gRPC для проверки правилами validate. Имена полей совпадают с notevault.proto, поэтому ошибки
+// в google.rpc.BadRequest указывают на поле сообщения. Блокноты и теги проверяются через NoteBookRequest и TagRequest
+type GRPCNoteInput struct {
+	Name       string   `json:"name" validate:"required,max=200"`
+	Text       string   `json:"text" validate:"max=100000"`
+	Color      string   `json:"color" validate:"color"`
+	NoteBookID string   `json:"notebook_id"`
+	TagIDs     []string `json:"tag_ids" validate:"max=100"`
+}
+
+type GRPCNotePatchInput struct {
+	Name   *string  `json:"name" validate:"max=200"`
+	Text   *string  `json:"text" validate:"max=100000"`
+	Color  *string  `json:"color" validate:"color"`
+	TagIDs []string `json:"tag_ids" validate:"max=100"`
+}
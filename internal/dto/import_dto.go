@@ -0,0 +1,30 @@
+package dto
+
+const (
+	ImportFormatMarkdown = "markdown"
+	ImportFormatENEX     = "enex"
+	ImportFormatKeep     = "keep"
+)
+
+const (
+	ImportStatusOK     = "ok"
+	ImportStatusFailed = "failed"
+)
+
+type ImportItem struct {
+	File   string `json:"file"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ImportResult struct {
+	Total    int          `json:"total"`
+	Imported int          `json:"imported"`
+	Items    []ImportItem `json:"items"`
+}
+
+type ImportResponse struct {
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
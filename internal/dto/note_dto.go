@@ -1,23 +1,93 @@
 package dto
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type NoteRequest struct {
-	Name       string             `json:"name,omitempty"`
-	Text       string             `json:"text,omitempty"`
-	Color      string             `json:"color,omitempty"`
+	Name       string             `json:"name,omitempty" validate:"required_without=template_id,max=200"`
+	Text       string             `json:"text,omitempty" validate:"max=100000"`
+	Color      string             `json:"color,omitempty" validate:"color"`
 	Order      int                `json:"order,omitempty"`
 	IsDeleted  *bool              `json:"is_deleted,omitempty"`
 	IsArchived *bool              `json:"is_archived,omitempty"`
 	NoteBookID primitive.ObjectID `json:"notebook_id,omitempty"`
-	TagName    string             `json:"tag_name,omitempty"`
+	TagID      string             `json:"tag_id,omitempty"`
+	TagName    string             `json:"tag_name,omitempty" validate:"max=100"`
+	TemplateID string             `json:"template_id,omitempty"`
+}
+
+// NotePatchRequest - тело PATCH /notes/{id} в формате JSON Merge Patch (RFC 7396):
+// отсутствующее поле не меняется, null очищает его, tags заменяет список тегов целиком
+type NotePatchRequest struct {
+	Name       *string  `json:"name" validate:"max=200"`
+	Text       *string  `json:"text" validate:"max=100000"`
+	Color      *string  `json:"color" validate:"color"`
+	Order      *int     `json:"order"`
+	NoteBookID *string  `json:"notebook_id"`
+	Tags       []string `json:"tags" validate:"max=100"`
+}
+
+const MergePatchContentType = "application/merge-patch+json"
+
+type NoteReorderRequest struct {
+	BeforeID string `json:"before_id,omitempty"`
+	AfterID  string `json:"after_id,omitempty"`
 }
 
 type NoteTagsRequest struct {
-	TagNames []string `json:"tags,omitempty"`
+	TagIDs             []string `json:"tag_ids,omitempty"`
+	TagNames           []string `json:"tags,omitempty"`
+	AnyOf              []string `json:"any_of,omitempty"`
+	AllOf              []string `json:"all_of,omitempty"`
+	NoneOf             []string `json:"none_of,omitempty"`
+	Query              string   `json:"query,omitempty"`
+	IncludeDescendants bool     `json:"include_descendants,omitempty"`
+	NoteBookID         string   `json:"notebook_id,omitempty"`
+	Status             string   `json:"status,omitempty"`
+}
+
+type NoteTag struct {
+	ID    primitive.ObjectID `json:"id"`
+	Name  string             `json:"name,omitempty"`
+	Color string             `json:"color,omitempty"`
+}
+
+type NoteView struct {
+	model.Note
+	Tags []NoteTag `json:"tags,omitempty"`
 }
 
 type NoteResponse struct {
 	Data  interface{} `json:"data,omitempty"`
 	Error string      `json:"error,omitempty"`
 }
+
+type NoteBulkRequest struct {
+	Action     string           `json:"action,omitempty"`
+	IDs        []string         `json:"ids,omitempty"`
+	Filter     *NoteTagsRequest `json:"filter,omitempty"`
+	NoteBookID string           `json:"notebook_id,omitempty"`
+	TagID      string           `json:"tag_id,omitempty"`
+	TagName    string           `json:"tag_name,omitempty" validate:"max=100"`
+	Color      string           `json:"color,omitempty" validate:"color"`
+}
+
+type NoteBulkResult struct {
+	Total     int          `json:"total"`
+	Succeeded int          `json:"succeeded"`
+	Items     []BulkResult `json:"items"`
+}
+
+// BulkResult - итог по одной заметке; статусы те же, что у repository.BulkResult
+type BulkResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type JournalCalendar struct {
+	Month string   `json:"month"`
+	Days  []string `json:"days"`
+}
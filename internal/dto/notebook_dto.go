@@ -1,8 +1,8 @@
 package dto
 
 type NoteBookRequest struct {
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
+	Name        string `json:"name,omitempty" validate:"required,max=100"`
+	Description string `json:"description,omitempty" validate:"max=1000"`
 	IsActive    *bool  `json:"is_active"`
 }
 
@@ -10,3 +10,10 @@ type NoteBookResponse struct {
 	Data  interface{} `json:"data,omitempty"`
 	Error string      `json:"error,omitempty"`
 }
+
+const (
+	DeleteModeUnlink   = "unlink"
+	DeleteModeMove     = "move"
+	DeleteModeTrash    = "trash"
+	DeleteModeRestrict = "restrict"
+)
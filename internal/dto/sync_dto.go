@@ -0,0 +1,103 @@
+package dto
+
+import "github.com/LoL-KeKovich/NoteVault/internal/model"
+
+const (
+	SyncOpCreate = "create"
+	SyncOpUpdate = "update"
+	SyncOpDelete = "delete"
+)
+
+const (
+	SyncStatusOK       = "ok"
+	SyncStatusConflict = "conflict"
+	SyncStatusNotFound = "not_found"
+	SyncStatusInvalid  = "invalid"
+	SyncStatusFailed   = "failed"
+)
+
+// SyncPull - изменения после token клиента. Full - в ответе все данные пользователя, и клиент заменяет ими
+// локальную копию. HasMore - изменения не поместились в ответ, их нужно сразу дочитать с новым token
+type SyncPull struct {
+	Token     string          `json:"token"`
+	Full      bool            `json:"full"`
+	HasMore   bool            `json:"has_more"`
+	Notes     []SyncNote      `json:"notes"`
+	NoteBooks []SyncNoteBook  `json:"notebooks"`
+	Tags      []SyncTag       `json:"tags"`
+	Deleted   []SyncTombstone `json:"deleted"`
+}
+
+// Rev - ревизия сущности: клиент передает ее при изменении, чтобы сервер заметил конфликт
+type SyncNote struct {
+	model.Note
+	Rev int64 `json:"rev"`
+}
+
+type SyncNoteBook struct {
+	model.NoteBook
+	Rev int64 `json:"rev"`
+}
+
+type SyncTag struct {
+	model.Tag
+	Rev int64 `json:"rev"`
+}
+
+// SyncTombstone - удаленная сущность
+type SyncTombstone struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	Rev  int64  `json:"rev"`
+}
+
+type SyncPushRequest struct {
+	Changes []SyncChange `json:"changes,omitempty" validate:"required,max=500"`
+}
+
+// SyncChange - локальное изменение клиента. Для update и delete ID - ID на сервере, а Rev - ревизия,
+// с которой клиент начинал изменение. ClientID возвращается в результате, чтобы связать созданную сущность
+// с локальной. В note, notebook или tag - полное новое состояние сущности
+type SyncChange struct {
+	Kind     string           `json:"kind,omitempty"`
+	Op       string           `json:"op,omitempty"`
+	ID       string           `json:"id,omitempty"`
+	ClientID string           `json:"client_id,omitempty"`
+	Rev      int64            `json:"rev,omitempty"`
+	Note     *SyncNoteData    `json:"note,omitempty"`
+	NoteBook *NoteBookRequest `json:"notebook,omitempty"`
+	Tag      *TagRequest      `json:"tag,omitempty"`
+}
+
+// SyncNoteData - состояние заметки с клиента. Флаги со значением nil не меняются
+type SyncNoteData struct {
+	Name        string   `json:"name,omitempty" validate:"required,max=200"`
+	Text        string   `json:"text,omitempty" validate:"max=100000"`
+	Color       string   `json:"color,omitempty" validate:"color"`
+	NoteBookID  string   `json:"notebook_id,omitempty"`
+	Tags        []string `json:"tags,omitempty" validate:"max=100"`
+	IsDeleted   *bool    `json:"is_deleted,omitempty"`
+	IsArchived  *bool    `json:"is_archived,omitempty"`
+	IsPinned    *bool    `json:"is_pinned,omitempty"`
+	IsFavourite *bool    `json:"is_favourite,omitempty"`
+}
+
+// SyncResult - результат одного изменения. При конфликте в note, notebook или tag - текущее состояние
+// сущности на сервере, а Deleted - сущность на сервере уже удалена
+type SyncResult struct {
+	Kind     string        `json:"kind"`
+	ID       string        `json:"id,omitempty"`
+	ClientID string        `json:"client_id,omitempty"`
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Rev      int64         `json:"rev,omitempty"`
+	Deleted  bool          `json:"deleted,omitempty"`
+	Note     *SyncNote     `json:"note,omitempty"`
+	NoteBook *SyncNoteBook `json:"notebook,omitempty"`
+	Tag      *SyncTag      `json:"tag,omitempty"`
+}
+
+type SyncResponse struct {
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
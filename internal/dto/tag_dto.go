@@ -1,8 +1,19 @@
 package dto
 
+import "github.com/LoL-KeKovich/NoteVault/internal/model"
+
 type TagRequest struct {
-	Name  string `json:"name,omitempty"`
-	Color string `json:"color,omitempty"`
+	Name  string `json:"name,omitempty" validate:"required,max=100"`
+	Color string `json:"color,omitempty" validate:"color"`
+}
+
+type TagMergeRequest struct {
+	TargetID string `json:"target_id,omitempty"`
+}
+
+type TagUsage struct {
+	model.Tag
+	NoteCount int `json:"note_count"`
 }
 
 type TagResponse struct {
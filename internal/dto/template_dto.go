@@ -0,0 +1,14 @@
+package dto
+
+type TemplateRequest struct {
+	Name       string   `json:"name,omitempty" validate:"required,max=200"`
+	Text       string   `json:"text,omitempty" validate:"max=100000"`
+	Color      string   `json:"color,omitempty" validate:"color"`
+	TagIDs     []string `json:"tags,omitempty" validate:"max=100"`
+	NoteBookID string   `json:"notebook_id,omitempty"`
+}
+
+type TemplateResponse struct {
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
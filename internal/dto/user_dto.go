@@ -11,10 +11,10 @@ type LoginResponse struct {
 }
 
 type RegisterRequest struct {
-	Email     string `json:"email,omitempty"`
-	Password  string `json:"password,omitempty"`
-	FirstName string `json:"first_name,omitempty"`
-	LastName  string `json:"last_name,omitempty"`
+	Email     string `json:"email,omitempty" validate:"required,email,max=254"`
+	Password  string `json:"password,omitempty" validate:"required,max=72"`
+	FirstName string `json:"first_name,omitempty" validate:"max=100"`
+	LastName  string `json:"last_name,omitempty" validate:"max=100"`
 }
 
 type RegisterResponse struct {
@@ -0,0 +1,27 @@
+package dto
+
+import "github.com/LoL-KeKovich/NoteVault/internal/model"
+
+// WebhookRequest - создание и изменение вебхука. Без secret при создании сервер генерирует его сам,
+// в PUT пустое поле не меняется, а пустой список events подписывает на все события
+type WebhookRequest struct {
+	URL      string   `json:"url,omitempty" validate:"required,url,max=2000"`
+	Secret   string   `json:"secret,omitempty" validate:"min=16,max=200"`
+	Events   []string `json:"events,omitempty" validate:"max=20"`
+	IsActive *bool    `json:"is_active,omitempty"`
+}
+
+type WebhookResponse struct {
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// WebhookPayload - тело запроса, которое получает адрес вебхука. ID совпадает с заголовком X-NoteVault-Delivery
+// и не меняется между повторами. Note нет у удаленной заметки и у пробного события ping
+type WebhookPayload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt string      `json:"created_at"`
+	NoteID    string      `json:"note_id,omitempty"`
+	Note      *model.Note `json:"note,omitempty"`
+}
@@ -0,0 +1,119 @@
+package events
+
+import "sync"
+
+const (
+	KindNote     = "note"
+	KindNoteBook = "notebook"
+	KindTag      = "tag"
+)
+
+const (
+	ActionCreated  = "created"
+	ActionUpdated  = "updated"
+	ActionTagged   = "tagged"
+	ActionUntagged = "untagged"
+	ActionTrashed  = "trashed"
+	ActionArchived = "archived"
+	ActionRestored = "restored"
+	ActionDeleted  = "deleted"
+)
+
+// subscriberBuffer - сколько событий может ждать медленный подписчик, прежде чем его отключат
+const subscriberBuffer = 64
+
+// Event - изменение сущности. Клиент получает только ID и перечитывает сущность через API.
+// UserID - владелец; событие без владельца (блокноты, теги, заметки без пользователя) получают все подписчики
+type Event struct {
+	Kind   string `json:"kind"`
+	Action string `json:"action"`
+	ID     string `json:"id"`
+	UserID string `json:"-"`
+}
+
+// Type - имя события в потоке, например note.created
+func (e Event) Type() string {
+	return e.Kind + "." + e.Action
+}
+
+// Bus рассылает события подписчикам внутри процесса. Nil-шина ничего не рассылает
+type Bus struct {
+	mu        sync.Mutex
+	subs      map[chan Event]string
+	listeners []func(Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: map[chan Event]string{}}
+}
+
+// Subscribe подписывает пользователя на его события и общие события.
+// Канал закрывается после отписки или если подписчик не успевает читать: тогда клиенту нужно переподключиться
+// и перечитать данные
+func (b *Bus) Subscribe(userID string) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subs[ch] = userID
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Listen добавляет слушателя всех событий, в том числе чужих. Слушатели вызываются синхронно
+// из Publish под блокировкой шины, поэтому получают события в порядке публикации и должны работать быстро
+func (b *Bus) Listen(fn func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.listeners = append(b.listeners, fn)
+}
+
+// Active сообщает, есть ли подписчики: без них не нужно искать владельцев измененных сущностей
+func (b *Bus) Active() bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subs) > 0 || len(b.listeners) > 0
+}
+
+// Publish не блокируется: подписчик с переполненной очередью отключается
+func (b *Bus) Publish(events ...Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, e := range events {
+		for _, fn := range b.listeners {
+			fn(e)
+		}
+
+		for ch, userID := range b.subs {
+			if e.UserID != "" && e.UserID != userID {
+				continue
+			}
+
+			select {
+			case ch <- e:
+			default:
+				delete(b.subs, ch)
+				close(ch)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Виды ошибок, общие для хранилищ и сервисов. Обработчики выбирают по ним HTTP-статус через errors.Is
+var (
+	ErrInvalidID    = errors.New("invalid id")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrValidation   = errors.New("validation failed")
+)
+
+// Error - ошибка одного из видов выше с текстом, который можно показать клиенту
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func InvalidID(format string, args ...any) error {
+	return &Error{Kind: ErrInvalidID, Message: fmt.Sprintf(format, args...)}
+}
+
+func NotFound(format string, args ...any) error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func Conflict(format string, args ...any) error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+func Unauthorized(format string, args ...any) error {
+	return &Error{Kind: ErrUnauthorized, Message: fmt.Sprintf(format, args...)}
+}
+
+func Validation(format string, args ...any) error {
+	return &Error{Kind: ErrValidation, Message: fmt.Sprintf(format, args...)}
+}
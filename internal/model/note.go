@@ -5,15 +5,20 @@ import (
 )
 
 type Note struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Name       string             `bson:"name,omitempty" json:"name,omitempty"`
-	Text       string             `bson:"text,omitempty" json:"text,omitempty"`
-	Color      string             `bson:"color,omitempty" json:"color,omitempty"`
-	Order      int                `bson:"order,omitempty" json:"order,omitempty"`
-	IsDeleted  *bool              `bson:"is_deleted,omitempty" json:"is_deleted,omitempty"`
-	IsArchived *bool              `bson:"is_archived,omitempty" json:"is_archived,omitempty"`
-	CreatedAt  string             `bson:"created_at" json:"created_at"`
-	UpdatedAt  string             `bson:"updated_at" json:"updated_at"`
-	NoteBookID primitive.ObjectID `bson:"notebook_id,omitempty" json:"notebook_id,omitempty"`
-	Tags       []string           `bson:"tags,omitempty" json:"tags,omitempty"`
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	Name        string               `bson:"name,omitempty" json:"name,omitempty"`
+	Text        string               `bson:"text,omitempty" json:"text,omitempty"`
+	Color       string               `bson:"color,omitempty" json:"color,omitempty"`
+	Order       int                  `bson:"order,omitempty" json:"order,omitempty"`
+	Rank        string               `bson:"rank,omitempty" json:"rank,omitempty"`
+	IsDeleted   *bool                `bson:"is_deleted,omitempty" json:"is_deleted,omitempty"`
+	IsArchived  *bool                `bson:"is_archived,omitempty" json:"is_archived,omitempty"`
+	IsPinned    *bool                `bson:"is_pinned,omitempty" json:"is_pinned,omitempty"`
+	IsFavourite *bool                `bson:"is_favourite,omitempty" json:"is_favourite,omitempty"`
+	CreatedAt   string               `bson:"created_at" json:"created_at"`
+	UpdatedAt   string               `bson:"updated_at" json:"updated_at"`
+	NoteBookID  primitive.ObjectID   `bson:"notebook_id,omitempty" json:"notebook_id,omitempty"`
+	Tags        []primitive.ObjectID `bson:"tags,omitempty" json:"tags,omitempty"`
+	UserID      primitive.ObjectID   `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	JournalDate string               `bson:"journal_date,omitempty" json:"journal_date,omitempty"`
 }
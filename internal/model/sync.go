@@ -0,0 +1,11 @@
+package model
+
+// SyncChange - последнее изменение сущности в журнале синхронизации. Seq растет с каждым изменением
+// и служит ревизией сущности, Deleted - надгробие удаленной сущности. UserID пустой у общих сущностей
+type SyncChange struct {
+	Kind     string `bson:"kind" json:"kind"`
+	EntityID string `bson:"entity_id" json:"id"`
+	UserID   string `bson:"user_id" json:"-"`
+	Seq      int64  `bson:"seq" json:"rev"`
+	Deleted  bool   `bson:"deleted" json:"deleted"`
+}
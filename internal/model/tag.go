@@ -1,9 +1,26 @@
 package model
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Иерархия тегов задается через имя: "work/clientA" - потомок "work"
+const TagPathSeparator = "/"
 
 type Tag struct {
 	ID    primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	Name  string             `bson:"name,omitempty" json:"name,omitempty"`
 	Color string             `bson:"color,omitempty" json:"color,omitempty"`
 }
+
+func ValidTagName(name string) bool {
+	for _, part := range strings.Split(name, TagPathSeparator) {
+		if strings.TrimSpace(part) == "" {
+			return false
+		}
+	}
+
+	return true
+}
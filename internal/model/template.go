@@ -0,0 +1,13 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type Template struct {
+	ID         primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID     primitive.ObjectID   `bson:"user_id" json:"user_id"`
+	Name       string               `bson:"name,omitempty" json:"name,omitempty"`
+	Text       string               `bson:"text,omitempty" json:"text,omitempty"`
+	Color      string               `bson:"color,omitempty" json:"color,omitempty"`
+	Tags       []primitive.ObjectID `bson:"tags,omitempty" json:"tags,omitempty"`
+	NoteBookID primitive.ObjectID   `bson:"notebook_id,omitempty" json:"notebook_id,omitempty"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Webhook - подписка пользователя на события его заметок. Events - имена событий вида note.created,
+// пустой список означает все события. Secret подписывает тело запроса HMAC-SHA256
+type Webhook struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	URL       string             `bson:"url" json:"url"`
+	Secret    string             `bson:"secret" json:"secret"`
+	Events    []string           `bson:"events,omitempty" json:"events,omitempty"`
+	IsActive  *bool              `bson:"is_active,omitempty" json:"is_active,omitempty"`
+	CreatedAt string             `bson:"created_at" json:"created_at"`
+}
+
+// WebhookDelivery - одна попытка доставки. Попытки одного события имеют общий DeliveryID
+type WebhookDelivery struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	WebhookID  primitive.ObjectID `bson:"webhook_id" json:"webhook_id"`
+	DeliveryID string             `bson:"delivery_id" json:"delivery_id"`
+	Event      string             `bson:"event" json:"event"`
+	Attempt    int                `bson:"attempt" json:"attempt"`
+	StatusCode int                `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	Success    bool               `bson:"success" json:"success"`
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"`
+	DurationMs int                `bson:"duration_ms" json:"duration_ms"`
+	Payload    string             `bson:"payload" json:"payload"`
+	CreatedAt  string             `bson:"created_at" json:"created_at"`
+}
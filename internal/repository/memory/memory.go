@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Store хранит все коллекции в памяти в порядке вставки - как естественный порядок документов в MongoDB.
+// Наружу отдаются только копии, поэтому изменения моделей вне репозитория не затрагивают хранилище
+type Store struct {
+	mu        sync.Mutex
+	tx        sync.Mutex
+	notes     []model.Note
+	noteBooks []model.NoteBook
+	tags      []model.Tag
+	users     []model.User
+	templates []model.Template
+	webhooks  []model.Webhook
+	reminders []model.Reminder
+	//Журналы доставки по ID вебхука, от старых попыток к новым. Пишутся вне транзакций и при откате не меняются
+	deliveries map[primitive.ObjectID][]model.WebhookDelivery
+	//Журнал синхронизации: последнее изменение каждой сущности по ключу kind/id, тоже вне транзакций
+	changes map[string]model.SyncChange
+	seq     int64
+}
+
+func NewStore() *Store {
+	return &Store{}
+}
+
+type MemoryClient struct {
+	Store *Store
+}
+
+type MemoryUnitOfWork struct {
+	Store *Store
+}
+
+// Do выполняет fn над снимком хранилища и откатывает все изменения, если fn вернула ошибку.
+// Транзакции выполняются по очереди
+func (uow MemoryUnitOfWork) Do(ctx context.Context, fn func(repository.Tx) error) error {
+	uow.Store.tx.Lock()
+	defer uow.Store.tx.Unlock()
+
+	//Пока ждали очереди, запрос мог уже завершиться
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	snapshot := uow.Store.snapshot()
+
+	client := MemoryClient{Store: uow.Store}
+
+	err := fn(repository.Tx{
+		Notes:     client,
+		NoteBooks: client,
+		Tags:      client,
+		Users:     client,
+		Templates: client,
+		Webhooks:  client,
+		Reminders: client,
+	})
+	if err != nil {
+		uow.Store.restore(snapshot)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store) snapshot() *Store {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := &Store{
+		noteBooks: make([]model.NoteBook, 0, len(s.noteBooks)),
+		tags:      slices.Clone(s.tags),
+		users:     slices.Clone(s.users),
+		templates: make([]model.Template, 0, len(s.templates)),
+		notes:     make([]model.Note, 0, len(s.notes)),
+		webhooks:  make([]model.Webhook, 0, len(s.webhooks)),
+		reminders: make([]model.Reminder, 0, len(s.reminders)),
+	}
+	for _, note := range s.notes {
+		snapshot.notes = append(snapshot.notes, copyNote(note))
+	}
+	for _, noteBook := range s.noteBooks {
+		snapshot.noteBooks = append(snapshot.noteBooks, copyNoteBook(noteBook))
+	}
+	for _, template := range s.templates {
+		snapshot.templates = append(snapshot.templates, copyTemplate(template))
+	}
+	for _, webhook := range s.webhooks {
+		snapshot.webhooks = append(snapshot.webhooks, copyWebhook(webhook))
+	}
+	for _, reminder := range s.reminders {
+		snapshot.reminders = append(snapshot.reminders, copyReminder(reminder))
+	}
+
+	return snapshot
+}
+
+func (s *Store) restore(snapshot *Store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.notes = snapshot.notes
+	s.noteBooks = snapshot.noteBooks
+	s.tags = snapshot.tags
+	s.users = snapshot.users
+	s.templates = snapshot.templates
+	s.webhooks = snapshot.webhooks
+	s.reminders = snapshot.reminders
+}
+
+func copyNote(note model.Note) model.Note {
+	note.IsDeleted = copyBool(note.IsDeleted)
+	note.IsArchived = copyBool(note.IsArchived)
+	note.IsPinned = copyBool(note.IsPinned)
+	note.IsFavourite = copyBool(note.IsFavourite)
+	note.Tags = slices.Clone(note.Tags)
+
+	return note
+}
+
+func copyNoteBook(noteBook model.NoteBook) model.NoteBook {
+	noteBook.IsActive = copyBool(noteBook.IsActive)
+
+	return noteBook
+}
+
+func copyTemplate(template model.Template) model.Template {
+	template.Tags = slices.Clone(template.Tags)
+
+	return template
+}
+
+func copyWebhook(webhook model.Webhook) model.Webhook {
+	webhook.Events = slices.Clone(webhook.Events)
+	webhook.IsActive = copyBool(webhook.IsActive)
+
+	return webhook
+}
+
+func copyReminder(reminder model.Reminder) model.Reminder {
+	reminder.IsActive = copyBool(reminder.IsActive)
+
+	return reminder
+}
+
+func copyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+
+	v := *b
+
+	return &v
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+func newID(id primitive.ObjectID) primitive.ObjectID {
+	if id.IsZero() {
+		return primitive.NewObjectID()
+	}
+
+	return id
+}
+
+func parseID(id, message string) (primitive.ObjectID, error) {
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, model.InvalidID("%s", message)
+	}
+
+	return docId, nil
+}
+
+// modified повторяет ModifiedCount из MongoDB: обновление, не изменившее документ, не считается
+func modified[T any](before, after T) int {
+	if reflect.DeepEqual(before, after) {
+		return 0
+	}
+
+	return 1
+}
+
+// duplicate - аналог нарушения уникального индекса в MongoDB
+func duplicate(message string) error {
+	return model.Conflict("%s", message)
+}
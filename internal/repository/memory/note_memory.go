@@ -0,0 +1,1020 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/rank"
+	"github.com/LoL-KeKovich/NoteVault/lib/tagquery"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func isActive(note model.Note) bool {
+	return !isTrue(note.IsDeleted) && !isTrue(note.IsArchived)
+}
+
+// pinnedKey упорядочивает is_pinned так же, как сортировка MongoDB: отсутствующее поле меньше false
+func pinnedKey(b *bool) int {
+	if b == nil {
+		return 0
+	}
+	if !*b {
+		return 1
+	}
+
+	return 2
+}
+
+// sortNotes ставит закрепленные заметки первыми, остальные - по ключу сортировки
+func sortNotes(notes []model.Note) {
+	sort.SliceStable(notes, func(i, j int) bool {
+		a, b := notes[i], notes[j]
+		if pa, pb := pinnedKey(a.IsPinned), pinnedKey(b.IsPinned); pa != pb {
+			return pa > pb
+		}
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+
+		return a.ID.Hex() < b.ID.Hex()
+	})
+}
+
+// findNotes возвращает копии подходящих заметок в естественном порядке
+func (mc MemoryClient) findNotes(ctx context.Context, match func(model.Note) bool) []model.Note {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	var notes []model.Note
+	for _, note := range mc.Store.notes {
+		if match(note) {
+			notes = append(notes, copyNote(note))
+		}
+	}
+
+	return notes
+}
+
+// updateNotes применяет fn к подходящим заметкам и возвращает число измененных
+func (mc MemoryClient) updateNotes(ctx context.Context, match func(model.Note) bool, fn func(*model.Note)) int {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	count := 0
+	for i := range mc.Store.notes {
+		if !match(mc.Store.notes[i]) {
+			continue
+		}
+
+		before := copyNote(mc.Store.notes[i])
+		fn(&mc.Store.notes[i])
+		count += modified(before, mc.Store.notes[i])
+	}
+
+	return count
+}
+
+// moveNotes переносит подходящие заметки в конец блокнота toDocId, сохраняя их порядок между собой.
+// Заметки, которые уже лежат в этом блокноте, не меняются
+func (mc MemoryClient) moveNotes(ctx context.Context, match func(model.Note) bool, toDocId primitive.ObjectID) (int, error) {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	last := ""
+	var moved []int
+	for i, note := range mc.Store.notes {
+		if note.NoteBookID == toDocId {
+			last = max(last, note.Rank)
+		} else if match(note) {
+			moved = append(moved, i)
+		}
+	}
+
+	sort.SliceStable(moved, func(i, j int) bool {
+		a, b := mc.Store.notes[moved[i]], mc.Store.notes[moved[j]]
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+
+		return a.ID.Hex() < b.ID.Hex()
+	})
+
+	for _, i := range moved {
+		noteRank, err := rank.After(last)
+		if err != nil {
+			return 0, err
+		}
+		last = noteRank
+
+		mc.Store.notes[i].NoteBookID = toDocId
+		mc.Store.notes[i].Rank = noteRank
+	}
+
+	return len(moved), nil
+}
+
+func byID(docId primitive.ObjectID) func(model.Note) bool {
+	return func(note model.Note) bool {
+		return note.ID == docId
+	}
+}
+
+func byNoteBook(docId primitive.ObjectID) func(model.Note) bool {
+	return func(note model.Note) bool {
+		return note.NoteBookID == docId
+	}
+}
+
+func (mc MemoryClient) CreateNote(ctx context.Context, note model.Note) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	note = copyNote(note)
+	note.ID = newID(note.ID)
+
+	for _, existing := range mc.Store.notes {
+		if existing.ID == note.ID {
+			return "", duplicate("note already exists")
+		}
+		if note.JournalDate != "" && existing.JournalDate == note.JournalDate && existing.UserID == note.UserID {
+			return "", duplicate("daily note already exists")
+		}
+	}
+
+	mc.Store.notes = append(mc.Store.notes, note)
+
+	return note.ID.Hex(), nil
+}
+
+func (mc MemoryClient) GetNoteByID(ctx context.Context, id string) (model.Note, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Note{}, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	notes := mc.findNotes(ctx, byID(docId))
+	if len(notes) == 0 {
+		return model.Note{}, model.NotFound("note not found")
+	}
+
+	return notes[0], nil
+}
+
+func (mc MemoryClient) GetNotesByIDs(ctx context.Context, ids []string) ([]model.Note, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	docIds := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		docId, err := parseID(id, "wrong id")
+		if err != nil {
+			return []model.Note{}, err
+		}
+		docIds = append(docIds, docId)
+	}
+
+	return mc.findNotes(ctx, func(note model.Note) bool { return slices.Contains(docIds, note.ID) }), nil
+}
+
+func (mc MemoryClient) GetNotes(ctx context.Context) ([]model.Note, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	notes := mc.findNotes(ctx, isActive)
+	sortNotes(notes)
+
+	return notes, nil
+}
+
+func (mc MemoryClient) GetNotesByNoteBookID(ctx context.Context, id string) ([]model.Note, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	docId, err := parseID(id, "wrong notebook id")
+	if err != nil {
+		return []model.Note{}, err
+	}
+
+	notes := mc.findNotes(ctx, func(note model.Note) bool {
+		return note.NoteBookID == docId && isActive(note)
+	})
+	sortNotes(notes)
+
+	return notes, nil
+}
+
+func (mc MemoryClient) GetTrashedNotes(ctx context.Context) ([]model.Note, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return mc.findNotes(ctx, func(note model.Note) bool {
+		return isTrue(note.IsDeleted)
+	}), nil
+}
+
+func (mc MemoryClient) GetArchivedNotes(ctx context.Context) ([]model.Note, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return mc.findNotes(ctx, func(note model.Note) bool {
+		return isTrue(note.IsArchived)
+	}), nil
+}
+
+func (mc MemoryClient) GetFavouriteNotes(ctx context.Context) ([]model.Note, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	notes := mc.findNotes(ctx, func(note model.Note) bool {
+		return isTrue(note.IsFavourite) && isActive(note)
+	})
+	sortNotes(notes)
+
+	return notes, nil
+}
+
+// GetJournalNote возвращает заметку с пустым ID, если за этот день записи нет
+func (mc MemoryClient) GetJournalNote(ctx context.Context, userID, date string) (model.Note, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Note{}, err
+	}
+
+	docId, err := parseID(userID, "wrong user id")
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	notes := mc.findNotes(ctx, func(note model.Note) bool {
+		return note.UserID == docId && note.JournalDate == date
+	})
+	if len(notes) == 0 {
+		return model.Note{}, nil
+	}
+
+	return notes[0], nil
+}
+
+// GetJournalDates возвращает даты записей в полуинтервале [from, to)
+func (mc MemoryClient) GetJournalDates(ctx context.Context, userID, from, to string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	docId, err := parseID(userID, "wrong user id")
+	if err != nil {
+		return []string{}, err
+	}
+
+	notes := mc.findNotes(ctx, func(note model.Note) bool {
+		return note.UserID == docId && note.JournalDate != "" &&
+			note.JournalDate >= from && note.JournalDate < to && !isTrue(note.IsDeleted)
+	})
+
+	var dates []string
+	for _, note := range notes {
+		dates = append(dates, note.JournalDate)
+	}
+	slices.Sort(dates)
+
+	return dates, nil
+}
+
+func (mc MemoryClient) FindNotes(ctx context.Context, noteFilter repository.NoteFilter) ([]model.Note, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var conditions []func(model.Note) bool
+
+	switch noteFilter.Status {
+	case repository.NoteStatusActive, "":
+		conditions = append(conditions, isActive)
+	case repository.NoteStatusArchived:
+		conditions = append(conditions, func(note model.Note) bool { return isTrue(note.IsArchived) })
+	case repository.NoteStatusTrashed:
+		conditions = append(conditions, func(note model.Note) bool { return isTrue(note.IsDeleted) })
+	case repository.NoteStatusAll:
+	default:
+		return nil, fmt.Errorf("unknown note status %q", noteFilter.Status)
+	}
+
+	if noteFilter.NoteBookID != "" {
+		docId, err := parseID(noteFilter.NoteBookID, "wrong notebook id")
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, byNoteBook(docId))
+	}
+
+	if noteFilter.Tags != nil {
+		match, err := tagExprMatch(noteFilter.Tags)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, match)
+	}
+
+	notes := mc.findNotes(ctx, func(note model.Note) bool {
+		for _, cond := range conditions {
+			if !cond(note) {
+				return false
+			}
+		}
+		return true
+	})
+	sortNotes(notes)
+
+	if noteFilter.Limit > 0 && len(notes) > noteFilter.Limit {
+		notes = notes[:noteFilter.Limit]
+	}
+
+	return notes, nil
+}
+
+func tagExprMatch(expr tagquery.Expr) (func(model.Note) bool, error) {
+	switch e := expr.(type) {
+	case *tagquery.Tag:
+		tagDocIds := make([]primitive.ObjectID, 0, len(e.IDs))
+		for _, tagID := range e.IDs {
+			tagDocId, err := primitive.ObjectIDFromHex(tagID)
+			if err != nil {
+				return nil, model.InvalidID("invalid tag ID: %v", err)
+			}
+			tagDocIds = append(tagDocIds, tagDocId)
+		}
+		return func(note model.Note) bool {
+			for _, tagID := range note.Tags {
+				if slices.Contains(tagDocIds, tagID) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case tagquery.And, tagquery.Or:
+		var exprs []tagquery.Expr
+		all := false
+		if and, ok := e.(tagquery.And); ok {
+			exprs, all = and, true
+		} else {
+			exprs = e.(tagquery.Or)
+		}
+
+		subs := make([]func(model.Note) bool, 0, len(exprs))
+		for _, sub := range exprs {
+			match, err := tagExprMatch(sub)
+			if err != nil {
+				return nil, err
+			}
+			subs = append(subs, match)
+		}
+
+		return func(note model.Note) bool {
+			for _, match := range subs {
+				if match(note) != all {
+					return !all
+				}
+			}
+			return all
+		}, nil
+	case tagquery.Not:
+		match, err := tagExprMatch(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return func(note model.Note) bool { return !match(note) }, nil
+	default:
+		return nil, fmt.Errorf("unknown tag expression %T", expr)
+	}
+}
+
+// StreamNotes передает в fn по одной заметке, доступной пользователю: его собственные и общие
+func (mc MemoryClient) StreamNotes(ctx context.Context, userID string, fn func(model.Note) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	docId, err := parseID(userID, "wrong user id")
+	if err != nil {
+		return err
+	}
+
+	notes := mc.findNotes(ctx, func(note model.Note) bool {
+		return note.UserID == docId || note.UserID.IsZero()
+	})
+	sort.SliceStable(notes, func(i, j int) bool {
+		a, b := notes[i], notes[j]
+		if a.NoteBookID != b.NoteBookID {
+			return a.NoteBookID.IsZero() || (!b.NoteBookID.IsZero() && a.NoteBookID.Hex() < b.NoteBookID.Hex())
+		}
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+		return a.ID.Hex() < b.ID.Hex()
+	})
+
+	for _, note := range notes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := fn(note)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mc MemoryClient) CountNotesByTags(ctx context.Context) (map[string]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+
+	for _, note := range mc.findNotes(ctx, func(note model.Note) bool { return !isTrue(note.IsDeleted) }) {
+		for _, tagID := range note.Tags {
+			counts[tagID.Hex()]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (mc MemoryClient) UpdateNote(ctx context.Context, id, name, text, color, updatedAt string, order int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	if name == "" && text == "" && color == "" && order == 0 {
+		return 0, nil
+	}
+
+	return mc.updateNotes(ctx, byID(docId), func(note *model.Note) {
+		if name != "" {
+			note.Name = name
+		}
+		if text != "" {
+			note.Text = text
+		}
+		if color != "" {
+			note.Color = color
+		}
+		if order != 0 {
+			note.Order = order
+		}
+		note.UpdatedAt = updatedAt
+	}), nil
+}
+
+func (mc MemoryClient) PatchNote(ctx context.Context, id string, patch repository.NotePatch) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	noteBookDocId := primitive.NilObjectID
+	if patch.NoteBookID != nil && *patch.NoteBookID != "" {
+		noteBookDocId, err = parseID(*patch.NoteBookID, "wrong notebook id")
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var tagDocIds []primitive.ObjectID
+	if patch.Tags != nil {
+		for _, tagID := range *patch.Tags {
+			tagDocId, err := parseID(tagID, "invalid tag ID")
+			if err != nil {
+				return 0, err
+			}
+			if !slices.Contains(tagDocIds, tagDocId) {
+				tagDocIds = append(tagDocIds, tagDocId)
+			}
+		}
+	}
+
+	//Перенесенная заметка встает в конец нового блокнота
+	moved := 0
+	if patch.NoteBookID != nil {
+		moved, err = mc.moveNotes(ctx, byID(docId), noteBookDocId)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	updated := mc.updateNotes(ctx, byID(docId), func(note *model.Note) {
+		if patch.Name != nil {
+			note.Name = *patch.Name
+		}
+		if patch.Text != nil {
+			note.Text = *patch.Text
+		}
+		if patch.Color != nil {
+			note.Color = *patch.Color
+		}
+		if patch.Order != nil {
+			note.Order = *patch.Order
+		}
+		if patch.Tags != nil {
+			note.Tags = tagDocIds
+		}
+		note.UpdatedAt = patch.UpdatedAt
+	})
+
+	return max(moved, updated), nil
+}
+
+func (mc MemoryClient) UpdateNoteNoteBook(ctx context.Context, noteID, noteBookID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(noteID, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	noteBookDocId, err := parseID(noteBookID, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.moveNotes(ctx, byID(docId), noteBookDocId)
+}
+
+func (mc MemoryClient) UpdateNoteRank(ctx context.Context, id, rank string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.updateNotes(ctx, byID(docId), func(note *model.Note) {
+		note.Rank = rank
+	}), nil
+}
+
+func (mc MemoryClient) GetLastNoteRank(ctx context.Context, noteBookID string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return mc.findNoteRank(ctx, noteBookID, func(r string) bool { return true }, true)
+}
+
+func (mc MemoryClient) GetPrevNoteRank(ctx context.Context, noteBookID, rank string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return mc.findNoteRank(ctx, noteBookID, func(r string) bool { return r < rank }, true)
+}
+
+func (mc MemoryClient) GetNextNoteRank(ctx context.Context, noteBookID, rank string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return mc.findNoteRank(ctx, noteBookID, func(r string) bool { return r > rank }, false)
+}
+
+// findNoteRank ищет ближайший ключ в блокноте; пустой noteBookID - заметки без блокнота.
+// Если подходящей заметки нет, возвращается пустая строка.
+func (mc MemoryClient) findNoteRank(ctx context.Context, noteBookID string, cond func(string) bool, last bool) (string, error) {
+	var noteBookDocId primitive.ObjectID
+	if noteBookID != "" {
+		docId, err := parseID(noteBookID, "wrong notebook id")
+		if err != nil {
+			return "", err
+		}
+		noteBookDocId = docId
+	}
+
+	found := ""
+	for _, note := range mc.findNotes(ctx, byNoteBook(noteBookDocId)) {
+		//Заметки без ключа в MongoDB не проходят ни $exists, ни сравнения
+		if note.Rank == "" || !cond(note.Rank) {
+			continue
+		}
+		if found == "" || (last && note.Rank > found) || (!last && note.Rank < found) {
+			found = note.Rank
+		}
+	}
+
+	return found, nil
+}
+
+func (mc MemoryClient) RemoveNoteBookFromNote(ctx context.Context, noteID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(noteID, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.moveNotes(ctx, byID(docId), primitive.NilObjectID)
+}
+
+func (mc MemoryClient) UnlinkNotesFromNoteBook(ctx context.Context, id string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.moveNotes(ctx, byNoteBook(docId), primitive.NilObjectID)
+}
+
+func (mc MemoryClient) CountNotesByNoteBookID(ctx context.Context, id string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong notebook id")
+	if err != nil {
+		return 0, err
+	}
+
+	return len(mc.findNotes(ctx, byNoteBook(docId))), nil
+}
+
+func (mc MemoryClient) MoveNotesToNoteBook(ctx context.Context, fromID, toID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	fromDocId, err := parseID(fromID, "wrong notebook id")
+	if err != nil {
+		return 0, err
+	}
+
+	toDocId, err := parseID(toID, "wrong target notebook id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.moveNotes(ctx, byNoteBook(fromDocId), toDocId)
+}
+
+func (mc MemoryClient) TrashNotesFromNoteBook(ctx context.Context, id string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong notebook id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.updateNotes(ctx, byNoteBook(docId), func(note *model.Note) {
+		note.IsDeleted = boolPtr(true)
+		note.IsArchived = boolPtr(false)
+		note.NoteBookID = primitive.NilObjectID
+	}), nil
+}
+
+func (mc MemoryClient) UnlinkNotesFromTag(ctx context.Context, tagID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(tagID, "wrong tag id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.updateNotes(ctx, func(note model.Note) bool { return slices.Contains(note.Tags, docId) }, func(note *model.Note) {
+		note.Tags = pullTag(note.Tags, docId)
+	}), nil
+}
+
+func (mc MemoryClient) ReplaceTagInNotes(ctx context.Context, fromID, toID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	fromDocId, err := primitive.ObjectIDFromHex(fromID)
+	if err != nil {
+		return 0, model.InvalidID("invalid tag ID: %v", err)
+	}
+
+	toDocId, err := primitive.ObjectIDFromHex(toID)
+	if err != nil {
+		return 0, model.InvalidID("invalid tag ID: %v", err)
+	}
+
+	return mc.updateNotes(ctx, func(note model.Note) bool { return slices.Contains(note.Tags, fromDocId) }, func(note *model.Note) {
+		note.Tags = pullTag(addTag(note.Tags, toDocId), fromDocId)
+	}), nil
+}
+
+func (mc MemoryClient) AddTagToNote(ctx context.Context, noteID, tagID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := primitive.ObjectIDFromHex(noteID)
+	if err != nil {
+		return 0, model.InvalidID("invalid note ID: %v", err)
+	}
+
+	tagDocId, err := primitive.ObjectIDFromHex(tagID)
+	if err != nil {
+		return 0, model.InvalidID("invalid tag ID: %v", err)
+	}
+
+	return mc.updateNotes(ctx, byID(docId), func(note *model.Note) {
+		note.Tags = addTag(note.Tags, tagDocId)
+	}), nil
+}
+
+func (mc MemoryClient) RemoveTagFromNote(ctx context.Context, noteID, tagID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := primitive.ObjectIDFromHex(noteID)
+	if err != nil {
+		return 0, model.InvalidID("invalid note ID: %v", err)
+	}
+
+	tagDocId, err := primitive.ObjectIDFromHex(tagID)
+	if err != nil {
+		return 0, model.InvalidID("invalid tag ID: %v", err)
+	}
+
+	return mc.updateNotes(ctx, byID(docId), func(note *model.Note) {
+		note.Tags = pullTag(note.Tags, tagDocId)
+	}), nil
+}
+
+// addTag и pullTag повторяют $addToSet и $pull
+func addTag(tags []primitive.ObjectID, tagID primitive.ObjectID) []primitive.ObjectID {
+	if slices.Contains(tags, tagID) {
+		return tags
+	}
+
+	return append(tags, tagID)
+}
+
+func pullTag(tags []primitive.ObjectID, tagID primitive.ObjectID) []primitive.ObjectID {
+	return slices.DeleteFunc(tags, func(id primitive.ObjectID) bool { return id == tagID })
+}
+
+func (mc MemoryClient) MoveNoteToTrash(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return mc.setNoteFlags(ctx, id, func(note *model.Note) {
+		note.IsDeleted = boolPtr(true)
+		note.IsArchived = boolPtr(false)
+	})
+}
+
+func (mc MemoryClient) MoveNoteToArchive(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return mc.setNoteFlags(ctx, id, func(note *model.Note) {
+		note.IsArchived = boolPtr(true)
+		note.IsDeleted = boolPtr(false)
+	})
+}
+
+func (mc MemoryClient) RestoreNoteFromTrash(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return mc.setNoteFlags(ctx, id, func(note *model.Note) {
+		note.IsDeleted = boolPtr(false)
+	})
+}
+
+func (mc MemoryClient) RestoreNoteFromArchive(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return mc.setNoteFlags(ctx, id, func(note *model.Note) {
+		note.IsArchived = boolPtr(false)
+	})
+}
+
+func (mc MemoryClient) SetNotePinned(ctx context.Context, id string, pinned bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return mc.setNoteFlags(ctx, id, func(note *model.Note) {
+		note.IsPinned = boolPtr(pinned)
+	})
+}
+
+func (mc MemoryClient) SetNoteFavourite(ctx context.Context, id string, favourite bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return mc.setNoteFlags(ctx, id, func(note *model.Note) {
+		note.IsFavourite = boolPtr(favourite)
+	})
+}
+
+func (mc MemoryClient) setNoteFlags(ctx context.Context, id string, fn func(*model.Note)) error {
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	for i := range mc.Store.notes {
+		if mc.Store.notes[i].ID == docId {
+			fn(&mc.Store.notes[i])
+			return nil
+		}
+	}
+
+	return model.NotFound("note not found")
+}
+
+func (mc MemoryClient) DeleteNote(ctx context.Context, id string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.deleteNotes(ctx, byID(docId)), nil
+}
+
+func (mc MemoryClient) DeleteNotesByUserID(ctx context.Context, userID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(userID, "wrong user id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.deleteNotes(ctx, func(note model.Note) bool { return note.UserID == docId }), nil
+}
+
+func (mc MemoryClient) deleteNotes(ctx context.Context, match func(model.Note) bool) int {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	before := len(mc.Store.notes)
+	mc.Store.notes = slices.DeleteFunc(mc.Store.notes, match)
+
+	return before - len(mc.Store.notes)
+}
+
+func (mc MemoryClient) BulkUpdateNotes(ctx context.Context, ids []string, op repository.NoteBulkOp) ([]repository.BulkResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]repository.BulkResult, len(ids))
+	docIds := make([]primitive.ObjectID, len(ids))
+	valid := 0
+
+	for i, id := range ids {
+		results[i] = repository.BulkResult{ID: id, Status: repository.BulkStatusOK}
+
+		docId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			results[i].Status = repository.BulkStatusInvalidID
+			continue
+		}
+		docIds[i] = docId
+		valid++
+	}
+
+	if valid == 0 {
+		return results, nil
+	}
+
+	//Перенос задается блокнотом, остальные действия - изменением одной заметки
+	var noteBookDocId primitive.ObjectID
+	var update func(*model.Note)
+	var err error
+	if op.Action == repository.BulkActionMove {
+		noteBookDocId, err = parseID(op.NoteBookID, "wrong notebook id")
+	} else {
+		update, err = bulkUpdate(op)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range ids {
+		if results[i].Status != repository.BulkStatusOK {
+			continue
+		}
+
+		if len(mc.findNotes(ctx, byID(docIds[i]))) == 0 {
+			results[i].Status = repository.BulkStatusNotFound
+			continue
+		}
+
+		switch {
+		case op.Action == repository.BulkActionMove:
+			//Перенесенные заметки встают в конец блокнота в порядке запроса
+			if _, err := mc.moveNotes(ctx, byID(docIds[i]), noteBookDocId); err != nil {
+				results[i].Status = repository.BulkStatusFailed
+				results[i].Error = err.Error()
+			}
+		case update == nil:
+			mc.deleteNotes(ctx, byID(docIds[i]))
+		default:
+			mc.updateNotes(ctx, byID(docIds[i]), update)
+		}
+	}
+
+	return results, nil
+}
+
+// bulkUpdate возвращает изменение одной заметки; nil - удаление. Перенос делает moveNotes
+func bulkUpdate(op repository.NoteBulkOp) (func(*model.Note), error) {
+	switch op.Action {
+	case repository.BulkActionAddTag, repository.BulkActionRemoveTag:
+		tagDocId, err := primitive.ObjectIDFromHex(op.TagID)
+		if err != nil {
+			return nil, model.InvalidID("invalid tag ID: %v", err)
+		}
+		if op.Action == repository.BulkActionRemoveTag {
+			return func(note *model.Note) { note.Tags = pullTag(note.Tags, tagDocId) }, nil
+		}
+		return func(note *model.Note) { note.Tags = addTag(note.Tags, tagDocId) }, nil
+	case repository.BulkActionArchive:
+		return func(note *model.Note) {
+			note.IsArchived = boolPtr(true)
+			note.IsDeleted = boolPtr(false)
+		}, nil
+	case repository.BulkActionTrash:
+		return func(note *model.Note) {
+			note.IsDeleted = boolPtr(true)
+			note.IsArchived = boolPtr(false)
+		}, nil
+	case repository.BulkActionRestore:
+		return func(note *model.Note) {
+			note.IsDeleted = boolPtr(false)
+			note.IsArchived = boolPtr(false)
+		}, nil
+	case repository.BulkActionRecolor:
+		return func(note *model.Note) {
+			note.Color = op.Color
+			note.UpdatedAt = op.UpdatedAt
+		}, nil
+	case repository.BulkActionDelete:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown bulk action %q", op.Action)
+	}
+}
@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+func (mc MemoryClient) CreateNoteBook(ctx context.Context, notebook model.NoteBook) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	notebook = copyNoteBook(notebook)
+	notebook.ID = newID(notebook.ID)
+
+	for _, existing := range mc.Store.noteBooks {
+		if existing.ID == notebook.ID {
+			return "", duplicate("notebook already exists")
+		}
+		if existing.Name == notebook.Name {
+			return "", duplicate("notebook with this name already exists")
+		}
+	}
+
+	mc.Store.noteBooks = append(mc.Store.noteBooks, notebook)
+
+	return notebook.ID.Hex(), nil
+}
+
+func (mc MemoryClient) findNoteBook(ctx context.Context, match func(model.NoteBook) bool) (model.NoteBook, error) {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	for _, noteBook := range mc.Store.noteBooks {
+		if match(noteBook) {
+			return copyNoteBook(noteBook), nil
+		}
+	}
+
+	return model.NoteBook{}, model.NotFound("notebook not found")
+}
+
+func (mc MemoryClient) GetNoteBookByID(ctx context.Context, id string) (model.NoteBook, error) {
+	if err := ctx.Err(); err != nil {
+		return model.NoteBook{}, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return model.NoteBook{}, err
+	}
+
+	return mc.findNoteBook(ctx, func(noteBook model.NoteBook) bool { return noteBook.ID == docId })
+}
+
+func (mc MemoryClient) GetNoteBookByName(ctx context.Context, name string) (model.NoteBook, error) {
+	if err := ctx.Err(); err != nil {
+		return model.NoteBook{}, err
+	}
+
+	return mc.findNoteBook(ctx, func(noteBook model.NoteBook) bool { return noteBook.Name == name })
+}
+
+func (mc MemoryClient) GetOrCreateNoteBook(ctx context.Context, notebook model.NoteBook) (model.NoteBook, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return model.NoteBook{}, false, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	for _, existing := range mc.Store.noteBooks {
+		if existing.Name == notebook.Name {
+			return copyNoteBook(existing), false, nil
+		}
+	}
+
+	notebook = copyNoteBook(notebook)
+	notebook.ID = newID(notebook.ID)
+	mc.Store.noteBooks = append(mc.Store.noteBooks, notebook)
+
+	return copyNoteBook(notebook), true, nil
+}
+
+func (mc MemoryClient) GetNoteBooks(ctx context.Context) ([]model.NoteBook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	var noteBooks []model.NoteBook
+	for _, noteBook := range mc.Store.noteBooks {
+		noteBooks = append(noteBooks, copyNoteBook(noteBook))
+	}
+
+	return noteBooks, nil
+}
+
+func (mc MemoryClient) UpdateNoteBook(ctx context.Context, id, name, description string, isActive *bool) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	if name == "" && description == "" && isActive == nil {
+		return 0, nil
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	if name != "" {
+		for _, existing := range mc.Store.noteBooks {
+			if existing.Name == name && existing.ID != docId {
+				return 0, duplicate("notebook with this name already exists")
+			}
+		}
+	}
+
+	for i, noteBook := range mc.Store.noteBooks {
+		if noteBook.ID != docId {
+			continue
+		}
+
+		before := copyNoteBook(noteBook)
+		if name != "" {
+			noteBook.Name = name
+		}
+		if description != "" {
+			noteBook.Description = description
+		}
+		if isActive != nil {
+			noteBook.IsActive = copyBool(isActive)
+		}
+		mc.Store.noteBooks[i] = noteBook
+
+		return modified(before, noteBook), nil
+	}
+
+	return 0, nil
+}
+
+func (mc MemoryClient) DeleteNoteBook(ctx context.Context, id string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	before := len(mc.Store.noteBooks)
+	mc.Store.noteBooks = slices.DeleteFunc(mc.Store.noteBooks, func(noteBook model.NoteBook) bool { return noteBook.ID == docId })
+
+	return before - len(mc.Store.noteBooks), nil
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (mc MemoryClient) CreateReminder(ctx context.Context, reminder model.Reminder) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	reminder.ID = newID(reminder.ID)
+	if reminder.IsActive == nil {
+		reminder.IsActive = boolPtr(true)
+	}
+
+	for _, existing := range mc.Store.reminders {
+		if existing.ID == reminder.ID {
+			return "", duplicate("reminder already exists")
+		}
+	}
+
+	mc.Store.reminders = append(mc.Store.reminders, copyReminder(reminder))
+
+	return reminder.ID.Hex(), nil
+}
+
+func (mc MemoryClient) findReminders(ctx context.Context, match func(model.Reminder) bool) []model.Reminder {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	var reminders []model.Reminder
+	for _, reminder := range mc.Store.reminders {
+		if match(reminder) {
+			reminders = append(reminders, copyReminder(reminder))
+		}
+	}
+
+	return reminders
+}
+
+func (mc MemoryClient) GetReminderByID(ctx context.Context, id string) (model.Reminder, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Reminder{}, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return model.Reminder{}, err
+	}
+
+	reminders := mc.findReminders(ctx, func(reminder model.Reminder) bool { return reminder.ID == docId })
+	if len(reminders) == 0 {
+		return model.Reminder{}, model.NotFound("reminder not found")
+	}
+
+	return reminders[0], nil
+}
+
+func (mc MemoryClient) GetRemindersByNotes(ctx context.Context, noteIDs []string) ([]model.Reminder, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	docIds := make([]primitive.ObjectID, 0, len(noteIDs))
+	for _, id := range noteIDs {
+		docId, err := parseID(id, "wrong id")
+		if err != nil {
+			return []model.Reminder{}, err
+		}
+		docIds = append(docIds, docId)
+	}
+
+	return mc.findReminders(ctx, func(reminder model.Reminder) bool { return slices.Contains(docIds, reminder.NoteID) }), nil
+}
+
+func (mc MemoryClient) GetActiveReminders(ctx context.Context) ([]model.Reminder, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return mc.findReminders(ctx, func(reminder model.Reminder) bool { return isTrue(reminder.IsActive) }), nil
+}
+
+func (mc MemoryClient) UpdateReminder(ctx context.Context, id, name, message string, remindAt time.Time, isActive *bool, repeat string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	for i, reminder := range mc.Store.reminders {
+		if reminder.ID != docId {
+			continue
+		}
+
+		before := copyReminder(reminder)
+		if name != "" {
+			reminder.Name = name
+		}
+		if message != "" {
+			reminder.Message = message
+		}
+		if !remindAt.IsZero() {
+			reminder.RemindAt = remindAt
+		}
+		if isActive != nil {
+			reminder.IsActive = copyBool(isActive)
+		}
+		if repeat != "" {
+			reminder.Repeat = repeat
+		}
+		mc.Store.reminders[i] = reminder
+
+		return modified(before, reminder), nil
+	}
+
+	return 0, nil
+}
+
+func (mc MemoryClient) DeleteReminder(ctx context.Context, id string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	before := len(mc.Store.reminders)
+	mc.Store.reminders = slices.DeleteFunc(mc.Store.reminders, func(reminder model.Reminder) bool { return reminder.ID == docId })
+
+	return before - len(mc.Store.reminders), nil
+}
+
+func (mc MemoryClient) DeleteRemindersByNotes(ctx context.Context, noteIDs []string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docIds := make([]primitive.ObjectID, 0, len(noteIDs))
+	for _, id := range noteIDs {
+		docId, err := parseID(id, "wrong id")
+		if err != nil {
+			return 0, err
+		}
+		docIds = append(docIds, docId)
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	before := len(mc.Store.reminders)
+	mc.Store.reminders = slices.DeleteFunc(mc.Store.reminders, func(reminder model.Reminder) bool { return slices.Contains(docIds, reminder.NoteID) })
+
+	return before - len(mc.Store.reminders), nil
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+func changeKey(kind, id string) string {
+	return kind + "/" + id
+}
+
+func (mc MemoryClient) RecordChange(ctx context.Context, change model.SyncChange) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	if mc.Store.changes == nil {
+		mc.Store.changes = map[string]model.SyncChange{}
+	}
+
+	mc.Store.seq++
+	change.Seq = mc.Store.seq
+	mc.Store.changes[changeKey(change.Kind, change.EntityID)] = change
+
+	return change.Seq, nil
+}
+
+func (mc MemoryClient) GetChange(ctx context.Context, kind, id string) (model.SyncChange, error) {
+	if err := ctx.Err(); err != nil {
+		return model.SyncChange{}, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	change, ok := mc.Store.changes[changeKey(kind, id)]
+	if !ok {
+		return model.SyncChange{}, model.NotFound("change not found")
+	}
+
+	return change, nil
+}
+
+func (mc MemoryClient) GetChanges(ctx context.Context, userID string, since int64, limit int) ([]model.SyncChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	changes := []model.SyncChange{}
+	for _, change := range mc.Store.changes {
+		if change.Seq > since && (change.UserID == userID || change.UserID == "") {
+			changes = append(changes, change)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Seq < changes[j].Seq })
+	if len(changes) > limit {
+		changes = changes[:limit]
+	}
+
+	return changes, nil
+}
+
+func (mc MemoryClient) GetLastSyncSeq(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	return mc.Store.seq, nil
+}
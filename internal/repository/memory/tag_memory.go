@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (mc MemoryClient) CreateTag(ctx context.Context, tag model.Tag) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	tag.ID = newID(tag.ID)
+
+	for _, existing := range mc.Store.tags {
+		if existing.ID == tag.ID {
+			return "", duplicate("tag already exists")
+		}
+		if existing.Name == tag.Name {
+			return "", duplicate("tag with this name already exists")
+		}
+	}
+
+	mc.Store.tags = append(mc.Store.tags, tag)
+
+	return tag.ID.Hex(), nil
+}
+
+func (mc MemoryClient) findTags(ctx context.Context, match func(model.Tag) bool) []model.Tag {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	var tags []model.Tag
+	for _, tag := range mc.Store.tags {
+		if match(tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+func (mc MemoryClient) GetTagByID(ctx context.Context, id string) (model.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Tag{}, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	tags := mc.findTags(ctx, func(tag model.Tag) bool { return tag.ID == docId })
+	if len(tags) == 0 {
+		return model.Tag{}, model.NotFound("tag not found")
+	}
+
+	return tags[0], nil
+}
+
+func (mc MemoryClient) GetTagByName(ctx context.Context, tagName string) (model.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Tag{}, err
+	}
+
+	tags := mc.findTags(ctx, func(tag model.Tag) bool { return tag.Name == tagName })
+	if len(tags) == 0 {
+		return model.Tag{}, model.NotFound("tag not found")
+	}
+
+	return tags[0], nil
+}
+
+func (mc MemoryClient) GetTags(ctx context.Context) ([]model.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return mc.findTags(ctx, func(model.Tag) bool { return true }), nil
+}
+
+func (mc MemoryClient) GetTagsByIDs(ctx context.Context, ids []string) ([]model.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	docIds := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		docId, err := parseID(id, "wrong id")
+		if err != nil {
+			return []model.Tag{}, err
+		}
+		docIds = append(docIds, docId)
+	}
+
+	return mc.findTags(ctx, func(tag model.Tag) bool { return slices.Contains(docIds, tag.ID) }), nil
+}
+
+func (mc MemoryClient) GetTagDescendants(ctx context.Context, tagName string) ([]model.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	prefix := tagName + model.TagPathSeparator
+
+	return mc.findTags(ctx, func(tag model.Tag) bool { return strings.HasPrefix(tag.Name, prefix) }), nil
+}
+
+func (mc MemoryClient) UpdateTag(ctx context.Context, id, name, color string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	if name == "" && color == "" {
+		return 0, nil
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	if name != "" {
+		for _, existing := range mc.Store.tags {
+			if existing.Name == name && existing.ID != docId {
+				return 0, duplicate("tag with this name already exists")
+			}
+		}
+	}
+
+	for i, tag := range mc.Store.tags {
+		if tag.ID != docId {
+			continue
+		}
+
+		before := tag
+		if name != "" {
+			tag.Name = name
+		}
+		if color != "" {
+			tag.Color = color
+		}
+		mc.Store.tags[i] = tag
+
+		return modified(before, tag), nil
+	}
+
+	return 0, nil
+}
+
+func (mc MemoryClient) RenameTagDescendants(ctx context.Context, oldName, newName string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	oldPrefix := oldName + model.TagPathSeparator
+	newPrefix := newName + model.TagPathSeparator
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	//Как и уникальный индекс в БД, не даем переименованию совпасть с именем другого тега
+	for _, tag := range mc.Store.tags {
+		if strings.HasPrefix(tag.Name, oldPrefix) {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(tag.Name, newPrefix); ok && mc.Store.hasTagName(oldPrefix+rest) {
+			return 0, duplicate("tag with this name already exists")
+		}
+	}
+
+	count := 0
+	for i, tag := range mc.Store.tags {
+		if rest, ok := strings.CutPrefix(tag.Name, oldPrefix); ok {
+			renamed := newPrefix + rest
+			if renamed != tag.Name {
+				mc.Store.tags[i].Name = renamed
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
+func (mc MemoryClient) DeleteTag(ctx context.Context, id string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	before := len(mc.Store.tags)
+	mc.Store.tags = slices.DeleteFunc(mc.Store.tags, func(tag model.Tag) bool { return tag.ID == docId })
+
+	return before - len(mc.Store.tags), nil
+}
+
+// hasTagName вызывается под s.mu
+func (s *Store) hasTagName(name string) bool {
+	return slices.ContainsFunc(s.tags, func(tag model.Tag) bool { return tag.Name == name })
+}
@@ -0,0 +1,275 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (mc MemoryClient) CreateTemplate(ctx context.Context, template model.Template) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	template = copyTemplate(template)
+	template.ID = newID(template.ID)
+
+	for _, existing := range mc.Store.templates {
+		if existing.ID == template.ID {
+			return "", duplicate("template already exists")
+		}
+	}
+
+	mc.Store.templates = append(mc.Store.templates, template)
+
+	return template.ID.Hex(), nil
+}
+
+func (mc MemoryClient) findTemplates(ctx context.Context, match func(model.Template) bool) []model.Template {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	var templates []model.Template
+	for _, template := range mc.Store.templates {
+		if match(template) {
+			templates = append(templates, copyTemplate(template))
+		}
+	}
+
+	return templates
+}
+
+func (mc MemoryClient) GetTemplateByID(ctx context.Context, id string) (model.Template, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Template{}, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return model.Template{}, err
+	}
+
+	templates := mc.findTemplates(ctx, func(template model.Template) bool { return template.ID == docId })
+	if len(templates) == 0 {
+		return model.Template{}, model.NotFound("template not found")
+	}
+
+	return templates[0], nil
+}
+
+func (mc MemoryClient) GetTemplateByName(ctx context.Context, userID, name string) (model.Template, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Template{}, err
+	}
+
+	docId, err := parseID(userID, "wrong user id")
+	if err != nil {
+		return model.Template{}, err
+	}
+
+	templates := mc.findTemplates(ctx, func(template model.Template) bool {
+		return template.UserID == docId && template.Name == name
+	})
+	if len(templates) == 0 {
+		return model.Template{}, model.NotFound("template not found")
+	}
+
+	return templates[0], nil
+}
+
+func (mc MemoryClient) GetTemplatesByUserID(ctx context.Context, userID string) ([]model.Template, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	docId, err := parseID(userID, "wrong user id")
+	if err != nil {
+		return []model.Template{}, err
+	}
+
+	return mc.findTemplates(ctx, func(template model.Template) bool { return template.UserID == docId }), nil
+}
+
+func (mc MemoryClient) UpdateTemplate(ctx context.Context, id, name, text, color, noteBookID string, tagIDs []string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	var noteBookDocId primitive.ObjectID
+	if noteBookID != "" {
+		noteBookDocId, err = parseID(noteBookID, "wrong notebook id")
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var tagDocIds []primitive.ObjectID
+	if tagIDs != nil {
+		tagDocIds = make([]primitive.ObjectID, 0, len(tagIDs))
+		for _, tagID := range tagIDs {
+			tagDocId, err := primitive.ObjectIDFromHex(tagID)
+			if err != nil {
+				return 0, model.InvalidID("invalid tag ID: %v", err)
+			}
+			tagDocIds = append(tagDocIds, tagDocId)
+		}
+	}
+
+	if name == "" && text == "" && color == "" && noteBookID == "" && tagIDs == nil {
+		return 0, nil
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	for i, template := range mc.Store.templates {
+		if template.ID != docId {
+			continue
+		}
+
+		before := copyTemplate(template)
+		if name != "" {
+			template.Name = name
+		}
+		if text != "" {
+			template.Text = text
+		}
+		if color != "" {
+			template.Color = color
+		}
+		if noteBookID != "" {
+			template.NoteBookID = noteBookDocId
+		}
+		if tagIDs != nil {
+			template.Tags = tagDocIds
+		}
+		mc.Store.templates[i] = template
+
+		return modified(before, template), nil
+	}
+
+	return 0, nil
+}
+
+func (mc MemoryClient) DeleteTemplate(ctx context.Context, id string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.deleteTemplates(ctx, func(template model.Template) bool { return template.ID == docId }), nil
+}
+
+func (mc MemoryClient) DeleteTemplatesByUserID(ctx context.Context, userID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(userID, "wrong user id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.deleteTemplates(ctx, func(template model.Template) bool { return template.UserID == docId }), nil
+}
+
+func (mc MemoryClient) deleteTemplates(ctx context.Context, match func(model.Template) bool) int {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	before := len(mc.Store.templates)
+	mc.Store.templates = slices.DeleteFunc(mc.Store.templates, match)
+
+	return before - len(mc.Store.templates)
+}
+
+func (mc MemoryClient) UnlinkTemplatesFromTag(ctx context.Context, tagID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(tagID, "wrong tag id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.updateTemplates(ctx, func(template *model.Template) bool {
+		if !slices.Contains(template.Tags, docId) {
+			return false
+		}
+		template.Tags = pullTag(template.Tags, docId)
+		return true
+	}), nil
+}
+
+func (mc MemoryClient) ReplaceTagInTemplates(ctx context.Context, fromID, toID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	fromDocId, err := primitive.ObjectIDFromHex(fromID)
+	if err != nil {
+		return 0, model.InvalidID("invalid tag ID: %v", err)
+	}
+
+	toDocId, err := primitive.ObjectIDFromHex(toID)
+	if err != nil {
+		return 0, model.InvalidID("invalid tag ID: %v", err)
+	}
+
+	return mc.updateTemplates(ctx, func(template *model.Template) bool {
+		if !slices.Contains(template.Tags, fromDocId) {
+			return false
+		}
+		template.Tags = pullTag(addTag(template.Tags, toDocId), fromDocId)
+		return true
+	}), nil
+}
+
+func (mc MemoryClient) UnlinkTemplatesFromNoteBook(ctx context.Context, noteBookID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(noteBookID, "wrong notebook id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.updateTemplates(ctx, func(template *model.Template) bool {
+		if template.NoteBookID != docId {
+			return false
+		}
+		template.NoteBookID = primitive.NilObjectID
+		return true
+	}), nil
+}
+
+// updateTemplates применяет fn ко всем шаблонам и возвращает число тех, которые fn изменила
+func (mc MemoryClient) updateTemplates(ctx context.Context, fn func(*model.Template) bool) int {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	count := 0
+	for i := range mc.Store.templates {
+		if fn(&mc.Store.templates[i]) {
+			count++
+		}
+	}
+
+	return count
+}
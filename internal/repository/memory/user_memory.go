@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+func (mc MemoryClient) RegisterUser(ctx context.Context, user model.User) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	user.ID = newID(user.ID)
+
+	for _, existing := range mc.Store.users {
+		if existing.ID == user.ID {
+			return "", duplicate("user already exists")
+		}
+		if existing.Email == user.Email {
+			return "", duplicate("user with this email already exists")
+		}
+	}
+
+	mc.Store.users = append(mc.Store.users, user)
+
+	return user.ID.Hex(), nil
+}
+
+func (mc MemoryClient) findUser(ctx context.Context, match func(model.User) bool) (model.User, error) {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	for _, user := range mc.Store.users {
+		if match(user) {
+			return user, nil
+		}
+	}
+
+	return model.User{}, model.NotFound("user not found")
+}
+
+func (mc MemoryClient) LoginUser(ctx context.Context, email string) (model.User, error) {
+	if err := ctx.Err(); err != nil {
+		return model.User{}, err
+	}
+
+	return mc.findUser(ctx, func(user model.User) bool { return user.Email == email })
+}
+
+func (mc MemoryClient) GetProfile(ctx context.Context, id string) (model.User, error) {
+	if err := ctx.Err(); err != nil {
+		return model.User{}, err
+	}
+
+	docId, err := parseID(id, "wrong user id")
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return mc.findUser(ctx, func(user model.User) bool { return user.ID == docId })
+}
+
+func (mc MemoryClient) DeleteUser(ctx context.Context, id string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong user id")
+	if err != nil {
+		return 0, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	before := len(mc.Store.users)
+	mc.Store.users = slices.DeleteFunc(mc.Store.users, func(user model.User) bool { return user.ID == docId })
+
+	return before - len(mc.Store.users), nil
+}
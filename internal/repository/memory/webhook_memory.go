@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (mc MemoryClient) CreateWebhook(ctx context.Context, webhook model.Webhook) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	webhook = copyWebhook(webhook)
+	webhook.ID = newID(webhook.ID)
+
+	for _, existing := range mc.Store.webhooks {
+		if existing.ID == webhook.ID {
+			return "", duplicate("webhook already exists")
+		}
+	}
+
+	mc.Store.webhooks = append(mc.Store.webhooks, webhook)
+
+	return webhook.ID.Hex(), nil
+}
+
+func (mc MemoryClient) findWebhooks(ctx context.Context, match func(model.Webhook) bool) []model.Webhook {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	var webhooks []model.Webhook
+	for _, webhook := range mc.Store.webhooks {
+		if match(webhook) {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+
+	return webhooks
+}
+
+func (mc MemoryClient) GetWebhookByID(ctx context.Context, id string) (model.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Webhook{}, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	webhooks := mc.findWebhooks(ctx, func(webhook model.Webhook) bool { return webhook.ID == docId })
+	if len(webhooks) == 0 {
+		return model.Webhook{}, model.NotFound("webhook not found")
+	}
+
+	return webhooks[0], nil
+}
+
+func (mc MemoryClient) GetWebhooksByUserID(ctx context.Context, userID string) ([]model.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	docId, err := parseID(userID, "wrong user id")
+	if err != nil {
+		return []model.Webhook{}, err
+	}
+
+	return mc.findWebhooks(ctx, func(webhook model.Webhook) bool { return webhook.UserID == docId }), nil
+}
+
+func (mc MemoryClient) UpdateWebhook(ctx context.Context, id string, update repository.WebhookUpdate) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	for i, webhook := range mc.Store.webhooks {
+		if webhook.ID != docId {
+			continue
+		}
+
+		before := copyWebhook(webhook)
+		if update.URL != nil {
+			webhook.URL = *update.URL
+		}
+		if update.Secret != nil {
+			webhook.Secret = *update.Secret
+		}
+		if update.Events != nil {
+			webhook.Events = slices.Clone(*update.Events)
+		}
+		if update.IsActive != nil {
+			webhook.IsActive = boolPtr(*update.IsActive)
+		}
+		mc.Store.webhooks[i] = webhook
+
+		return modified(before, webhook), nil
+	}
+
+	return 0, nil
+}
+
+func (mc MemoryClient) DeleteWebhook(ctx context.Context, id string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(id, "wrong id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.deleteWebhooks(ctx, func(webhook model.Webhook) bool { return webhook.ID == docId }), nil
+}
+
+func (mc MemoryClient) DeleteWebhooksByUserID(ctx context.Context, userID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docId, err := parseID(userID, "wrong user id")
+	if err != nil {
+		return 0, err
+	}
+
+	return mc.deleteWebhooks(ctx, func(webhook model.Webhook) bool { return webhook.UserID == docId }), nil
+}
+
+func (mc MemoryClient) deleteWebhooks(ctx context.Context, match func(model.Webhook) bool) int {
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	before := len(mc.Store.webhooks)
+	mc.Store.webhooks = slices.DeleteFunc(mc.Store.webhooks, func(webhook model.Webhook) bool {
+		if match(webhook) {
+			delete(mc.Store.deliveries, webhook.ID)
+			return true
+		}
+		return false
+	})
+
+	return before - len(mc.Store.webhooks)
+}
+
+func (mc MemoryClient) AddWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	delivery.ID = newID(delivery.ID)
+
+	if !slices.ContainsFunc(mc.Store.webhooks, func(webhook model.Webhook) bool { return webhook.ID == delivery.WebhookID }) {
+		return model.NotFound("webhook not found")
+	}
+
+	if mc.Store.deliveries == nil {
+		mc.Store.deliveries = map[primitive.ObjectID][]model.WebhookDelivery{}
+	}
+
+	log := append(mc.Store.deliveries[delivery.WebhookID], delivery)
+	if len(log) > repository.MaxWebhookDeliveries {
+		log = slices.Clone(log[len(log)-repository.MaxWebhookDeliveries:])
+	}
+	mc.Store.deliveries[delivery.WebhookID] = log
+
+	return nil
+}
+
+func (mc MemoryClient) GetWebhookDeliveries(ctx context.Context, webhookID string) ([]model.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	docId, err := parseID(webhookID, "wrong webhook id")
+	if err != nil {
+		return []model.WebhookDelivery{}, err
+	}
+
+	mc.Store.mu.Lock()
+	defer mc.Store.mu.Unlock()
+
+	deliveries := slices.Clone(mc.Store.deliveries[docId])
+	slices.Reverse(deliveries)
+
+	return deliveries, nil
+}
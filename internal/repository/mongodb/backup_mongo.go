@@ -0,0 +1,312 @@
+package mongodb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	BackupFormat  = "notevault-backup"
+	BackupVersion = 1
+)
+
+const (
+	backupRecordHeader     = "header"
+	backupRecordCollection = "collection"
+	backupRecordDoc        = "doc"
+	backupRecordEnd        = "end"
+)
+
+const (
+	restoreBatchSize   = 500
+	maxBackupLineBytes = 64 << 20
+)
+
+// backupRecord - одна строка архива. Документы хранятся в каноническом Extended JSON,
+// поэтому ObjectID, даты и типы чисел восстанавливаются без потерь
+type backupRecord struct {
+	Type        string          `json:"type"`
+	Format      string          `json:"format,omitempty"`
+	Version     int             `json:"version,omitempty"`
+	CreatedAt   string          `json:"created_at,omitempty"`
+	Database    string          `json:"database,omitempty"`
+	Collections []string        `json:"collections,omitempty"`
+	Collection  string          `json:"collection,omitempty"`
+	Doc         json.RawMessage `json:"doc,omitempty"`
+	Count       int             `json:"count,omitempty"`
+	SHA256      string          `json:"sha256,omitempty"`
+}
+
+// DumpDatabase пишет коллекции в архив JSON-lines: заголовок, затем для каждой коллекции
+// ее документы и итоговая запись с количеством и контрольной суммой
+func DumpDatabase(ctx context.Context, db *mongo.Database, collections []string, w io.Writer) (map[string]int, error) {
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+
+	err := encoder.Encode(backupRecord{
+		Type:        backupRecordHeader,
+		Format:      BackupFormat,
+		Version:     BackupVersion,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		Database:    db.Name(),
+		Collections: collections,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+
+	for _, name := range collections {
+		count, err := dumpCollection(ctx, db.Collection(name), encoder)
+		if err != nil {
+			return nil, fmt.Errorf("dump %s: %w", name, err)
+		}
+		counts[name] = count
+	}
+
+	return counts, out.Flush()
+}
+
+func dumpCollection(ctx context.Context, coll *mongo.Collection, encoder *json.Encoder) (int, error) {
+	err := encoder.Encode(backupRecord{Type: backupRecordCollection, Collection: coll.Name()})
+	if err != nil {
+		return 0, err
+	}
+
+	cursor, err := coll.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	sum := sha256.New()
+	count := 0
+
+	for cursor.Next(ctx) {
+		doc, err := bson.MarshalExtJSON(cursor.Current, true, false)
+		if err != nil {
+			return 0, err
+		}
+
+		addBackupChecksum(sum, doc)
+		count++
+
+		err = encoder.Encode(backupRecord{Type: backupRecordDoc, Collection: coll.Name(), Doc: doc})
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+
+	err = encoder.Encode(backupRecord{
+		Type:       backupRecordEnd,
+		Collection: coll.Name(),
+		Count:      count,
+		SHA256:     hex.EncodeToString(sum.Sum(nil)),
+	})
+
+	return count, err
+}
+
+// RestoreDatabase восстанавливает архив в пустую базу. Архив читается дважды:
+// сначала проверяются формат, полнота и контрольные суммы, и только потом пишутся документы,
+// чтобы поврежденный архив не оставил базу наполовину заполненной
+func RestoreDatabase(ctx context.Context, db *mongo.Database, collections []string, open func() (io.ReadCloser, error)) (map[string]int, error) {
+	r, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := verifyBackup(r, collections)
+	r.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range counts {
+		n, err := db.Collection(name).CountDocuments(ctx, bson.D{}, options.Count().SetLimit(1))
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("collection %s is not empty", name)
+		}
+	}
+
+	r, err = open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	batches := map[string][]interface{}{}
+
+	flush := func(name string) error {
+		if len(batches[name]) == 0 {
+			return nil
+		}
+
+		_, err := db.Collection(name).InsertMany(ctx, batches[name], options.InsertMany().SetOrdered(false))
+		batches[name] = batches[name][:0]
+
+		return err
+	}
+
+	err = readBackup(r, func(rec backupRecord) error {
+		switch rec.Type {
+		case backupRecordDoc:
+			var doc bson.D
+			err := bson.UnmarshalExtJSON(rec.Doc, true, &doc)
+			if err != nil {
+				return err
+			}
+
+			batches[rec.Collection] = append(batches[rec.Collection], doc)
+			if len(batches[rec.Collection]) >= restoreBatchSize {
+				return flush(rec.Collection)
+			}
+		case backupRecordEnd:
+			return flush(rec.Collection)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("restore: %w", err)
+	}
+
+	return counts, nil
+}
+
+func verifyBackup(r io.Reader, collections []string) (map[string]int, error) {
+	var header *backupRecord
+	var current string
+	var sum hash.Hash
+	count := 0
+	counts := map[string]int{}
+
+	err := readBackup(r, func(rec backupRecord) error {
+		if header == nil {
+			if rec.Type != backupRecordHeader || rec.Format != BackupFormat {
+				return errors.New("not a NoteVault backup")
+			}
+			if rec.Version != BackupVersion {
+				return fmt.Errorf("unsupported backup version %d", rec.Version)
+			}
+			for _, name := range rec.Collections {
+				if !slices.Contains(collections, name) {
+					return fmt.Errorf("unknown collection %s", name)
+				}
+			}
+			header = &rec
+			return nil
+		}
+
+		switch rec.Type {
+		case backupRecordCollection:
+			if current != "" {
+				return fmt.Errorf("collection %s is not finished", current)
+			}
+			if !slices.Contains(header.Collections, rec.Collection) {
+				return fmt.Errorf("collection %s is not listed in header", rec.Collection)
+			}
+			if _, ok := counts[rec.Collection]; ok {
+				return fmt.Errorf("collection %s is duplicated", rec.Collection)
+			}
+			current, sum, count = rec.Collection, sha256.New(), 0
+		case backupRecordDoc:
+			if rec.Collection != current {
+				return fmt.Errorf("document of %s outside of its collection", rec.Collection)
+			}
+			addBackupChecksum(sum, rec.Doc)
+			count++
+		case backupRecordEnd:
+			if rec.Collection != current {
+				return fmt.Errorf("unexpected end of %s", rec.Collection)
+			}
+			if rec.Count != count {
+				return fmt.Errorf("collection %s: expected %d documents, found %d", current, rec.Count, count)
+			}
+			if rec.SHA256 != hex.EncodeToString(sum.Sum(nil)) {
+				return fmt.Errorf("collection %s: checksum mismatch", current)
+			}
+			counts[current] = count
+			current = ""
+		default:
+			return fmt.Errorf("unknown record type %q", rec.Type)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if header == nil {
+		return nil, errors.New("empty backup")
+	}
+	if current != "" {
+		return nil, fmt.Errorf("backup is truncated in collection %s", current)
+	}
+	for _, name := range header.Collections {
+		if _, ok := counts[name]; !ok {
+			return nil, fmt.Errorf("collection %s is missing", name)
+		}
+	}
+
+	return counts, nil
+}
+
+func readBackup(r io.Reader, fn func(backupRecord) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBackupLineBytes)
+
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		var rec backupRecord
+		err := json.Unmarshal(scanner.Bytes(), &rec)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		err = fn(rec)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// addBackupChecksum учитывает документ в контрольной сумме коллекции.
+// Сумма считается по компактному JSON, чтобы не зависеть от форматирования строки архива
+func addBackupChecksum(sum hash.Hash, doc json.RawMessage) {
+	var compact bytes.Buffer
+	if json.Compact(&compact, doc) != nil {
+		sum.Write(doc)
+	} else {
+		sum.Write(compact.Bytes())
+	}
+	sum.Write([]byte("\n"))
+}
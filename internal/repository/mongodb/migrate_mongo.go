@@ -0,0 +1,153 @@
+package mongodb
+
+import (
+	"context"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/lib/rank"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrateNoteTags заменяет имена тегов в заметках на ссылки по _id.
+// Теги, которых нет в коллекции, создаются. Повторный запуск ничего не меняет.
+func MigrateNoteTags(ctx context.Context, notes, tags MongoClient) (int, error) {
+	filter := bson.D{{Key: "tags", Value: bson.D{{Key: "$type", Value: "string"}}}}
+
+	cursor, err := notes.Client.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	tagIDs := map[string]primitive.ObjectID{}
+	migrated := 0
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID   primitive.ObjectID `bson:"_id"`
+			Tags bson.A             `bson:"tags"`
+		}
+
+		err := cursor.Decode(&doc)
+		if err != nil {
+			return migrated, err
+		}
+
+		var ids []primitive.ObjectID
+		seen := map[primitive.ObjectID]bool{}
+
+		for _, value := range doc.Tags {
+			var id primitive.ObjectID
+
+			switch v := value.(type) {
+			case primitive.ObjectID:
+				id = v
+			case string:
+				id, err = migrateTagName(ctx, tags, tagIDs, v)
+				if err != nil {
+					return migrated, err
+				}
+			default:
+				continue
+			}
+
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+
+		updateStmt := bson.D{{Key: "$set", Value: bson.D{{Key: "tags", Value: ids}}}}
+
+		_, err = notes.Client.UpdateByID(ctx, doc.ID, updateStmt)
+		if err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+
+	return migrated, cursor.Err()
+}
+
+func migrateTagName(ctx context.Context, tags MongoClient, cache map[string]primitive.ObjectID, name string) (primitive.ObjectID, error) {
+	if id, ok := cache[name]; ok {
+		return id, nil
+	}
+
+	tag, err := tags.GetTagByName(ctx, name)
+	if err == nil {
+		cache[name] = tag.ID
+		return tag.ID, nil
+	}
+
+	res, err := tags.CreateTag(ctx, model.Tag{Name: name})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(res)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	cache[name] = id
+	return id, nil
+}
+
+// MigrateNoteRanks выдает ключи сортировки заметкам, у которых их нет.
+// Внутри блокнота такие заметки встают в конец в порядке старого поля order.
+func MigrateNoteRanks(ctx context.Context, notes MongoClient) (int, error) {
+	filter := bson.D{{Key: "rank", Value: bson.D{{Key: "$exists", Value: false}}}}
+	opts := options.Find().SetSort(bson.D{
+		{Key: "notebook_id", Value: 1},
+		{Key: "order", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+
+	cursor, err := notes.Client.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	lastRanks := map[primitive.ObjectID]string{}
+	migrated := 0
+
+	for cursor.Next(ctx) {
+		var note model.Note
+
+		err := cursor.Decode(&note)
+		if err != nil {
+			return migrated, err
+		}
+
+		last, ok := lastRanks[note.NoteBookID]
+		if !ok {
+			noteBookID := ""
+			if !note.NoteBookID.IsZero() {
+				noteBookID = note.NoteBookID.Hex()
+			}
+
+			last, err = notes.GetLastNoteRank(ctx, noteBookID)
+			if err != nil {
+				return migrated, err
+			}
+		}
+
+		next, err := rank.After(last)
+		if err != nil {
+			return migrated, err
+		}
+
+		_, err = notes.UpdateNoteRank(ctx, note.ID.Hex(), next)
+		if err != nil {
+			return migrated, err
+		}
+
+		lastRanks[note.NoteBookID] = next
+		migrated++
+	}
+
+	return migrated, cursor.Err()
+}
@@ -1,7 +1,38 @@
 package mongodb
 
-import "go.mongodb.org/mongo-driver/mongo"
+import (
+	"context"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/mongo"
+)
 
 type MongoClient struct {
-	Client mongo.Collection
+	Client  mongo.Collection
+	Timeout time.Duration
+	session mongo.SessionContext
+}
+
+// ctx ограничивает запрос таймаутом из конфига. Отмена запроса клиентом прерывает и операцию в базе,
+// а внутри единицы работы операция выполняется в сессии ее транзакции
+func (mc MongoClient) ctx(ctx context.Context) (context.Context, context.CancelFunc) {
+	if mc.session != nil {
+		ctx = mongo.NewSessionContext(ctx, mc.session)
+	}
+
+	if mc.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, mc.Timeout)
+}
+
+// conflict заменяет нарушение уникального индекса на model.ErrConflict с понятным клиенту текстом
+func conflict(err error, message string) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return model.Conflict("%s", message)
+	}
+
+	return err
 }
@@ -3,17 +3,19 @@ package mongodb
 import (
 	"context"
 	"fmt"
-	"log/slog"
 
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func (mc MongoClient) MoveNoteToArchive(id string) error {
+func (mc MongoClient) MoveNoteToArchive(ctx context.Context, id string) error {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("wrong id")
+		return model.InvalidID("wrong id")
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
@@ -24,53 +26,48 @@ func (mc MongoClient) MoveNoteToArchive(id string) error {
 		}},
 	}
 
-	_, err = mc.Client.UpdateOne(context.Background(), filter, updateStmt)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return noteMatched(mc.Client.UpdateOne(ctx, filter, updateStmt))
 }
 
-func (mc MongoClient) RestoreNoteFromArchive(id string) error {
+func (mc MongoClient) RestoreNoteFromArchive(ctx context.Context, id string) error {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("wrong id")
+		return model.InvalidID("wrong id")
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
 	updateStmt := bson.D{{Key: "$set", Value: bson.D{{Key: "is_archived", Value: false}}}}
 
-	_, err = mc.Client.UpdateOne(context.Background(), filter, updateStmt)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return noteMatched(mc.Client.UpdateOne(ctx, filter, updateStmt))
 }
 
-func (mc MongoClient) GetArchivedNotes() ([]model.Note, error) {
+func (mc MongoClient) GetArchivedNotes(ctx context.Context) ([]model.Note, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	filter := bson.D{{Key: "is_archived", Value: true}}
 
-	cursor, err := mc.Client.Find(context.Background(), filter)
+	cursor, err := mc.Client.Find(ctx, filter)
 	if err != nil {
-		return []model.Note{}, fmt.Errorf("error finding notes in archive")
+		return []model.Note{}, fmt.Errorf("error finding notes in archive: %w", err)
 	}
-	defer cursor.Close(context.Background())
+	defer cursor.Close(ctx)
 
 	var notes []model.Note
 
-	for cursor.Next(context.Background()) {
+	for cursor.Next(ctx) {
 		var note model.Note
 
 		err := cursor.Decode(&note)
 		if err != nil {
-			slog.Error("error decoding notes", slog.String("error", err.Error()))
-			continue
+			return nil, fmt.Errorf("error decoding notes: %w", err)
 		}
 
 		notes = append(notes, note)
 	}
 
-	return notes, nil
+	return notes, cursor.Err()
 }
@@ -0,0 +1,191 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/rank"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (mc MongoClient) BulkUpdateNotes(ctx context.Context, ids []string, op repository.NoteBulkOp) ([]repository.BulkResult, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	results := make([]repository.BulkResult, len(ids))
+	docIds := make([]primitive.ObjectID, 0, len(ids))
+
+	for i, id := range ids {
+		results[i] = repository.BulkResult{ID: id, Status: repository.BulkStatusOK}
+
+		docId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			results[i].Status = repository.BulkStatusInvalidID
+			continue
+		}
+		docIds = append(docIds, docId)
+	}
+
+	if len(docIds) == 0 {
+		return results, nil
+	}
+
+	existing, err := mc.existingNoteIDs(ctx, docIds)
+	if err != nil {
+		return nil, err
+	}
+
+	updateStmt, err := bulkUpdateStmt(op)
+	if err != nil {
+		return nil, err
+	}
+
+	//Перенесенные заметки встают в конец блокнота в порядке запроса
+	var last string
+	var targetDocId primitive.ObjectID
+	if op.Action == repository.BulkActionMove {
+		targetDocId, _ = primitive.ObjectIDFromHex(op.NoteBookID)
+		last, err = mc.GetLastNoteRank(ctx, op.NoteBookID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var models []mongo.WriteModel
+	var modelItems []int
+
+	for i, id := range ids {
+		if results[i].Status != repository.BulkStatusOK {
+			continue
+		}
+
+		docId, _ := primitive.ObjectIDFromHex(id)
+		noteBookID, ok := existing[docId]
+		if !ok {
+			results[i].Status = repository.BulkStatusNotFound
+			continue
+		}
+
+		filter := bson.D{{Key: "_id", Value: docId}}
+		switch {
+		case op.Action == repository.BulkActionDelete:
+			models = append(models, mongo.NewDeleteOneModel().SetFilter(filter))
+		case op.Action == repository.BulkActionMove:
+			if noteBookID == targetDocId {
+				continue
+			}
+			last, err = rank.After(last)
+			if err != nil {
+				return nil, err
+			}
+			update := bson.D{{Key: "$set", Value: bson.D{{Key: "notebook_id", Value: targetDocId}, {Key: "rank", Value: last}}}}
+			models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update))
+		default:
+			models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(updateStmt))
+		}
+		modelItems = append(modelItems, i)
+	}
+
+	if len(models) == 0 {
+		return results, nil
+	}
+
+	_, err = mc.Client.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) {
+		for _, writeErr := range bulkErr.WriteErrors {
+			item := modelItems[writeErr.Index]
+			results[item].Status = repository.BulkStatusFailed
+			results[item].Error = writeErr.Message
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// existingNoteIDs возвращает найденные заметки и их блокноты
+func (mc MongoClient) existingNoteIDs(ctx context.Context, docIds []primitive.ObjectID) (map[primitive.ObjectID]primitive.ObjectID, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: docIds}}}}
+	opts := options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}, {Key: "notebook_id", Value: 1}})
+
+	cursor, err := mc.Client.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding notes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	existing := map[primitive.ObjectID]primitive.ObjectID{}
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID         primitive.ObjectID `bson:"_id"`
+			NoteBookID primitive.ObjectID `bson:"notebook_id"`
+		}
+
+		err := cursor.Decode(&doc)
+		if err != nil {
+			return nil, err
+		}
+
+		existing[doc.ID] = doc.NoteBookID
+	}
+
+	return existing, cursor.Err()
+}
+
+func bulkUpdateStmt(op repository.NoteBulkOp) (bson.D, error) {
+	switch op.Action {
+	case repository.BulkActionMove:
+		noteBookDocId, err := primitive.ObjectIDFromHex(op.NoteBookID)
+		if err != nil {
+			return nil, model.InvalidID("wrong notebook id")
+		}
+		return bson.D{{Key: "$set", Value: bson.D{{Key: "notebook_id", Value: noteBookDocId}}}}, nil
+	case repository.BulkActionAddTag, repository.BulkActionRemoveTag:
+		tagDocId, err := primitive.ObjectIDFromHex(op.TagID)
+		if err != nil {
+			return nil, model.InvalidID("invalid tag ID: %v", err)
+		}
+		operator := "$addToSet"
+		if op.Action == repository.BulkActionRemoveTag {
+			operator = "$pull"
+		}
+		return bson.D{{Key: operator, Value: bson.D{{Key: "tags", Value: tagDocId}}}}, nil
+	case repository.BulkActionArchive:
+		return bson.D{{Key: "$set", Value: bson.D{
+			{Key: "is_archived", Value: true},
+			{Key: "is_deleted", Value: false},
+		}}}, nil
+	case repository.BulkActionTrash:
+		return bson.D{{Key: "$set", Value: bson.D{
+			{Key: "is_deleted", Value: true},
+			{Key: "is_archived", Value: false},
+		}}}, nil
+	case repository.BulkActionRestore:
+		return bson.D{{Key: "$set", Value: bson.D{
+			{Key: "is_deleted", Value: false},
+			{Key: "is_archived", Value: false},
+		}}}, nil
+	case repository.BulkActionRecolor:
+		return bson.D{{Key: "$set", Value: bson.D{
+			{Key: "color", Value: op.Color},
+			{Key: "updated_at", Value: op.UpdatedAt},
+		}}}, nil
+	case repository.BulkActionDelete:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown bulk action %q", op.Action)
+	}
+}
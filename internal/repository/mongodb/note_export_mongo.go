@@ -0,0 +1,55 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StreamNotes передает в fn по одной заметке, доступной пользователю: его собственные и общие,
+// не загружая всю выборку в память. Таймаут запроса здесь не действует: выгрузка большого хранилища
+// может идти дольше, ее ограничивает только контекст запроса
+func (mc MongoClient) StreamNotes(ctx context.Context, userID string, fn func(model.Note) error) error {
+	docId, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return model.InvalidID("wrong user id")
+	}
+
+	filter := bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "user_id", Value: docId}},
+			bson.D{{Key: "user_id", Value: bson.D{{Key: "$exists", Value: false}}}},
+		}},
+	}
+	opts := options.Find().SetSort(bson.D{
+		{Key: "notebook_id", Value: 1},
+		{Key: "rank", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+
+	cursor, err := mc.Client.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("error finding notes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var note model.Note
+
+		err := cursor.Decode(&note)
+		if err != nil {
+			return fmt.Errorf("error decoding notes: %w", err)
+		}
+
+		err = fn(note)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
@@ -0,0 +1,77 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetJournalNote возвращает заметку с пустым ID, если за этот день записи нет
+func (mc MongoClient) GetJournalNote(ctx context.Context, userID, date string) (model.Note, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return model.Note{}, model.InvalidID("wrong user id")
+	}
+
+	var note model.Note
+
+	filter := bson.D{{Key: "user_id", Value: docId}, {Key: "journal_date", Value: date}}
+
+	err = mc.Client.FindOne(ctx, filter).Decode(&note)
+	if err == mongo.ErrNoDocuments {
+		return model.Note{}, nil
+	} else if err != nil {
+		return model.Note{}, err
+	}
+
+	return note, nil
+}
+
+// GetJournalDates возвращает даты записей в полуинтервале [from, to)
+func (mc MongoClient) GetJournalDates(ctx context.Context, userID, from, to string) ([]string, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return []string{}, model.InvalidID("wrong user id")
+	}
+
+	filter := bson.D{
+		{Key: "user_id", Value: docId},
+		{Key: "journal_date", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: to}}},
+		{Key: "is_deleted", Value: bson.D{{Key: "$ne", Value: true}}},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "journal_date", Value: 1}}).
+		SetProjection(bson.D{{Key: "journal_date", Value: 1}})
+
+	cursor, err := mc.Client.Find(ctx, filter, opts)
+	if err != nil {
+		return []string{}, fmt.Errorf("error finding journal notes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var dates []string
+
+	for cursor.Next(ctx) {
+		var note model.Note
+
+		err := cursor.Decode(&note)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding notes: %w", err)
+		}
+
+		dates = append(dates, note.JournalDate)
+	}
+
+	return dates, cursor.Err()
+}
@@ -3,36 +3,44 @@ package mongodb
 import (
 	"context"
 	"fmt"
-	"log/slog"
+	"slices"
 
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func (mc MongoClient) CreateNote(note model.Note) (string, error) {
-	res, err := mc.Client.InsertOne(context.Background(), note)
+func (mc MongoClient) CreateNote(ctx context.Context, note model.Note) (string, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	res, err := mc.Client.InsertOne(ctx, note)
 	if err != nil {
-		return "", err
+		return "", conflict(err, "daily note already exists")
 	}
 
 	return res.InsertedID.(primitive.ObjectID).Hex(), nil
 }
 
-func (mc MongoClient) GetNoteByID(id string) (model.Note, error) {
+func (mc MongoClient) GetNoteByID(ctx context.Context, id string) (model.Note, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return model.Note{}, fmt.Errorf("wrong id")
+		return model.Note{}, model.InvalidID("wrong id")
 	}
 
 	var note model.Note
 
 	filter := bson.D{{Key: "_id", Value: docId}}
 
-	err = mc.Client.FindOne(context.Background(), filter).Decode(&note)
+	err = mc.Client.FindOne(ctx, filter).Decode(&note)
 	if err == mongo.ErrNoDocuments {
-		return model.Note{}, fmt.Errorf("note not found")
+		return model.Note{}, model.NotFound("note not found")
 	} else if err != nil {
 		return model.Note{}, err
 	}
@@ -40,7 +48,10 @@ func (mc MongoClient) GetNoteByID(id string) (model.Note, error) {
 	return note, nil
 }
 
-func (mc MongoClient) GetNotes() ([]model.Note, error) {
+func (mc MongoClient) GetNotes(ctx context.Context) ([]model.Note, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	filter := bson.D{
 		{Key: "$and", Value: bson.A{
 			bson.D{
@@ -58,33 +69,35 @@ func (mc MongoClient) GetNotes() ([]model.Note, error) {
 		}},
 	}
 
-	cursor, err := mc.Client.Find(context.Background(), filter)
+	cursor, err := mc.Client.Find(ctx, filter, noteSortOptions())
 	if err != nil {
-		return []model.Note{}, fmt.Errorf("error finding notes")
+		return []model.Note{}, fmt.Errorf("error finding notes: %w", err)
 	}
-	defer cursor.Close(context.Background())
+	defer cursor.Close(ctx)
 
 	var notes []model.Note
 
-	for cursor.Next(context.Background()) {
+	for cursor.Next(ctx) {
 		var note model.Note
 
 		err := cursor.Decode(&note)
 		if err != nil {
-			slog.Error("error decoding notes", slog.String("error", err.Error()))
-			continue
+			return nil, fmt.Errorf("error decoding notes: %w", err)
 		}
 
 		notes = append(notes, note)
 	}
 
-	return notes, nil
+	return notes, cursor.Err()
 }
 
-func (mc MongoClient) GetNotesByNoteBookID(id string) ([]model.Note, error) {
+func (mc MongoClient) GetNotesByNoteBookID(ctx context.Context, id string) ([]model.Note, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return []model.Note{}, fmt.Errorf("wrong notebook id")
+		return []model.Note{}, model.InvalidID("wrong notebook id")
 	}
 
 	filter := bson.D{
@@ -105,33 +118,72 @@ func (mc MongoClient) GetNotesByNoteBookID(id string) ([]model.Note, error) {
 		}},
 	}
 
-	cursor, err := mc.Client.Find(context.Background(), filter)
+	cursor, err := mc.Client.Find(ctx, filter, noteSortOptions())
+	if err != nil {
+		return []model.Note{}, fmt.Errorf("error finding notes in notebook: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var notes []model.Note
+
+	for cursor.Next(ctx) {
+		var note model.Note
+
+		err := cursor.Decode(&note)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding notes: %w", err)
+		}
+
+		notes = append(notes, note)
+	}
+
+	return notes, cursor.Err()
+}
+
+func (mc MongoClient) GetNotesByIDs(ctx context.Context, ids []string) ([]model.Note, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docIds := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		docId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return []model.Note{}, model.InvalidID("wrong id")
+		}
+		docIds = append(docIds, docId)
+	}
+
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: docIds}}}}
+
+	cursor, err := mc.Client.Find(ctx, filter)
 	if err != nil {
-		return []model.Note{}, fmt.Errorf("error finding notes in notebook")
+		return []model.Note{}, fmt.Errorf("error finding notes: %w", err)
 	}
-	defer cursor.Close(context.Background())
+	defer cursor.Close(ctx)
 
 	var notes []model.Note
 
-	for cursor.Next(context.Background()) {
+	for cursor.Next(ctx) {
 		var note model.Note
 
 		err := cursor.Decode(&note)
 		if err != nil {
-			slog.Error("error decoding notes", slog.String("error", err.Error()))
-			continue
+			return nil, fmt.Errorf("error decoding notes: %w", err)
 		}
 
 		notes = append(notes, note)
 	}
 
-	return notes, nil
+	return notes, cursor.Err()
 }
 
-func (mc MongoClient) UpdateNote(id, name, text, color, updatedAt string, order int) (int, error) {
+func (mc MongoClient) UpdateNote(ctx context.Context, id, name, text, color, updatedAt string, order int) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return 0, fmt.Errorf("wrong id")
+		return 0, model.InvalidID("wrong id")
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
@@ -156,7 +208,7 @@ func (mc MongoClient) UpdateNote(id, name, text, color, updatedAt string, order
 
 	updateStmt := bson.D{{Key: "$set", Value: setDoc}}
 
-	res, err := mc.Client.UpdateOne(context.Background(), filter, updateStmt)
+	res, err := mc.Client.UpdateOne(ctx, filter, updateStmt)
 	if err != nil {
 		return 0, err
 	}
@@ -164,21 +216,63 @@ func (mc MongoClient) UpdateNote(id, name, text, color, updatedAt string, order
 	return int(res.ModifiedCount), nil
 }
 
-func (mc MongoClient) UpdateNoteNoteBook(noteID, noteBookID string) (int, error) {
-	docId, err := primitive.ObjectIDFromHex(noteID)
+// PatchNote меняет все поля одним UpdateOne, поэтому изменение применяется целиком или не применяется вовсе
+func (mc MongoClient) PatchNote(ctx context.Context, id string, patch repository.NotePatch) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return 0, fmt.Errorf("wrong id")
+		return 0, model.InvalidID("wrong id")
 	}
 
-	objNoteBookId, err := primitive.ObjectIDFromHex(noteBookID)
-	if err != nil {
-		return 0, fmt.Errorf("wrong id")
+	setDoc := bson.D{{Key: "updated_at", Value: patch.UpdatedAt}}
+	if patch.Name != nil {
+		setDoc = append(setDoc, bson.E{Key: "name", Value: *patch.Name})
+	}
+	if patch.Text != nil {
+		setDoc = append(setDoc, bson.E{Key: "text", Value: *patch.Text})
+	}
+	if patch.Color != nil {
+		setDoc = append(setDoc, bson.E{Key: "color", Value: *patch.Color})
+	}
+	if patch.Order != nil {
+		setDoc = append(setDoc, bson.E{Key: "order", Value: *patch.Order})
+	}
+	if patch.NoteBookID != nil {
+		noteBookDocId, err := noteBookValue(*patch.NoteBookID)
+		if err != nil {
+			return 0, err
+		}
+		setDoc = append(setDoc, bson.E{Key: "notebook_id", Value: noteBookDocId})
+
+		//Перенесенная заметка встает в конец нового блокнота
+		noteRank, err := mc.rankForMove(ctx, docId, *patch.NoteBookID)
+		if err != nil {
+			return 0, err
+		}
+		if noteRank != "" {
+			setDoc = append(setDoc, bson.E{Key: "rank", Value: noteRank})
+		}
+	}
+	if patch.Tags != nil {
+		tagDocIds := []primitive.ObjectID{}
+		for _, tagID := range *patch.Tags {
+			tagDocId, err := primitive.ObjectIDFromHex(tagID)
+			if err != nil {
+				return 0, model.InvalidID("invalid tag ID: %v", err)
+			}
+			if !slices.Contains(tagDocIds, tagDocId) {
+				tagDocIds = append(tagDocIds, tagDocId)
+			}
+		}
+		setDoc = append(setDoc, bson.E{Key: "tags", Value: tagDocIds})
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
-	updateStmt := bson.D{{Key: "$set", Value: bson.D{{Key: "notebook_id", Value: objNoteBookId}}}}
+	updateStmt := bson.D{{Key: "$set", Value: setDoc}}
 
-	res, err := mc.Client.UpdateOne(context.Background(), filter, updateStmt)
+	res, err := mc.Client.UpdateOne(ctx, filter, updateStmt)
 	if err != nil {
 		return 0, err
 	}
@@ -186,33 +280,88 @@ func (mc MongoClient) UpdateNoteNoteBook(noteID, noteBookID string) (int, error)
 	return int(res.ModifiedCount), nil
 }
 
-func (mc MongoClient) RemoveNoteBookFromNote(noteID string) (int, error) {
+func (mc MongoClient) UpdateNoteNoteBook(ctx context.Context, noteID, noteBookID string) (int, error) {
 	docId, err := primitive.ObjectIDFromHex(noteID)
 	if err != nil {
-		return 0, fmt.Errorf("wrong id")
+		return 0, model.InvalidID("wrong id")
 	}
 
-	filter := bson.D{{Key: "_id", Value: docId}}
-	updateStmt := bson.D{{Key: "$set", Value: bson.D{{Key: "notebook_id", Value: nil}}}}
+	if _, err := primitive.ObjectIDFromHex(noteBookID); err != nil {
+		return 0, model.InvalidID("wrong id")
+	}
 
-	res, err := mc.Client.UpdateOne(context.Background(), filter, updateStmt)
+	return mc.moveNotes(ctx, bson.D{{Key: "_id", Value: docId}}, noteBookID)
+}
+
+func (mc MongoClient) RemoveNoteBookFromNote(ctx context.Context, noteID string) (int, error) {
+	docId, err := primitive.ObjectIDFromHex(noteID)
+	if err != nil {
+		return 0, model.InvalidID("wrong id")
+	}
+
+	return mc.moveNotes(ctx, bson.D{{Key: "_id", Value: docId}}, "")
+}
+
+func (mc MongoClient) UnlinkNotesFromNoteBook(ctx context.Context, id string) (int, error) {
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, model.InvalidID("wrong id")
+	}
+
+	return mc.moveNotes(ctx, bson.D{{Key: "notebook_id", Value: docId}}, "")
+}
+
+func (mc MongoClient) CountNotesByNoteBookID(ctx context.Context, id string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, model.InvalidID("wrong notebook id")
+	}
+
+	filter := bson.D{{Key: "notebook_id", Value: docId}}
+
+	count, err := mc.Client.CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, err
 	}
 
-	return int(res.ModifiedCount), nil
+	return int(count), nil
 }
 
-func (mc MongoClient) UnlinkNotesFromNoteBook(id string) (int, error) {
+func (mc MongoClient) MoveNotesToNoteBook(ctx context.Context, fromID, toID string) (int, error) {
+	fromDocId, err := primitive.ObjectIDFromHex(fromID)
+	if err != nil {
+		return 0, model.InvalidID("wrong notebook id")
+	}
+
+	if _, err := primitive.ObjectIDFromHex(toID); err != nil {
+		return 0, model.InvalidID("wrong target notebook id")
+	}
+
+	return mc.moveNotes(ctx, bson.D{{Key: "notebook_id", Value: fromDocId}}, toID)
+}
+
+func (mc MongoClient) TrashNotesFromNoteBook(ctx context.Context, id string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return 0, fmt.Errorf("wrong id")
+		return 0, model.InvalidID("wrong notebook id")
 	}
 
 	filter := bson.D{{Key: "notebook_id", Value: docId}}
-	updateStmt := bson.D{{Key: "$unset", Value: bson.D{{Key: "notebook_id", Value: ""}}}}
+	updateStmt := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "is_deleted", Value: true},
+			{Key: "is_archived", Value: false},
+		}},
+		{Key: "$unset", Value: bson.D{{Key: "notebook_id", Value: ""}}},
+	}
 
-	res, err := mc.Client.UpdateMany(context.Background(), filter, updateStmt)
+	res, err := mc.Client.UpdateMany(ctx, filter, updateStmt)
 	if err != nil {
 		return 0, err
 	}
@@ -220,15 +369,18 @@ func (mc MongoClient) UnlinkNotesFromNoteBook(id string) (int, error) {
 	return int(res.ModifiedCount), nil
 }
 
-func (mc MongoClient) DeleteNote(id string) (int, error) {
+func (mc MongoClient) DeleteNote(ctx context.Context, id string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return 0, fmt.Errorf("wrong id")
+		return 0, model.InvalidID("wrong id")
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
 
-	res, err := mc.Client.DeleteOne(context.Background(), filter)
+	res, err := mc.Client.DeleteOne(ctx, filter)
 	if err != nil {
 		return 0, err
 	}
@@ -236,14 +388,62 @@ func (mc MongoClient) DeleteNote(id string) (int, error) {
 	return int(res.DeletedCount), nil
 }
 
-func (mc MongoClient) UnlinkNotesFromTag(tagName string) (int, error) {
-	filter := bson.D{{Key: "tags", Value: tagName}}
-	updateStmt := bson.D{{Key: "$pull", Value: bson.D{{Key: "tags", Value: tagName}}}}
+func (mc MongoClient) DeleteNotesByUserID(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
 
-	res, err := mc.Client.UpdateMany(context.Background(), filter, updateStmt)
+	docId, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, model.InvalidID("wrong user id")
+	}
+
+	filter := bson.D{{Key: "user_id", Value: docId}}
+
+	res, err := mc.Client.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.DeletedCount), nil
+}
+
+func (mc MongoClient) UnlinkNotesFromTag(ctx context.Context, tagID string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(tagID)
+	if err != nil {
+		return 0, model.InvalidID("wrong tag id")
+	}
+
+	filter := bson.D{{Key: "tags", Value: docId}}
+	updateStmt := bson.D{{Key: "$pull", Value: bson.D{{Key: "tags", Value: docId}}}}
+
+	res, err := mc.Client.UpdateMany(ctx, filter, updateStmt)
 	if err != nil {
 		return 0, err
 	}
 
 	return int(res.ModifiedCount), nil
 }
+
+// noteSortOptions ставит закрепленные заметки первыми, остальные - по ключу сортировки
+func noteSortOptions() *options.FindOptions {
+	return options.Find().SetSort(bson.D{
+		{Key: "is_pinned", Value: -1},
+		{Key: "rank", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+}
+
+// noteMatched заменяет обновление, не нашедшее заметку, на model.ErrNotFound
+func noteMatched(res *mongo.UpdateResult, err error) error {
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return model.NotFound("note not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (mc MongoClient) SetNotePinned(ctx context.Context, id string, pinned bool) error {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return model.InvalidID("wrong id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+	updateStmt := bson.D{{Key: "$set", Value: bson.D{{Key: "is_pinned", Value: pinned}}}}
+
+	return noteMatched(mc.Client.UpdateOne(ctx, filter, updateStmt))
+}
+
+func (mc MongoClient) SetNoteFavourite(ctx context.Context, id string, favourite bool) error {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return model.InvalidID("wrong id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+	updateStmt := bson.D{{Key: "$set", Value: bson.D{{Key: "is_favourite", Value: favourite}}}}
+
+	return noteMatched(mc.Client.UpdateOne(ctx, filter, updateStmt))
+}
+
+func (mc MongoClient) GetFavouriteNotes(ctx context.Context) ([]model.Note, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	filter := bson.D{
+		{Key: "is_favourite", Value: true},
+		{Key: "is_deleted", Value: bson.D{{Key: "$ne", Value: true}}},
+		{Key: "is_archived", Value: bson.D{{Key: "$ne", Value: true}}},
+	}
+
+	cursor, err := mc.Client.Find(ctx, filter, noteSortOptions())
+	if err != nil {
+		return []model.Note{}, fmt.Errorf("error finding favourite notes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var notes []model.Note
+
+	for cursor.Next(ctx) {
+		var note model.Note
+
+		err := cursor.Decode(&note)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding notes: %w", err)
+		}
+
+		notes = append(notes, note)
+	}
+
+	return notes, cursor.Err()
+}
@@ -0,0 +1,130 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/tagquery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (mc MongoClient) FindNotes(ctx context.Context, noteFilter repository.NoteFilter) ([]model.Note, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	filter, err := noteFilterDoc(noteFilter)
+	if err != nil {
+		return []model.Note{}, err
+	}
+
+	opts := noteSortOptions()
+	if noteFilter.Limit > 0 {
+		opts.SetLimit(int64(noteFilter.Limit))
+	}
+
+	cursor, err := mc.Client.Find(ctx, filter, opts)
+	if err != nil {
+		return []model.Note{}, fmt.Errorf("error finding notes by filter: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var notes []model.Note
+
+	for cursor.Next(ctx) {
+		var note model.Note
+
+		err := cursor.Decode(&note)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding notes: %w", err)
+		}
+
+		notes = append(notes, note)
+	}
+
+	return notes, cursor.Err()
+}
+
+func noteFilterDoc(noteFilter repository.NoteFilter) (bson.D, error) {
+	conditions := bson.A{}
+
+	switch noteFilter.Status {
+	case repository.NoteStatusActive, "":
+		conditions = append(conditions,
+			bson.D{{Key: "is_deleted", Value: bson.D{{Key: "$ne", Value: true}}}},
+			bson.D{{Key: "is_archived", Value: bson.D{{Key: "$ne", Value: true}}}},
+		)
+	case repository.NoteStatusArchived:
+		conditions = append(conditions, bson.D{{Key: "is_archived", Value: true}})
+	case repository.NoteStatusTrashed:
+		conditions = append(conditions, bson.D{{Key: "is_deleted", Value: true}})
+	case repository.NoteStatusAll:
+	default:
+		return nil, fmt.Errorf("unknown note status %q", noteFilter.Status)
+	}
+
+	if noteFilter.NoteBookID != "" {
+		docId, err := primitive.ObjectIDFromHex(noteFilter.NoteBookID)
+		if err != nil {
+			return nil, model.InvalidID("wrong notebook id")
+		}
+		conditions = append(conditions, bson.D{{Key: "notebook_id", Value: docId}})
+	}
+
+	if noteFilter.Tags != nil {
+		tagsDoc, err := tagExprDoc(noteFilter.Tags)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, tagsDoc)
+	}
+
+	if len(conditions) == 0 {
+		return bson.D{}, nil
+	}
+
+	return bson.D{{Key: "$and", Value: conditions}}, nil
+}
+
+func tagExprDoc(expr tagquery.Expr) (bson.D, error) {
+	switch e := expr.(type) {
+	case *tagquery.Tag:
+		tagDocIds := make([]primitive.ObjectID, 0, len(e.IDs))
+		for _, tagID := range e.IDs {
+			tagDocId, err := primitive.ObjectIDFromHex(tagID)
+			if err != nil {
+				return nil, model.InvalidID("invalid tag ID: %v", err)
+			}
+			tagDocIds = append(tagDocIds, tagDocId)
+		}
+		//Пустой $in не совпадает ни с одной заметкой - так ведет себя несуществующий тег
+		return bson.D{{Key: "tags", Value: bson.D{{Key: "$in", Value: tagDocIds}}}}, nil
+	case tagquery.And:
+		return tagExprListDoc("$and", e)
+	case tagquery.Or:
+		return tagExprListDoc("$or", e)
+	case tagquery.Not:
+		sub, err := tagExprDoc(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return bson.D{{Key: "$nor", Value: bson.A{sub}}}, nil
+	default:
+		return nil, fmt.Errorf("unknown tag expression %T", expr)
+	}
+}
+
+func tagExprListDoc(op string, exprs []tagquery.Expr) (bson.D, error) {
+	docs := bson.A{}
+	for _, sub := range exprs {
+		doc, err := tagExprDoc(sub)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	return bson.D{{Key: op, Value: docs}}, nil
+}
@@ -0,0 +1,188 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/lib/rank"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (mc MongoClient) UpdateNoteRank(ctx context.Context, id, noteRank string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, model.InvalidID("wrong id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+	updateStmt := bson.D{{Key: "$set", Value: bson.D{{Key: "rank", Value: noteRank}}}}
+
+	res, err := mc.Client.UpdateOne(ctx, filter, updateStmt)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+func (mc MongoClient) GetLastNoteRank(ctx context.Context, noteBookID string) (string, error) {
+	return mc.findNoteRank(ctx, noteBookID, bson.D{{Key: "$exists", Value: true}}, -1)
+}
+
+func (mc MongoClient) GetPrevNoteRank(ctx context.Context, noteBookID, noteRank string) (string, error) {
+	return mc.findNoteRank(ctx, noteBookID, bson.D{{Key: "$lt", Value: noteRank}}, -1)
+}
+
+func (mc MongoClient) GetNextNoteRank(ctx context.Context, noteBookID, noteRank string) (string, error) {
+	return mc.findNoteRank(ctx, noteBookID, bson.D{{Key: "$gt", Value: noteRank}}, 1)
+}
+
+// findNoteRank ищет ближайший ключ в блокноте; пустой noteBookID - заметки без блокнота.
+// Если подходящей заметки нет, возвращается пустая строка.
+func (mc MongoClient) findNoteRank(ctx context.Context, noteBookID string, rankCond bson.D, direction int) (string, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	var noteBookValue interface{}
+
+	if noteBookID != "" {
+		docId, err := primitive.ObjectIDFromHex(noteBookID)
+		if err != nil {
+			return "", model.InvalidID("wrong notebook id")
+		}
+		noteBookValue = docId
+	}
+
+	filter := bson.D{
+		{Key: "notebook_id", Value: noteBookValue},
+		{Key: "rank", Value: rankCond},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "rank", Value: direction}})
+
+	var note model.Note
+
+	err := mc.Client.FindOne(ctx, filter, opts).Decode(&note)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return note.Rank, nil
+}
+
+// noteBookValue - значение notebook_id для блокнота; пустой ID - null, как в RemoveNoteBookFromNote
+func noteBookValue(noteBookID string) (any, error) {
+	if noteBookID == "" {
+		return nil, nil
+	}
+
+	docId, err := primitive.ObjectIDFromHex(noteBookID)
+	if err != nil {
+		return nil, model.InvalidID("wrong notebook id")
+	}
+
+	return docId, nil
+}
+
+// moveNotes переносит подходящие под filter заметки в конец блокнота toID (пустой - заметки без блокнота),
+// сохраняя их порядок между собой. Заметки, которые уже лежат в этом блокноте, не меняются
+func (mc MongoClient) moveNotes(ctx context.Context, filter bson.D, toID string) (int, error) {
+	toValue, err := noteBookValue(toID)
+	if err != nil {
+		return 0, err
+	}
+
+	last, err := mc.GetLastNoteRank(ctx, toID)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	filter = bson.D{{Key: "$and", Value: bson.A{filter, bson.D{{Key: "notebook_id", Value: bson.D{{Key: "$ne", Value: toValue}}}}}}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.D{{Key: "_id", Value: 1}})
+
+	cursor, err := mc.Client.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, fmt.Errorf("error finding notes to move: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var models []mongo.WriteModel
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+
+		err := cursor.Decode(&doc)
+		if err != nil {
+			return 0, err
+		}
+
+		last, err = rank.After(last)
+		if err != nil {
+			return 0, err
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: doc.ID}}).
+			SetUpdate(bson.D{{Key: "$set", Value: bson.D{{Key: "notebook_id", Value: toValue}, {Key: "rank", Value: last}}}}))
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	res, err := mc.Client.BulkWrite(ctx, models)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+// rankForMove возвращает ключ в конце блокнота toID для заметки, которую туда переносят. Пустой ключ -
+// заметка уже лежит в этом блокноте или не найдена
+func (mc MongoClient) rankForMove(ctx context.Context, docId primitive.ObjectID, toID string) (string, error) {
+	note, err := mc.GetNoteByID(ctx, docId.Hex())
+	if errors.Is(err, model.ErrNotFound) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if noteBookHex(note.NoteBookID) == toID {
+		return "", nil
+	}
+
+	last, err := mc.GetLastNoteRank(ctx, toID)
+	if err != nil {
+		return "", err
+	}
+
+	return rank.After(last)
+}
+
+func noteBookHex(docId primitive.ObjectID) string {
+	if docId.IsZero() {
+		return ""
+	}
+
+	return docId.Hex()
+}
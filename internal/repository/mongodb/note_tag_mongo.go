@@ -3,23 +3,31 @@ package mongodb
 import (
 	"context"
 	"fmt"
-	"log/slog"
 
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func (mc MongoClient) AddTagToNote(noteID, tagName string) (int, error) {
+func (mc MongoClient) AddTagToNote(ctx context.Context, noteID, tagID string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(noteID)
 	if err != nil {
-		return 0, fmt.Errorf("invalid note ID: %v", err)
+		return 0, model.InvalidID("invalid note ID: %v", err)
+	}
+
+	tagDocId, err := primitive.ObjectIDFromHex(tagID)
+	if err != nil {
+		return 0, model.InvalidID("invalid tag ID: %v", err)
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
-	updateStmt := bson.D{{Key: "$addToSet", Value: bson.D{{Key: "tags", Value: tagName}}}}
+	updateStmt := bson.D{{Key: "$addToSet", Value: bson.D{{Key: "tags", Value: tagDocId}}}}
 
-	res, err := mc.Client.UpdateOne(context.Background(), filter, updateStmt)
+	res, err := mc.Client.UpdateOne(ctx, filter, updateStmt)
 	if err != nil {
 		return 0, err
 	}
@@ -27,16 +35,24 @@ func (mc MongoClient) AddTagToNote(noteID, tagName string) (int, error) {
 	return int(res.ModifiedCount), nil
 }
 
-func (mc MongoClient) RemoveTagFromNote(noteID, tagName string) (int, error) {
+func (mc MongoClient) RemoveTagFromNote(ctx context.Context, noteID, tagID string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(noteID)
 	if err != nil {
-		return 0, fmt.Errorf("invalid note ID: %v", err)
+		return 0, model.InvalidID("invalid note ID: %v", err)
+	}
+
+	tagDocId, err := primitive.ObjectIDFromHex(tagID)
+	if err != nil {
+		return 0, model.InvalidID("invalid tag ID: %v", err)
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
-	updateStmt := bson.D{{Key: "$pull", Value: bson.D{{Key: "tags", Value: tagName}}}}
+	updateStmt := bson.D{{Key: "$pull", Value: bson.D{{Key: "tags", Value: tagDocId}}}}
 
-	res, err := mc.Client.UpdateOne(context.Background(), filter, updateStmt)
+	res, err := mc.Client.UpdateOne(ctx, filter, updateStmt)
 	if err != nil {
 		return 0, err
 	}
@@ -44,43 +60,70 @@ func (mc MongoClient) RemoveTagFromNote(noteID, tagName string) (int, error) {
 	return int(res.ModifiedCount), nil
 }
 
-func (mc MongoClient) GetNotesByTags(tagNames []string) ([]model.Note, error) {
-	filter := bson.D{
-		{Key: "$and", Value: bson.A{
-			bson.D{{Key: "tags", Value: bson.D{{Key: "$all", Value: tagNames}}}},
-			bson.D{
-				{Key: "$or", Value: bson.A{
-					bson.D{{Key: "is_deleted", Value: bson.D{{Key: "$exists", Value: false}}}},
-					bson.D{{Key: "is_deleted", Value: false}},
-				}},
-			},
-			bson.D{
-				{Key: "$or", Value: bson.A{
-					bson.D{{Key: "is_archived", Value: bson.D{{Key: "$exists", Value: false}}}},
-					bson.D{{Key: "is_archived", Value: false}},
-				}},
-			},
-		}},
+func (mc MongoClient) ReplaceTagInNotes(ctx context.Context, fromID, toID string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	fromDocId, err := primitive.ObjectIDFromHex(fromID)
+	if err != nil {
+		return 0, model.InvalidID("invalid tag ID: %v", err)
 	}
-	cursor, err := mc.Client.Find(context.Background(), filter)
+
+	toDocId, err := primitive.ObjectIDFromHex(toID)
 	if err != nil {
-		return []model.Note{}, fmt.Errorf("error finding notes by tags: %v", err)
+		return 0, model.InvalidID("invalid tag ID: %v", err)
 	}
-	defer cursor.Close(context.Background())
 
-	var notes []model.Note
+	filter := bson.D{{Key: "tags", Value: fromDocId}}
+
+	//$addToSet и $pull по одному полю нельзя совместить в одном обновлении
+	_, err = mc.Client.UpdateMany(ctx, filter, bson.D{{Key: "$addToSet", Value: bson.D{{Key: "tags", Value: toDocId}}}})
+	if err != nil {
+		return 0, err
+	}
 
-	for cursor.Next(context.Background()) {
-		var note model.Note
+	res, err := mc.Client.UpdateMany(ctx, filter, bson.D{{Key: "$pull", Value: bson.D{{Key: "tags", Value: fromDocId}}}})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+func (mc MongoClient) CountNotesByTags(ctx context.Context) (map[string]int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "is_deleted", Value: bson.D{{Key: "$ne", Value: true}}}}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$tags"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+
+	cursor, err := mc.Client.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error counting notes by tags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := map[string]int{}
+
+	for cursor.Next(ctx) {
+		var row struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Count int                `bson:"count"`
+		}
 
-		err := cursor.Decode(&note)
+		err := cursor.Decode(&row)
 		if err != nil {
-			slog.Error("error decoding notes", slog.String("error", err.Error()))
-			continue
+			return nil, fmt.Errorf("error decoding tag counts: %w", err)
 		}
 
-		notes = append(notes, note)
+		counts[row.ID.Hex()] = row.Count
 	}
 
-	return notes, nil
+	return counts, cursor.Err()
 }
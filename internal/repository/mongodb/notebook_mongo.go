@@ -3,36 +3,42 @@ package mongodb
 import (
 	"context"
 	"fmt"
-	"log/slog"
 
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func (mc MongoClient) CreateNoteBook(notebook model.NoteBook) (string, error) {
-	res, err := mc.Client.InsertOne(context.Background(), notebook)
+func (mc MongoClient) CreateNoteBook(ctx context.Context, notebook model.NoteBook) (string, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	res, err := mc.Client.InsertOne(ctx, notebook)
 	if err != nil {
-		return "", err
+		return "", conflict(err, "notebook with this name already exists")
 	}
 
 	return res.InsertedID.(primitive.ObjectID).Hex(), nil
 }
 
-func (mc MongoClient) GetNoteBookByID(id string) (model.NoteBook, error) {
+func (mc MongoClient) GetNoteBookByID(ctx context.Context, id string) (model.NoteBook, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return model.NoteBook{}, fmt.Errorf("wrong id")
+		return model.NoteBook{}, model.InvalidID("wrong id")
 	}
 
 	var noteBook model.NoteBook
 
 	filter := bson.D{{Key: "_id", Value: docId}}
 
-	err = mc.Client.FindOne(context.Background(), filter).Decode(&noteBook)
+	err = mc.Client.FindOne(ctx, filter).Decode(&noteBook)
 	if err == mongo.ErrNoDocuments {
-		return model.NoteBook{}, fmt.Errorf("notebook not found")
+		return model.NoteBook{}, model.NotFound("notebook not found")
 	} else if err != nil {
 		return model.NoteBook{}, err
 	}
@@ -40,36 +46,87 @@ func (mc MongoClient) GetNoteBookByID(id string) (model.NoteBook, error) {
 	return noteBook, nil
 }
 
-func (mc MongoClient) GetNoteBooks() ([]model.NoteBook, error) {
+func (mc MongoClient) GetNoteBookByName(ctx context.Context, name string) (model.NoteBook, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	var noteBook model.NoteBook
+
+	filter := bson.D{{Key: "name", Value: name}}
+
+	err := mc.Client.FindOne(ctx, filter).Decode(&noteBook)
+	if err == mongo.ErrNoDocuments {
+		return model.NoteBook{}, model.NotFound("notebook not found")
+	} else if err != nil {
+		return model.NoteBook{}, err
+	}
+
+	return noteBook, nil
+}
+
+func (mc MongoClient) GetOrCreateNoteBook(ctx context.Context, notebook model.NoteBook) (model.NoteBook, bool, error) {
+	upsertCtx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	filter := bson.D{{Key: "name", Value: notebook.Name}}
+	updateStmt := bson.D{{Key: "$setOnInsert", Value: bson.D{
+		{Key: "description", Value: notebook.Description},
+		{Key: "is_active", Value: notebook.IsActive},
+	}}}
+
+	//Upsert атомарен только благодаря уникальному индексу по name: проигравший гонку получает
+	//ошибку дубликата и просто читает блокнот, созданный другим запросом
+	created := false
+	res, err := mc.Client.UpdateOne(upsertCtx, filter, updateStmt, options.Update().SetUpsert(true))
+	if err == nil {
+		created = res.UpsertedID != nil
+	} else if !mongo.IsDuplicateKeyError(err) {
+		return model.NoteBook{}, false, err
+	}
+
+	noteBook, err := mc.GetNoteBookByName(ctx, notebook.Name)
+	if err != nil {
+		return model.NoteBook{}, false, err
+	}
+
+	return noteBook, created, nil
+}
+
+func (mc MongoClient) GetNoteBooks(ctx context.Context) ([]model.NoteBook, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	filter := bson.D{}
 
-	cursor, err := mc.Client.Find(context.Background(), filter)
+	cursor, err := mc.Client.Find(ctx, filter)
 	if err != nil {
-		return []model.NoteBook{}, fmt.Errorf("error finding notebooks")
+		return []model.NoteBook{}, fmt.Errorf("error finding notebooks: %w", err)
 	}
-	defer cursor.Close(context.Background())
+	defer cursor.Close(ctx)
 
 	var noteBooks []model.NoteBook
 
-	for cursor.Next(context.Background()) {
+	for cursor.Next(ctx) {
 		var noteBook model.NoteBook
 
 		err := cursor.Decode(&noteBook)
 		if err != nil {
-			slog.Error("error decoding notebooks", slog.String("error", err.Error()))
-			continue
+			return nil, fmt.Errorf("error decoding notebooks: %w", err)
 		}
 
 		noteBooks = append(noteBooks, noteBook)
 	}
 
-	return noteBooks, nil
+	return noteBooks, cursor.Err()
 }
 
-func (mc MongoClient) UpdateNoteBook(id, name, description string, isActive *bool) (int, error) {
+func (mc MongoClient) UpdateNoteBook(ctx context.Context, id, name, description string, isActive *bool) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return 0, fmt.Errorf("wrong id")
+		return 0, model.InvalidID("wrong id")
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
@@ -90,23 +147,26 @@ func (mc MongoClient) UpdateNoteBook(id, name, description string, isActive *boo
 
 	updateStmt := bson.D{{Key: "$set", Value: setDoc}}
 
-	res, err := mc.Client.UpdateOne(context.Background(), filter, updateStmt)
+	res, err := mc.Client.UpdateOne(ctx, filter, updateStmt)
 	if err != nil {
-		return 0, err
+		return 0, conflict(err, "notebook with this name already exists")
 	}
 
 	return int(res.ModifiedCount), nil
 }
 
-func (mc MongoClient) DeleteNoteBook(id string) (int, error) {
+func (mc MongoClient) DeleteNoteBook(ctx context.Context, id string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return 0, fmt.Errorf("wrong id")
+		return 0, model.InvalidID("wrong id")
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
 
-	res, err := mc.Client.DeleteOne(context.Background(), filter)
+	res, err := mc.Client.DeleteOne(ctx, filter)
 	if err != nil {
 		return 0, err
 	}
@@ -2,21 +2,177 @@ package mongodb
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func (mc MongoClient) CreateReminder(reminder model.Reminder) (string, error) {
+func (mc MongoClient) CreateReminder(ctx context.Context, reminder model.Reminder) (string, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	if reminder.IsActive == nil {
 		isActive := true
 		reminder.IsActive = &isActive
 	}
 
-	res, err := mc.Client.InsertOne(context.Background(), reminder)
+	res, err := mc.Client.InsertOne(ctx, reminder)
 	if err != nil {
 		return "", err
 	}
 
 	return res.InsertedID.(primitive.ObjectID).Hex(), nil
 }
+
+func (mc MongoClient) GetReminderByID(ctx context.Context, id string) (model.Reminder, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return model.Reminder{}, model.InvalidID("wrong id")
+	}
+
+	var reminder model.Reminder
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+
+	err = mc.Client.FindOne(ctx, filter).Decode(&reminder)
+	if err == mongo.ErrNoDocuments {
+		return model.Reminder{}, model.NotFound("reminder not found")
+	} else if err != nil {
+		return model.Reminder{}, err
+	}
+
+	return reminder, nil
+}
+
+func (mc MongoClient) findReminders(ctx context.Context, filter bson.D) ([]model.Reminder, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	cursor, err := mc.Client.Find(ctx, filter)
+	if err != nil {
+		return []model.Reminder{}, fmt.Errorf("error finding reminders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reminders []model.Reminder
+
+	for cursor.Next(ctx) {
+		var reminder model.Reminder
+
+		err := cursor.Decode(&reminder)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding reminders: %w", err)
+		}
+
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, cursor.Err()
+}
+
+func (mc MongoClient) GetRemindersByNotes(ctx context.Context, noteIDs []string) ([]model.Reminder, error) {
+	docIds := make([]primitive.ObjectID, 0, len(noteIDs))
+	for _, id := range noteIDs {
+		docId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return []model.Reminder{}, model.InvalidID("wrong id")
+		}
+		docIds = append(docIds, docId)
+	}
+
+	return mc.findReminders(ctx, bson.D{{Key: "note_id", Value: bson.D{{Key: "$in", Value: docIds}}}})
+}
+
+func (mc MongoClient) GetActiveReminders(ctx context.Context) ([]model.Reminder, error) {
+	return mc.findReminders(ctx, bson.D{{Key: "is_active", Value: true}})
+}
+
+func (mc MongoClient) UpdateReminder(ctx context.Context, id, name, message string, remindAt time.Time, isActive *bool, repeat string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, model.InvalidID("wrong id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+
+	setDoc := bson.D{}
+	if name != "" {
+		setDoc = append(setDoc, bson.E{Key: "name", Value: name})
+	}
+	if message != "" {
+		setDoc = append(setDoc, bson.E{Key: "message", Value: message})
+	}
+	if !remindAt.IsZero() {
+		setDoc = append(setDoc, bson.E{Key: "remind_at", Value: remindAt})
+	}
+	if isActive != nil {
+		setDoc = append(setDoc, bson.E{Key: "is_active", Value: *isActive})
+	}
+	if repeat != "" {
+		setDoc = append(setDoc, bson.E{Key: "repeat", Value: repeat})
+	}
+	if len(setDoc) == 0 {
+		return 0, nil
+	}
+
+	updateStmt := bson.D{{Key: "$set", Value: setDoc}}
+
+	res, err := mc.Client.UpdateOne(ctx, filter, updateStmt)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+func (mc MongoClient) DeleteReminder(ctx context.Context, id string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, model.InvalidID("wrong id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+
+	res, err := mc.Client.DeleteOne(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.DeletedCount), nil
+}
+
+func (mc MongoClient) DeleteRemindersByNotes(ctx context.Context, noteIDs []string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docIds := make([]primitive.ObjectID, 0, len(noteIDs))
+	for _, id := range noteIDs {
+		docId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return 0, model.InvalidID("wrong id")
+		}
+		docIds = append(docIds, docId)
+	}
+
+	filter := bson.D{{Key: "note_id", Value: bson.D{{Key: "$in", Value: docIds}}}}
+
+	res, err := mc.Client.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.DeletedCount), nil
+}
@@ -0,0 +1,116 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Журнал синхронизации: документ с _id "kind/id" на каждую сущность и счетчик seqCounterID.
+// У счетчика нет user_id, поэтому в выборку изменений он не попадает
+const seqCounterID = "seq"
+
+type syncChangeDoc struct {
+	ID               string `bson:"_id"`
+	model.SyncChange `bson:",inline"`
+}
+
+func (mc MongoClient) RecordChange(ctx context.Context, change model.SyncChange) (int64, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	var counter struct {
+		Value int64 `bson:"value"`
+	}
+
+	err := mc.Client.FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: seqCounterID}},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "value", Value: 1}}}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+
+	change.Seq = counter.Value
+	doc := syncChangeDoc{ID: change.Kind + "/" + change.EntityID, SyncChange: change}
+
+	_, err = mc.Client.ReplaceOne(ctx, bson.D{{Key: "_id", Value: doc.ID}}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return 0, err
+	}
+
+	return change.Seq, nil
+}
+
+func (mc MongoClient) GetChange(ctx context.Context, kind, id string) (model.SyncChange, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	var doc syncChangeDoc
+
+	filter := bson.D{{Key: "_id", Value: kind + "/" + id}}
+
+	err := mc.Client.FindOne(ctx, filter).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return model.SyncChange{}, model.NotFound("change not found")
+	} else if err != nil {
+		return model.SyncChange{}, err
+	}
+
+	return doc.SyncChange, nil
+}
+
+func (mc MongoClient) GetChanges(ctx context.Context, userID string, since int64, limit int) ([]model.SyncChange, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	filter := bson.D{
+		{Key: "seq", Value: bson.D{{Key: "$gt", Value: since}}},
+		{Key: "user_id", Value: bson.D{{Key: "$in", Value: bson.A{userID, ""}}}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}).SetLimit(int64(limit))
+
+	cursor, err := mc.Client.Find(ctx, filter, opts)
+	if err != nil {
+		return []model.SyncChange{}, fmt.Errorf("error finding changes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	changes := []model.SyncChange{}
+
+	for cursor.Next(ctx) {
+		var doc syncChangeDoc
+
+		err := cursor.Decode(&doc)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding changes: %w", err)
+		}
+
+		changes = append(changes, doc.SyncChange)
+	}
+
+	return changes, cursor.Err()
+}
+
+func (mc MongoClient) GetLastSyncSeq(ctx context.Context) (int64, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	var counter struct {
+		Value int64 `bson:"value"`
+	}
+
+	err := mc.Client.FindOne(ctx, bson.D{{Key: "_id", Value: seqCounterID}}).Decode(&counter)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return counter.Value, nil
+}
@@ -3,7 +3,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
-	"log/slog"
+	"regexp"
 
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
 	"go.mongodb.org/mongo-driver/bson"
@@ -11,28 +11,34 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func (mc MongoClient) CreateTag(tag model.Tag) (string, error) {
-	res, err := mc.Client.InsertOne(context.Background(), tag)
+func (mc MongoClient) CreateTag(ctx context.Context, tag model.Tag) (string, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	res, err := mc.Client.InsertOne(ctx, tag)
 	if err != nil {
-		return "", err
+		return "", conflict(err, "tag with this name already exists")
 	}
 
 	return res.InsertedID.(primitive.ObjectID).Hex(), nil
 }
 
-func (mc MongoClient) GetTagByID(id string) (model.Tag, error) {
+func (mc MongoClient) GetTagByID(ctx context.Context, id string) (model.Tag, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return model.Tag{}, fmt.Errorf("wrong id")
+		return model.Tag{}, model.InvalidID("wrong id")
 	}
 
 	var tag model.Tag
 
 	filter := bson.D{{Key: "_id", Value: docId}}
 
-	err = mc.Client.FindOne(context.Background(), filter).Decode(&tag)
+	err = mc.Client.FindOne(ctx, filter).Decode(&tag)
 	if err == mongo.ErrNoDocuments {
-		return model.Tag{}, fmt.Errorf("tag not found")
+		return model.Tag{}, model.NotFound("tag not found")
 	} else if err != nil {
 		return model.Tag{}, err
 	}
@@ -40,14 +46,17 @@ func (mc MongoClient) GetTagByID(id string) (model.Tag, error) {
 	return tag, nil
 }
 
-func (mc MongoClient) GetTagByName(tagName string) (model.Tag, error) {
+func (mc MongoClient) GetTagByName(ctx context.Context, tagName string) (model.Tag, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	var tag model.Tag
 
 	filter := bson.D{{Key: "name", Value: tagName}}
 
-	err := mc.Client.FindOne(context.Background(), filter).Decode(&tag)
+	err := mc.Client.FindOne(ctx, filter).Decode(&tag)
 	if err == mongo.ErrNoDocuments {
-		return model.Tag{}, fmt.Errorf("tag not found")
+		return model.Tag{}, model.NotFound("tag not found")
 	} else if err != nil {
 		return model.Tag{}, err
 	}
@@ -55,36 +64,107 @@ func (mc MongoClient) GetTagByName(tagName string) (model.Tag, error) {
 	return tag, nil
 }
 
-func (mc MongoClient) GetTags() ([]model.Tag, error) {
+func (mc MongoClient) GetTags(ctx context.Context) ([]model.Tag, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	filter := bson.D{}
 
-	cursor, err := mc.Client.Find(context.Background(), filter)
+	cursor, err := mc.Client.Find(ctx, filter)
+	if err != nil {
+		return []model.Tag{}, fmt.Errorf("error finding tags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tags []model.Tag
+
+	for cursor.Next(ctx) {
+		var tag model.Tag
+
+		err := cursor.Decode(&tag)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding tags: %w", err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, cursor.Err()
+}
+
+func (mc MongoClient) GetTagsByIDs(ctx context.Context, ids []string) ([]model.Tag, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docIds := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		docId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return []model.Tag{}, model.InvalidID("wrong id")
+		}
+		docIds = append(docIds, docId)
+	}
+
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: docIds}}}}
+
+	cursor, err := mc.Client.Find(ctx, filter)
 	if err != nil {
-		return []model.Tag{}, fmt.Errorf("error finding tags")
+		return []model.Tag{}, fmt.Errorf("error finding tags: %w", err)
 	}
-	defer cursor.Close(context.Background())
+	defer cursor.Close(ctx)
 
 	var tags []model.Tag
 
-	for cursor.Next(context.Background()) {
+	for cursor.Next(ctx) {
 		var tag model.Tag
 
 		err := cursor.Decode(&tag)
 		if err != nil {
-			slog.Error("error decoding notes", slog.String("error", err.Error()))
-			continue
+			return nil, fmt.Errorf("error decoding tags: %w", err)
 		}
 
 		tags = append(tags, tag)
 	}
 
-	return tags, nil
+	return tags, cursor.Err()
 }
 
-func (mc MongoClient) UpdateTag(id, name, color string) (int, error) {
+func (mc MongoClient) GetTagDescendants(ctx context.Context, tagName string) ([]model.Tag, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	prefix := "^" + regexp.QuoteMeta(tagName+model.TagPathSeparator)
+	filter := bson.D{{Key: "name", Value: primitive.Regex{Pattern: prefix}}}
+
+	cursor, err := mc.Client.Find(ctx, filter)
+	if err != nil {
+		return []model.Tag{}, fmt.Errorf("error finding descendant tags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tags []model.Tag
+
+	for cursor.Next(ctx) {
+		var tag model.Tag
+
+		err := cursor.Decode(&tag)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding tags: %w", err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, cursor.Err()
+}
+
+func (mc MongoClient) UpdateTag(ctx context.Context, id, name, color string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return 0, fmt.Errorf("wrong id")
+		return 0, model.InvalidID("wrong id")
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
@@ -102,23 +182,55 @@ func (mc MongoClient) UpdateTag(id, name, color string) (int, error) {
 
 	updateStmt := bson.D{{Key: "$set", Value: setDoc}}
 
-	res, err := mc.Client.UpdateOne(context.Background(), filter, updateStmt)
+	res, err := mc.Client.UpdateOne(ctx, filter, updateStmt)
 	if err != nil {
-		return 0, err
+		return 0, conflict(err, "tag with this name already exists")
 	}
 
 	return int(res.ModifiedCount), nil
 }
 
-func (mc MongoClient) DeleteTag(id string) (int, error) {
+func (mc MongoClient) RenameTagDescendants(ctx context.Context, oldName, newName string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	oldPrefix := oldName + model.TagPathSeparator
+	newPrefix := newName + model.TagPathSeparator
+
+	filter := bson.D{{Key: "name", Value: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(oldPrefix)}}}
+	updateStmt := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "name", Value: bson.D{{Key: "$concat", Value: bson.A{
+				newPrefix,
+				bson.D{{Key: "$substrCP", Value: bson.A{
+					"$name",
+					len([]rune(oldPrefix)),
+					bson.D{{Key: "$strLenCP", Value: "$name"}},
+				}}},
+			}}}},
+		}}},
+	}
+
+	res, err := mc.Client.UpdateMany(ctx, filter, updateStmt)
+	if err != nil {
+		return 0, conflict(err, "tag with this name already exists")
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+func (mc MongoClient) DeleteTag(ctx context.Context, id string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return 0, fmt.Errorf("wrong id")
+		return 0, model.InvalidID("wrong id")
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
 
-	res, err := mc.Client.DeleteOne(context.Background(), filter)
+	res, err := mc.Client.DeleteOne(ctx, filter)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,263 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (mc MongoClient) CreateTemplate(ctx context.Context, template model.Template) (string, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	res, err := mc.Client.InsertOne(ctx, template)
+	if err != nil {
+		return "", err
+	}
+
+	return res.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (mc MongoClient) GetTemplateByID(ctx context.Context, id string) (model.Template, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return model.Template{}, model.InvalidID("wrong id")
+	}
+
+	var template model.Template
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+
+	err = mc.Client.FindOne(ctx, filter).Decode(&template)
+	if err == mongo.ErrNoDocuments {
+		return model.Template{}, model.NotFound("template not found")
+	} else if err != nil {
+		return model.Template{}, err
+	}
+
+	return template, nil
+}
+
+func (mc MongoClient) GetTemplateByName(ctx context.Context, userID, name string) (model.Template, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return model.Template{}, model.InvalidID("wrong user id")
+	}
+
+	var template model.Template
+
+	filter := bson.D{{Key: "user_id", Value: docId}, {Key: "name", Value: name}}
+
+	err = mc.Client.FindOne(ctx, filter).Decode(&template)
+	if err == mongo.ErrNoDocuments {
+		return model.Template{}, model.NotFound("template not found")
+	} else if err != nil {
+		return model.Template{}, err
+	}
+
+	return template, nil
+}
+
+func (mc MongoClient) GetTemplatesByUserID(ctx context.Context, userID string) ([]model.Template, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return []model.Template{}, model.InvalidID("wrong user id")
+	}
+
+	filter := bson.D{{Key: "user_id", Value: docId}}
+
+	cursor, err := mc.Client.Find(ctx, filter)
+	if err != nil {
+		return []model.Template{}, fmt.Errorf("error finding templates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var templates []model.Template
+
+	for cursor.Next(ctx) {
+		var template model.Template
+
+		err := cursor.Decode(&template)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding templates: %w", err)
+		}
+
+		templates = append(templates, template)
+	}
+
+	return templates, cursor.Err()
+}
+
+func (mc MongoClient) UpdateTemplate(ctx context.Context, id, name, text, color, noteBookID string, tagIDs []string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, model.InvalidID("wrong id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+
+	setDoc := bson.D{}
+	if name != "" {
+		setDoc = append(setDoc, bson.E{Key: "name", Value: name})
+	}
+	if text != "" {
+		setDoc = append(setDoc, bson.E{Key: "text", Value: text})
+	}
+	if color != "" {
+		setDoc = append(setDoc, bson.E{Key: "color", Value: color})
+	}
+	if noteBookID != "" {
+		noteBookDocId, err := primitive.ObjectIDFromHex(noteBookID)
+		if err != nil {
+			return 0, model.InvalidID("wrong notebook id")
+		}
+		setDoc = append(setDoc, bson.E{Key: "notebook_id", Value: noteBookDocId})
+	}
+	if tagIDs != nil {
+		tagDocIds := make([]primitive.ObjectID, 0, len(tagIDs))
+		for _, tagID := range tagIDs {
+			tagDocId, err := primitive.ObjectIDFromHex(tagID)
+			if err != nil {
+				return 0, model.InvalidID("invalid tag ID: %v", err)
+			}
+			tagDocIds = append(tagDocIds, tagDocId)
+		}
+		setDoc = append(setDoc, bson.E{Key: "tags", Value: tagDocIds})
+	}
+	if len(setDoc) == 0 {
+		return 0, nil
+	}
+
+	updateStmt := bson.D{{Key: "$set", Value: setDoc}}
+
+	res, err := mc.Client.UpdateOne(ctx, filter, updateStmt)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+func (mc MongoClient) DeleteTemplate(ctx context.Context, id string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, model.InvalidID("wrong id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+
+	res, err := mc.Client.DeleteOne(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.DeletedCount), nil
+}
+
+func (mc MongoClient) DeleteTemplatesByUserID(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, model.InvalidID("wrong user id")
+	}
+
+	filter := bson.D{{Key: "user_id", Value: docId}}
+
+	res, err := mc.Client.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.DeletedCount), nil
+}
+
+func (mc MongoClient) UnlinkTemplatesFromTag(ctx context.Context, tagID string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(tagID)
+	if err != nil {
+		return 0, model.InvalidID("wrong tag id")
+	}
+
+	filter := bson.D{{Key: "tags", Value: docId}}
+	updateStmt := bson.D{{Key: "$pull", Value: bson.D{{Key: "tags", Value: docId}}}}
+
+	res, err := mc.Client.UpdateMany(ctx, filter, updateStmt)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+func (mc MongoClient) ReplaceTagInTemplates(ctx context.Context, fromID, toID string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	fromDocId, err := primitive.ObjectIDFromHex(fromID)
+	if err != nil {
+		return 0, model.InvalidID("invalid tag ID: %v", err)
+	}
+
+	toDocId, err := primitive.ObjectIDFromHex(toID)
+	if err != nil {
+		return 0, model.InvalidID("invalid tag ID: %v", err)
+	}
+
+	filter := bson.D{{Key: "tags", Value: fromDocId}}
+
+	//Как в ReplaceTagInNotes: $addToSet и $pull по одному полю - разными обновлениями
+	_, err = mc.Client.UpdateMany(ctx, filter, bson.D{{Key: "$addToSet", Value: bson.D{{Key: "tags", Value: toDocId}}}})
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := mc.Client.UpdateMany(ctx, filter, bson.D{{Key: "$pull", Value: bson.D{{Key: "tags", Value: fromDocId}}}})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+func (mc MongoClient) UnlinkTemplatesFromNoteBook(ctx context.Context, noteBookID string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(noteBookID)
+	if err != nil {
+		return 0, model.InvalidID("wrong notebook id")
+	}
+
+	filter := bson.D{{Key: "notebook_id", Value: docId}}
+	updateStmt := bson.D{{Key: "$unset", Value: bson.D{{Key: "notebook_id", Value: ""}}}}
+
+	res, err := mc.Client.UpdateMany(ctx, filter, updateStmt)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
@@ -0,0 +1,45 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MongoUnitOfWork struct {
+	Client    *mongo.Client
+	Timeout   time.Duration
+	Notes     mongo.Collection
+	NoteBooks mongo.Collection
+	Tags      mongo.Collection
+	Users     mongo.Collection
+	Templates mongo.Collection
+	Webhooks  mongo.Collection
+	Reminders mongo.Collection
+}
+
+func (uow MongoUnitOfWork) Do(ctx context.Context, fn func(repository.Tx) error) error {
+	session, err := uow.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.WithoutCancel(ctx))
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		tx := repository.Tx{
+			Notes:     MongoClient{Client: uow.Notes, Timeout: uow.Timeout, session: sc},
+			NoteBooks: MongoClient{Client: uow.NoteBooks, Timeout: uow.Timeout, session: sc},
+			Tags:      MongoClient{Client: uow.Tags, Timeout: uow.Timeout, session: sc},
+			Users:     MongoClient{Client: uow.Users, Timeout: uow.Timeout, session: sc},
+			Templates: MongoClient{Client: uow.Templates, Timeout: uow.Timeout, session: sc},
+			Webhooks:  MongoClient{Client: uow.Webhooks, Timeout: uow.Timeout, session: sc},
+			Reminders: MongoClient{Client: uow.Reminders, Timeout: uow.Timeout, session: sc},
+		}
+
+		return nil, fn(tx)
+	})
+
+	return err
+}
@@ -2,28 +2,33 @@ package mongodb
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func (mc MongoClient) RegisterUser(user model.User) (string, error) {
-	res, err := mc.Client.InsertOne(context.Background(), user)
+func (mc MongoClient) RegisterUser(ctx context.Context, user model.User) (string, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	res, err := mc.Client.InsertOne(ctx, user)
 	if err != nil {
-		return "", err
+		return "", conflict(err, "user with this email already exists")
 	}
 
 	return res.InsertedID.(primitive.ObjectID).Hex(), nil
 }
 
-func (mc MongoClient) LoginUser(email string) (model.User, error) {
+func (mc MongoClient) LoginUser(ctx context.Context, email string) (model.User, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	var user model.User
 
 	filter := bson.D{{Key: "email", Value: email}}
 
-	err := mc.Client.FindOne(context.Background(), filter).Decode(&user)
+	err := mc.Client.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		return model.User{}, err
 	}
@@ -31,20 +36,42 @@ func (mc MongoClient) LoginUser(email string) (model.User, error) {
 	return user, nil
 }
 
-func (mc MongoClient) GetProfile(id string) (model.User, error) {
+func (mc MongoClient) GetProfile(ctx context.Context, id string) (model.User, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
 	var user model.User
 
 	docId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return model.User{}, fmt.Errorf("wrong user id")
+		return model.User{}, model.InvalidID("wrong user id")
 	}
 
 	filter := bson.D{{Key: "_id", Value: docId}}
 
-	err = mc.Client.FindOne(context.Background(), filter).Decode(&user)
+	err = mc.Client.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		return model.User{}, err
 	}
 
 	return user, nil
 }
+
+func (mc MongoClient) DeleteUser(ctx context.Context, id string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, model.InvalidID("wrong user id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+
+	res, err := mc.Client.DeleteOne(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.DeletedCount), nil
+}
@@ -0,0 +1,217 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Журнал доставок хранится в документе вебхука в массиве deliveries и не возвращается вместе с вебхуком
+var withoutDeliveries = bson.D{{Key: "deliveries", Value: 0}}
+
+func (mc MongoClient) CreateWebhook(ctx context.Context, webhook model.Webhook) (string, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	res, err := mc.Client.InsertOne(ctx, webhook)
+	if err != nil {
+		return "", conflict(err, "webhook already exists")
+	}
+
+	return res.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (mc MongoClient) GetWebhookByID(ctx context.Context, id string) (model.Webhook, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return model.Webhook{}, model.InvalidID("wrong id")
+	}
+
+	var webhook model.Webhook
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+
+	err = mc.Client.FindOne(ctx, filter, options.FindOne().SetProjection(withoutDeliveries)).Decode(&webhook)
+	if err == mongo.ErrNoDocuments {
+		return model.Webhook{}, model.NotFound("webhook not found")
+	} else if err != nil {
+		return model.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+func (mc MongoClient) GetWebhooksByUserID(ctx context.Context, userID string) ([]model.Webhook, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return []model.Webhook{}, model.InvalidID("wrong user id")
+	}
+
+	filter := bson.D{{Key: "user_id", Value: docId}}
+
+	cursor, err := mc.Client.Find(ctx, filter, options.Find().SetProjection(withoutDeliveries))
+	if err != nil {
+		return []model.Webhook{}, fmt.Errorf("error finding webhooks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var webhooks []model.Webhook
+
+	for cursor.Next(ctx) {
+		var webhook model.Webhook
+
+		err := cursor.Decode(&webhook)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding webhooks: %w", err)
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, cursor.Err()
+}
+
+func (mc MongoClient) UpdateWebhook(ctx context.Context, id string, update repository.WebhookUpdate) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, model.InvalidID("wrong id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+
+	setDoc := bson.D{}
+	if update.URL != nil {
+		setDoc = append(setDoc, bson.E{Key: "url", Value: *update.URL})
+	}
+	if update.Secret != nil {
+		setDoc = append(setDoc, bson.E{Key: "secret", Value: *update.Secret})
+	}
+	if update.Events != nil {
+		setDoc = append(setDoc, bson.E{Key: "events", Value: *update.Events})
+	}
+	if update.IsActive != nil {
+		setDoc = append(setDoc, bson.E{Key: "is_active", Value: *update.IsActive})
+	}
+	if len(setDoc) == 0 {
+		return 0, nil
+	}
+
+	updateStmt := bson.D{{Key: "$set", Value: setDoc}}
+
+	res, err := mc.Client.UpdateOne(ctx, filter, updateStmt)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+func (mc MongoClient) DeleteWebhook(ctx context.Context, id string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, model.InvalidID("wrong id")
+	}
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+
+	res, err := mc.Client.DeleteOne(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.DeletedCount), nil
+}
+
+func (mc MongoClient) DeleteWebhooksByUserID(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, model.InvalidID("wrong user id")
+	}
+
+	filter := bson.D{{Key: "user_id", Value: docId}}
+
+	res, err := mc.Client.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.DeletedCount), nil
+}
+
+// AddWebhookDelivery дописывает попытку в журнал, $slice оставляет в нем последние MaxWebhookDeliveries
+func (mc MongoClient) AddWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	if delivery.ID.IsZero() {
+		delivery.ID = primitive.NewObjectID()
+	}
+
+	filter := bson.D{{Key: "_id", Value: delivery.WebhookID}}
+
+	updateStmt := bson.D{{Key: "$push", Value: bson.D{{Key: "deliveries", Value: bson.D{
+		{Key: "$each", Value: []model.WebhookDelivery{delivery}},
+		{Key: "$slice", Value: -repository.MaxWebhookDeliveries},
+	}}}}}
+
+	res, err := mc.Client.UpdateOne(ctx, filter, updateStmt)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return model.NotFound("webhook not found")
+	}
+
+	return nil
+}
+
+func (mc MongoClient) GetWebhookDeliveries(ctx context.Context, webhookID string) ([]model.WebhookDelivery, error) {
+	ctx, cancel := mc.ctx(ctx)
+	defer cancel()
+
+	docId, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return []model.WebhookDelivery{}, model.InvalidID("wrong webhook id")
+	}
+
+	var doc struct {
+		Deliveries []model.WebhookDelivery `bson:"deliveries"`
+	}
+
+	filter := bson.D{{Key: "_id", Value: docId}}
+	projection := bson.D{{Key: "deliveries", Value: 1}}
+
+	err = mc.Client.FindOne(ctx, filter, options.FindOne().SetProjection(projection)).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return []model.WebhookDelivery{}, nil
+	} else if err != nil {
+		return []model.WebhookDelivery{}, err
+	}
+
+	deliveries := slices.Clone(doc.Deliveries)
+	slices.Reverse(deliveries)
+
+	return deliveries, nil
+}
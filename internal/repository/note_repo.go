@@ -1,27 +1,107 @@
 package repository
 
 import (
+	"context"
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/lib/tagquery"
 )
 
+const (
+	NoteStatusActive   = "active"
+	NoteStatusArchived = "archived"
+	NoteStatusTrashed  = "trashed"
+	NoteStatusAll      = "all"
+)
+
+const (
+	BulkActionMove      = "move"
+	BulkActionAddTag    = "add_tag"
+	BulkActionRemoveTag = "remove_tag"
+	BulkActionArchive   = "archive"
+	BulkActionTrash     = "trash"
+	BulkActionRestore   = "restore"
+	BulkActionRecolor   = "recolor"
+	BulkActionDelete    = "delete"
+)
+
+const (
+	BulkStatusOK        = "ok"
+	BulkStatusNotFound  = "not_found"
+	BulkStatusInvalidID = "invalid_id"
+	BulkStatusFailed    = "failed"
+)
+
+type NoteBulkOp struct {
+	Action     string
+	NoteBookID string
+	TagID      string
+	Color      string
+	UpdatedAt  string
+}
+
+type BulkResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NotePatch - изменения заметки из PATCH. nil - поле не меняется, пустое значение очищает поле:
+// NoteBookID "" убирает заметку из блокнота, пустой Tags снимает все теги
+type NotePatch struct {
+	Name       *string
+	Text       *string
+	Color      *string
+	Order      *int
+	NoteBookID *string
+	Tags       *[]string
+	UpdatedAt  string
+}
+
+type NoteFilter struct {
+	Tags       tagquery.Expr
+	NoteBookID string
+	Status     string
+	Limit      int //0 - без ограничения
+}
+
 type NoteRepo interface {
-	CreateNote(model.Note) (string, error)
-	GetNoteByID(string) (model.Note, error)
-	GetNotes() ([]model.Note, error)
-	GetNotesByNoteBookID(string) ([]model.Note, error)
-	GetTrashedNotes() ([]model.Note, error)
-	GetArchivedNotes() ([]model.Note, error)
-	GetNotesByTags([]string) ([]model.Note, error)
-	UpdateNote(string, string, string, string, string, int) (int, error)
-	UpdateNoteNoteBook(string, string) (int, error)
-	RemoveNoteBookFromNote(string) (int, error)
-	UnlinkNotesFromNoteBook(string) (int, error)
-	UnlinkNotesFromTag(string) (int, error)
-	AddTagToNote(string, string) (int, error)
-	MoveNoteToTrash(string) error
-	MoveNoteToArchive(string) error
-	RestoreNoteFromTrash(string) error
-	RestoreNoteFromArchive(string) error
-	RemoveTagFromNote(string, string) (int, error)
-	DeleteNote(string) (int, error)
+	CreateNote(context.Context, model.Note) (string, error)
+	GetNoteByID(context.Context, string) (model.Note, error)
+	GetNotes(context.Context) ([]model.Note, error)
+	GetNotesByNoteBookID(context.Context, string) ([]model.Note, error)
+	// GetNotesByIDs возвращает заметки в любом состоянии, в том числе в корзине и архиве
+	GetNotesByIDs(context.Context, []string) ([]model.Note, error)
+	GetTrashedNotes(context.Context) ([]model.Note, error)
+	GetArchivedNotes(context.Context) ([]model.Note, error)
+	GetFavouriteNotes(context.Context) ([]model.Note, error)
+	GetJournalNote(context.Context, string, string) (model.Note, error)
+	GetJournalDates(context.Context, string, string, string) ([]string, error)
+	FindNotes(context.Context, NoteFilter) ([]model.Note, error)
+	StreamNotes(context.Context, string, func(model.Note) error) error
+	CountNotesByTags(context.Context) (map[string]int, error)
+	UpdateNote(context.Context, string, string, string, string, string, int) (int, error)
+	PatchNote(context.Context, string, NotePatch) (int, error)
+	UpdateNoteNoteBook(context.Context, string, string) (int, error)
+	UpdateNoteRank(context.Context, string, string) (int, error)
+	GetLastNoteRank(context.Context, string) (string, error)
+	GetPrevNoteRank(context.Context, string, string) (string, error)
+	GetNextNoteRank(context.Context, string, string) (string, error)
+	RemoveNoteBookFromNote(context.Context, string) (int, error)
+	UnlinkNotesFromNoteBook(context.Context, string) (int, error)
+	CountNotesByNoteBookID(context.Context, string) (int, error)
+	MoveNotesToNoteBook(context.Context, string, string) (int, error)
+	TrashNotesFromNoteBook(context.Context, string) (int, error)
+	UnlinkNotesFromTag(context.Context, string) (int, error)
+	ReplaceTagInNotes(context.Context, string, string) (int, error)
+	AddTagToNote(context.Context, string, string) (int, error)
+	MoveNoteToTrash(context.Context, string) error
+	MoveNoteToArchive(context.Context, string) error
+	RestoreNoteFromTrash(context.Context, string) error
+	RestoreNoteFromArchive(context.Context, string) error
+	SetNotePinned(context.Context, string, bool) error
+	SetNoteFavourite(context.Context, string, bool) error
+	RemoveTagFromNote(context.Context, string, string) (int, error)
+	DeleteNote(context.Context, string) (int, error)
+	DeleteNotesByUserID(context.Context, string) (int, error)
+	BulkUpdateNotes(context.Context, []string, NoteBulkOp) ([]BulkResult, error)
 }
@@ -1,11 +1,17 @@
 package repository
 
-import "github.com/LoL-KeKovich/NoteVault/internal/model"
+import (
+	"context"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
 
 type NoteBookRepo interface {
-	CreateNoteBook(model.NoteBook) (string, error)
-	GetNoteBookByID(string) (model.NoteBook, error)
-	GetNoteBooks() ([]model.NoteBook, error)
-	UpdateNoteBook(string, string, string, *bool) (int, error)
-	DeleteNoteBook(string) (int, error)
+	CreateNoteBook(context.Context, model.NoteBook) (string, error)
+	GetNoteBookByID(context.Context, string) (model.NoteBook, error)
+	GetNoteBookByName(context.Context, string) (model.NoteBook, error)
+	//GetOrCreateNoteBook возвращает блокнот с таким именем, создавая его одной операцией, если его нет; bool - блокнот создан
+	GetOrCreateNoteBook(context.Context, model.NoteBook) (model.NoteBook, bool, error)
+	GetNoteBooks(context.Context) ([]model.NoteBook, error)
+	UpdateNoteBook(context.Context, string, string, string, *bool) (int, error)
+	DeleteNoteBook(context.Context, string) (int, error)
 }
@@ -1,15 +1,18 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
 )
 
 type ReminderRepo interface {
-	CreateReminder(model.Reminder) (string, error)
-	GetRemindersByNote(string) ([]model.Reminder, error)
-	GetActiveReminders() ([]model.Reminder, error)
-	UpdateReminder(string, string, string, time.Time, *bool, string) (int, error)
-	DeleteReminder(string) (int, error)
+	CreateReminder(context.Context, model.Reminder) (string, error)
+	GetReminderByID(context.Context, string) (model.Reminder, error)
+	GetRemindersByNotes(context.Context, []string) ([]model.Reminder, error)
+	GetActiveReminders(context.Context) ([]model.Reminder, error)
+	UpdateReminder(context.Context, string, string, string, time.Time, *bool, string) (int, error)
+	DeleteReminder(context.Context, string) (int, error)
+	DeleteRemindersByNotes(context.Context, []string) (int, error)
 }
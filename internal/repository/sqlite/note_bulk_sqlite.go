@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/rank"
+)
+
+func (sc SQLiteClient) BulkUpdateNotes(ctx context.Context, ids []string, op repository.NoteBulkOp) ([]repository.BulkResult, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	results := make([]repository.BulkResult, len(ids))
+	var validIDs []string
+
+	for i, id := range ids {
+		results[i] = repository.BulkResult{ID: id, Status: repository.BulkStatusOK}
+
+		if err := checkID(id, "wrong id"); err != nil {
+			results[i].Status = repository.BulkStatusInvalidID
+			continue
+		}
+		validIDs = append(validIDs, id)
+	}
+
+	if len(validIDs) == 0 {
+		return results, nil
+	}
+
+	existing, err := sc.existingNoteIDs(ctx, validIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, args, err := bulkUpdateSQL(op)
+	if err != nil {
+		return nil, err
+	}
+
+	//Перенесенные заметки встают в конец блокнота в порядке запроса
+	var last string
+	if op.Action == repository.BulkActionMove {
+		last, err = sc.GetLastNoteRank(ctx, op.NoteBookID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//Как и неупорядоченный BulkWrite в MongoDB: ошибка одной заметки не отменяет остальные
+	for i, id := range ids {
+		if results[i].Status != repository.BulkStatusOK {
+			continue
+		}
+
+		noteBookID, ok := existing[id]
+		if !ok {
+			results[i].Status = repository.BulkStatusNotFound
+			continue
+		}
+
+		itemArgs := args
+		if op.Action == repository.BulkActionMove {
+			if noteBookID == op.NoteBookID {
+				continue
+			}
+			last, err = rank.After(last)
+			if err != nil {
+				return nil, err
+			}
+			itemArgs = append(itemArgs, last)
+		}
+
+		_, err := sc.q().ExecContext(ctx, stmt, append(append([]any{}, itemArgs...), id)...)
+		if err != nil {
+			results[i].Status = repository.BulkStatusFailed
+			results[i].Error = err.Error()
+		}
+	}
+
+	return results, nil
+}
+
+// existingNoteIDs возвращает найденные заметки и их блокноты
+func (sc SQLiteClient) existingNoteIDs(ctx context.Context, ids []string) (map[string]string, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT id, coalesce(notebook_id, '') FROM notes WHERE id IN (`+placeholders(len(ids))+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error finding notes: %w", err)
+	}
+	defer rows.Close()
+
+	existing := map[string]string{}
+
+	for rows.Next() {
+		var id, noteBookID string
+
+		err := rows.Scan(&id, &noteBookID)
+		if err != nil {
+			return nil, err
+		}
+
+		existing[id] = noteBookID
+	}
+
+	return existing, rows.Err()
+}
+
+// bulkUpdateSQL возвращает запрос для одной заметки; id заметки - последний параметр,
+// при переносе перед ним идет ключ сортировки
+func bulkUpdateSQL(op repository.NoteBulkOp) (string, []any, error) {
+	switch op.Action {
+	case repository.BulkActionMove:
+		if err := checkID(op.NoteBookID, "wrong notebook id"); err != nil {
+			return "", nil, err
+		}
+		return `UPDATE notes SET notebook_id = ?, rank = ? WHERE id = ?`, []any{op.NoteBookID}, nil
+	case repository.BulkActionAddTag:
+		if err := checkID(op.TagID, "invalid tag ID"); err != nil {
+			return "", nil, err
+		}
+		return `INSERT OR IGNORE INTO note_tags (tag_id, note_id) VALUES (?, ?)`, []any{op.TagID}, nil
+	case repository.BulkActionRemoveTag:
+		if err := checkID(op.TagID, "invalid tag ID"); err != nil {
+			return "", nil, err
+		}
+		return `DELETE FROM note_tags WHERE tag_id = ? AND note_id = ?`, []any{op.TagID}, nil
+	case repository.BulkActionArchive:
+		return `UPDATE notes SET is_archived = 1, is_deleted = 0 WHERE id = ?`, nil, nil
+	case repository.BulkActionTrash:
+		return `UPDATE notes SET is_deleted = 1, is_archived = 0 WHERE id = ?`, nil, nil
+	case repository.BulkActionRestore:
+		return `UPDATE notes SET is_deleted = 0, is_archived = 0 WHERE id = ?`, nil, nil
+	case repository.BulkActionRecolor:
+		return `UPDATE notes SET color = ?, updated_at = ? WHERE id = ?`, []any{op.Color, op.UpdatedAt}, nil
+	case repository.BulkActionDelete:
+		return `DELETE FROM notes WHERE id = ?`, nil, nil
+	default:
+		return "", nil, fmt.Errorf("unknown bulk action %q", op.Action)
+	}
+}
@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+// StreamNotes передает в fn по одной заметке, доступной пользователю: его собственные и общие.
+// Как и в MongoDB, выгрузку ограничивает только контекст запроса, а не таймаут
+func (sc SQLiteClient) StreamNotes(ctx context.Context, userID string, fn func(model.Note) error) error {
+	if err := checkID(userID, "wrong user id"); err != nil {
+		return err
+	}
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT `+noteColumns+` FROM notes
+		WHERE user_id = ? OR user_id IS NULL
+		ORDER BY notebook_id, rank, id`, userID)
+	if err != nil {
+		return fmt.Errorf("error finding notes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return fmt.Errorf("error decoding notes: %w", err)
+		}
+
+		err = fn(note)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
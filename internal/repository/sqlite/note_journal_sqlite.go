@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+// GetJournalNote возвращает заметку с пустым ID, если за этот день записи нет
+func (sc SQLiteClient) GetJournalNote(ctx context.Context, userID, date string) (model.Note, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(userID, "wrong user id"); err != nil {
+		return model.Note{}, err
+	}
+
+	row := sc.q().QueryRowContext(ctx, `SELECT `+noteColumns+` FROM notes WHERE user_id = ? AND journal_date = ?`, userID, date)
+
+	note, err := scanNote(row)
+	if err == sql.ErrNoRows {
+		return model.Note{}, nil
+	} else if err != nil {
+		return model.Note{}, err
+	}
+
+	return note, nil
+}
+
+// GetJournalDates возвращает даты записей в полуинтервале [from, to)
+func (sc SQLiteClient) GetJournalDates(ctx context.Context, userID, from, to string) ([]string, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(userID, "wrong user id"); err != nil {
+		return []string{}, err
+	}
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT journal_date FROM notes
+		WHERE user_id = ? AND journal_date >= ? AND journal_date < ? AND `+notDeleted+`
+		ORDER BY journal_date`, userID, from, to)
+	if err != nil {
+		return []string{}, fmt.Errorf("error finding journal notes: %w", err)
+	}
+	defer rows.Close()
+
+	var dates []string
+
+	for rows.Next() {
+		var date string
+
+		err := rows.Scan(&date)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding notes: %w", err)
+		}
+
+		dates = append(dates, date)
+	}
+
+	return dates, rows.Err()
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/tagquery"
+)
+
+func (sc SQLiteClient) FindNotes(ctx context.Context, noteFilter repository.NoteFilter) ([]model.Note, error) {
+	where, args, err := noteFilterSQL(noteFilter)
+	if err != nil {
+		return []model.Note{}, err
+	}
+
+	query := `WHERE ` + where + noteOrder
+	if noteFilter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, noteFilter.Limit)
+	}
+
+	return sc.queryNotes(ctx, "error finding notes by filter", query, args...)
+}
+
+func noteFilterSQL(noteFilter repository.NoteFilter) (string, []any, error) {
+	conditions := []string{"1"}
+	var args []any
+
+	switch noteFilter.Status {
+	case repository.NoteStatusActive, "":
+		conditions = append(conditions, notDeleted, notArchived)
+	case repository.NoteStatusArchived:
+		conditions = append(conditions, `is_archived = 1`)
+	case repository.NoteStatusTrashed:
+		conditions = append(conditions, `is_deleted = 1`)
+	case repository.NoteStatusAll:
+	default:
+		return "", nil, fmt.Errorf("unknown note status %q", noteFilter.Status)
+	}
+
+	if noteFilter.NoteBookID != "" {
+		if err := checkID(noteFilter.NoteBookID, "wrong notebook id"); err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, `notebook_id = ?`)
+		args = append(args, noteFilter.NoteBookID)
+	}
+
+	if noteFilter.Tags != nil {
+		tagsSQL, tagArgs, err := tagExprSQL(noteFilter.Tags)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, tagsSQL)
+		args = append(args, tagArgs...)
+	}
+
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+func tagExprSQL(expr tagquery.Expr) (string, []any, error) {
+	switch e := expr.(type) {
+	case *tagquery.Tag:
+		//Тег без найденных id не совпадает ни с одной заметкой - так ведет себя несуществующий тег
+		if len(e.IDs) == 0 {
+			return "0", nil, nil
+		}
+
+		args := make([]any, 0, len(e.IDs))
+		for _, tagID := range e.IDs {
+			if err := checkID(tagID, "invalid tag ID"); err != nil {
+				return "", nil, err
+			}
+			args = append(args, tagID)
+		}
+
+		return `EXISTS (SELECT 1 FROM note_tags nt WHERE nt.note_id = notes.id AND nt.tag_id IN (` + placeholders(len(args)) + `))`, args, nil
+	case tagquery.And:
+		return tagExprListSQL(" AND ", e)
+	case tagquery.Or:
+		return tagExprListSQL(" OR ", e)
+	case tagquery.Not:
+		sub, args, err := tagExprSQL(e.Expr)
+		if err != nil {
+			return "", nil, err
+		}
+		return `NOT (` + sub + `)`, args, nil
+	default:
+		return "", nil, fmt.Errorf("unknown tag expression %T", expr)
+	}
+}
+
+func tagExprListSQL(op string, exprs []tagquery.Expr) (string, []any, error) {
+	parts := make([]string, 0, len(exprs))
+	var args []any
+
+	for _, sub := range exprs {
+		part, subArgs, err := tagExprSQL(sub)
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, "("+part+")")
+		args = append(args, subArgs...)
+	}
+
+	return `(` + strings.Join(parts, op) + `)`, args, nil
+}
@@ -0,0 +1,159 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/LoL-KeKovich/NoteVault/lib/rank"
+)
+
+func (sc SQLiteClient) UpdateNoteRank(ctx context.Context, id, noteRank string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `UPDATE notes SET rank = ? WHERE id = ?`, noteRank, id))
+}
+
+func (sc SQLiteClient) GetLastNoteRank(ctx context.Context, noteBookID string) (string, error) {
+	return sc.findNoteRank(ctx, noteBookID, "DESC", `rank <> ''`)
+}
+
+func (sc SQLiteClient) GetPrevNoteRank(ctx context.Context, noteBookID, noteRank string) (string, error) {
+	return sc.findNoteRank(ctx, noteBookID, "DESC", `rank <> '' AND rank < ?`, noteRank)
+}
+
+func (sc SQLiteClient) GetNextNoteRank(ctx context.Context, noteBookID, noteRank string) (string, error) {
+	return sc.findNoteRank(ctx, noteBookID, "ASC", `rank > ?`, noteRank)
+}
+
+// findNoteRank ищет ближайший ключ в блокноте; пустой noteBookID - заметки без блокнота.
+// Если подходящей заметки нет, возвращается пустая строка. rankArgs подставляются в плейсхолдеры rankCond,
+// в том числе пустой ключ якоря у заметок, созданных до появления ранжирования
+func (sc SQLiteClient) findNoteRank(ctx context.Context, noteBookID, direction, rankCond string, rankArgs ...any) (string, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	query := `SELECT rank FROM notes WHERE `
+	var args []any
+
+	if noteBookID != "" {
+		if err := checkID(noteBookID, "wrong notebook id"); err != nil {
+			return "", err
+		}
+		query += `notebook_id = ?`
+		args = append(args, noteBookID)
+	} else {
+		query += `notebook_id IS NULL`
+	}
+
+	query += ` AND ` + rankCond
+	args = append(args, rankArgs...)
+	query += ` ORDER BY rank ` + direction + ` LIMIT 1`
+
+	var found string
+
+	err := sc.q().QueryRowContext(ctx, query, args...).Scan(&found)
+	if err == sql.ErrNoRows {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return found, nil
+}
+
+// noteBookValue - значение notebook_id для блокнота; пустой ID - NULL
+func noteBookValue(noteBookID string) any {
+	if noteBookID == "" {
+		return nil
+	}
+
+	return noteBookID
+}
+
+// moveNotes переносит заметки, подходящие под where, в конец блокнота toID (пустой - заметки без блокнота),
+// сохраняя их порядок между собой. Заметки, которые уже лежат в этом блокноте, не меняются
+func (sc SQLiteClient) moveNotes(ctx context.Context, where string, args []any, toID string) (int, error) {
+	var moved int
+
+	err := sc.atomic(ctx, func(tc SQLiteClient) error {
+		last, err := tc.GetLastNoteRank(ctx, toID)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := tc.ctx(ctx)
+		defer cancel()
+
+		query := `SELECT id FROM notes WHERE (` + where + `) AND notebook_id IS NOT ? ORDER BY rank, rowid`
+		rows, err := tc.q().QueryContext(ctx, query, append(args[:len(args):len(args)], noteBookValue(toID))...)
+		if err != nil {
+			return err
+		}
+
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			last, err = rank.After(last)
+			if err != nil {
+				return err
+			}
+
+			_, err = tc.q().ExecContext(ctx, `UPDATE notes SET notebook_id = ?, rank = ? WHERE id = ?`, noteBookValue(toID), last, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		moved = len(ids)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+// rankForMove возвращает ключ в конце блокнота toID для заметки, которую туда переносят. Пустой ключ -
+// заметка уже лежит в этом блокноте или не найдена
+func (sc SQLiteClient) rankForMove(ctx context.Context, id, toID string) (string, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	var current string
+
+	err := sc.q().QueryRowContext(ctx, `SELECT coalesce(notebook_id, '') FROM notes WHERE id = ?`, id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if current == toID {
+		return "", nil
+	}
+
+	last, err := sc.GetLastNoteRank(ctx, toID)
+	if err != nil {
+		return "", err
+	}
+
+	return rank.After(last)
+}
@@ -0,0 +1,452 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+)
+
+const noteColumns = `id, name, text, color, "order", rank, is_deleted, is_archived, is_pinned, is_favourite,
+	created_at, updated_at, notebook_id, user_id, journal_date,
+	(SELECT group_concat(tag_id, ',' ORDER BY rowid) FROM note_tags WHERE note_id = notes.id)`
+
+// noteOrder ставит закрепленные заметки первыми, остальные - по ключу сортировки
+const noteOrder = ` ORDER BY is_pinned DESC, rank, id`
+
+const (
+	notDeleted  = `IFNULL(is_deleted, 0) = 0`
+	notArchived = `IFNULL(is_archived, 0) = 0`
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNote(row rowScanner) (model.Note, error) {
+	var note model.Note
+	var id, noteBookID, userID, journalDate, tags sql.NullString
+	var isDeleted, isArchived, isPinned, isFavourite sql.NullBool
+
+	err := row.Scan(&id, &note.Name, &note.Text, &note.Color, &note.Order, &note.Rank,
+		&isDeleted, &isArchived, &isPinned, &isFavourite,
+		&note.CreatedAt, &note.UpdatedAt, &noteBookID, &userID, &journalDate, &tags)
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	note.ID = objectID(id)
+	note.IsDeleted = boolPtr(isDeleted)
+	note.IsArchived = boolPtr(isArchived)
+	note.IsPinned = boolPtr(isPinned)
+	note.IsFavourite = boolPtr(isFavourite)
+	note.NoteBookID = objectID(noteBookID)
+	note.UserID = objectID(userID)
+	note.JournalDate = journalDate.String
+	note.Tags = objectIDs(tags)
+
+	return note, nil
+}
+
+func (sc SQLiteClient) queryNotes(ctx context.Context, message, query string, args ...any) ([]model.Note, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT `+noteColumns+` FROM notes `+query, args...)
+	if err != nil {
+		return []model.Note{}, fmt.Errorf("%s: %w", message, err)
+	}
+	defer rows.Close()
+
+	var notes []model.Note
+
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding notes: %w", err)
+		}
+
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
+
+func (sc SQLiteClient) CreateNote(ctx context.Context, note model.Note) (string, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	id := newID(note.ID)
+
+	err := sc.atomic(ctx, func(tc SQLiteClient) error {
+		_, err := tc.q().ExecContext(ctx, `INSERT INTO notes (id, name, text, color, "order", rank,
+			is_deleted, is_archived, is_pinned, is_favourite, created_at, updated_at, notebook_id, user_id, journal_date)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, note.Name, note.Text, note.Color, note.Order, note.Rank,
+			nullBool(note.IsDeleted), nullBool(note.IsArchived), nullBool(note.IsPinned), nullBool(note.IsFavourite),
+			note.CreatedAt, note.UpdatedAt, nullID(note.NoteBookID), nullID(note.UserID), nullString(note.JournalDate))
+		if err != nil {
+			return conflict(err, "daily note already exists")
+		}
+
+		for _, tagID := range note.Tags {
+			_, err := tc.q().ExecContext(ctx, `INSERT OR IGNORE INTO note_tags (note_id, tag_id) VALUES (?, ?)`, id, tagID.Hex())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (sc SQLiteClient) GetNoteByID(ctx context.Context, id string) (model.Note, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return model.Note{}, err
+	}
+
+	row := sc.q().QueryRowContext(ctx, `SELECT `+noteColumns+` FROM notes WHERE id = ?`, id)
+
+	note, err := scanNote(row)
+	if err == sql.ErrNoRows {
+		return model.Note{}, model.NotFound("note not found")
+	} else if err != nil {
+		return model.Note{}, err
+	}
+
+	return note, nil
+}
+
+func (sc SQLiteClient) GetNotes(ctx context.Context) ([]model.Note, error) {
+	return sc.queryNotes(ctx, "error finding notes", `WHERE `+notDeleted+` AND `+notArchived+noteOrder)
+}
+
+func (sc SQLiteClient) GetNotesByNoteBookID(ctx context.Context, id string) ([]model.Note, error) {
+	if err := checkID(id, "wrong notebook id"); err != nil {
+		return []model.Note{}, err
+	}
+
+	return sc.queryNotes(ctx, "error finding notes in notebook", `WHERE notebook_id = ? AND `+notDeleted+` AND `+notArchived+noteOrder, id)
+}
+
+func (sc SQLiteClient) GetNotesByIDs(ctx context.Context, ids []string) ([]model.Note, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		if err := checkID(id, "wrong id"); err != nil {
+			return []model.Note{}, err
+		}
+		args = append(args, id)
+	}
+
+	return sc.queryNotes(ctx, "error finding notes", `WHERE id IN (`+placeholders(len(args))+`) ORDER BY rowid`, args...)
+}
+
+func (sc SQLiteClient) GetTrashedNotes(ctx context.Context) ([]model.Note, error) {
+	return sc.queryNotes(ctx, "error finding notes in trash", `WHERE is_deleted = 1 ORDER BY rowid`)
+}
+
+func (sc SQLiteClient) GetArchivedNotes(ctx context.Context) ([]model.Note, error) {
+	return sc.queryNotes(ctx, "error finding notes in archive", `WHERE is_archived = 1 ORDER BY rowid`)
+}
+
+func (sc SQLiteClient) GetFavouriteNotes(ctx context.Context) ([]model.Note, error) {
+	return sc.queryNotes(ctx, "error finding favourite notes", `WHERE is_favourite = 1 AND `+notDeleted+` AND `+notArchived+noteOrder)
+}
+
+func (sc SQLiteClient) UpdateNote(ctx context.Context, id, name, text, color, updatedAt string, order int) (int, error) {
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	var sets []string
+	var args []any
+	if name != "" {
+		sets, args = append(sets, "name = ?"), append(args, name)
+	}
+	if text != "" {
+		sets, args = append(sets, "text = ?"), append(args, text)
+	}
+	if color != "" {
+		sets, args = append(sets, "color = ?"), append(args, color)
+	}
+	if order != 0 {
+		sets, args = append(sets, `"order" = ?`), append(args, order)
+	}
+	if len(sets) == 0 {
+		return 0, nil
+	}
+	sets, args = append(sets, "updated_at = ?"), append(args, updatedAt)
+
+	return sc.update(ctx, "notes", sets, args, id)
+}
+
+func (sc SQLiteClient) PatchNote(ctx context.Context, id string, patch repository.NotePatch) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	sets, args := []string{"updated_at = ?"}, []any{patch.UpdatedAt}
+	if patch.Name != nil {
+		sets, args = append(sets, "name = ?"), append(args, *patch.Name)
+	}
+	if patch.Text != nil {
+		sets, args = append(sets, "text = ?"), append(args, *patch.Text)
+	}
+	if patch.Color != nil {
+		sets, args = append(sets, "color = ?"), append(args, *patch.Color)
+	}
+	if patch.Order != nil {
+		sets, args = append(sets, `"order" = ?`), append(args, *patch.Order)
+	}
+	if patch.NoteBookID != nil {
+		if *patch.NoteBookID != "" {
+			if err := checkID(*patch.NoteBookID, "wrong notebook id"); err != nil {
+				return 0, err
+			}
+		}
+		sets, args = append(sets, "notebook_id = ?"), append(args, noteBookValue(*patch.NoteBookID))
+	}
+	if patch.Tags != nil {
+		for _, tagID := range *patch.Tags {
+			if err := checkID(tagID, "invalid tag ID"); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var res int
+
+	//Поля и теги лежат в разных таблицах, поэтому обновляются в одной транзакции
+	err := sc.atomic(ctx, func(tc SQLiteClient) error {
+		sets, args := sets, args
+
+		//Перенесенная заметка встает в конец нового блокнота
+		if patch.NoteBookID != nil {
+			noteRank, err := tc.rankForMove(ctx, id, *patch.NoteBookID)
+			if err != nil {
+				return err
+			}
+			if noteRank != "" {
+				sets, args = append(sets, "rank = ?"), append(args, noteRank)
+			}
+		}
+
+		var err error
+		res, err = tc.update(ctx, "notes", sets, args, id)
+		if err != nil || res == 0 || patch.Tags == nil {
+			return err
+		}
+
+		_, err = tc.q().ExecContext(ctx, `DELETE FROM note_tags WHERE note_id = ?`, id)
+		if err != nil {
+			return err
+		}
+
+		for _, tagID := range *patch.Tags {
+			_, err := tc.q().ExecContext(ctx, `INSERT OR IGNORE INTO note_tags (note_id, tag_id) VALUES (?, ?)`, id, tagID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+// update выполняет UPDATE по первичному ключу с уже собранным списком присваиваний
+func (sc SQLiteClient) update(ctx context.Context, table string, sets []string, args []any, id string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	query := `UPDATE ` + table + ` SET ` + strings.Join(sets, ", ") + ` WHERE id = ?`
+
+	return affected(sc.q().ExecContext(ctx, query, append(args, id)...))
+}
+
+func (sc SQLiteClient) UpdateNoteNoteBook(ctx context.Context, noteID, noteBookID string) (int, error) {
+	if err := checkID(noteID, "wrong id"); err != nil {
+		return 0, err
+	}
+	if err := checkID(noteBookID, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	return sc.moveNotes(ctx, `id = ?`, []any{noteID}, noteBookID)
+}
+
+func (sc SQLiteClient) RemoveNoteBookFromNote(ctx context.Context, noteID string) (int, error) {
+	if err := checkID(noteID, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	return sc.moveNotes(ctx, `id = ?`, []any{noteID}, "")
+}
+
+func (sc SQLiteClient) UnlinkNotesFromNoteBook(ctx context.Context, id string) (int, error) {
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	return sc.moveNotes(ctx, `notebook_id = ?`, []any{id}, "")
+}
+
+func (sc SQLiteClient) CountNotesByNoteBookID(ctx context.Context, id string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong notebook id"); err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	err := sc.q().QueryRowContext(ctx, `SELECT count(*) FROM notes WHERE notebook_id = ?`, id).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (sc SQLiteClient) MoveNotesToNoteBook(ctx context.Context, fromID, toID string) (int, error) {
+	if err := checkID(fromID, "wrong notebook id"); err != nil {
+		return 0, err
+	}
+	if err := checkID(toID, "wrong target notebook id"); err != nil {
+		return 0, err
+	}
+
+	return sc.moveNotes(ctx, `notebook_id = ?`, []any{fromID}, toID)
+}
+
+func (sc SQLiteClient) TrashNotesFromNoteBook(ctx context.Context, id string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong notebook id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx,
+		`UPDATE notes SET is_deleted = 1, is_archived = 0, notebook_id = NULL WHERE notebook_id = ?`, id))
+}
+
+func (sc SQLiteClient) MoveNoteToTrash(ctx context.Context, id string) error {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return err
+	}
+
+	return noteFound(affected(sc.q().ExecContext(ctx, `UPDATE notes SET is_deleted = 1, is_archived = 0 WHERE id = ?`, id)))
+}
+
+func (sc SQLiteClient) RestoreNoteFromTrash(ctx context.Context, id string) error {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return err
+	}
+
+	return noteFound(affected(sc.q().ExecContext(ctx, `UPDATE notes SET is_deleted = 0 WHERE id = ?`, id)))
+}
+
+func (sc SQLiteClient) MoveNoteToArchive(ctx context.Context, id string) error {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return err
+	}
+
+	return noteFound(affected(sc.q().ExecContext(ctx, `UPDATE notes SET is_archived = 1, is_deleted = 0 WHERE id = ?`, id)))
+}
+
+func (sc SQLiteClient) RestoreNoteFromArchive(ctx context.Context, id string) error {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return err
+	}
+
+	return noteFound(affected(sc.q().ExecContext(ctx, `UPDATE notes SET is_archived = 0 WHERE id = ?`, id)))
+}
+
+func (sc SQLiteClient) SetNotePinned(ctx context.Context, id string, pinned bool) error {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return err
+	}
+
+	return noteFound(affected(sc.q().ExecContext(ctx, `UPDATE notes SET is_pinned = ? WHERE id = ?`, pinned, id)))
+}
+
+func (sc SQLiteClient) SetNoteFavourite(ctx context.Context, id string, favourite bool) error {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return err
+	}
+
+	return noteFound(affected(sc.q().ExecContext(ctx, `UPDATE notes SET is_favourite = ? WHERE id = ?`, favourite, id)))
+}
+
+func (sc SQLiteClient) DeleteNote(ctx context.Context, id string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM notes WHERE id = ?`, id))
+}
+
+func (sc SQLiteClient) DeleteNotesByUserID(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(userID, "wrong user id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM notes WHERE user_id = ?`, userID))
+}
+
+// noteFound заменяет UPDATE, не нашедший заметку, на model.ErrNotFound
+func noteFound(n int, err error) error {
+	if err == nil && n == 0 {
+		return model.NotFound("note not found")
+	}
+
+	return err
+}
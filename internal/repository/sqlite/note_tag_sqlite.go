@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+func (sc SQLiteClient) AddTagToNote(ctx context.Context, noteID, tagID string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(noteID, "invalid note ID"); err != nil {
+		return 0, err
+	}
+	if err := checkID(tagID, "invalid tag ID"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx,
+		`INSERT OR IGNORE INTO note_tags (note_id, tag_id) SELECT id, ? FROM notes WHERE id = ?`, tagID, noteID))
+}
+
+func (sc SQLiteClient) RemoveTagFromNote(ctx context.Context, noteID, tagID string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(noteID, "invalid note ID"); err != nil {
+		return 0, err
+	}
+	if err := checkID(tagID, "invalid tag ID"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM note_tags WHERE note_id = ? AND tag_id = ?`, noteID, tagID))
+}
+
+func (sc SQLiteClient) ReplaceTagInNotes(ctx context.Context, fromID, toID string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(fromID, "invalid tag ID"); err != nil {
+		return 0, err
+	}
+	if err := checkID(toID, "invalid tag ID"); err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	err := sc.atomic(ctx, func(tc SQLiteClient) error {
+		_, err := tc.q().ExecContext(ctx,
+			`INSERT OR IGNORE INTO note_tags (note_id, tag_id) SELECT note_id, ? FROM note_tags WHERE tag_id = ?`, toID, fromID)
+		if err != nil {
+			return err
+		}
+
+		count, err = affected(tc.q().ExecContext(ctx, `DELETE FROM note_tags WHERE tag_id = ?`, fromID))
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (sc SQLiteClient) UnlinkNotesFromTag(ctx context.Context, tagID string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(tagID, "wrong tag id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM note_tags WHERE tag_id = ?`, tagID))
+}
+
+func (sc SQLiteClient) CountNotesByTags(ctx context.Context) (map[string]int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT nt.tag_id, count(*) FROM note_tags nt
+		JOIN notes ON notes.id = nt.note_id
+		WHERE `+notDeleted+` GROUP BY nt.tag_id`)
+	if err != nil {
+		return nil, fmt.Errorf("error counting notes by tags: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+
+	for rows.Next() {
+		var tagID string
+		var count int
+
+		err := rows.Scan(&tagID, &count)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding tag counts: %w", err)
+		}
+
+		counts[tagID] = count
+	}
+
+	return counts, rows.Err()
+}
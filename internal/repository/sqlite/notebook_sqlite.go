@@ -0,0 +1,159 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+func scanNoteBook(row rowScanner) (model.NoteBook, error) {
+	var noteBook model.NoteBook
+	var id sql.NullString
+	var isActive sql.NullBool
+
+	err := row.Scan(&id, &noteBook.Name, &noteBook.Description, &isActive)
+	if err != nil {
+		return model.NoteBook{}, err
+	}
+
+	noteBook.ID = objectID(id)
+	noteBook.IsActive = boolPtr(isActive)
+
+	return noteBook, nil
+}
+
+func (sc SQLiteClient) CreateNoteBook(ctx context.Context, notebook model.NoteBook) (string, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	id := newID(notebook.ID)
+
+	_, err := sc.q().ExecContext(ctx, `INSERT INTO notebooks (id, name, description, is_active) VALUES (?, ?, ?, ?)`,
+		id, notebook.Name, notebook.Description, nullBool(notebook.IsActive))
+	if err != nil {
+		return "", conflict(err, "notebook with this name already exists")
+	}
+
+	return id, nil
+}
+
+func (sc SQLiteClient) GetNoteBookByID(ctx context.Context, id string) (model.NoteBook, error) {
+	if err := checkID(id, "wrong id"); err != nil {
+		return model.NoteBook{}, err
+	}
+
+	return sc.getNoteBook(ctx, `id = ?`, id)
+}
+
+func (sc SQLiteClient) GetNoteBookByName(ctx context.Context, name string) (model.NoteBook, error) {
+	return sc.getNoteBook(ctx, `name = ?`, name)
+}
+
+func (sc SQLiteClient) GetOrCreateNoteBook(ctx context.Context, notebook model.NoteBook) (model.NoteBook, bool, error) {
+	var noteBook model.NoteBook
+	var created bool
+
+	err := sc.atomic(ctx, func(tc SQLiteClient) error {
+		ctx, cancel := tc.ctx(ctx)
+		defer cancel()
+
+		res, err := tc.q().ExecContext(ctx, `INSERT INTO notebooks (id, name, description, is_active)
+			SELECT ?, ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM notebooks WHERE name = ?)`,
+			newID(notebook.ID), notebook.Name, notebook.Description, nullBool(notebook.IsActive), notebook.Name)
+		if err != nil {
+			return err
+		}
+
+		inserted, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		created = inserted > 0
+
+		noteBook, err = tc.GetNoteBookByName(ctx, notebook.Name)
+		return err
+	})
+	if err != nil {
+		return model.NoteBook{}, false, err
+	}
+
+	return noteBook, created, nil
+}
+
+func (sc SQLiteClient) getNoteBook(ctx context.Context, cond string, arg any) (model.NoteBook, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	row := sc.q().QueryRowContext(ctx, `SELECT id, name, description, is_active FROM notebooks WHERE `+cond+` ORDER BY rowid LIMIT 1`, arg)
+
+	noteBook, err := scanNoteBook(row)
+	if err == sql.ErrNoRows {
+		return model.NoteBook{}, model.NotFound("notebook not found")
+	} else if err != nil {
+		return model.NoteBook{}, err
+	}
+
+	return noteBook, nil
+}
+
+func (sc SQLiteClient) GetNoteBooks(ctx context.Context) ([]model.NoteBook, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT id, name, description, is_active FROM notebooks ORDER BY rowid`)
+	if err != nil {
+		return []model.NoteBook{}, fmt.Errorf("error finding notebooks: %w", err)
+	}
+	defer rows.Close()
+
+	var noteBooks []model.NoteBook
+
+	for rows.Next() {
+		noteBook, err := scanNoteBook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding notebooks: %w", err)
+		}
+
+		noteBooks = append(noteBooks, noteBook)
+	}
+
+	return noteBooks, rows.Err()
+}
+
+func (sc SQLiteClient) UpdateNoteBook(ctx context.Context, id, name, description string, isActive *bool) (int, error) {
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	var sets []string
+	var args []any
+	if name != "" {
+		sets, args = append(sets, "name = ?"), append(args, name)
+	}
+	if description != "" {
+		sets, args = append(sets, "description = ?"), append(args, description)
+	}
+	if isActive != nil {
+		sets, args = append(sets, "is_active = ?"), append(args, *isActive)
+	}
+	if len(sets) == 0 {
+		return 0, nil
+	}
+
+	res, err := sc.update(ctx, "notebooks", sets, args, id)
+
+	return res, conflict(err, "notebook with this name already exists")
+}
+
+func (sc SQLiteClient) DeleteNoteBook(ctx context.Context, id string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM notebooks WHERE id = ?`, id))
+}
@@ -0,0 +1,188 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+const reminderColumns = `id, name, message, remind_at, is_active, repeat, note_id`
+
+func scanReminder(row rowScanner) (model.Reminder, error) {
+	var reminder model.Reminder
+	var id, noteID sql.NullString
+	var remindAt string
+	var isActive sql.NullBool
+
+	err := row.Scan(&id, &reminder.Name, &reminder.Message, &remindAt, &isActive, &reminder.Repeat, &noteID)
+	if err != nil {
+		return model.Reminder{}, err
+	}
+
+	reminder.ID = objectID(id)
+	reminder.NoteID = objectID(noteID)
+	reminder.IsActive = boolPtr(isActive)
+	if remindAt != "" {
+		reminder.RemindAt, err = time.Parse(time.RFC3339Nano, remindAt)
+		if err != nil {
+			return model.Reminder{}, err
+		}
+	}
+
+	return reminder, nil
+}
+
+// formatTime хранит время в UTC, чтобы строки сравнивались в порядке времени
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func (sc SQLiteClient) queryReminders(ctx context.Context, query string, args ...any) ([]model.Reminder, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT `+reminderColumns+` FROM reminders `+query, args...)
+	if err != nil {
+		return []model.Reminder{}, fmt.Errorf("error finding reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []model.Reminder
+
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding reminders: %w", err)
+		}
+
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, rows.Err()
+}
+
+func (sc SQLiteClient) CreateReminder(ctx context.Context, reminder model.Reminder) (string, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	id := newID(reminder.ID)
+
+	if reminder.IsActive == nil {
+		isActive := true
+		reminder.IsActive = &isActive
+	}
+
+	_, err := sc.q().ExecContext(ctx, `INSERT INTO reminders (`+reminderColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		id, reminder.Name, reminder.Message, formatTime(reminder.RemindAt), nullBool(reminder.IsActive), reminder.Repeat, nullID(reminder.NoteID))
+	if err != nil {
+		return "", conflict(err, "reminder already exists")
+	}
+
+	return id, nil
+}
+
+func (sc SQLiteClient) GetReminderByID(ctx context.Context, id string) (model.Reminder, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return model.Reminder{}, err
+	}
+
+	row := sc.q().QueryRowContext(ctx, `SELECT `+reminderColumns+` FROM reminders WHERE id = ?`, id)
+
+	reminder, err := scanReminder(row)
+	if err == sql.ErrNoRows {
+		return model.Reminder{}, model.NotFound("reminder not found")
+	} else if err != nil {
+		return model.Reminder{}, err
+	}
+
+	return reminder, nil
+}
+
+func (sc SQLiteClient) GetRemindersByNotes(ctx context.Context, noteIDs []string) ([]model.Reminder, error) {
+	if len(noteIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(noteIDs))
+	for _, id := range noteIDs {
+		if err := checkID(id, "wrong id"); err != nil {
+			return []model.Reminder{}, err
+		}
+		args = append(args, id)
+	}
+
+	return sc.queryReminders(ctx, `WHERE note_id IN (`+placeholders(len(args))+`) ORDER BY rowid`, args...)
+}
+
+func (sc SQLiteClient) GetActiveReminders(ctx context.Context) ([]model.Reminder, error) {
+	return sc.queryReminders(ctx, `WHERE is_active = 1 ORDER BY rowid`)
+}
+
+func (sc SQLiteClient) UpdateReminder(ctx context.Context, id, name, message string, remindAt time.Time, isActive *bool, repeat string) (int, error) {
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	var sets []string
+	var args []any
+	if name != "" {
+		sets, args = append(sets, "name = ?"), append(args, name)
+	}
+	if message != "" {
+		sets, args = append(sets, "message = ?"), append(args, message)
+	}
+	if !remindAt.IsZero() {
+		sets, args = append(sets, "remind_at = ?"), append(args, formatTime(remindAt))
+	}
+	if isActive != nil {
+		sets, args = append(sets, "is_active = ?"), append(args, *isActive)
+	}
+	if repeat != "" {
+		sets, args = append(sets, "repeat = ?"), append(args, repeat)
+	}
+	if len(sets) == 0 {
+		return 0, nil
+	}
+
+	return sc.update(ctx, "reminders", sets, args, id)
+}
+
+func (sc SQLiteClient) DeleteReminder(ctx context.Context, id string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM reminders WHERE id = ?`, id))
+}
+
+func (sc SQLiteClient) DeleteRemindersByNotes(ctx context.Context, noteIDs []string) (int, error) {
+	if len(noteIDs) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	args := make([]any, 0, len(noteIDs))
+	for _, id := range noteIDs {
+		if err := checkID(id, "wrong id"); err != nil {
+			return 0, err
+		}
+		args = append(args, id)
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM reminders WHERE note_id IN (`+placeholders(len(args))+`)`, args...))
+}
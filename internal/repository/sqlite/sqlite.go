@@ -0,0 +1,298 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// querier - общее у *sql.DB и *sql.Tx, чтобы одни и те же методы работали и внутри транзакции
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type SQLiteClient struct {
+	DB      *sql.DB
+	Timeout time.Duration
+	tx      *sql.Tx
+}
+
+func (sc SQLiteClient) q() querier {
+	if sc.tx != nil {
+		return sc.tx
+	}
+
+	return sc.DB
+}
+
+// ctx ограничивает запрос таймаутом из конфига; отмена запроса клиентом прерывает и запрос к базе
+func (sc SQLiteClient) ctx(ctx context.Context) (context.Context, context.CancelFunc) {
+	if sc.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, sc.Timeout)
+}
+
+// Идентификаторы те же, что в MongoDB: hex ObjectID, поэтому модели и сервисы не зависят от хранилища
+const schema = `
+CREATE TABLE IF NOT EXISTS notebooks (
+	id          TEXT PRIMARY KEY,
+	name        TEXT NOT NULL DEFAULT '',
+	description TEXT NOT NULL DEFAULT '',
+	is_active   INTEGER
+);
+DROP INDEX IF EXISTS notebooks_name;
+CREATE UNIQUE INDEX IF NOT EXISTS notebooks_name_unique ON notebooks (name);
+
+CREATE TABLE IF NOT EXISTS tags (
+	id    TEXT PRIMARY KEY,
+	name  TEXT NOT NULL UNIQUE,
+	color TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS users (
+	id            TEXT PRIMARY KEY,
+	password_hash TEXT NOT NULL DEFAULT '',
+	email         TEXT NOT NULL UNIQUE,
+	first_name    TEXT NOT NULL DEFAULT '',
+	last_name     TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS notes (
+	id           TEXT PRIMARY KEY,
+	name         TEXT NOT NULL DEFAULT '',
+	text         TEXT NOT NULL DEFAULT '',
+	color        TEXT NOT NULL DEFAULT '',
+	"order"      INTEGER NOT NULL DEFAULT 0,
+	rank         TEXT NOT NULL DEFAULT '',
+	is_deleted   INTEGER,
+	is_archived  INTEGER,
+	is_pinned    INTEGER,
+	is_favourite INTEGER,
+	created_at   TEXT NOT NULL DEFAULT '',
+	updated_at   TEXT NOT NULL DEFAULT '',
+	notebook_id  TEXT,
+	user_id      TEXT,
+	journal_date TEXT
+);
+CREATE INDEX IF NOT EXISTS notes_notebook_rank ON notes (notebook_id, rank);
+CREATE UNIQUE INDEX IF NOT EXISTS notes_journal ON notes (user_id, journal_date) WHERE journal_date IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS note_tags (
+	note_id TEXT NOT NULL REFERENCES notes (id) ON DELETE CASCADE,
+	tag_id  TEXT NOT NULL,
+	PRIMARY KEY (note_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS note_tags_tag ON note_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS templates (
+	id          TEXT PRIMARY KEY,
+	user_id     TEXT NOT NULL,
+	name        TEXT NOT NULL DEFAULT '',
+	text        TEXT NOT NULL DEFAULT '',
+	color       TEXT NOT NULL DEFAULT '',
+	notebook_id TEXT
+);
+CREATE INDEX IF NOT EXISTS templates_user ON templates (user_id, name);
+
+CREATE TABLE IF NOT EXISTS template_tags (
+	template_id TEXT NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	tag_id      TEXT NOT NULL,
+	PRIMARY KEY (template_id, tag_id)
+);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+	id         TEXT PRIMARY KEY,
+	user_id    TEXT NOT NULL,
+	url        TEXT NOT NULL,
+	secret     TEXT NOT NULL,
+	events     TEXT NOT NULL DEFAULT '',
+	is_active  INTEGER,
+	created_at TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS webhooks_user ON webhooks (user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+	id          TEXT PRIMARY KEY,
+	webhook_id  TEXT NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+	delivery_id TEXT NOT NULL,
+	event       TEXT NOT NULL,
+	attempt     INTEGER NOT NULL,
+	status_code INTEGER NOT NULL DEFAULT 0,
+	success     INTEGER NOT NULL,
+	error       TEXT NOT NULL DEFAULT '',
+	duration_ms INTEGER NOT NULL DEFAULT 0,
+	payload     TEXT NOT NULL DEFAULT '',
+	created_at  TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook ON webhook_deliveries (webhook_id);
+
+CREATE TABLE IF NOT EXISTS sync_changes (
+	seq       INTEGER PRIMARY KEY AUTOINCREMENT,
+	kind      TEXT NOT NULL,
+	entity_id TEXT NOT NULL,
+	user_id   TEXT NOT NULL DEFAULT '',
+	deleted   INTEGER NOT NULL DEFAULT 0,
+	UNIQUE (kind, entity_id)
+);
+CREATE INDEX IF NOT EXISTS sync_changes_user ON sync_changes (user_id, seq);
+
+CREATE TABLE IF NOT EXISTS reminders (
+	id        TEXT PRIMARY KEY,
+	name      TEXT NOT NULL DEFAULT '',
+	message   TEXT NOT NULL DEFAULT '',
+	remind_at TEXT NOT NULL DEFAULT '',
+	is_active INTEGER,
+	repeat    TEXT NOT NULL DEFAULT '',
+	note_id   TEXT
+);
+CREATE INDEX IF NOT EXISTS reminders_note ON reminders (note_id);
+`
+
+// Open открывает файл базы и создает таблицы, если их еще нет
+func Open(path string) (*sql.DB, error) {
+	//_txlock=immediate: транзакция сразу берет блокировку на запись, иначе при переходе
+	//от чтения к записи SQLite возвращает SQLITE_BUSY, не дожидаясь busy_timeout
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(schema)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating schema: %v", err)
+	}
+
+	return db, nil
+}
+
+func newID(id primitive.ObjectID) string {
+	if id.IsZero() {
+		return primitive.NewObjectID().Hex()
+	}
+
+	return id.Hex()
+}
+
+// checkID проверяет идентификатор так же, как это делает ObjectIDFromHex в реализации для MongoDB
+func checkID(id, message string) error {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return model.InvalidID("%s", message)
+	}
+
+	return nil
+}
+
+// conflict заменяет нарушение ограничения UNIQUE на model.ErrConflict с понятным клиенту текстом
+func conflict(err error, message string) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY) {
+		return model.Conflict("%s", message)
+	}
+
+	return err
+}
+
+func objectID(s sql.NullString) primitive.ObjectID {
+	id, _ := primitive.ObjectIDFromHex(s.String)
+	return id
+}
+
+func objectIDs(s sql.NullString) []primitive.ObjectID {
+	if s.String == "" {
+		return nil
+	}
+
+	var ids []primitive.ObjectID
+	for _, hex := range strings.Split(s.String, ",") {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func nullID(id primitive.ObjectID) any {
+	if id.IsZero() {
+		return nil
+	}
+
+	return id.Hex()
+}
+
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+func nullBool(b *bool) any {
+	if b == nil {
+		return nil
+	}
+
+	return *b
+}
+
+func boolPtr(b sql.NullBool) *bool {
+	if !b.Valid {
+		return nil
+	}
+
+	return &b.Bool
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func affected(res sql.Result, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+// atomic выполняет несколько запросов одной транзакцией; внутри единицы работы использует ее транзакцию
+func (sc SQLiteClient) atomic(ctx context.Context, fn func(SQLiteClient) error) error {
+	if sc.tx != nil {
+		return fn(sc)
+	}
+
+	tx, err := sc.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = fn(SQLiteClient{DB: sc.DB, Timeout: sc.Timeout, tx: tx})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,170 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository/sqlite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newClient(t *testing.T) sqlite.SQLiteClient {
+	t.Helper()
+
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "notevault.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return sqlite.SQLiteClient{DB: db, Timeout: 5 * time.Second}
+}
+
+func createNoteBook(t *testing.T, sc sqlite.SQLiteClient, name string) string {
+	t.Helper()
+
+	id, err := sc.CreateNoteBook(context.Background(), model.NoteBook{Name: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
+
+func createNote(t *testing.T, sc sqlite.SQLiteClient, noteBookID, noteRank string) string {
+	t.Helper()
+
+	note := model.Note{Name: "note", Rank: noteRank}
+	if noteBookID != "" {
+		note.NoteBookID, _ = primitive.ObjectIDFromHex(noteBookID)
+	}
+
+	id, err := sc.CreateNote(context.Background(), note)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
+
+func noteRank(t *testing.T, sc sqlite.SQLiteClient, id string) (string, string) {
+	t.Helper()
+
+	note, err := sc.GetNoteByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noteBookID := ""
+	if !note.NoteBookID.IsZero() {
+		noteBookID = note.NoteBookID.Hex()
+	}
+
+	return noteBookID, note.Rank
+}
+
+func TestNoteRank(t *testing.T) {
+	ctx := context.Background()
+	sc := newClient(t)
+
+	noteBook := createNoteBook(t, sc, "Work")
+	createNote(t, sc, noteBook, "")
+	createNote(t, sc, noteBook, "b")
+	createNote(t, sc, noteBook, "d")
+	createNote(t, sc, "", "x")
+
+	tests := []struct {
+		name string
+		find func() (string, error)
+		want string
+	}{
+		{"last", func() (string, error) { return sc.GetLastNoteRank(ctx, noteBook) }, "d"},
+		{"last without notebook", func() (string, error) { return sc.GetLastNoteRank(ctx, "") }, "x"},
+		{"prev", func() (string, error) { return sc.GetPrevNoteRank(ctx, noteBook, "d") }, "b"},
+		{"prev of first", func() (string, error) { return sc.GetPrevNoteRank(ctx, noteBook, "b") }, ""},
+		{"next", func() (string, error) { return sc.GetNextNoteRank(ctx, noteBook, "b") }, "d"},
+		{"next of last", func() (string, error) { return sc.GetNextNoteRank(ctx, noteBook, "d") }, ""},
+		//Заметки, созданные до ранжирования, не имеют ключа и стоят перед остальными
+		{"prev of unranked", func() (string, error) { return sc.GetPrevNoteRank(ctx, noteBook, "") }, ""},
+		{"next of unranked", func() (string, error) { return sc.GetNextNoteRank(ctx, noteBook, "") }, "b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.find()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("rank = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	_, err := sc.GetLastNoteRank(ctx, "wrong")
+	if !errors.Is(err, model.ErrInvalidID) {
+		t.Fatalf("wrong notebook id: %v, want invalid id", err)
+	}
+}
+
+func TestMoveNotes(t *testing.T) {
+	ctx := context.Background()
+	sc := newClient(t)
+
+	from := createNoteBook(t, sc, "Inbox")
+	to := createNoteBook(t, sc, "Work")
+	first := createNote(t, sc, from, "b")
+	second := createNote(t, sc, from, "c")
+	target := createNote(t, sc, to, "m")
+
+	//Перенесенная заметка встает в конец нового блокнота
+	n, err := sc.UpdateNoteNoteBook(ctx, second, to)
+	if err != nil || n != 1 {
+		t.Fatalf("UpdateNoteNoteBook = %d, %v", n, err)
+	}
+	noteBookID, secondRank := noteRank(t, sc, second)
+	if noteBookID != to || secondRank <= "m" {
+		t.Fatalf("moved note in %s with rank %q, want %s after m", noteBookID, secondRank, to)
+	}
+
+	//Заметка, которая уже лежит в блокноте, не меняется
+	n, err = sc.UpdateNoteNoteBook(ctx, target, to)
+	if err != nil || n != 0 {
+		t.Fatalf("UpdateNoteNoteBook into own notebook = %d, %v", n, err)
+	}
+	if _, r := noteRank(t, sc, target); r != "m" {
+		t.Fatalf("note in own notebook got rank %q, want m", r)
+	}
+
+	n, err = sc.MoveNotesToNoteBook(ctx, from, to)
+	if err != nil || n != 1 {
+		t.Fatalf("MoveNotesToNoteBook = %d, %v", n, err)
+	}
+	noteBookID, firstRank := noteRank(t, sc, first)
+	if noteBookID != to || firstRank <= secondRank {
+		t.Fatalf("moved note in %s with rank %q, want %s after %q", noteBookID, firstRank, to, secondRank)
+	}
+
+	count, err := sc.CountNotesByNoteBookID(ctx, from)
+	if err != nil || count != 0 {
+		t.Fatalf("notes left in source notebook = %d, %v", count, err)
+	}
+
+	//Без блокнота заметки тоже идут в конец
+	n, err = sc.RemoveNoteBookFromNote(ctx, first)
+	if err != nil || n != 1 {
+		t.Fatalf("RemoveNoteBookFromNote = %d, %v", n, err)
+	}
+	if noteBookID, r := noteRank(t, sc, first); noteBookID != "" || r == "" {
+		t.Fatalf("removed note in %q with rank %q, want no notebook and a rank", noteBookID, r)
+	}
+
+	_, err = sc.MoveNotesToNoteBook(ctx, from, "wrong")
+	if !errors.Is(err, model.ErrInvalidID) {
+		t.Fatalf("wrong target notebook id: %v, want invalid id", err)
+	}
+}
@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+// RecordChange удаляет прежнюю запись о сущности и вставляет новую: AUTOINCREMENT не выдает seq повторно
+func (sc SQLiteClient) RecordChange(ctx context.Context, change model.SyncChange) (int64, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	var seq int64
+
+	err := sc.atomic(ctx, func(tc SQLiteClient) error {
+		_, err := tc.q().ExecContext(ctx, `DELETE FROM sync_changes WHERE kind = ? AND entity_id = ?`, change.Kind, change.EntityID)
+		if err != nil {
+			return err
+		}
+
+		res, err := tc.q().ExecContext(ctx, `INSERT INTO sync_changes (kind, entity_id, user_id, deleted) VALUES (?, ?, ?, ?)`,
+			change.Kind, change.EntityID, change.UserID, change.Deleted)
+		if err != nil {
+			return err
+		}
+
+		seq, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return seq, nil
+}
+
+func (sc SQLiteClient) GetChange(ctx context.Context, kind, id string) (model.SyncChange, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	change := model.SyncChange{Kind: kind, EntityID: id}
+
+	row := sc.q().QueryRowContext(ctx, `SELECT user_id, seq, deleted FROM sync_changes WHERE kind = ? AND entity_id = ?`, kind, id)
+
+	err := row.Scan(&change.UserID, &change.Seq, &change.Deleted)
+	if err == sql.ErrNoRows {
+		return model.SyncChange{}, model.NotFound("change not found")
+	} else if err != nil {
+		return model.SyncChange{}, err
+	}
+
+	return change, nil
+}
+
+func (sc SQLiteClient) GetChanges(ctx context.Context, userID string, since int64, limit int) ([]model.SyncChange, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT kind, entity_id, user_id, seq, deleted FROM sync_changes
+		WHERE seq > ? AND user_id IN (?, '') ORDER BY seq LIMIT ?`, since, userID, limit)
+	if err != nil {
+		return []model.SyncChange{}, fmt.Errorf("error finding changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []model.SyncChange{}
+
+	for rows.Next() {
+		var change model.SyncChange
+
+		err := rows.Scan(&change.Kind, &change.EntityID, &change.UserID, &change.Seq, &change.Deleted)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding changes: %w", err)
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+func (sc SQLiteClient) GetLastSyncSeq(ctx context.Context) (int64, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	var seq int64
+
+	err := sc.q().QueryRowContext(ctx, `SELECT COALESCE(MAX(seq), 0) FROM sync_changes`).Scan(&seq)
+	if err != nil {
+		return 0, err
+	}
+
+	return seq, nil
+}
@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+func scanTag(row rowScanner) (model.Tag, error) {
+	var tag model.Tag
+	var id sql.NullString
+
+	err := row.Scan(&id, &tag.Name, &tag.Color)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	tag.ID = objectID(id)
+
+	return tag, nil
+}
+
+func (sc SQLiteClient) queryTags(ctx context.Context, message, query string, args ...any) ([]model.Tag, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT id, name, color FROM tags `+query, args...)
+	if err != nil {
+		return []model.Tag{}, fmt.Errorf("%s: %w", message, err)
+	}
+	defer rows.Close()
+
+	var tags []model.Tag
+
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding tags: %w", err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (sc SQLiteClient) getTag(ctx context.Context, cond string, arg any) (model.Tag, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	row := sc.q().QueryRowContext(ctx, `SELECT id, name, color FROM tags WHERE `+cond, arg)
+
+	tag, err := scanTag(row)
+	if err == sql.ErrNoRows {
+		return model.Tag{}, model.NotFound("tag not found")
+	} else if err != nil {
+		return model.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+func (sc SQLiteClient) CreateTag(ctx context.Context, tag model.Tag) (string, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	id := newID(tag.ID)
+
+	_, err := sc.q().ExecContext(ctx, `INSERT INTO tags (id, name, color) VALUES (?, ?, ?)`, id, tag.Name, tag.Color)
+	if err != nil {
+		return "", conflict(err, "tag with this name already exists")
+	}
+
+	return id, nil
+}
+
+func (sc SQLiteClient) GetTagByID(ctx context.Context, id string) (model.Tag, error) {
+	if err := checkID(id, "wrong id"); err != nil {
+		return model.Tag{}, err
+	}
+
+	return sc.getTag(ctx, `id = ?`, id)
+}
+
+func (sc SQLiteClient) GetTagByName(ctx context.Context, tagName string) (model.Tag, error) {
+	return sc.getTag(ctx, `name = ?`, tagName)
+}
+
+func (sc SQLiteClient) GetTags(ctx context.Context) ([]model.Tag, error) {
+	return sc.queryTags(ctx, "error finding tags", `ORDER BY rowid`)
+}
+
+func (sc SQLiteClient) GetTagsByIDs(ctx context.Context, ids []string) ([]model.Tag, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		if err := checkID(id, "wrong id"); err != nil {
+			return []model.Tag{}, err
+		}
+		args = append(args, id)
+	}
+
+	return sc.queryTags(ctx, "error finding tags", `WHERE id IN (`+placeholders(len(args))+`) ORDER BY rowid`, args...)
+}
+
+func (sc SQLiteClient) GetTagDescendants(ctx context.Context, tagName string) ([]model.Tag, error) {
+	prefix := tagName + model.TagPathSeparator
+
+	return sc.queryTags(ctx, "error finding descendant tags", `WHERE substr(name, 1, length(?1)) = ?1 ORDER BY rowid`, prefix)
+}
+
+func (sc SQLiteClient) UpdateTag(ctx context.Context, id, name, color string) (int, error) {
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	var sets []string
+	var args []any
+	if name != "" {
+		sets, args = append(sets, "name = ?"), append(args, name)
+	}
+	if color != "" {
+		sets, args = append(sets, "color = ?"), append(args, color)
+	}
+	if len(sets) == 0 {
+		return 0, nil
+	}
+
+	res, err := sc.update(ctx, "tags", sets, args, id)
+
+	return res, conflict(err, "tag with this name already exists")
+}
+
+func (sc SQLiteClient) RenameTagDescendants(ctx context.Context, oldName, newName string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	oldPrefix := oldName + model.TagPathSeparator
+	newPrefix := newName + model.TagPathSeparator
+
+	res, err := affected(sc.q().ExecContext(ctx,
+		`UPDATE tags SET name = ?2 || substr(name, length(?1) + 1) WHERE substr(name, 1, length(?1)) = ?1`,
+		oldPrefix, newPrefix))
+
+	return res, conflict(err, "tag with this name already exists")
+}
+
+func (sc SQLiteClient) DeleteTag(ctx context.Context, id string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM tags WHERE id = ?`, id))
+}
@@ -0,0 +1,269 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+const templateColumns = `id, user_id, name, text, color, notebook_id,
+	(SELECT group_concat(tag_id, ',' ORDER BY rowid) FROM template_tags WHERE template_id = templates.id)`
+
+func scanTemplate(row rowScanner) (model.Template, error) {
+	var template model.Template
+	var id, userID, noteBookID, tags sql.NullString
+
+	err := row.Scan(&id, &userID, &template.Name, &template.Text, &template.Color, &noteBookID, &tags)
+	if err != nil {
+		return model.Template{}, err
+	}
+
+	template.ID = objectID(id)
+	template.UserID = objectID(userID)
+	template.NoteBookID = objectID(noteBookID)
+	template.Tags = objectIDs(tags)
+
+	return template, nil
+}
+
+func (sc SQLiteClient) getTemplate(ctx context.Context, cond string, args ...any) (model.Template, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	row := sc.q().QueryRowContext(ctx, `SELECT `+templateColumns+` FROM templates WHERE `+cond+` ORDER BY rowid LIMIT 1`, args...)
+
+	template, err := scanTemplate(row)
+	if err == sql.ErrNoRows {
+		return model.Template{}, model.NotFound("template not found")
+	} else if err != nil {
+		return model.Template{}, err
+	}
+
+	return template, nil
+}
+
+func (sc SQLiteClient) setTemplateTags(ctx context.Context, id string, tagIDs []string) error {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	_, err := sc.q().ExecContext(ctx, `DELETE FROM template_tags WHERE template_id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	for _, tagID := range tagIDs {
+		_, err := sc.q().ExecContext(ctx, `INSERT OR IGNORE INTO template_tags (template_id, tag_id) VALUES (?, ?)`, id, tagID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sc SQLiteClient) CreateTemplate(ctx context.Context, template model.Template) (string, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	id := newID(template.ID)
+
+	tagIDs := make([]string, 0, len(template.Tags))
+	for _, tagID := range template.Tags {
+		tagIDs = append(tagIDs, tagID.Hex())
+	}
+
+	err := sc.atomic(ctx, func(tc SQLiteClient) error {
+		_, err := tc.q().ExecContext(ctx, `INSERT INTO templates (id, user_id, name, text, color, notebook_id) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, template.UserID.Hex(), template.Name, template.Text, template.Color, nullID(template.NoteBookID))
+		if err != nil {
+			return err
+		}
+
+		return tc.setTemplateTags(ctx, id, tagIDs)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (sc SQLiteClient) GetTemplateByID(ctx context.Context, id string) (model.Template, error) {
+	if err := checkID(id, "wrong id"); err != nil {
+		return model.Template{}, err
+	}
+
+	return sc.getTemplate(ctx, `id = ?`, id)
+}
+
+func (sc SQLiteClient) GetTemplateByName(ctx context.Context, userID, name string) (model.Template, error) {
+	if err := checkID(userID, "wrong user id"); err != nil {
+		return model.Template{}, err
+	}
+
+	return sc.getTemplate(ctx, `user_id = ? AND name = ?`, userID, name)
+}
+
+func (sc SQLiteClient) GetTemplatesByUserID(ctx context.Context, userID string) ([]model.Template, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(userID, "wrong user id"); err != nil {
+		return []model.Template{}, err
+	}
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT `+templateColumns+` FROM templates WHERE user_id = ? ORDER BY rowid`, userID)
+	if err != nil {
+		return []model.Template{}, fmt.Errorf("error finding templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []model.Template
+
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding templates: %w", err)
+		}
+
+		templates = append(templates, template)
+	}
+
+	return templates, rows.Err()
+}
+
+func (sc SQLiteClient) UpdateTemplate(ctx context.Context, id, name, text, color, noteBookID string, tagIDs []string) (int, error) {
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	var sets []string
+	var args []any
+	if name != "" {
+		sets, args = append(sets, "name = ?"), append(args, name)
+	}
+	if text != "" {
+		sets, args = append(sets, "text = ?"), append(args, text)
+	}
+	if color != "" {
+		sets, args = append(sets, "color = ?"), append(args, color)
+	}
+	if noteBookID != "" {
+		if err := checkID(noteBookID, "wrong notebook id"); err != nil {
+			return 0, err
+		}
+		sets, args = append(sets, "notebook_id = ?"), append(args, noteBookID)
+	}
+	for _, tagID := range tagIDs {
+		if err := checkID(tagID, "invalid tag ID"); err != nil {
+			return 0, err
+		}
+	}
+	if len(sets) == 0 && tagIDs == nil {
+		return 0, nil
+	}
+
+	count := 0
+
+	err := sc.atomic(ctx, func(tc SQLiteClient) error {
+		if len(sets) > 0 {
+			var err error
+			count, err = tc.update(ctx, "templates", sets, args, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		if tagIDs != nil {
+			err := tc.setTemplateTags(ctx, id, tagIDs)
+			if err != nil {
+				return err
+			}
+			count = 1
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (sc SQLiteClient) DeleteTemplate(ctx context.Context, id string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM templates WHERE id = ?`, id))
+}
+
+func (sc SQLiteClient) DeleteTemplatesByUserID(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(userID, "wrong user id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM templates WHERE user_id = ?`, userID))
+}
+
+func (sc SQLiteClient) UnlinkTemplatesFromTag(ctx context.Context, tagID string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(tagID, "wrong tag id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM template_tags WHERE tag_id = ?`, tagID))
+}
+
+func (sc SQLiteClient) ReplaceTagInTemplates(ctx context.Context, fromID, toID string) (int, error) {
+	if err := checkID(fromID, "invalid tag ID"); err != nil {
+		return 0, err
+	}
+	if err := checkID(toID, "invalid tag ID"); err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	err := sc.atomic(ctx, func(tc SQLiteClient) error {
+		ctx, cancel := tc.ctx(ctx)
+		defer cancel()
+
+		_, err := tc.q().ExecContext(ctx,
+			`INSERT OR IGNORE INTO template_tags (template_id, tag_id) SELECT template_id, ? FROM template_tags WHERE tag_id = ?`, toID, fromID)
+		if err != nil {
+			return err
+		}
+
+		count, err = affected(tc.q().ExecContext(ctx, `DELETE FROM template_tags WHERE tag_id = ?`, fromID))
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (sc SQLiteClient) UnlinkTemplatesFromNoteBook(ctx context.Context, noteBookID string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(noteBookID, "wrong notebook id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `UPDATE templates SET notebook_id = NULL WHERE notebook_id = ?`, noteBookID))
+}
@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+)
+
+type SQLiteUnitOfWork struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func (uow SQLiteUnitOfWork) Do(ctx context.Context, fn func(repository.Tx) error) error {
+	return SQLiteClient{DB: uow.DB, Timeout: uow.Timeout}.atomic(ctx, func(tc SQLiteClient) error {
+		return fn(repository.Tx{
+			Notes:     tc,
+			NoteBooks: tc,
+			Tags:      tc,
+			Users:     tc,
+			Templates: tc,
+			Webhooks:  tc,
+			Reminders: tc,
+		})
+	})
+}
@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+func (sc SQLiteClient) getUser(ctx context.Context, cond string, arg any) (model.User, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	var user model.User
+	var id sql.NullString
+
+	err := sc.q().QueryRowContext(ctx, `SELECT id, password_hash, email, first_name, last_name FROM users WHERE `+cond, arg).
+		Scan(&id, &user.PasswordHash, &user.Email, &user.FirstName, &user.LastName)
+	if err == sql.ErrNoRows {
+		return model.User{}, model.NotFound("user not found")
+	} else if err != nil {
+		return model.User{}, err
+	}
+
+	user.ID = objectID(id)
+
+	return user, nil
+}
+
+func (sc SQLiteClient) RegisterUser(ctx context.Context, user model.User) (string, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	id := newID(user.ID)
+
+	_, err := sc.q().ExecContext(ctx, `INSERT INTO users (id, password_hash, email, first_name, last_name) VALUES (?, ?, ?, ?, ?)`,
+		id, user.PasswordHash, user.Email, user.FirstName, user.LastName)
+	if err != nil {
+		return "", conflict(err, "user with this email already exists")
+	}
+
+	return id, nil
+}
+
+func (sc SQLiteClient) LoginUser(ctx context.Context, email string) (model.User, error) {
+	return sc.getUser(ctx, `email = ?`, email)
+}
+
+func (sc SQLiteClient) GetProfile(ctx context.Context, id string) (model.User, error) {
+	if err := checkID(id, "wrong user id"); err != nil {
+		return model.User{}, err
+	}
+
+	return sc.getUser(ctx, `id = ?`, id)
+}
+
+func (sc SQLiteClient) DeleteUser(ctx context.Context, id string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong user id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM users WHERE id = ?`, id))
+}
@@ -0,0 +1,209 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const webhookColumns = `id, user_id, url, secret, events, is_active, created_at`
+
+func scanWebhook(row rowScanner) (model.Webhook, error) {
+	var webhook model.Webhook
+	var id, userID sql.NullString
+	var events string
+	var isActive sql.NullBool
+
+	err := row.Scan(&id, &userID, &webhook.URL, &webhook.Secret, &events, &isActive, &webhook.CreatedAt)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	webhook.ID = objectID(id)
+	webhook.UserID = objectID(userID)
+	webhook.IsActive = boolPtr(isActive)
+	if events != "" {
+		webhook.Events = strings.Split(events, ",")
+	}
+
+	return webhook, nil
+}
+
+func (sc SQLiteClient) CreateWebhook(ctx context.Context, webhook model.Webhook) (string, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	id := newID(webhook.ID)
+
+	_, err := sc.q().ExecContext(ctx, `INSERT INTO webhooks (id, user_id, url, secret, events, is_active, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		id, webhook.UserID.Hex(), webhook.URL, webhook.Secret, strings.Join(webhook.Events, ","), nullBool(webhook.IsActive), webhook.CreatedAt)
+	if err != nil {
+		return "", conflict(err, "webhook already exists")
+	}
+
+	return id, nil
+}
+
+func (sc SQLiteClient) GetWebhookByID(ctx context.Context, id string) (model.Webhook, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return model.Webhook{}, err
+	}
+
+	row := sc.q().QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = ?`, id)
+
+	webhook, err := scanWebhook(row)
+	if err == sql.ErrNoRows {
+		return model.Webhook{}, model.NotFound("webhook not found")
+	} else if err != nil {
+		return model.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+func (sc SQLiteClient) GetWebhooksByUserID(ctx context.Context, userID string) ([]model.Webhook, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(userID, "wrong user id"); err != nil {
+		return []model.Webhook{}, err
+	}
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE user_id = ? ORDER BY rowid`, userID)
+	if err != nil {
+		return []model.Webhook{}, fmt.Errorf("error finding webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []model.Webhook
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding webhooks: %w", err)
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (sc SQLiteClient) UpdateWebhook(ctx context.Context, id string, update repository.WebhookUpdate) (int, error) {
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	var sets []string
+	var args []any
+	if update.URL != nil {
+		sets, args = append(sets, "url = ?"), append(args, *update.URL)
+	}
+	if update.Secret != nil {
+		sets, args = append(sets, "secret = ?"), append(args, *update.Secret)
+	}
+	if update.Events != nil {
+		sets, args = append(sets, "events = ?"), append(args, strings.Join(*update.Events, ","))
+	}
+	if update.IsActive != nil {
+		sets, args = append(sets, "is_active = ?"), append(args, *update.IsActive)
+	}
+	if len(sets) == 0 {
+		return 0, nil
+	}
+
+	return sc.update(ctx, "webhooks", sets, args, id)
+}
+
+func (sc SQLiteClient) DeleteWebhook(ctx context.Context, id string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(id, "wrong id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id))
+}
+
+func (sc SQLiteClient) DeleteWebhooksByUserID(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(userID, "wrong user id"); err != nil {
+		return 0, err
+	}
+
+	return affected(sc.q().ExecContext(ctx, `DELETE FROM webhooks WHERE user_id = ?`, userID))
+}
+
+// AddWebhookDelivery пишет попытку в журнал и удаляет из него попытки старше последних MaxWebhookDeliveries
+func (sc SQLiteClient) AddWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	return sc.atomic(ctx, func(tc SQLiteClient) error {
+		_, err := tc.q().ExecContext(ctx, `INSERT INTO webhook_deliveries
+			(id, webhook_id, delivery_id, event, attempt, status_code, success, error, duration_ms, payload, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			newID(delivery.ID), delivery.WebhookID.Hex(), delivery.DeliveryID, delivery.Event, delivery.Attempt, delivery.StatusCode,
+			delivery.Success, delivery.Error, delivery.DurationMs, delivery.Payload, delivery.CreatedAt)
+
+		var sqliteErr *sqlite.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY {
+			return model.NotFound("webhook not found")
+		} else if err != nil {
+			return err
+		}
+
+		_, err = tc.q().ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE webhook_id = ? AND rowid NOT IN
+			(SELECT rowid FROM webhook_deliveries WHERE webhook_id = ? ORDER BY rowid DESC LIMIT ?)`,
+			delivery.WebhookID.Hex(), delivery.WebhookID.Hex(), repository.MaxWebhookDeliveries)
+		return err
+	})
+}
+
+func (sc SQLiteClient) GetWebhookDeliveries(ctx context.Context, webhookID string) ([]model.WebhookDelivery, error) {
+	ctx, cancel := sc.ctx(ctx)
+	defer cancel()
+
+	if err := checkID(webhookID, "wrong webhook id"); err != nil {
+		return []model.WebhookDelivery{}, err
+	}
+
+	rows, err := sc.q().QueryContext(ctx, `SELECT id, webhook_id, delivery_id, event, attempt, status_code, success, error, duration_ms, payload, created_at
+		FROM webhook_deliveries WHERE webhook_id = ? ORDER BY rowid DESC`, webhookID)
+	if err != nil {
+		return []model.WebhookDelivery{}, fmt.Errorf("error finding webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []model.WebhookDelivery{}
+
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		var id, hookID sql.NullString
+
+		err := rows.Scan(&id, &hookID, &delivery.DeliveryID, &delivery.Event, &delivery.Attempt, &delivery.StatusCode,
+			&delivery.Success, &delivery.Error, &delivery.DurationMs, &delivery.Payload, &delivery.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding webhook deliveries: %w", err)
+		}
+
+		delivery.ID = objectID(id)
+		delivery.WebhookID = objectID(hookID)
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
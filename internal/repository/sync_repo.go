@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+// SyncRepo - журнал синхронизации, в котором у каждой сущности хранится только последнее изменение
+type SyncRepo interface {
+	// RecordChange присваивает изменению следующий Seq, заменяет прежнюю запись о сущности и возвращает Seq
+	RecordChange(context.Context, model.SyncChange) (int64, error)
+	GetChange(context.Context, string, string) (model.SyncChange, error)
+	// GetChanges возвращает изменения пользователя и общих сущностей с Seq больше заданного по возрастанию Seq
+	GetChanges(context.Context, string, int64, int) ([]model.SyncChange, error)
+	GetLastSyncSeq(context.Context) (int64, error)
+}
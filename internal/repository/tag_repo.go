@@ -1,12 +1,18 @@
 package repository
 
-import "github.com/LoL-KeKovich/NoteVault/internal/model"
+import (
+	"context"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
 
 type TagRepo interface {
-	CreateTag(model.Tag) (string, error)
-	GetTagByID(string) (model.Tag, error)
-	GetTagByName(string) (model.Tag, error)
-	GetTags() ([]model.Tag, error)
-	UpdateTag(string, string, string) (int, error)
-	DeleteTag(string) (int, error)
+	CreateTag(context.Context, model.Tag) (string, error)
+	GetTagByID(context.Context, string) (model.Tag, error)
+	GetTagByName(context.Context, string) (model.Tag, error)
+	GetTags(context.Context) ([]model.Tag, error)
+	GetTagsByIDs(context.Context, []string) ([]model.Tag, error)
+	GetTagDescendants(context.Context, string) ([]model.Tag, error)
+	UpdateTag(context.Context, string, string, string) (int, error)
+	RenameTagDescendants(context.Context, string, string) (int, error)
+	DeleteTag(context.Context, string) (int, error)
 }
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+type TemplateRepo interface {
+	CreateTemplate(context.Context, model.Template) (string, error)
+	GetTemplateByID(context.Context, string) (model.Template, error)
+	GetTemplateByName(context.Context, string, string) (model.Template, error)
+	GetTemplatesByUserID(context.Context, string) ([]model.Template, error)
+	UpdateTemplate(context.Context, string, string, string, string, string, []string) (int, error)
+	DeleteTemplate(context.Context, string) (int, error)
+	DeleteTemplatesByUserID(context.Context, string) (int, error)
+	UnlinkTemplatesFromTag(context.Context, string) (int, error)
+	ReplaceTagInTemplates(context.Context, string, string) (int, error)
+	UnlinkTemplatesFromNoteBook(context.Context, string) (int, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "context"
+
+type Tx struct {
+	Notes     NoteRepo
+	NoteBooks NoteBookRepo
+	Tags      TagRepo
+	Users     UserRepo
+	Templates TemplateRepo
+	Webhooks  WebhookRepo
+	Reminders ReminderRepo
+}
+
+type UnitOfWork interface {
+	Do(context.Context, func(Tx) error) error
+}
@@ -1,9 +1,13 @@
 package repository
 
-import "github.com/LoL-KeKovich/NoteVault/internal/model"
+import (
+	"context"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
 
 type UserRepo interface {
-	RegisterUser(model.User) (string, error)
-	LoginUser(string) (model.User, error)
-	GetProfile(string) (model.User, error)
+	RegisterUser(context.Context, model.User) (string, error)
+	LoginUser(context.Context, string) (model.User, error)
+	GetProfile(context.Context, string) (model.User, error)
+	DeleteUser(context.Context, string) (int, error)
 }
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+)
+
+// MaxWebhookDeliveries - сколько последних попыток доставки хранится у каждого вебхука
+const MaxWebhookDeliveries = 100
+
+// WebhookUpdate - изменения вебхука, nil - поле не меняется
+type WebhookUpdate struct {
+	URL      *string
+	Secret   *string
+	Events   *[]string
+	IsActive *bool
+}
+
+type WebhookRepo interface {
+	CreateWebhook(context.Context, model.Webhook) (string, error)
+	GetWebhookByID(context.Context, string) (model.Webhook, error)
+	GetWebhooksByUserID(context.Context, string) ([]model.Webhook, error)
+	UpdateWebhook(context.Context, string, WebhookUpdate) (int, error)
+	DeleteWebhook(context.Context, string) (int, error)
+	DeleteWebhooksByUserID(context.Context, string) (int, error)
+	AddWebhookDelivery(context.Context, model.WebhookDelivery) error
+	GetWebhookDeliveries(context.Context, string) ([]model.WebhookDelivery, error)
+}
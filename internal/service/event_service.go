@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// eventsHeartbeat - интервал комментариев-пингов, чтобы прокси не закрывали молчащее соединение
+const eventsHeartbeat = 30 * time.Second
+
+type EventService struct {
+	Bus *events.Bus
+}
+
+// HandleEvents отдает поток server-sent events с изменениями заметок, блокнотов и тегов пользователя.
+// Пропущенные при разрыве события не повторяются: после переподключения клиент перечитывает данные
+func (srv EventService) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+
+	//Поток живет дольше WriteTimeout сервера
+	rc.SetWriteDeadline(time.Time{})
+
+	ch, unsubscribe := srv.Bus.Subscribe(userID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if rc.Flush() != nil {
+		return
+	}
+
+	slog.Info("Event stream opened", slog.String("user_id", userID))
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case e, ok := <-ch:
+			if !ok {
+				slog.Info("Event stream dropped, subscriber is too slow", slog.String("user_id", userID))
+				return
+			}
+
+			data, _ := json.Marshal(e)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type(), data)
+		}
+
+		if rc.Flush() != nil {
+			return
+		}
+	}
+}
+
+// noteOwners находит владельцев заметок для адресации событий одним запросом. Без подписчиков возвращает nil
+// и не ходит в базу. Неверные ID пропускаются: событий по ним не будет
+func (srv NoteService) noteOwners(ctx context.Context, ids ...string) map[string]string {
+	if !srv.Events.Active() {
+		return nil
+	}
+
+	valid := slices.DeleteFunc(slices.Clone(ids), func(id string) bool { return !primitive.IsValidObjectID(id) })
+	if len(valid) == 0 {
+		return nil
+	}
+
+	notes, err := srv.DBClient.GetNotesByIDs(ctx, valid)
+	if err != nil {
+		slog.Error("Error finding note owners", slog.String("error", err.Error()))
+		return nil
+	}
+
+	owners := make(map[string]string, len(notes))
+	for _, note := range notes {
+		owners[note.ID.Hex()] = ownerOf(note)
+	}
+
+	return owners
+}
+
+// publishNotes рассылает событие по заметкам; для удаления владельцев нужно найти до удаления через noteOwners
+func (srv NoteService) publishNotes(ctx context.Context, action string, ids ...string) {
+	srv.publishOwned(action, ids, srv.noteOwners(ctx, ids...))
+}
+
+func (srv NoteService) publishOwned(action string, ids []string, owners map[string]string) {
+	for _, id := range ids {
+		if userID, ok := owners[id]; ok {
+			srv.Events.Publish(events.Event{Kind: events.KindNote, Action: action, ID: id, UserID: userID})
+		}
+	}
+}
+
+func ownerOf(note model.Note) string {
+	if note.UserID.IsZero() {
+		return ""
+	}
+
+	return note.UserID.Hex()
+}
+
+// publishNoteChanges рассылает события по заметкам, которые изменились вместе с блокнотом или тегом
+func publishNoteChanges(bus *events.Bus, action string, notes []model.Note) {
+	for _, note := range notes {
+		bus.Publish(events.Event{Kind: events.KindNote, Action: action, ID: note.ID.Hex(), UserID: ownerOf(note)})
+	}
+}
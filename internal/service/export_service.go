@@ -0,0 +1,252 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/timezone"
+	"gopkg.in/yaml.v3"
+)
+
+const maxExportNameLength = 100
+
+type ExportService struct {
+	DBClient             repository.NoteRepo
+	HelperNoteBookClient repository.NoteBookRepo
+	HelperTagClient      repository.TagRepo
+}
+
+type exportFrontMatter struct {
+	ID          string   `yaml:"id"`
+	Title       string   `yaml:"title"`
+	NoteBook    string   `yaml:"notebook,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+	Color       string   `yaml:"color,omitempty"`
+	Pinned      bool     `yaml:"pinned,omitempty"`
+	Favourite   bool     `yaml:"favourite,omitempty"`
+	Archived    bool     `yaml:"archived"`
+	Trashed     bool     `yaml:"trashed"`
+	JournalDate string   `yaml:"journal_date,omitempty"`
+	CreatedAt   string   `yaml:"created_at"`
+	UpdatedAt   string   `yaml:"updated_at"`
+}
+
+type exportNoteBook struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	IsActive    bool   `yaml:"is_active"`
+	Folder      string `yaml:"folder"`
+}
+
+type exportTag struct {
+	ID    string `yaml:"id"`
+	Name  string `yaml:"name"`
+	Color string `yaml:"color,omitempty"`
+}
+
+// HandleExport отдает ZIP со всеми заметками пользователя в Markdown.
+// Архив пишется прямо в ответ, заметки читаются из базы по одной
+func (srv ExportService) HandleExport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	noteBooks, err := srv.HelperNoteBookClient.GetNoteBooks(r.Context())
+	if err != nil {
+		respondError(w, err, "Error finding notebooks in db")
+		return
+	}
+
+	tags, err := srv.HelperTagClient.GetTags(r.Context())
+	if err != nil {
+		respondError(w, err, "Error finding tags in db")
+		return
+	}
+
+	//Выгрузка большого хранилища может не уложиться в таймаут записи сервера
+	err = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	if err != nil {
+		slog.Warn("Failed to reset write deadline", slog.String("error", err.Error()))
+	}
+
+	fileName := fmt.Sprintf("notevault-%s.zip", timezone.Now().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+
+	archive := zip.NewWriter(w)
+
+	err = srv.writeExport(r.Context(), archive, userID, noteBooks, tags)
+	if err == nil {
+		err = archive.Close()
+	}
+	if err != nil {
+		//Заголовки уже отправлены, клиент получит оборванный архив
+		slog.Error("Export failed", slog.String("error", err.Error()))
+		return
+	}
+
+	slog.Info("Vault exported", slog.String("user_id", userID))
+}
+
+func (srv ExportService) writeExport(ctx context.Context, archive *zip.Writer, userID string, noteBooks []model.NoteBook, tags []model.Tag) error {
+	used := map[string]bool{"notebooks.yaml": true, "tags.yaml": true}
+
+	folders := map[string]string{}
+	names := map[string]string{}
+	exportNoteBooks := []exportNoteBook{}
+
+	for _, noteBook := range noteBooks {
+		id := noteBook.ID.Hex()
+		folder := uniqueExportName(used, "", exportFileName(noteBook.Name), "")
+		folders[id] = folder
+		names[id] = noteBook.Name
+
+		exportNoteBooks = append(exportNoteBooks, exportNoteBook{
+			ID:          id,
+			Name:        noteBook.Name,
+			Description: noteBook.Description,
+			IsActive:    noteBook.IsActive != nil && *noteBook.IsActive,
+			Folder:      folder,
+		})
+	}
+
+	tagNames := map[string]string{}
+	exportTags := []exportTag{}
+
+	for _, tag := range tags {
+		tagNames[tag.ID.Hex()] = tag.Name
+		exportTags = append(exportTags, exportTag{
+			ID:    tag.ID.Hex(),
+			Name:  tag.Name,
+			Color: tag.Color,
+		})
+	}
+
+	err := writeExportYAML(archive, "notebooks.yaml", exportNoteBooks)
+	if err != nil {
+		return err
+	}
+
+	err = writeExportYAML(archive, "tags.yaml", exportTags)
+	if err != nil {
+		return err
+	}
+
+	return srv.DBClient.StreamNotes(ctx, userID, func(note model.Note) error {
+		noteBookID := ""
+		if !note.NoteBookID.IsZero() {
+			noteBookID = note.NoteBookID.Hex()
+		}
+
+		fm := exportFrontMatter{
+			ID:          note.ID.Hex(),
+			Title:       note.Name,
+			NoteBook:    names[noteBookID],
+			Color:       note.Color,
+			Pinned:      note.IsPinned != nil && *note.IsPinned,
+			Favourite:   note.IsFavourite != nil && *note.IsFavourite,
+			Archived:    note.IsArchived != nil && *note.IsArchived,
+			Trashed:     note.IsDeleted != nil && *note.IsDeleted,
+			JournalDate: note.JournalDate,
+			CreatedAt:   note.CreatedAt,
+			UpdatedAt:   note.UpdatedAt,
+		}
+		for _, tagID := range note.Tags {
+			if name, ok := tagNames[tagID.Hex()]; ok {
+				fm.Tags = append(fm.Tags, name)
+			}
+		}
+
+		fileName := uniqueExportName(used, folders[noteBookID], exportFileName(note.Name), ".md")
+
+		file, err := archive.Create(fileName)
+		if err != nil {
+			return err
+		}
+
+		return writeExportNote(file, fm, note.Text)
+	})
+}
+
+func writeExportNote(w io.Writer, fm exportFrontMatter, text string) error {
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "---\n%s---\n\n%s", header, text)
+	if err != nil {
+		return err
+	}
+
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		_, err = io.WriteString(w, "\n")
+	}
+
+	return err
+}
+
+func writeExportYAML(archive *zip.Writer, name string, value any) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(file)
+	err = encoder.Encode(value)
+	if err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// exportFileName убирает из имени символы, недопустимые в путях распространенных файловых систем
+func exportFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	name = strings.Trim(name, " .")
+
+	runes := []rune(name)
+	if len(runes) > maxExportNameLength {
+		name = strings.TrimRight(string(runes[:maxExportNameLength]), " .")
+	}
+
+	if name == "" {
+		return "Untitled"
+	}
+
+	return name
+}
+
+// uniqueExportName добавляет к имени номер, если такой путь в архиве уже занят.
+// Сравнение без учета регистра, чтобы архив распаковывался и на нечувствительных к регистру ФС
+func uniqueExportName(used map[string]bool, dir, name, ext string) string {
+	candidate := path.Join(dir, name+ext)
+
+	for i := 2; used[strings.ToLower(candidate)]; i++ {
+		candidate = path.Join(dir, fmt.Sprintf("%s (%d)%s", name, i, ext))
+	}
+
+	used[strings.ToLower(candidate)] = true
+
+	return candidate
+}
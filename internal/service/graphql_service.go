@@ -0,0 +1,898 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/graphql"
+	"github.com/LoL-KeKovich/NoteVault/lib/timezone"
+	"github.com/LoL-KeKovich/NoteVault/lib/validate"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errReminderNotFound = model.NotFound("Reminder not found")
+
+// GraphQLService отдает те же данные, что и REST, одним запросом с нужными клиенту полями.
+// Связи заметок (блокнот, теги, напоминания) и заметки блокнотов и тегов читаются пакетно:
+// один запрос к базе на поле для всех объектов уровня, а не на каждый объект
+type GraphQLService struct {
+	Notes     NoteService
+	NoteBooks NoteBookService
+	Tags      TagService
+	Reminders repository.ReminderRepo
+}
+
+func (srv GraphQLService) HandleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var gqlReq dto.GraphQLRequest
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	if !decodeRequest(w, r, &gqlReq) {
+		return
+	}
+
+	//Ошибки запроса и резолверов возвращаются в errors со статусом 200, как принято в GraphQL
+	response := graphql.Execute(r.Context(), srv.schema(), graphql.Request{
+		Query:         gqlReq.Query,
+		OperationName: gqlReq.OperationName,
+		Variables:     gqlReq.Variables,
+	})
+
+	slog.Info("GraphQL executed", slog.String("user_id", userID), slog.String("operation", gqlReq.OperationName), slog.Int("errors", len(response.Errors)))
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleGraphQLSchema отдает схему на языке SDL для генераторов клиентов и IDE
+func (srv GraphQLService) HandleGraphQLSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(srv.schema().SDL()))
+}
+
+// graphQLError переводит ошибку резолвера в ошибку ответа с тем же кодом, что и в REST
+func graphQLError(err error) graphql.Error {
+	var fieldErrs validate.Errors
+	if errors.As(err, &fieldErrs) {
+		fields := make([]dto.FieldError, 0, len(fieldErrs))
+		for _, fe := range fieldErrs {
+			fields = append(fields, dto.FieldError{Field: fe.Field, Rule: fe.Rule, Message: fe.Message})
+		}
+
+		return graphql.Error{Message: "Validation failed", Extensions: map[string]any{"code": dto.ErrorCodeInvalidFields, "fields": fields}}
+	}
+
+	for _, s := range errorStatuses {
+		if errors.Is(err, s.kind) {
+			message := s.kind.Error()
+			var modelErr *model.Error
+			if errors.As(err, &modelErr) {
+				message = modelErr.Message
+			}
+
+			return graphql.Error{Message: message, Extensions: map[string]any{"code": s.code}}
+		}
+	}
+
+	slog.Error("Error resolving GraphQL field", slog.String("error", err.Error()))
+	return graphql.Error{Message: "Internal error", Extensions: map[string]any{"code": dto.ErrorCodeInternal}}
+}
+
+func (srv GraphQLService) schema() *graphql.Schema {
+	noteStatus := &graphql.Enum{Name: "NoteStatus", Values: []string{"ACTIVE", "ARCHIVED", "TRASHED", "ALL"}}
+	deleteMode := &graphql.Enum{Name: "DeleteMode", Values: []string{"UNLINK", "MOVE", "TRASH", "RESTRICT"}}
+	reminderRepeat := &graphql.Enum{Name: "ReminderRepeat", Values: []string{"DAILY", "WEEKLY", "MONTHLY", "YEARLY"}}
+
+	id := graphql.NewNonNull(graphql.ID)
+	str := graphql.NewNonNull(graphql.String)
+	boolean := graphql.NewNonNull(graphql.Boolean)
+
+	user := &graphql.Object{Name: "User", Fields: map[string]*graphql.Field{
+		"id":        gqlField(id, func(u model.User) any { return u.ID.Hex() }),
+		"email":     gqlField(str, func(u model.User) any { return u.Email }),
+		"firstName": gqlField(graphql.String, func(u model.User) any { return u.FirstName }),
+		"lastName":  gqlField(graphql.String, func(u model.User) any { return u.LastName }),
+	}}
+
+	note := &graphql.Object{Name: "Note", Description: "Заметка пользователя или общая заметка без владельца"}
+	noteBook := &graphql.Object{Name: "NoteBook"}
+	tag := &graphql.Object{Name: "Tag"}
+	reminder := &graphql.Object{Name: "Reminder"}
+
+	notesArgs := map[string]*graphql.Arg{
+		"status": {Type: noteStatus, Default: "ACTIVE"},
+	}
+
+	note.Fields = map[string]*graphql.Field{
+		"id":          gqlField(id, func(n model.Note) any { return n.ID.Hex() }),
+		"name":        gqlField(str, func(n model.Note) any { return n.Name }),
+		"text":        gqlField(graphql.String, func(n model.Note) any { return n.Text }),
+		"color":       gqlField(graphql.String, func(n model.Note) any { return n.Color }),
+		"order":       gqlField(graphql.Int, func(n model.Note) any { return n.Order }),
+		"isDeleted":   gqlField(boolean, func(n model.Note) any { return isSet(n.IsDeleted) }),
+		"isArchived":  gqlField(boolean, func(n model.Note) any { return isSet(n.IsArchived) }),
+		"isPinned":    gqlField(boolean, func(n model.Note) any { return isSet(n.IsPinned) }),
+		"isFavourite": gqlField(boolean, func(n model.Note) any { return isSet(n.IsFavourite) }),
+		"createdAt":   gqlField(graphql.String, func(n model.Note) any { return n.CreatedAt }),
+		"updatedAt":   gqlField(graphql.String, func(n model.Note) any { return n.UpdatedAt }),
+		"journalDate": gqlField(graphql.String, func(n model.Note) any { return optional(n.JournalDate) }),
+		"notebook":    {Type: noteBook, Batch: srv.noteNoteBooks},
+		"tags":        {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tag))), Batch: srv.noteTags},
+		"reminders":   {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reminder))), Batch: srv.noteReminders},
+	}
+
+	noteBook.Fields = map[string]*graphql.Field{
+		"id":          gqlField(id, func(nb model.NoteBook) any { return nb.ID.Hex() }),
+		"name":        gqlField(str, func(nb model.NoteBook) any { return nb.Name }),
+		"description": gqlField(graphql.String, func(nb model.NoteBook) any { return nb.Description }),
+		"isActive":    gqlField(boolean, func(nb model.NoteBook) any { return isSet(nb.IsActive) }),
+		"notes": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(note))),
+			Args: notesArgs,
+			Batch: func(p graphql.BatchParams) ([]any, error) {
+				return srv.groupNotes(p, func(n model.Note, source any) bool {
+					return n.NoteBookID == source.(model.NoteBook).ID
+				})
+			},
+		},
+	}
+
+	tag.Fields = map[string]*graphql.Field{
+		"id":    gqlField(id, func(t model.Tag) any { return t.ID.Hex() }),
+		"name":  gqlField(str, func(t model.Tag) any { return t.Name }),
+		"color": gqlField(graphql.String, func(t model.Tag) any { return t.Color }),
+		"notes": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(note))),
+			Args: notesArgs,
+			Batch: func(p graphql.BatchParams) ([]any, error) {
+				return srv.groupNotes(p, func(n model.Note, source any) bool {
+					return slices.Contains(n.Tags, source.(model.Tag).ID)
+				})
+			},
+		},
+	}
+
+	reminder.Fields = map[string]*graphql.Field{
+		"id":       gqlField(id, func(rm model.Reminder) any { return rm.ID.Hex() }),
+		"name":     gqlField(str, func(rm model.Reminder) any { return rm.Name }),
+		"message":  gqlField(graphql.String, func(rm model.Reminder) any { return rm.Message }),
+		"remindAt": gqlField(str, func(rm model.Reminder) any { return rm.RemindAt.UTC().Format(time.RFC3339) }),
+		"isActive": gqlField(boolean, func(rm model.Reminder) any { return isSet(rm.IsActive) }),
+		"repeat":   gqlField(reminderRepeat, func(rm model.Reminder) any { return optional(strings.ToUpper(rm.Repeat)) }),
+		"note":     {Type: note, Batch: srv.reminderNotes},
+	}
+
+	noteInput := &graphql.InputObject{Name: "NoteInput", Fields: map[string]*graphql.Arg{
+		"name":        {Type: str},
+		"text":        {Type: graphql.String},
+		"color":       {Type: graphql.String},
+		"notebookId":  {Type: graphql.ID},
+		"tagIds":      {Type: graphql.NewList(id)},
+		"isPinned":    {Type: graphql.Boolean},
+		"isFavourite": {Type: graphql.Boolean},
+	}}
+
+	notePatchInput := &graphql.InputObject{
+		Name:        "NotePatchInput",
+		Description: "Непереданное поле не меняется, null очищает text, color, notebookId и tagIds",
+		Fields: map[string]*graphql.Arg{
+			"name":        {Type: graphql.String},
+			"text":        {Type: graphql.String},
+			"color":       {Type: graphql.String},
+			"order":       {Type: graphql.Int},
+			"notebookId":  {Type: graphql.ID},
+			"tagIds":      {Type: graphql.NewList(id)},
+			"isDeleted":   {Type: graphql.Boolean},
+			"isArchived":  {Type: graphql.Boolean},
+			"isPinned":    {Type: graphql.Boolean},
+			"isFavourite": {Type: graphql.Boolean},
+		},
+	}
+
+	noteBookFields := func(name graphql.Type) map[string]*graphql.Arg {
+		return map[string]*graphql.Arg{
+			"name":        {Type: name},
+			"description": {Type: graphql.String},
+			"isActive":    {Type: graphql.Boolean},
+		}
+	}
+
+	tagFields := func(name graphql.Type) map[string]*graphql.Arg {
+		return map[string]*graphql.Arg{
+			"name":  {Type: name},
+			"color": {Type: graphql.String},
+		}
+	}
+
+	reminderInput := &graphql.InputObject{Name: "ReminderInput", Fields: map[string]*graphql.Arg{
+		"noteId":   {Type: id},
+		"name":     {Type: str},
+		"message":  {Type: graphql.String},
+		"remindAt": {Type: str, Description: "Время в RFC 3339"},
+		"repeat":   {Type: reminderRepeat},
+		"isActive": {Type: graphql.Boolean},
+	}}
+
+	reminderPatchInput := &graphql.InputObject{Name: "ReminderPatchInput", Fields: map[string]*graphql.Arg{
+		"name":     {Type: graphql.String},
+		"message":  {Type: graphql.String},
+		"remindAt": {Type: graphql.String},
+		"repeat":   {Type: reminderRepeat},
+		"isActive": {Type: graphql.Boolean},
+	}}
+
+	byID := map[string]*graphql.Arg{"id": {Type: id}}
+
+	query := &graphql.Object{Name: "Query", Fields: map[string]*graphql.Field{
+		"me": {Type: graphql.NewNonNull(user), Resolve: srv.resolveMe},
+		"notes": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(note))),
+			Args: map[string]*graphql.Arg{
+				"status":             {Type: noteStatus, Default: "ACTIVE"},
+				"notebookId":         {Type: graphql.ID},
+				"tagIds":             {Type: graphql.NewList(id), Description: "Заметки со всеми тегами из списка"},
+				"tagQuery":           {Type: graphql.String, Description: "Выражение по именам тегов, как query в POST /notes/tag"},
+				"includeDescendants": {Type: graphql.Boolean, Default: false},
+			},
+			Resolve: srv.resolveNotes,
+		},
+		"note":      {Type: note, Args: byID, Resolve: srv.resolveNote},
+		"notebooks": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(noteBook))), Resolve: srv.resolveNoteBooks},
+		"notebook":  {Type: noteBook, Args: byID, Resolve: srv.resolveNoteBook},
+		"tags":      {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tag))), Resolve: srv.resolveTags},
+		"tag":       {Type: tag, Args: byID, Resolve: srv.resolveTag},
+		"reminders": {
+			Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reminder))),
+			Args:    map[string]*graphql.Arg{"active": {Type: graphql.Boolean}},
+			Resolve: srv.resolveReminders,
+		},
+		"reminder": {Type: reminder, Args: byID, Resolve: srv.resolveReminder},
+	}}
+
+	mutation := &graphql.Object{Name: "Mutation", Fields: map[string]*graphql.Field{
+		"createNote": {
+			Type:    graphql.NewNonNull(note),
+			Args:    map[string]*graphql.Arg{"input": {Type: graphql.NewNonNull(noteInput)}},
+			Resolve: srv.createNote,
+		},
+		"updateNote": {
+			Type:    graphql.NewNonNull(note),
+			Args:    map[string]*graphql.Arg{"id": {Type: id}, "input": {Type: graphql.NewNonNull(notePatchInput)}},
+			Resolve: srv.updateNote,
+		},
+		"deleteNote": {Type: id, Args: byID, Resolve: srv.deleteNote},
+		"createNotebook": {
+			Type:    graphql.NewNonNull(noteBook),
+			Args:    map[string]*graphql.Arg{"input": {Type: graphql.NewNonNull(&graphql.InputObject{Name: "NoteBookInput", Fields: noteBookFields(str)})}},
+			Resolve: srv.createNoteBook,
+		},
+		"updateNotebook": {
+			Type: graphql.NewNonNull(noteBook),
+			Args: map[string]*graphql.Arg{
+				"id":    {Type: id},
+				"input": {Type: graphql.NewNonNull(&graphql.InputObject{Name: "NoteBookPatchInput", Fields: noteBookFields(graphql.String)})},
+			},
+			Resolve: srv.updateNoteBook,
+		},
+		"deleteNotebook": {
+			Type: id,
+			Args: map[string]*graphql.Arg{
+				"id":       {Type: id},
+				"mode":     {Type: deleteMode, Default: "UNLINK", Description: "Что сделать с заметками блокнота, как mode в DELETE /notebooks/{id}"},
+				"targetId": {Type: graphql.ID},
+			},
+			Resolve: srv.deleteNoteBook,
+		},
+		"createTag": {
+			Type:    graphql.NewNonNull(tag),
+			Args:    map[string]*graphql.Arg{"input": {Type: graphql.NewNonNull(&graphql.InputObject{Name: "TagInput", Fields: tagFields(str)})}},
+			Resolve: srv.createTag,
+		},
+		"updateTag": {
+			Type: graphql.NewNonNull(tag),
+			Args: map[string]*graphql.Arg{
+				"id":    {Type: id},
+				"input": {Type: graphql.NewNonNull(&graphql.InputObject{Name: "TagPatchInput", Fields: tagFields(graphql.String)})},
+			},
+			Resolve: srv.updateTag,
+		},
+		"deleteTag": {Type: id, Args: byID, Resolve: srv.deleteTag},
+		"createReminder": {
+			Type:    graphql.NewNonNull(reminder),
+			Args:    map[string]*graphql.Arg{"input": {Type: graphql.NewNonNull(reminderInput)}},
+			Resolve: srv.createReminder,
+		},
+		"updateReminder": {
+			Type:    graphql.NewNonNull(reminder),
+			Args:    map[string]*graphql.Arg{"id": {Type: id}, "input": {Type: graphql.NewNonNull(reminderPatchInput)}},
+			Resolve: srv.updateReminder,
+		},
+		"deleteReminder": {Type: id, Args: byID, Resolve: srv.deleteReminder},
+	}}
+
+	return &graphql.Schema{Query: query, Mutation: mutation, FormatError: graphQLError}
+}
+
+// gqlField - поле, которое просто читает значение из родительского объекта
+func gqlField[T any](t graphql.Type, get func(T) any) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: func(p graphql.Params) (any, error) {
+		return get(p.Source.(T)), nil
+	}}
+}
+
+func isSet(b *bool) bool {
+	return b != nil && *b
+}
+
+func optional(s string) any {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+func gqlUserID(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey).(string)
+	return userID
+}
+
+// decodeInput переносит входной объект мутации в DTO и проверяет его правилами из тегов validate
+func decodeInput(input graphql.Args, v any, check func(any) error) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return model.Validation("Wrong input")
+	}
+
+	return check(v)
+}
+
+// visibleNotes - заметки пользователя и общие заметки с нужным статусом
+func (srv GraphQLService) visibleNotes(ctx context.Context, status string) ([]model.Note, error) {
+	var notes []model.Note
+
+	err := srv.Notes.DBClient.StreamNotes(ctx, gqlUserID(ctx), func(note model.Note) error {
+		if matchStatus(note, status) {
+			notes = append(notes, note)
+		}
+		return nil
+	})
+
+	return notes, err
+}
+
+func matchStatus(note model.Note, status string) bool {
+	switch strings.ToLower(status) {
+	case repository.NoteStatusArchived:
+		return isSet(note.IsArchived)
+	case repository.NoteStatusTrashed:
+		return isSet(note.IsDeleted)
+	case repository.NoteStatusAll:
+		return true
+	default:
+		return !isSet(note.IsArchived) && !isSet(note.IsDeleted)
+	}
+}
+
+// groupNotes раскладывает видимые заметки по родителям одним чтением из базы
+func (srv GraphQLService) groupNotes(p graphql.BatchParams, belongs func(model.Note, any) bool) ([]any, error) {
+	notes, err := srv.visibleNotes(p.Context, p.Args.String("status"))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]any, len(p.Sources))
+	for i, source := range p.Sources {
+		group := []model.Note{}
+		for _, note := range notes {
+			if belongs(note, source) {
+				group = append(group, note)
+			}
+		}
+		out[i] = group
+	}
+
+	return out, nil
+}
+
+// noteNoteBooks читает все блокноты одним запросом: блокноты общие, и их немного
+func (srv GraphQLService) noteNoteBooks(p graphql.BatchParams) ([]any, error) {
+	out := make([]any, len(p.Sources))
+
+	if !slices.ContainsFunc(p.Sources, func(source any) bool { return !source.(model.Note).NoteBookID.IsZero() }) {
+		return out, nil
+	}
+
+	noteBooks, err := srv.NoteBooks.DBClient.GetNoteBooks(p.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]model.NoteBook, len(noteBooks))
+	for _, noteBook := range noteBooks {
+		byID[noteBook.ID] = noteBook
+	}
+
+	for i, source := range p.Sources {
+		if noteBook, ok := byID[source.(model.Note).NoteBookID]; ok {
+			out[i] = noteBook
+		}
+	}
+
+	return out, nil
+}
+
+func (srv GraphQLService) noteTags(p graphql.BatchParams) ([]any, error) {
+	var ids []string
+	for _, source := range p.Sources {
+		for _, tagID := range source.(model.Note).Tags {
+			if !slices.Contains(ids, tagID.Hex()) {
+				ids = append(ids, tagID.Hex())
+			}
+		}
+	}
+
+	byID := map[primitive.ObjectID]model.Tag{}
+	if len(ids) > 0 {
+		tags, err := srv.Tags.DBClient.GetTagsByIDs(p.Context, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			byID[tag.ID] = tag
+		}
+	}
+
+	out := make([]any, len(p.Sources))
+	for i, source := range p.Sources {
+		tags := []model.Tag{}
+		for _, tagID := range source.(model.Note).Tags {
+			if tag, ok := byID[tagID]; ok {
+				tags = append(tags, tag)
+			}
+		}
+		out[i] = tags
+	}
+
+	return out, nil
+}
+
+func (srv GraphQLService) noteReminders(p graphql.BatchParams) ([]any, error) {
+	ids := make([]string, 0, len(p.Sources))
+	for _, source := range p.Sources {
+		ids = append(ids, source.(model.Note).ID.Hex())
+	}
+
+	reminders, err := srv.Reminders.GetRemindersByNotes(p.Context, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]any, len(p.Sources))
+	for i, source := range p.Sources {
+		group := []model.Reminder{}
+		for _, reminder := range reminders {
+			if reminder.NoteID == source.(model.Note).ID {
+				group = append(group, reminder)
+			}
+		}
+		out[i] = group
+	}
+
+	return out, nil
+}
+
+func (srv GraphQLService) reminderNotes(p graphql.BatchParams) ([]any, error) {
+	notes, err := srv.visibleNotes(p.Context, repository.NoteStatusAll)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]any, len(p.Sources))
+	for i, source := range p.Sources {
+		idx := slices.IndexFunc(notes, func(note model.Note) bool { return note.ID == source.(model.Reminder).NoteID })
+		if idx >= 0 {
+			out[i] = notes[idx]
+		}
+	}
+
+	return out, nil
+}
+
+func (srv GraphQLService) resolveMe(p graphql.Params) (any, error) {
+	return srv.Notes.HelperUserClient.GetProfile(p.Context, gqlUserID(p.Context))
+}
+
+func (srv GraphQLService) resolveNotes(p graphql.Params) (any, error) {
+	tags := dto.NoteTagsRequest{
+		TagIDs:             p.Args.Strings("tagIds"),
+		Query:              p.Args.String("tagQuery"),
+		IncludeDescendants: isSet(p.Args.Bool("includeDescendants")),
+	}
+
+	return srv.Notes.findNotes(p.Context, gqlUserID(p.Context), tags, repository.NoteFilter{
+		NoteBookID: p.Args.String("notebookId"),
+		Status:     strings.ToLower(p.Args.String("status")),
+	})
+}
+
+func (srv GraphQLService) resolveNote(p graphql.Params) (any, error) {
+	return srv.Notes.findOwnNote(p.Context, p.Args.String("id"), gqlUserID(p.Context))
+}
+
+func (srv GraphQLService) resolveNoteBooks(p graphql.Params) (any, error) {
+	return srv.NoteBooks.DBClient.GetNoteBooks(p.Context)
+}
+
+func (srv GraphQLService) resolveNoteBook(p graphql.Params) (any, error) {
+	return srv.NoteBooks.DBClient.GetNoteBookByID(p.Context, p.Args.String("id"))
+}
+
+func (srv GraphQLService) resolveTags(p graphql.Params) (any, error) {
+	return srv.Tags.DBClient.GetTags(p.Context)
+}
+
+func (srv GraphQLService) resolveTag(p graphql.Params) (any, error) {
+	return srv.Tags.DBClient.GetTagByID(p.Context, p.Args.String("id"))
+}
+
+func (srv GraphQLService) resolveReminders(p graphql.Params) (any, error) {
+	notes, err := srv.visibleNotes(p.Context, repository.NoteStatusAll)
+	if err != nil || len(notes) == 0 {
+		return []model.Reminder{}, err
+	}
+
+	ids := make([]string, 0, len(notes))
+	for _, note := range notes {
+		ids = append(ids, note.ID.Hex())
+	}
+
+	reminders, err := srv.Reminders.GetRemindersByNotes(p.Context, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	active := p.Args.Bool("active")
+
+	filtered := []model.Reminder{}
+	for _, reminder := range reminders {
+		if active == nil || *active == isSet(reminder.IsActive) {
+			filtered = append(filtered, reminder)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (srv GraphQLService) resolveReminder(p graphql.Params) (any, error) {
+	return srv.findOwnReminder(p.Context, p.Args.String("id"))
+}
+
+// findOwnReminder находит напоминание к заметке, доступной пользователю
+func (srv GraphQLService) findOwnReminder(ctx context.Context, id string) (model.Reminder, error) {
+	reminder, err := srv.Reminders.GetReminderByID(ctx, id)
+	if err != nil {
+		return model.Reminder{}, err
+	}
+
+	_, err = srv.Notes.findOwnNote(ctx, reminder.NoteID.Hex(), gqlUserID(ctx))
+	if errors.Is(err, model.ErrNotFound) {
+		return model.Reminder{}, errReminderNotFound
+	} else if err != nil {
+		return model.Reminder{}, err
+	}
+
+	return reminder, nil
+}
+
+func (srv GraphQLService) createNote(p graphql.Params) (any, error) {
+	var input dto.GraphQLNoteInput
+
+	err := decodeInput(p.Args.Object("input"), &input, validate.Struct)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := srv.Notes.createOwnNote(p.Context, gqlUserID(p.Context), model.Note{
+		Name:        input.Name,
+		Text:        input.Text,
+		Color:       input.Color,
+		IsPinned:    input.IsPinned,
+		IsFavourite: input.IsFavourite,
+	}, input.NoteBookID, input.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Created note", slog.String("_id", note.ID.Hex()))
+	return note, nil
+}
+
+// updateNote меняет заметку по правилам PATCH /notes/{id}: null в input очищает поле
+func (srv GraphQLService) updateNote(p graphql.Params) (any, error) {
+	var input dto.GraphQLNotePatchInput
+
+	id := p.Args.String("id")
+
+	note, err := srv.Notes.findOwnNote(p.Context, id, gqlUserID(p.Context))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := p.Args.Object("input")
+
+	err = decodeInput(fields, &input, validate.Partial)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields.Has("name") && (input.Name == nil || strings.TrimSpace(*input.Name) == "") {
+		return nil, validate.Errors{{Field: "name", Rule: "required", Message: "cannot be cleared"}}
+	}
+
+	empty := ""
+	patch := repository.NotePatch{
+		Name:       input.Name,
+		Text:       input.Text,
+		Color:      input.Color,
+		Order:      input.Order,
+		NoteBookID: input.NoteBookID,
+		UpdatedAt:  timezone.Now().String(),
+	}
+	if fields.Has("text") && input.Text == nil {
+		patch.Text = &empty
+	}
+	if fields.Has("color") && input.Color == nil {
+		patch.Color = &empty
+	}
+	if fields.Has("order") && input.Order == nil {
+		patch.Order = new(int)
+	}
+	if fields.Has("notebookId") && input.NoteBookID == nil {
+		patch.NoteBookID = &empty
+	}
+	if fields.Has("tagIds") {
+		tags := uniqueIDs(input.TagIDs)
+		patch.Tags = &tags
+	}
+
+	err = srv.Notes.checkPatchRefs(p.Context, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	err = srv.Notes.updateNote(p.Context, note, patch, noteFlags{
+		IsDeleted:   input.IsDeleted,
+		IsArchived:  input.IsArchived,
+		IsPinned:    input.IsPinned,
+		IsFavourite: input.IsFavourite,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Note updated", slog.String("_id", id))
+	return srv.Notes.DBClient.GetNoteByID(p.Context, id)
+}
+
+func (srv GraphQLService) deleteNote(p graphql.Params) (any, error) {
+	id := p.Args.String("id")
+
+	err := srv.Notes.deleteOwnNote(p.Context, id, gqlUserID(p.Context))
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Note deleted", slog.String("_id", id))
+	return id, nil
+}
+
+func (srv GraphQLService) createNoteBook(p graphql.Params) (any, error) {
+	var input dto.GraphQLNoteBookInput
+
+	err := decodeInput(p.Args.Object("input"), &input, validate.Struct)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := srv.NoteBooks.createNoteBook(p.Context, dto.NoteBookRequest(input))
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Created notebook", slog.String("_id", res))
+	return srv.NoteBooks.DBClient.GetNoteBookByID(p.Context, res)
+}
+
+func (srv GraphQLService) updateNoteBook(p graphql.Params) (any, error) {
+	var input dto.GraphQLNoteBookInput
+
+	id := p.Args.String("id")
+
+	err := decodeInput(p.Args.Object("input"), &input, validate.Partial)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = srv.NoteBooks.DBClient.GetNoteBookByID(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = srv.NoteBooks.updateNoteBook(p.Context, id, dto.NoteBookRequest(input))
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Notebook updated", slog.String("_id", id))
+	return srv.NoteBooks.DBClient.GetNoteBookByID(p.Context, id)
+}
+
+func (srv GraphQLService) deleteNoteBook(p graphql.Params) (any, error) {
+	id := p.Args.String("id")
+	mode := strings.ToLower(p.Args.String("mode"))
+
+	_, err := srv.NoteBooks.deleteNoteBook(p.Context, id, mode, p.Args.String("targetId"))
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Notebook deleted", slog.String("mode", mode))
+	return id, nil
+}
+
+func (srv GraphQLService) createTag(p graphql.Params) (any, error) {
+	var input dto.TagRequest
+
+	err := decodeInput(p.Args.Object("input"), &input, validate.Struct)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := srv.Tags.createTag(p.Context, input)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Created tag", slog.String("_id", res))
+	return srv.Tags.DBClient.GetTagByID(p.Context, res)
+}
+
+func (srv GraphQLService) updateTag(p graphql.Params) (any, error) {
+	var input dto.TagRequest
+
+	id := p.Args.String("id")
+
+	err := decodeInput(p.Args.Object("input"), &input, validate.Partial)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = srv.Tags.updateTag(p.Context, id, input)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Tag updated", slog.String("_id", id))
+	return srv.Tags.DBClient.GetTagByID(p.Context, id)
+}
+
+func (srv GraphQLService) deleteTag(p graphql.Params) (any, error) {
+	id := p.Args.String("id")
+
+	_, err := srv.Tags.deleteTag(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Tag deleted", slog.String("_id", id))
+	return id, nil
+}
+
+func (srv GraphQLService) createReminder(p graphql.Params) (any, error) {
+	var input dto.GraphQLReminderInput
+
+	err := decodeInput(p.Args.Object("input"), &input, validate.Struct)
+	if err != nil {
+		return nil, err
+	}
+
+	remindAt, err := parseRemindAt(input.RemindAt)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := srv.Notes.findOwnNote(p.Context, input.NoteID, gqlUserID(p.Context))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := srv.Reminders.CreateReminder(p.Context, model.Reminder{
+		Name:     input.Name,
+		Message:  input.Message,
+		RemindAt: remindAt,
+		IsActive: input.IsActive,
+		Repeat:   strings.ToLower(input.Repeat),
+		NoteID:   note.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Created reminder", slog.String("_id", res))
+	return srv.Reminders.GetReminderByID(p.Context, res)
+}
+
+func (srv GraphQLService) updateReminder(p graphql.Params) (any, error) {
+	var input dto.GraphQLReminderInput
+
+	id := p.Args.String("id")
+
+	_, err := srv.findOwnReminder(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = decodeInput(p.Args.Object("input"), &input, validate.Partial)
+	if err != nil {
+		return nil, err
+	}
+
+	var remindAt time.Time
+	if input.RemindAt != "" {
+		remindAt, err = parseRemindAt(input.RemindAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = srv.Reminders.UpdateReminder(p.Context, id, input.Name, input.Message, remindAt, input.IsActive, strings.ToLower(input.Repeat))
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Reminder updated", slog.String("_id", id))
+	return srv.Reminders.GetReminderByID(p.Context, id)
+}
+
+func (srv GraphQLService) deleteReminder(p graphql.Params) (any, error) {
+	id := p.Args.String("id")
+
+	_, err := srv.findOwnReminder(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = srv.Reminders.DeleteReminder(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Reminder deleted", slog.String("_id", id))
+	return id, nil
+}
+
+func parseRemindAt(value string) (time.Time, error) {
+	remindAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, validate.Errors{{Field: "remindAt", Rule: "datetime", Message: "must be an RFC 3339 date-time"}}
+	}
+
+	return remindAt, nil
+}
@@ -0,0 +1,501 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/LoL-KeKovich/NoteVault/api/notevaultpb"
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/timezone"
+	"github.com/LoL-KeKovich/NoteVault/lib/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GRPCService реализует сервис notevault.v1.NoteVault из api/notevault.proto поверх тех же сервисов, что и HTTP API
+type GRPCService struct {
+	notevaultpb.UnimplementedNoteVaultServer
+
+	Notes     NoteService
+	NoteBooks NoteBookService
+	Tags      TagService
+	Users     UserService
+}
+
+var noteStatuses = map[notevaultpb.NoteStatus]string{
+	notevaultpb.NoteStatus_NOTE_STATUS_UNSPECIFIED: repository.NoteStatusActive,
+	notevaultpb.NoteStatus_NOTE_STATUS_ACTIVE:      repository.NoteStatusActive,
+	notevaultpb.NoteStatus_NOTE_STATUS_ARCHIVED:    repository.NoteStatusArchived,
+	notevaultpb.NoteStatus_NOTE_STATUS_TRASHED:     repository.NoteStatusTrashed,
+	notevaultpb.NoteStatus_NOTE_STATUS_ALL:         repository.NoteStatusAll,
+}
+
+var deleteModes = map[notevaultpb.DeleteMode]string{
+	notevaultpb.DeleteMode_DELETE_MODE_UNSPECIFIED: dto.DeleteModeUnlink,
+	notevaultpb.DeleteMode_DELETE_MODE_UNLINK:      dto.DeleteModeUnlink,
+	notevaultpb.DeleteMode_DELETE_MODE_MOVE:        dto.DeleteModeMove,
+	notevaultpb.DeleteMode_DELETE_MODE_TRASH:       dto.DeleteModeTrash,
+	notevaultpb.DeleteMode_DELETE_MODE_RESTRICT:    dto.DeleteModeRestrict,
+}
+
+// UnaryInterceptor кладет user_id из токена в контекст, как AuthMiddleware, и переводит ошибки в статусы gRPC.
+// Login вызывается без токена
+func (srv GRPCService) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if info.FullMethod != notevaultpb.NoteVault_Login_FullMethodName {
+		userID, err := userIDFromMetadata(ctx)
+		if err != nil {
+			slog.Error("Unauthorized request", "error", err.Error())
+			return nil, grpcError(model.Unauthorized("Authorization required"))
+		}
+		ctx = context.WithValue(ctx, userIDKey, userID)
+	}
+
+	resp, err := handler(ctx, req)
+	return resp, grpcError(err)
+}
+
+// StreamInterceptor - UnaryInterceptor для потоковых методов
+func (srv GRPCService) StreamInterceptor(server any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	userID, err := userIDFromMetadata(stream.Context())
+	if err != nil {
+		slog.Error("Unauthorized request", "error", err.Error())
+		return grpcError(model.Unauthorized("Authorization required"))
+	}
+
+	ctx := context.WithValue(stream.Context(), userIDKey, userID)
+	return grpcError(handler(server, authStream{ServerStream: stream, ctx: ctx}))
+}
+
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s authStream) Context() context.Context {
+	return s.ctx
+}
+
+func userIDFromMetadata(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", errors.New("metadata 'authorization' not found")
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return "", errors.New("authorization is not a bearer token")
+	}
+
+	return parseToken(token)
+}
+
+// grpcError переводит ошибку в статус gRPC по тем же правилам, что respondError: код ошибки из ErrorResponse
+// уходит в google.rpc.ErrorInfo, ошибки полей - в google.rpc.BadRequest, остальные ошибки считаются внутренними
+func grpcError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	slog.Error(err.Error())
+
+	var fieldErrs validate.Errors
+	if errors.As(err, &fieldErrs) {
+		badRequest := &errdetails.BadRequest{}
+		for _, fe := range fieldErrs {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fe.Field,
+				Description: fe.Message,
+			})
+		}
+		return rpcStatus(codes.InvalidArgument, dto.ErrorCodeInvalidFields, "Validation failed", badRequest)
+	}
+
+	for _, s := range errorStatuses {
+		if errors.Is(err, s.kind) {
+			message := s.kind.Error()
+			var modelErr *model.Error
+			if errors.As(err, &modelErr) {
+				message = modelErr.Message
+			}
+			return rpcStatus(s.rpc, s.code, message)
+		}
+	}
+
+	return rpcStatus(codes.Internal, dto.ErrorCodeInternal, "Internal error")
+}
+
+func rpcStatus(c codes.Code, code, message string, details ...protoadapt.MessageV1) error {
+	details = append([]protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: code, Domain: "notevault"}}, details...)
+
+	st, err := status.New(c, message).WithDetails(details...)
+	if err != nil {
+		return status.Error(c, message)
+	}
+
+	return st.Err()
+}
+
+func (srv GRPCService) Login(ctx context.Context, req *notevaultpb.LoginRequest) (*notevaultpb.LoginResponse, error) {
+	user, token, err := srv.Users.login(ctx, req.GetEmail(), req.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("User logged in", slog.String("email", user.Email))
+	return &notevaultpb.LoginResponse{Token: token, UserId: user.ID.Hex()}, nil
+}
+
+func (srv GRPCService) GetNote(ctx context.Context, req *notevaultpb.GetNoteRequest) (*notevaultpb.Note, error) {
+	userID, _ := ctx.Value(userIDKey).(string)
+
+	note, err := srv.Notes.findOwnNote(ctx, req.GetId(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return noteMessage(note), nil
+}
+
+// ListNotes без фильтров по блокноту и тегам читает заметки из базы потоком и не держит весь список в памяти
+func (srv GRPCService) ListNotes(req *notevaultpb.ListNotesRequest, stream notevaultpb.NoteVault_ListNotesServer) error {
+	ctx := stream.Context()
+	userID, _ := ctx.Value(userIDKey).(string)
+	noteStatus := noteStatuses[req.GetStatus()]
+
+	if req.GetNotebookId() == "" && len(req.GetTagIds()) == 0 && req.GetTagQuery() == "" {
+		return srv.Notes.DBClient.StreamNotes(ctx, userID, func(note model.Note) error {
+			if !matchStatus(note, noteStatus) {
+				return nil
+			}
+			return stream.Send(noteMessage(note))
+		})
+	}
+
+	tags := dto.NoteTagsRequest{
+		TagIDs:             req.GetTagIds(),
+		Query:              req.GetTagQuery(),
+		IncludeDescendants: req.GetIncludeDescendants(),
+	}
+
+	notes, err := srv.Notes.findNotes(ctx, userID, tags, repository.NoteFilter{NoteBookID: req.GetNotebookId(), Status: noteStatus})
+	if err != nil {
+		return err
+	}
+
+	for _, note := range notes {
+		err = stream.Send(noteMessage(note))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (srv GRPCService) CreateNote(ctx context.Context, req *notevaultpb.CreateNoteRequest) (*notevaultpb.Note, error) {
+	input := dto.GRPCNoteInput{
+		Name:       req.GetName(),
+		Text:       req.GetText(),
+		Color:      req.GetColor(),
+		NoteBookID: req.GetNotebookId(),
+		TagIDs:     req.GetTagIds(),
+	}
+
+	err := validate.Struct(input)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, _ := ctx.Value(userIDKey).(string)
+
+	note, err := srv.Notes.createOwnNote(ctx, userID, model.Note{
+		Name:        input.Name,
+		Text:        input.Text,
+		Color:       input.Color,
+		IsPinned:    req.IsPinned,
+		IsFavourite: req.IsFavourite,
+	}, input.NoteBookID, input.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Created note", slog.String("_id", note.ID.Hex()))
+	return noteMessage(note), nil
+}
+
+func (srv GRPCService) UpdateNote(ctx context.Context, req *notevaultpb.UpdateNoteRequest) (*notevaultpb.Note, error) {
+	userID, _ := ctx.Value(userIDKey).(string)
+
+	note, err := srv.Notes.findOwnNote(ctx, req.GetId(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	input := dto.GRPCNotePatchInput{Name: req.Name, Text: req.Text, Color: req.Color, TagIDs: req.GetTagIds().GetIds()}
+
+	err = validate.Partial(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		return nil, validate.Errors{{Field: "name", Rule: "required", Message: "cannot be cleared"}}
+	}
+
+	patch := repository.NotePatch{
+		Name:       req.Name,
+		Text:       req.Text,
+		Color:      req.Color,
+		NoteBookID: req.NotebookId,
+		UpdatedAt:  timezone.Now().String(),
+	}
+	if req.Order != nil {
+		order := int(req.GetOrder())
+		patch.Order = &order
+	}
+	if req.TagIds != nil {
+		tags := uniqueIDs(input.TagIDs)
+		patch.Tags = &tags
+	}
+
+	err = srv.Notes.checkPatchRefs(ctx, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	err = srv.Notes.updateNote(ctx, note, patch, noteFlags{
+		IsDeleted:   req.IsDeleted,
+		IsArchived:  req.IsArchived,
+		IsPinned:    req.IsPinned,
+		IsFavourite: req.IsFavourite,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	note, err = srv.Notes.DBClient.GetNoteByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Note updated", slog.String("_id", req.GetId()))
+	return noteMessage(note), nil
+}
+
+func (srv GRPCService) DeleteNote(ctx context.Context, req *notevaultpb.DeleteNoteRequest) (*emptypb.Empty, error) {
+	userID, _ := ctx.Value(userIDKey).(string)
+
+	err := srv.Notes.deleteOwnNote(ctx, req.GetId(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Note deleted", slog.String("_id", req.GetId()))
+	return &emptypb.Empty{}, nil
+}
+
+func (srv GRPCService) GetNoteBook(ctx context.Context, req *notevaultpb.GetNoteBookRequest) (*notevaultpb.NoteBook, error) {
+	noteBook, err := srv.NoteBooks.DBClient.GetNoteBookByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return noteBookMessage(noteBook), nil
+}
+
+func (srv GRPCService) ListNoteBooks(req *notevaultpb.ListNoteBooksRequest, stream notevaultpb.NoteVault_ListNoteBooksServer) error {
+	noteBooks, err := srv.NoteBooks.DBClient.GetNoteBooks(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	for _, noteBook := range noteBooks {
+		err = stream.Send(noteBookMessage(noteBook))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (srv GRPCService) CreateNoteBook(ctx context.Context, req *notevaultpb.CreateNoteBookRequest) (*notevaultpb.NoteBook, error) {
+	input := dto.NoteBookRequest{Name: req.GetName(), Description: req.GetDescription(), IsActive: req.IsActive}
+
+	err := validate.Struct(input)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := srv.NoteBooks.createNoteBook(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Created notebook", slog.String("_id", res))
+	return srv.GetNoteBook(ctx, &notevaultpb.GetNoteBookRequest{Id: res})
+}
+
+func (srv GRPCService) UpdateNoteBook(ctx context.Context, req *notevaultpb.UpdateNoteBookRequest) (*notevaultpb.NoteBook, error) {
+	input := dto.NoteBookRequest{Name: req.GetName(), Description: req.GetDescription(), IsActive: req.IsActive}
+
+	err := validate.Partial(input)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = srv.NoteBooks.DBClient.GetNoteBookByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = srv.NoteBooks.updateNoteBook(ctx, req.GetId(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Notebook updated", slog.String("_id", req.GetId()))
+	return srv.GetNoteBook(ctx, &notevaultpb.GetNoteBookRequest{Id: req.GetId()})
+}
+
+func (srv GRPCService) DeleteNoteBook(ctx context.Context, req *notevaultpb.DeleteNoteBookRequest) (*emptypb.Empty, error) {
+	mode, ok := deleteModes[req.GetMode()]
+	if !ok {
+		return nil, model.Validation("Wrong delete mode")
+	}
+
+	_, err := srv.NoteBooks.deleteNoteBook(ctx, req.GetId(), mode, req.GetTargetId())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Notebook deleted", slog.String("mode", mode))
+	return &emptypb.Empty{}, nil
+}
+
+func (srv GRPCService) GetTag(ctx context.Context, req *notevaultpb.GetTagRequest) (*notevaultpb.Tag, error) {
+	tag, err := srv.Tags.DBClient.GetTagByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return tagMessage(tag), nil
+}
+
+func (srv GRPCService) ListTags(req *notevaultpb.ListTagsRequest, stream notevaultpb.NoteVault_ListTagsServer) error {
+	tags, err := srv.Tags.DBClient.GetTags(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		err = stream.Send(tagMessage(tag))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (srv GRPCService) CreateTag(ctx context.Context, req *notevaultpb.CreateTagRequest) (*notevaultpb.Tag, error) {
+	input := dto.TagRequest{Name: req.GetName(), Color: req.GetColor()}
+
+	err := validate.Struct(input)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := srv.Tags.createTag(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Created tag", slog.String("_id", res))
+	return srv.GetTag(ctx, &notevaultpb.GetTagRequest{Id: res})
+}
+
+func (srv GRPCService) UpdateTag(ctx context.Context, req *notevaultpb.UpdateTagRequest) (*notevaultpb.Tag, error) {
+	input := dto.TagRequest{Name: req.GetName(), Color: req.GetColor()}
+
+	err := validate.Partial(input)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = srv.Tags.updateTag(ctx, req.GetId(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Tag updated", slog.String("_id", req.GetId()))
+	return srv.GetTag(ctx, &notevaultpb.GetTagRequest{Id: req.GetId()})
+}
+
+func (srv GRPCService) DeleteTag(ctx context.Context, req *notevaultpb.DeleteTagRequest) (*emptypb.Empty, error) {
+	_, err := srv.Tags.deleteTag(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Tag deleted", slog.String("_id", req.GetId()))
+	return &emptypb.Empty{}, nil
+}
+
+func noteMessage(note model.Note) *notevaultpb.Note {
+	message := &notevaultpb.Note{
+		Id:          note.ID.Hex(),
+		Name:        note.Name,
+		Text:        note.Text,
+		Color:       note.Color,
+		Order:       int32(note.Order),
+		Rank:        note.Rank,
+		IsDeleted:   isSet(note.IsDeleted),
+		IsArchived:  isSet(note.IsArchived),
+		IsPinned:    isSet(note.IsPinned),
+		IsFavourite: isSet(note.IsFavourite),
+		UserId:      ownerOf(note),
+		CreatedAt:   note.CreatedAt,
+		UpdatedAt:   note.UpdatedAt,
+		JournalDate: note.JournalDate,
+	}
+
+	if !note.NoteBookID.IsZero() {
+		message.NotebookId = note.NoteBookID.Hex()
+	}
+	for _, tagID := range note.Tags {
+		message.TagIds = append(message.TagIds, tagID.Hex())
+	}
+
+	return message
+}
+
+func noteBookMessage(noteBook model.NoteBook) *notevaultpb.NoteBook {
+	return &notevaultpb.NoteBook{
+		Id:          noteBook.ID.Hex(),
+		Name:        noteBook.Name,
+		Description: noteBook.Description,
+		IsActive:    isSet(noteBook.IsActive),
+	}
+}
+
+func tagMessage(tag model.Tag) *notevaultpb.Tag {
+	return &notevaultpb.Tag{Id: tag.ID.Hex(), Name: tag.Name, Color: tag.Color}
+}
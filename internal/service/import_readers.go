@@ -0,0 +1,468 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const enexTimeLayout = "20060102T150405Z"
+
+// Ограничения распаковки: архив в 64 МБ может развернуться в гигабайты, поэтому файлы читаются
+// не больше maxImportEntrySize каждый и не больше maxImportUnpackedSize вместе
+const (
+	maxImportEntrySize    = 4 << 20
+	maxImportUnpackedSize = 256 << 20
+)
+
+var (
+	errImportEntryTooLarge   = fmt.Errorf("file is larger than %d MB unpacked", maxImportEntrySize>>20)
+	errImportArchiveTooLarge = fmt.Errorf("archive is larger than %d MB unpacked", maxImportUnpackedSize>>20)
+)
+
+// importArchive - zip-архив импорта с остатком допустимого распакованного объема
+type importArchive struct {
+	*zip.Reader
+	left int64
+}
+
+func newImportArchive(reader *zip.Reader) *importArchive {
+	return &importArchive{Reader: reader, left: maxImportUnpackedSize}
+}
+
+var blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+
+type importFrontMatter struct {
+	Title     string     `yaml:"title"`
+	NoteBook  string     `yaml:"notebook"`
+	Tags      importTags `yaml:"tags"`
+	Color     string     `yaml:"color"`
+	Pinned    bool       `yaml:"pinned"`
+	Favourite bool       `yaml:"favourite"`
+	Archived  bool       `yaml:"archived"`
+	Trashed   bool       `yaml:"trashed"`
+	CreatedAt string     `yaml:"created_at"`
+	UpdatedAt string     `yaml:"updated_at"`
+}
+
+// importTags принимает теги и списком, и строкой через запятую, как пишут другие редакторы
+type importTags []string
+
+func (t *importTags) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		for _, tag := range strings.Split(node.Value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				*t = append(*t, tag)
+			}
+		}
+		return nil
+	}
+
+	var tags []string
+	err := node.Decode(&tags)
+	if err != nil {
+		return err
+	}
+
+	*t = tags
+
+	return nil
+}
+
+// readMarkdownZip читает архив Markdown-файлов, в том числе созданный экспортом NoteVault:
+// цвета тегов и имена блокнотов берутся из tags.yaml и notebooks.yaml, если они есть
+func readMarkdownZip(archive *importArchive, tagColors map[string]string, add func(importedNote)) error {
+	folders := map[string]string{}
+
+	for _, file := range archive.File {
+		switch file.Name {
+		case "tags.yaml":
+			var tags []exportTag
+			err := archive.readYAML(file, &tags)
+			if err != nil {
+				return fmt.Errorf("tags.yaml: %w", err)
+			}
+			for _, tag := range tags {
+				tagColors[tag.Name] = tag.Color
+			}
+		case "notebooks.yaml":
+			var noteBooks []exportNoteBook
+			err := archive.readYAML(file, &noteBooks)
+			if err != nil {
+				return fmt.Errorf("notebooks.yaml: %w", err)
+			}
+			for _, noteBook := range noteBooks {
+				folders[noteBook.Folder] = noteBook.Name
+			}
+		}
+	}
+
+	for _, file := range archive.File {
+		if !isImportFile(file, ".md", ".markdown") {
+			continue
+		}
+
+		note := importedNote{File: file.Name}
+
+		data, err := archive.read(file)
+		if err == nil {
+			err = parseMarkdownNote(data, &note)
+		}
+		if err != nil {
+			note.Err = err
+			add(note)
+			continue
+		}
+
+		if note.Title == "" {
+			base := path.Base(file.Name)
+			note.Title = strings.TrimSuffix(base, path.Ext(base))
+		}
+
+		if note.NoteBook == "" {
+			if dir, _, ok := strings.Cut(file.Name, "/"); ok {
+				note.NoteBook = dir
+				if name, ok := folders[dir]; ok {
+					note.NoteBook = name
+				}
+			}
+		}
+
+		add(note)
+	}
+
+	return nil
+}
+
+func parseMarkdownNote(data []byte, note *importedNote) error {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	if strings.HasPrefix(text, "---\n") {
+		header, body, ok := strings.Cut(text[len("---\n"):], "\n---\n")
+		if !ok {
+			header, ok = strings.CutSuffix(text[len("---\n"):], "\n---")
+			body = ""
+		}
+		if !ok {
+			return errors.New("front-matter is not closed")
+		}
+
+		var fm importFrontMatter
+		err := yaml.Unmarshal([]byte(header), &fm)
+		if err != nil {
+			return fmt.Errorf("wrong front-matter: %w", err)
+		}
+
+		note.Title = fm.Title
+		note.NoteBook = fm.NoteBook
+		note.Tags = fm.Tags
+		note.Color = fm.Color
+		note.Pinned = fm.Pinned
+		note.Favourite = fm.Favourite
+		note.Archived = fm.Archived
+		note.Trashed = fm.Trashed
+		note.CreatedAt = fm.CreatedAt
+		note.UpdatedAt = fm.UpdatedAt
+
+		text = strings.TrimPrefix(body, "\n")
+	}
+
+	note.Text = strings.TrimRight(text, "\n")
+
+	return nil
+}
+
+type keepNote struct {
+	Title                   string `json:"title"`
+	TextContent             string `json:"textContent"`
+	Color                   string `json:"color"`
+	IsTrashed               bool   `json:"isTrashed"`
+	IsArchived              bool   `json:"isArchived"`
+	IsPinned                bool   `json:"isPinned"`
+	CreatedTimestampUsec    int64  `json:"createdTimestampUsec"`
+	UserEditedTimestampUsec int64  `json:"userEditedTimestampUsec"`
+	ListContent             []struct {
+		Text      string `json:"text"`
+		IsChecked bool   `json:"isChecked"`
+	} `json:"listContent"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// readKeep читает архив Google Takeout: каждая заметка Keep лежит в отдельном JSON-файле
+func readKeep(archive *importArchive, add func(importedNote)) error {
+	for _, file := range archive.File {
+		if !isImportFile(file, ".json") {
+			continue
+		}
+
+		note := importedNote{File: file.Name}
+
+		data, err := archive.read(file)
+		if err != nil {
+			note.Err = err
+			add(note)
+			continue
+		}
+
+		var keep keepNote
+		err = json.Unmarshal(data, &keep)
+		if err != nil {
+			note.Err = fmt.Errorf("wrong Keep note: %w", err)
+			add(note)
+			continue
+		}
+
+		text := keep.TextContent
+		if len(keep.ListContent) > 0 {
+			var sb strings.Builder
+			for _, item := range keep.ListContent {
+				if item.IsChecked {
+					sb.WriteString("- [x] ")
+				} else {
+					sb.WriteString("- [ ] ")
+				}
+				sb.WriteString(item.Text)
+				sb.WriteString("\n")
+			}
+			text = strings.TrimRight(sb.String(), "\n")
+		}
+
+		note.Title = keep.Title
+		note.Text = text
+		note.Pinned = keep.IsPinned
+		note.Archived = keep.IsArchived
+		note.Trashed = keep.IsTrashed
+		if keep.Color != "" && keep.Color != "DEFAULT" {
+			note.Color = strings.ToLower(keep.Color)
+		}
+		if keep.CreatedTimestampUsec != 0 {
+			note.CreatedAt = importTime(time.UnixMicro(keep.CreatedTimestampUsec))
+		}
+		if keep.UserEditedTimestampUsec != 0 {
+			note.UpdatedAt = importTime(time.UnixMicro(keep.UserEditedTimestampUsec))
+		}
+		for _, label := range keep.Labels {
+			note.Tags = append(note.Tags, label.Name)
+		}
+
+		add(note)
+	}
+
+	return nil
+}
+
+type enexNote struct {
+	Title   string   `xml:"title"`
+	Content string   `xml:"content"`
+	Created string   `xml:"created"`
+	Updated string   `xml:"updated"`
+	Tags    []string `xml:"tag"`
+}
+
+// readENEX разбирает экспорт Evernote потоково, не загружая весь файл в память
+func readENEX(r io.Reader, add func(importedNote)) error {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+
+	index := 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "note" {
+			continue
+		}
+
+		index++
+
+		var enex enexNote
+		err = decoder.DecodeElement(&enex, &start)
+		if err != nil {
+			return err
+		}
+
+		note := importedNote{
+			File:  fmt.Sprintf("note %d", index),
+			Title: enex.Title,
+			Tags:  enex.Tags,
+		}
+		if enex.Title != "" {
+			note.File = fmt.Sprintf("note %d (%s)", index, enex.Title)
+		}
+
+		note.Text, err = enmlToMarkdown(enex.Content)
+		if err != nil {
+			note.Err = fmt.Errorf("wrong note content: %w", err)
+		}
+
+		if created, err := time.Parse(enexTimeLayout, enex.Created); err == nil {
+			note.CreatedAt = importTime(created)
+		}
+		if updated, err := time.Parse(enexTimeLayout, enex.Updated); err == nil {
+			note.UpdatedAt = importTime(updated)
+		}
+
+		add(note)
+	}
+}
+
+// enmlToMarkdown переводит основную разметку ENML в Markdown, остальные теги отбрасывает
+func enmlToMarkdown(content string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var sb strings.Builder
+	var links []string
+
+	newLine := func() {
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteString("\n")
+		}
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "div", "p", "ul", "ol", "table", "tr":
+				newLine()
+			case "br":
+				sb.WriteString("\n")
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				newLine()
+				sb.WriteString(strings.Repeat("#", int(t.Name.Local[1]-'0')) + " ")
+			case "li":
+				newLine()
+				sb.WriteString("- ")
+			case "en-todo":
+				if xmlAttr(t, "checked") == "true" {
+					sb.WriteString("[x] ")
+				} else {
+					sb.WriteString("[ ] ")
+				}
+			case "b", "strong":
+				sb.WriteString("**")
+			case "i", "em":
+				sb.WriteString("_")
+			case "a":
+				links = append(links, xmlAttr(t, "href"))
+				sb.WriteString("[")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "div", "p", "ul", "ol", "table", "tr", "li", "h1", "h2", "h3", "h4", "h5", "h6":
+				newLine()
+			case "td", "th":
+				sb.WriteString(" ")
+			case "b", "strong":
+				sb.WriteString("**")
+			case "i", "em":
+				sb.WriteString("_")
+			case "a":
+				if len(links) > 0 {
+					sb.WriteString("](" + links[len(links)-1] + ")")
+					links = links[:len(links)-1]
+				}
+			}
+		case xml.CharData:
+			sb.Write(bytes.ReplaceAll(t, []byte("\u00a0"), []byte(" ")))
+		}
+	}
+
+	text := blankLinesRegexp.ReplaceAllString(sb.String(), "\n\n")
+
+	return strings.TrimSpace(text), nil
+}
+
+func xmlAttr(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// isImportFile отсеивает каталоги и служебные файлы, которые добавляют архиваторы
+func isImportFile(file *zip.File, exts ...string) bool {
+	if file.FileInfo().IsDir() || strings.HasPrefix(file.Name, "__MACOSX/") || strings.HasPrefix(path.Base(file.Name), ".") {
+		return false
+	}
+
+	ext := strings.ToLower(path.Ext(file.Name))
+	for _, e := range exts {
+		if ext == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// read распаковывает файл, пока он укладывается в ограничения. Размер из заголовка zip не проверяется:
+// его можно подделать, поэтому считаются прочитанные байты
+func (a *importArchive) read(file *zip.File) ([]byte, error) {
+	if a.left <= 0 {
+		return nil, errImportArchiveTooLarge
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	limit := min(maxImportEntrySize, a.left)
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	a.left -= min(int64(len(data)), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > limit {
+		if limit < maxImportEntrySize {
+			a.left = 0
+			return nil, errImportArchiveTooLarge
+		}
+		return nil, errImportEntryTooLarge
+	}
+
+	return data, nil
+}
+
+func (a *importArchive) readYAML(file *zip.File, value any) error {
+	data, err := a.read(file)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(data, value)
+}
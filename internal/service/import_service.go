@@ -0,0 +1,346 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/rank"
+	"github.com/LoL-KeKovich/NoteVault/lib/timezone"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxImportSize = 64 << 20
+
+var errEmptyImport = errors.New("nothing to import")
+
+type ImportService struct {
+	DBClient             repository.NoteRepo
+	HelperNoteBookClient repository.NoteBookRepo
+	HelperTagClient      repository.TagRepo
+	Events               *events.Bus
+}
+
+// importedNote - заметка, прочитанная из внешнего формата, до сохранения в базу
+type importedNote struct {
+	File      string
+	Title     string
+	Text      string
+	NoteBook  string
+	Tags      []string
+	Color     string
+	Pinned    bool
+	Favourite bool
+	Archived  bool
+	Trashed   bool
+	CreatedAt string
+	UpdatedAt string
+	Err       error
+}
+
+// importer сохраняет заметки, создавая недостающие блокноты и теги.
+// Найденные по имени блокноты, теги и последние ключи сортировки кэшируются на время импорта
+// importer живет в пределах одного запроса, поэтому хранит его контекст для колбэков читателей
+type importer struct {
+	ctx       context.Context
+	srv       ImportService
+	userID    primitive.ObjectID
+	noteBook  string
+	noteBooks map[string]primitive.ObjectID
+	tags      map[string]primitive.ObjectID
+	ranks     map[primitive.ObjectID]string
+	tagColors map[string]string
+	result    dto.ImportResult
+}
+
+func (srv ImportService) HandleImport(w http.ResponseWriter, r *http.Request) {
+	response := dto.ImportResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = dto.ImportFormatMarkdown
+	}
+
+	userDocId, _ := primitive.ObjectIDFromHex(userID)
+
+	imp := &importer{
+		ctx:       r.Context(),
+		srv:       srv,
+		userID:    userDocId,
+		noteBook:  strings.TrimSpace(r.URL.Query().Get("notebook")),
+		noteBooks: map[string]primitive.ObjectID{},
+		tags:      map[string]primitive.ObjectID{},
+		ranks:     map[primitive.ObjectID]string{},
+		tagColors: map[string]string{},
+		result:    dto.ImportResult{Items: []dto.ImportItem{}},
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var err error
+	switch format {
+	case dto.ImportFormatMarkdown, dto.ImportFormatKeep:
+		err = imp.importZip(body, format)
+	case dto.ImportFormatENEX:
+		err = readENEX(body, imp.add)
+	default:
+		respondInvalid(w, "Wrong format, expected markdown, enex or keep")
+		return
+	}
+
+	if err == nil && imp.result.Total == 0 {
+		err = errEmptyImport
+	}
+
+	if err != nil {
+		slog.Error(err.Error())
+		errResponse := dto.ErrorResponse{Error: "Wrong import file: " + err.Error(), Code: dto.ErrorCodeValidation}
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			errResponse.Error, errResponse.Code = "Import file is too large", dto.ErrorCodeTooLarge
+			status = http.StatusRequestEntityTooLarge
+		}
+		if imp.result.Total > 0 {
+			errResponse.Data = imp.result
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(errResponse)
+		return
+	}
+
+	slog.Info("Notes imported", slog.String("format", format), slog.Int("imported", imp.result.Imported), slog.Int("total", imp.result.Total))
+	response.Data = imp.result
+	json.NewEncoder(w).Encode(response)
+}
+
+// importZip сохраняет архив во временный файл: zip читается с произвольным доступом
+func (imp *importer) importZip(body io.Reader, format string) error {
+	tmp, err := os.CreateTemp("", "notevault-import-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, body)
+	if err != nil {
+		return err
+	}
+
+	archive, err := zip.NewReader(tmp, size)
+	if err != nil {
+		return err
+	}
+
+	if format == dto.ImportFormatKeep {
+		return readKeep(newImportArchive(archive), imp.add)
+	}
+
+	return readMarkdownZip(newImportArchive(archive), imp.tagColors, imp.add)
+}
+
+func (imp *importer) add(in importedNote) {
+	imp.result.Total++
+
+	if in.Err != nil {
+		imp.result.Items = append(imp.result.Items, dto.ImportItem{
+			File:   in.File,
+			Status: dto.ImportStatusFailed,
+			Error:  in.Err.Error(),
+		})
+		return
+	}
+
+	id, err := imp.save(in)
+	if err != nil {
+		slog.Error("Failed to import note", slog.String("file", in.File), slog.String("error", err.Error()))
+		imp.result.Items = append(imp.result.Items, dto.ImportItem{
+			File:   in.File,
+			Status: dto.ImportStatusFailed,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	imp.result.Imported++
+	imp.result.Items = append(imp.result.Items, dto.ImportItem{
+		File:   in.File,
+		ID:     id,
+		Status: dto.ImportStatusOK,
+	})
+}
+
+func (imp *importer) save(in importedNote) (string, error) {
+	noteBookName := in.NoteBook
+	if noteBookName == "" {
+		noteBookName = imp.noteBook
+	}
+
+	noteBookID, err := imp.noteBookID(noteBookName)
+	if err != nil {
+		return "", err
+	}
+
+	var tagIDs []primitive.ObjectID
+	for _, name := range in.Tags {
+		tagID, err := imp.tagID(name)
+		if err != nil {
+			return "", err
+		}
+		tagIDs = append(tagIDs, tagID)
+	}
+
+	now := timezone.Now().String()
+	if in.CreatedAt == "" {
+		in.CreatedAt = now
+	}
+	if in.UpdatedAt == "" {
+		in.UpdatedAt = in.CreatedAt
+	}
+
+	name := in.Title
+	if name == "" {
+		name = "Untitled"
+	}
+
+	note := model.Note{
+		Name:       name,
+		Text:       in.Text,
+		Color:      in.Color,
+		IsDeleted:  &in.Trashed,
+		IsArchived: &in.Archived,
+		CreatedAt:  in.CreatedAt,
+		UpdatedAt:  in.UpdatedAt,
+		NoteBookID: noteBookID,
+		Tags:       tagIDs,
+		UserID:     imp.userID,
+	}
+	if in.Pinned {
+		note.IsPinned = &in.Pinned
+	}
+	if in.Favourite {
+		note.IsFavourite = &in.Favourite
+	}
+
+	note.Rank, err = imp.nextRank(noteBookID)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := imp.srv.DBClient.CreateNote(imp.ctx, note)
+	if err != nil {
+		return "", err
+	}
+
+	imp.ranks[noteBookID] = note.Rank
+	imp.srv.Events.Publish(events.Event{Kind: events.KindNote, Action: events.ActionCreated, ID: id, UserID: imp.userID.Hex()})
+
+	return id, nil
+}
+
+func (imp *importer) noteBookID(name string) (primitive.ObjectID, error) {
+	if name == "" {
+		return primitive.NilObjectID, nil
+	}
+
+	if id, ok := imp.noteBooks[name]; ok {
+		return id, nil
+	}
+
+	noteBook, err := imp.srv.HelperNoteBookClient.GetNoteBookByName(imp.ctx, name)
+	if err == nil {
+		imp.noteBooks[name] = noteBook.ID
+		return noteBook.ID, nil
+	}
+
+	isActive := true
+
+	hexID, err := imp.srv.HelperNoteBookClient.CreateNoteBook(imp.ctx, model.NoteBook{
+		Name:     name,
+		IsActive: &isActive,
+	})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	id, _ := primitive.ObjectIDFromHex(hexID)
+	imp.noteBooks[name] = id
+	imp.srv.Events.Publish(events.Event{Kind: events.KindNoteBook, Action: events.ActionCreated, ID: hexID})
+
+	return id, nil
+}
+
+func (imp *importer) tagID(name string) (primitive.ObjectID, error) {
+	if id, ok := imp.tags[name]; ok {
+		return id, nil
+	}
+
+	if !model.ValidTagName(name) {
+		return primitive.NilObjectID, errors.New("wrong tag name: " + name)
+	}
+
+	tag, err := imp.srv.HelperTagClient.GetTagByName(imp.ctx, name)
+	if err == nil {
+		imp.tags[name] = tag.ID
+		return tag.ID, nil
+	}
+
+	hexID, err := imp.srv.HelperTagClient.CreateTag(imp.ctx, model.Tag{
+		Name:  name,
+		Color: imp.tagColors[name],
+	})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	id, _ := primitive.ObjectIDFromHex(hexID)
+	imp.tags[name] = id
+	imp.srv.Events.Publish(events.Event{Kind: events.KindTag, Action: events.ActionCreated, ID: hexID})
+
+	return id, nil
+}
+
+func (imp *importer) nextRank(noteBookID primitive.ObjectID) (string, error) {
+	last, ok := imp.ranks[noteBookID]
+	if !ok {
+		id := ""
+		if !noteBookID.IsZero() {
+			id = noteBookID.Hex()
+		}
+
+		var err error
+		last, err = imp.srv.DBClient.GetLastNoteRank(imp.ctx, id)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return rank.After(last)
+}
+
+// importTime приводит время из внешнего формата к виду, в котором хранятся даты заметок
+func importTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.In(timezone.Get()).String()
+}
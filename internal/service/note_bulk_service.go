@@ -0,0 +1,152 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/timezone"
+)
+
+const (
+	maxBulkNotes     = 1000
+	tooManyBulkNotes = "Too many notes, the limit is 1000"
+)
+
+var bulkEventActions = map[string]string{
+	repository.BulkActionMove:      events.ActionUpdated,
+	repository.BulkActionAddTag:    events.ActionTagged,
+	repository.BulkActionRemoveTag: events.ActionUntagged,
+	repository.BulkActionRecolor:   events.ActionUpdated,
+	repository.BulkActionArchive:   events.ActionArchived,
+	repository.BulkActionTrash:     events.ActionTrashed,
+	repository.BulkActionRestore:   events.ActionRestored,
+	repository.BulkActionDelete:    events.ActionDeleted,
+}
+
+func (srv NoteService) HandleBulkNotes(w http.ResponseWriter, r *http.Request) {
+	response := dto.NoteResponse{}
+	var bulkReq dto.NoteBulkRequest
+
+	if !decodeRequest(w, r, &bulkReq) {
+		return
+	}
+
+	if (len(bulkReq.IDs) == 0) == (bulkReq.Filter == nil) {
+		respondInvalid(w, "Either ids or filter is required")
+		return
+	}
+
+	if bulkReq.Filter != nil && !validNoteStatus(bulkReq.Filter.Status) {
+		respondInvalid(w, "Wrong status")
+		return
+	}
+
+	if len(bulkReq.IDs) > maxBulkNotes {
+		respondInvalid(w, tooManyBulkNotes)
+		return
+	}
+
+	op := repository.NoteBulkOp{
+		Action:    bulkReq.Action,
+		Color:     bulkReq.Color,
+		UpdatedAt: timezone.Now().String(),
+	}
+
+	switch bulkReq.Action {
+	case repository.BulkActionMove:
+		_, err := srv.HelperNoteBookClient.GetNoteBookByID(r.Context(), bulkReq.NoteBookID)
+		if errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrInvalidID) {
+			respondInvalid(w, "Wrong notebook id")
+			return
+		} else if err != nil {
+			respondError(w, err, "Error finding notebook")
+			return
+		}
+		op.NoteBookID = bulkReq.NoteBookID
+	case repository.BulkActionAddTag, repository.BulkActionRemoveTag:
+		tag, err := srv.findTag(r.Context(), dto.NoteRequest{TagID: bulkReq.TagID, TagName: bulkReq.TagName})
+		if err != nil {
+			respondInvalid(w, "wrong tag")
+			return
+		}
+		op.TagID = tag.ID.Hex()
+	case repository.BulkActionRecolor:
+		if bulkReq.Color == "" {
+			respondInvalid(w, "No color")
+			return
+		}
+	case repository.BulkActionArchive, repository.BulkActionTrash, repository.BulkActionRestore, repository.BulkActionDelete:
+	default:
+		respondInvalid(w, "Wrong action")
+		return
+	}
+
+	ids := bulkReq.IDs
+
+	if bulkReq.Filter != nil {
+		tagExpr, err := buildTagExpr(*bulkReq.Filter)
+		if err != nil {
+			respondInvalid(w, "Wrong tag query: "+err.Error())
+			return
+		}
+
+		if tagExpr != nil {
+			err = srv.resolveTagExpr(r.Context(), tagExpr, bulkReq.Filter.IncludeDescendants)
+			if err != nil {
+				respondError(w, err, "Error resolving tags")
+				return
+			}
+		}
+
+		//На одну заметку больше лимита - чтобы узнать о превышении, не загружая все подходящие заметки
+		notes, err := srv.DBClient.FindNotes(r.Context(), repository.NoteFilter{
+			Tags:       tagExpr,
+			NoteBookID: bulkReq.Filter.NoteBookID,
+			Status:     bulkReq.Filter.Status,
+			Limit:      maxBulkNotes + 1,
+		})
+		if err != nil {
+			respondError(w, err, "Error finding notes by filter")
+			return
+		}
+
+		if len(notes) > maxBulkNotes {
+			respondInvalid(w, tooManyBulkNotes)
+			return
+		}
+
+		for _, note := range notes {
+			ids = append(ids, note.ID.Hex())
+		}
+	}
+
+	owners := srv.noteOwners(r.Context(), ids...)
+
+	results, err := srv.DBClient.BulkUpdateNotes(r.Context(), ids, op)
+	if err != nil {
+		respondError(w, err, "Error applying bulk operation")
+		return
+	}
+
+	bulkRes := dto.NoteBulkResult{Total: len(results), Items: make([]dto.BulkResult, 0, len(results))}
+	var changed []string
+	for _, result := range results {
+		bulkRes.Items = append(bulkRes.Items, dto.BulkResult{ID: result.ID, Status: result.Status, Error: result.Error})
+		if result.Status == repository.BulkStatusOK {
+			bulkRes.Succeeded++
+			changed = append(changed, result.ID)
+		}
+	}
+
+	srv.publishOwned(bulkEventActions[op.Action], changed, owners)
+
+	slog.Info("Bulk operation applied", slog.String("action", op.Action), slog.Int("succeeded", bulkRes.Succeeded))
+	response.Data = bulkRes
+	json.NewEncoder(w).Encode(response)
+}
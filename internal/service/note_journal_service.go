@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/lib/timezone"
+	"github.com/go-chi/chi"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	journalDateLayout  = "2006-01-02"
+	journalMonthLayout = "2006-01"
+)
+
+func (srv NoteService) HandleGetDailyNote(w http.ResponseWriter, r *http.Request) {
+	response := dto.NoteResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	date := chi.URLParam(r, "date")
+	day, err := time.ParseInLocation(journalDateLayout, date, timezone.Get())
+	if err != nil {
+		respondInvalid(w, "Wrong date, expected YYYY-MM-DD")
+		return
+	}
+
+	note, err := srv.DBClient.GetJournalNote(r.Context(), userID, date)
+	if err == nil && note.ID.IsZero() {
+		note, err = srv.createDailyNote(r.Context(), userID, day)
+	} else if err == nil && note.IsDeleted != nil && *note.IsDeleted {
+		//Календарь не показывает записи из корзины, а вторую запись за день не создать - возвращаем эту из корзины
+		note, err = srv.restoreDailyNote(r.Context(), note)
+	}
+	if err != nil {
+		respondError(w, err, "Error getting daily note")
+		return
+	}
+
+	views, err := srv.expandTags(r.Context(), []model.Note{note})
+	if err != nil {
+		respondError(w, err, "Error finding tags of note")
+		return
+	}
+
+	slog.Info("Daily note found", slog.String("date", date))
+	response.Data = views[0]
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv NoteService) HandleGetJournalCalendar(w http.ResponseWriter, r *http.Request) {
+	response := dto.NoteResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	month := chi.URLParam(r, "month")
+	start, err := time.Parse(journalMonthLayout, month)
+	if err != nil {
+		respondInvalid(w, "Wrong month, expected YYYY-MM")
+		return
+	}
+
+	dates, err := srv.DBClient.GetJournalDates(r.Context(), userID, start.Format(journalDateLayout), start.AddDate(0, 1, 0).Format(journalDateLayout))
+	if err != nil {
+		respondError(w, err, "Error finding journal notes")
+		return
+	}
+
+	if dates == nil {
+		dates = []string{}
+	}
+
+	slog.Info("Journal calendar found", slog.String("month", month))
+	response.Data = dto.JournalCalendar{Month: month, Days: dates}
+	json.NewEncoder(w).Encode(response)
+}
+
+// createDailyNote создает запись в блокноте журнала из шаблона пользователя с именем из конфига,
+// а если такого шаблона нет - из текста по умолчанию
+func (srv NoteService) createDailyNote(ctx context.Context, userID string, day time.Time) (model.Note, error) {
+	noteBookID, err := srv.journalNoteBookID(ctx)
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	now := timezone.Now()
+	//Плейсхолдеры даты относятся к дню записи, а не к моменту создания
+	at := time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), 0, 0, day.Location())
+
+	isDeleted, isArchived := false, false
+	userDocId, _ := primitive.ObjectIDFromHex(userID)
+
+	note := model.Note{
+		Name:        day.Format(journalDateLayout),
+		IsDeleted:   &isDeleted,
+		IsArchived:  &isArchived,
+		NoteBookID:  noteBookID,
+		UserID:      userDocId,
+		JournalDate: day.Format(journalDateLayout),
+		CreatedAt:   now.String(),
+		UpdatedAt:   now.String(),
+	}
+
+	template, err := srv.HelperTemplateClient.GetTemplateByName(ctx, userID, srv.Journal.Template)
+	if errors.Is(err, model.ErrNotFound) {
+		template = model.Template{Text: srv.Journal.Text}
+	} else if err != nil {
+		return model.Note{}, err
+	}
+
+	err = srv.applyTemplate(ctx, &note, template, userID, at)
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	note.Rank, err = srv.nextRank(ctx, note.NoteBookID)
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	id, err := srv.DBClient.CreateNote(ctx, note)
+	if err != nil {
+		//Запись могла создать параллельный запрос - уникальный индекс не даст сделать вторую
+		existing, getErr := srv.DBClient.GetJournalNote(ctx, userID, note.JournalDate)
+		if getErr == nil && !existing.ID.IsZero() {
+			return existing, nil
+		}
+		return model.Note{}, err
+	}
+
+	srv.Events.Publish(events.Event{Kind: events.KindNote, Action: events.ActionCreated, ID: id, UserID: userID})
+	slog.Info("Created daily note", slog.String("_id", id))
+	note.ID, _ = primitive.ObjectIDFromHex(id)
+
+	return note, nil
+}
+
+func (srv NoteService) restoreDailyNote(ctx context.Context, note model.Note) (model.Note, error) {
+	err := srv.DBClient.RestoreNoteFromTrash(ctx, note.ID.Hex())
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	srv.publishNotes(ctx, events.ActionRestored, note.ID.Hex())
+	slog.Info("Restored daily note from trash", slog.String("_id", note.ID.Hex()))
+
+	isDeleted := false
+	note.IsDeleted = &isDeleted
+
+	return note, nil
+}
+
+// journalNoteBookID возвращает блокнот журнала; параллельные запросы первого дня не создадут второй такой же
+func (srv NoteService) journalNoteBookID(ctx context.Context) (primitive.ObjectID, error) {
+	isActive := true
+
+	noteBook, created, err := srv.HelperNoteBookClient.GetOrCreateNoteBook(ctx, model.NoteBook{
+		Name:     srv.Journal.NoteBook,
+		IsActive: &isActive,
+	})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if created {
+		srv.Events.Publish(events.Event{Kind: events.KindNoteBook, Action: events.ActionCreated, ID: noteBook.ID.Hex()})
+	}
+
+	return noteBook.ID, nil
+}
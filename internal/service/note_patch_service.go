@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/timezone"
+	"github.com/LoL-KeKovich/NoteVault/lib/validate"
+	"github.com/go-chi/chi"
+)
+
+// HandlePatchNote применяет JSON Merge Patch к заметке и возвращает ее новое состояние.
+// Все поля меняются одним обновлением в базе, поэтому патч применяется целиком или не применяется
+func (srv NoteService) HandlePatchNote(w http.ResponseWriter, r *http.Request) {
+	response := dto.NoteResponse{}
+	var patchReq dto.NotePatchRequest
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	nulls, ok := decodeMergePatch(w, r, &patchReq)
+	if !ok {
+		return
+	}
+
+	if nulls["name"] || patchReq.Name != nil && strings.TrimSpace(*patchReq.Name) == "" {
+		respondError(w, validate.Errors{{Field: "name", Rule: "required", Message: "cannot be cleared"}}, "")
+		return
+	}
+
+	empty := ""
+	patch := repository.NotePatch{
+		Name:       patchReq.Name,
+		Text:       patchReq.Text,
+		Color:      patchReq.Color,
+		Order:      patchReq.Order,
+		NoteBookID: patchReq.NoteBookID,
+		UpdatedAt:  timezone.Now().String(),
+	}
+	if nulls["text"] {
+		patch.Text = &empty
+	}
+	if nulls["color"] {
+		patch.Color = &empty
+	}
+	if nulls["order"] {
+		patch.Order = new(int)
+	}
+	if nulls["notebook_id"] {
+		patch.NoteBookID = &empty
+	}
+	if patchReq.Tags != nil || nulls["tags"] {
+		tags := []string{}
+		for _, tagID := range patchReq.Tags {
+			if !slices.Contains(tags, tagID) {
+				tags = append(tags, tagID)
+			}
+		}
+		patch.Tags = &tags
+	}
+
+	err := srv.checkPatchRefs(r.Context(), patch)
+	if err != nil {
+		respondError(w, err, "")
+		return
+	}
+
+	_, err = srv.DBClient.PatchNote(r.Context(), id, patch)
+	if err != nil {
+		respondError(w, err, "Error updating note in db")
+		return
+	}
+
+	note, err := srv.DBClient.GetNoteByID(r.Context(), id)
+	if err != nil {
+		respondError(w, err, "Error finding note in db")
+		return
+	}
+
+	views, err := srv.expandTags(r.Context(), []model.Note{note})
+	if err != nil {
+		respondError(w, err, "Error finding tags of note")
+		return
+	}
+
+	srv.Events.Publish(events.Event{Kind: events.KindNote, Action: events.ActionUpdated, ID: id, UserID: ownerOf(note)})
+	slog.Info("Note patched", slog.String("_id", id))
+	response.Data = views[0]
+	json.NewEncoder(w).Encode(response)
+}
+
+// noteFlags - флаги заметки, которые меняются отдельными методами репозитория. nil - флаг не меняется
+type noteFlags struct {
+	IsDeleted   *bool
+	IsArchived  *bool
+	IsPinned    *bool
+	IsFavourite *bool
+}
+
+// updateNote применяет патч и флаги к найденной заметке и рассылает события по каждому изменению
+func (srv NoteService) updateNote(ctx context.Context, note model.Note, patch repository.NotePatch, want noteFlags) error {
+	id := note.ID.Hex()
+
+	_, err := srv.DBClient.PatchNote(ctx, id, patch)
+	if err != nil {
+		return err
+	}
+
+	actions := []string{events.ActionUpdated}
+
+	flags := []struct {
+		want, have *bool
+		set        func(bool) error
+		action     string
+	}{
+		{want.IsDeleted, note.IsDeleted, func(on bool) error {
+			if on {
+				return srv.DBClient.MoveNoteToTrash(ctx, id)
+			}
+			return srv.DBClient.RestoreNoteFromTrash(ctx, id)
+		}, events.ActionTrashed},
+		{want.IsArchived, note.IsArchived, func(on bool) error {
+			if on {
+				return srv.DBClient.MoveNoteToArchive(ctx, id)
+			}
+			return srv.DBClient.RestoreNoteFromArchive(ctx, id)
+		}, events.ActionArchived},
+		{want.IsPinned, note.IsPinned, func(on bool) error {
+			return srv.DBClient.SetNotePinned(ctx, id, on)
+		}, ""},
+		{want.IsFavourite, note.IsFavourite, func(on bool) error {
+			return srv.DBClient.SetNoteFavourite(ctx, id, on)
+		}, ""},
+	}
+
+	for _, flag := range flags {
+		if flag.want == nil || *flag.want == (flag.have != nil && *flag.have) {
+			continue
+		}
+
+		err = flag.set(*flag.want)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case flag.action == "":
+		case *flag.want:
+			actions = append(actions, flag.action)
+		default:
+			actions = append(actions, events.ActionRestored)
+		}
+	}
+
+	for _, action := range actions {
+		srv.Events.Publish(events.Event{Kind: events.KindNote, Action: action, ID: id, UserID: ownerOf(note)})
+	}
+
+	return nil
+}
+
+// checkPatchRefs проверяет, что блокнот и теги из патча существуют
+func (srv NoteService) checkPatchRefs(ctx context.Context, patch repository.NotePatch) error {
+	if patch.NoteBookID != nil && *patch.NoteBookID != "" {
+		_, err := srv.HelperNoteBookClient.GetNoteBookByID(ctx, *patch.NoteBookID)
+		if err != nil {
+			return model.Validation("Wrong notebook id")
+		}
+	}
+
+	if patch.Tags != nil && len(*patch.Tags) > 0 {
+		tags, err := srv.HelperTagClient.GetTagsByIDs(ctx, *patch.Tags)
+		if err != nil || len(tags) != len(*patch.Tags) {
+			return model.Validation("Wrong tag ids")
+		}
+	}
+
+	return nil
+}
@@ -1,33 +1,44 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/LoL-KeKovich/NoteVault/internal/config"
 	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
 	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/rank"
+	"github.com/LoL-KeKovich/NoteVault/lib/tagquery"
 	"github.com/LoL-KeKovich/NoteVault/lib/timezone"
 	"github.com/go-chi/chi"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var errNoteNotFound = model.NotFound("Note not found")
+
 type NoteService struct {
 	DBClient             repository.NoteRepo
 	HelperNoteBookClient repository.NoteBookRepo
 	HelperTagClient      repository.TagRepo
+	HelperTemplateClient repository.TemplateRepo
+	HelperUserClient     repository.UserRepo
+	Journal              config.Journal
+	Events               *events.Bus
 }
 
 func (srv NoteService) HandleCreateNote(w http.ResponseWriter, r *http.Request) {
 	response := dto.NoteResponse{}
 	var noteReq dto.NoteRequest
 
-	err := json.NewDecoder(r.Body).Decode(&noteReq)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Wrong request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if !decodeRequest(w, r, &noteReq) {
 		return
 	}
 
@@ -58,12 +69,36 @@ func (srv NoteService) HandleCreateNote(w http.ResponseWriter, r *http.Request)
 		UpdatedAt:  now.String(),
 	}
 
-	res, err := srv.DBClient.CreateNote(note)
+	userID, _ := r.Context().Value(userIDKey).(string)
+	if userID != "" {
+		note.UserID, _ = primitive.ObjectIDFromHex(userID)
+	}
+
+	if noteReq.TemplateID != "" {
+		if userID == "" {
+			respondError(w, model.Unauthorized("Templates require authorization"), "")
+			return
+		}
+
+		template, err := srv.findOwnTemplate(r.Context(), noteReq.TemplateID, userID)
+		if errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrInvalidID) {
+			respondInvalid(w, "Wrong template id")
+			return
+		} else if err != nil {
+			respondError(w, err, "Error finding template")
+			return
+		}
+
+		err = srv.applyTemplate(r.Context(), &note, template, userID, now)
+		if err != nil {
+			respondError(w, err, "Error applying template")
+			return
+		}
+	}
+
+	res, err := srv.createNote(r.Context(), note)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error inserting note in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error inserting note in db")
 		return
 	}
 
@@ -77,75 +112,104 @@ func (srv NoteService) HandleGetNoteByID(w http.ResponseWriter, r *http.Request)
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	note, err := srv.DBClient.GetNoteByID(r.Context(), id)
+	if err != nil {
+		respondError(w, err, "Error finding note in db")
 		return
 	}
 
-	note, err := srv.DBClient.GetNoteByID(id)
+	views, err := srv.expandTags(r.Context(), []model.Note{note})
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error finding note in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error finding tags of note")
 		return
 	}
 
 	slog.Info("Note found")
-	response.Data = note
+	response.Data = views[0]
 	json.NewEncoder(w).Encode(response)
 }
 
 func (srv NoteService) HandleGetNotes(w http.ResponseWriter, r *http.Request) {
 	response := dto.NoteResponse{}
 
-	notes, err := srv.DBClient.GetNotes()
+	notes, err := srv.DBClient.GetNotes(r.Context())
+	if err != nil {
+		respondError(w, err, "Error finding notes in db")
+		return
+	}
+
+	views, err := srv.expandTags(r.Context(), notes)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error finding notes in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error finding tags of notes")
 		return
 	}
 
 	slog.Info("Notes found")
-	response.Data = notes
+	response.Data = views
 	json.NewEncoder(w).Encode(response)
 }
 
 func (srv NoteService) HandleGetTrashedNotes(w http.ResponseWriter, r *http.Request) {
 	response := dto.NoteResponse{}
 
-	notes, err := srv.DBClient.GetTrashedNotes()
+	notes, err := srv.DBClient.GetTrashedNotes(r.Context())
+	if err != nil {
+		respondError(w, err, "Error finding trashed notes in db")
+		return
+	}
+
+	views, err := srv.expandTags(r.Context(), notes)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error finding trashed notes in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error finding tags of notes")
 		return
 	}
 
 	slog.Info("Trashed notes found")
-	response.Data = notes
+	response.Data = views
 	json.NewEncoder(w).Encode(response)
 }
 
 func (srv NoteService) HandleGetArchivedNotes(w http.ResponseWriter, r *http.Request) {
 	response := dto.NoteResponse{}
 
-	notes, err := srv.DBClient.GetArchivedNotes()
+	notes, err := srv.DBClient.GetArchivedNotes(r.Context())
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error finding archived notes in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error finding archived notes in db")
+		return
+	}
+
+	views, err := srv.expandTags(r.Context(), notes)
+	if err != nil {
+		respondError(w, err, "Error finding tags of notes")
 		return
 	}
 
 	slog.Info("Archived notes found")
-	response.Data = notes
+	response.Data = views
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv NoteService) HandleGetFavouriteNotes(w http.ResponseWriter, r *http.Request) {
+	response := dto.NoteResponse{}
+
+	notes, err := srv.DBClient.GetFavouriteNotes(r.Context())
+	if err != nil {
+		respondError(w, err, "Error finding favourite notes in db")
+		return
+	}
+
+	views, err := srv.expandTags(r.Context(), notes)
+	if err != nil {
+		respondError(w, err, "Error finding tags of notes")
+		return
+	}
+
+	slog.Info("Favourite notes found")
+	response.Data = views
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -154,33 +218,30 @@ func (srv NoteService) HandleGetNotesByNoteBookID(w http.ResponseWriter, r *http
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	_, err := srv.HelperNoteBookClient.GetNoteBookByID(id)
+	_, err := srv.HelperNoteBookClient.GetNoteBookByID(r.Context(), id)
 	if err != nil {
-		slog.Error("No such notebook")
-		response.Error = "Wrong notebook id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error finding notebook in db")
 		return
 	}
 
-	notes, err := srv.DBClient.GetNotesByNoteBookID(id)
+	notes, err := srv.DBClient.GetNotesByNoteBookID(r.Context(), id)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error finding notes from notebook"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error finding notes from notebook")
+		return
+	}
+
+	views, err := srv.expandTags(r.Context(), notes)
+	if err != nil {
+		respondError(w, err, "Error finding tags of notes")
 		return
 	}
 
 	slog.Info("Notes from notebook found")
-	response.Data = notes
+	response.Data = views
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -188,30 +249,47 @@ func (srv NoteService) HandleGetNotesByTags(w http.ResponseWriter, r *http.Reque
 	response := dto.NoteResponse{}
 	var noteReq dto.NoteTagsRequest
 
-	err := json.NewDecoder(r.Body).Decode(&noteReq)
+	if !decodeRequest(w, r, &noteReq) {
+		return
+	}
+
+	if !validNoteStatus(noteReq.Status) {
+		respondInvalid(w, "Wrong status")
+		return
+	}
+
+	tagExpr, err := buildTagExpr(noteReq)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Wrong request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong tag query: "+err.Error())
 		return
 	}
 
-	var tagNames []string
+	if tagExpr != nil {
+		err = srv.resolveTagExpr(r.Context(), tagExpr, noteReq.IncludeDescendants)
+		if err != nil {
+			respondError(w, err, "Error resolving tags")
+			return
+		}
+	}
 
-	tagNames = append(tagNames, noteReq.TagNames...)
+	notes, err := srv.DBClient.FindNotes(r.Context(), repository.NoteFilter{
+		Tags:       tagExpr,
+		NoteBookID: noteReq.NoteBookID,
+		Status:     noteReq.Status,
+	})
+	if err != nil {
+		respondError(w, err, "Error finding notes by tags")
+		return
+	}
 
-	notes, err := srv.DBClient.GetNotesByTags(tagNames)
+	views, err := srv.expandTags(r.Context(), notes)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error finding notes by tags"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error finding tags of notes")
 		return
 	}
 
 	slog.Info("Notes by tags found")
-	response.Data = notes
+	response.Data = views
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -221,33 +299,23 @@ func (srv NoteService) HandleUpdateNote(w http.ResponseWriter, r *http.Request)
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&noteReq)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Wrong request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if !decodePartial(w, r, &noteReq) {
 		return
 	}
 
 	now := timezone.Now()
 
-	res, err := srv.DBClient.UpdateNote(id, noteReq.Name, noteReq.Text, noteReq.Color, now.String(), noteReq.Order)
+	res, err := srv.DBClient.UpdateNote(r.Context(), id, noteReq.Name, noteReq.Text, noteReq.Color, now.String(), noteReq.Order)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error updating note in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error updating note in db")
 		return
 	}
 
+	srv.publishNotes(r.Context(), events.ActionUpdated, id)
 	slog.Info("Note updated")
 	response.Data = res
 	json.NewEncoder(w).Encode(response)
@@ -259,66 +327,129 @@ func (srv NoteService) HandleUpdateNoteNoteBook(w http.ResponseWriter, r *http.R
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&noteReq)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Wrong request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if !decodePartial(w, r, &noteReq) {
 		return
 	}
 
-	_, err = srv.HelperNoteBookClient.GetNoteBookByID(noteReq.NoteBookID.Hex())
+	_, err := srv.HelperNoteBookClient.GetNoteBookByID(r.Context(), noteReq.NoteBookID.Hex())
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "error: wrong group id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "error: wrong group id")
 		return
 	}
 
-	res, err := srv.DBClient.UpdateNoteNoteBook(id, noteReq.NoteBookID.Hex())
+	res, err := srv.DBClient.UpdateNoteNoteBook(r.Context(), id, noteReq.NoteBookID.Hex())
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error changing notebook for note"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error changing notebook for note")
 		return
 	}
 
+	srv.publishNotes(r.Context(), events.ActionUpdated, id)
 	slog.Info("Notebook for note changed")
 	response.Data = res
 	json.NewEncoder(w).Encode(response)
 }
 
+func (srv NoteService) HandleReorderNote(w http.ResponseWriter, r *http.Request) {
+	response := dto.NoteResponse{}
+	var reorderReq dto.NoteReorderRequest
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	if !decodeRequest(w, r, &reorderReq) {
+		return
+	}
+
+	anchorID := reorderReq.AfterID
+	if reorderReq.BeforeID != "" {
+		anchorID = reorderReq.BeforeID
+	}
+
+	if (reorderReq.BeforeID == "") == (reorderReq.AfterID == "") || anchorID == id {
+		respondInvalid(w, "Exactly one of before_id and after_id is required")
+		return
+	}
+
+	note, err := srv.DBClient.GetNoteByID(r.Context(), id)
+	if err != nil {
+		respondError(w, err, "Error finding note in db")
+		return
+	}
+
+	anchor, err := srv.DBClient.GetNoteByID(r.Context(), anchorID)
+	if errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrInvalidID) {
+		respondInvalid(w, "Wrong anchor note id")
+		return
+	} else if err != nil {
+		respondError(w, err, "Error finding anchor note")
+		return
+	}
+
+	if anchor.NoteBookID != note.NoteBookID {
+		respondInvalid(w, "Notes are in different notebooks")
+		return
+	}
+
+	noteBookID := ""
+	if !note.NoteBookID.IsZero() {
+		noteBookID = note.NoteBookID.Hex()
+	}
+
+	//Меняется только ключ перемещаемой заметки: он встает между якорем и его соседом
+	var lo, hi string
+	if reorderReq.AfterID != "" {
+		lo = anchor.Rank
+		hi, err = srv.DBClient.GetNextNoteRank(r.Context(), noteBookID, anchor.Rank)
+	} else {
+		hi = anchor.Rank
+		lo, err = srv.DBClient.GetPrevNoteRank(r.Context(), noteBookID, anchor.Rank)
+	}
+	if err != nil {
+		respondError(w, err, "Error finding neighbour note")
+		return
+	}
+
+	newRank, err := rank.Between(lo, hi)
+	if err != nil {
+		respondError(w, err, "Error ordering note")
+		return
+	}
+
+	_, err = srv.DBClient.UpdateNoteRank(r.Context(), id, newRank)
+	if err != nil {
+		respondError(w, err, "Error updating note rank")
+		return
+	}
+
+	srv.publishNotes(r.Context(), events.ActionUpdated, id)
+	slog.Info("Note reordered", slog.String("rank", newRank))
+	response.Data = newRank
+	json.NewEncoder(w).Encode(response)
+}
+
 func (srv NoteService) HandleRemoveNoteBookFromNote(w http.ResponseWriter, r *http.Request) {
 	response := dto.NoteResponse{}
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	res, err := srv.DBClient.RemoveNoteBookFromNote(id)
+	res, err := srv.DBClient.RemoveNoteBookFromNote(r.Context(), id)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error removing notebook from note"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error removing notebook from note")
 		return
 	}
 
+	srv.publishNotes(r.Context(), events.ActionUpdated, id)
 	slog.Info("Notebook removed", "modifiedCount", res)
 	response.Data = res
 	w.WriteHeader(http.StatusOK)
@@ -331,48 +462,35 @@ func (srv NoteService) HandleAddTagToNote(w http.ResponseWriter, r *http.Request
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&noteReq)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Wrong request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if !decodePartial(w, r, &noteReq) {
 		return
 	}
 
-	if noteReq.TagName == "" {
-		slog.Error("Empty tag_name field")
-		response.Error = "No tag name"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if noteReq.TagID == "" && noteReq.TagName == "" {
+		respondInvalid(w, "No tag id or name")
 		return
 	}
 
-	_, err = srv.HelperTagClient.GetTagByName(noteReq.TagName)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "wrong tag name"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	tag, err := srv.findTag(r.Context(), noteReq)
+	if errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrInvalidID) {
+		respondInvalid(w, "wrong tag")
+		return
+	} else if err != nil {
+		respondError(w, err, "Error finding tag")
 		return
 	}
 
-	res, err := srv.DBClient.AddTagToNote(id, noteReq.TagName)
+	res, err := srv.DBClient.AddTagToNote(r.Context(), id, tag.ID.Hex())
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error adding tag to note"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error adding tag to note")
 		return
 	}
 
+	srv.publishNotes(r.Context(), events.ActionTagged, id)
 	slog.Info("Added tag to notebook")
 	response.Data = res
 	json.NewEncoder(w).Encode(response)
@@ -383,22 +501,17 @@ func (srv NoteService) HandleMoveNoteToTrash(w http.ResponseWriter, r *http.Requ
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	err := srv.DBClient.MoveNoteToTrash(id)
+	err := srv.DBClient.MoveNoteToTrash(r.Context(), id)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error moving note to trash"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error moving note to trash")
 		return
 	}
 
+	srv.publishNotes(r.Context(), events.ActionTrashed, id)
 	slog.Info("Changed |is_deleted| field to true")
 	response.Data = "Successfully moved note to trash"
 	json.NewEncoder(w).Encode(response)
@@ -409,48 +522,122 @@ func (srv NoteService) HandleMoveNoteToArchive(w http.ResponseWriter, r *http.Re
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	err := srv.DBClient.MoveNoteToArchive(id)
+	err := srv.DBClient.MoveNoteToArchive(r.Context(), id)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error moving note to archive"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error moving note to archive")
 		return
 	}
 
+	srv.publishNotes(r.Context(), events.ActionArchived, id)
 	slog.Info("Changed |is_archived| field to true")
 	response.Data = "Successfully moved note to archive"
 	json.NewEncoder(w).Encode(response)
 }
 
+func (srv NoteService) HandlePinNote(w http.ResponseWriter, r *http.Request) {
+	response := dto.NoteResponse{}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	err := srv.DBClient.SetNotePinned(r.Context(), id, true)
+	if err != nil {
+		respondError(w, err, "Error pinning note")
+		return
+	}
+
+	srv.publishNotes(r.Context(), events.ActionUpdated, id)
+	slog.Info("Changed |is_pinned| field to true")
+	response.Data = "Successfully pinned note"
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv NoteService) HandleUnpinNote(w http.ResponseWriter, r *http.Request) {
+	response := dto.NoteResponse{}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	err := srv.DBClient.SetNotePinned(r.Context(), id, false)
+	if err != nil {
+		respondError(w, err, "Error unpinning note")
+		return
+	}
+
+	srv.publishNotes(r.Context(), events.ActionUpdated, id)
+	slog.Info("Changed |is_pinned| field to false")
+	response.Data = "Successfully unpinned note"
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv NoteService) HandleAddNoteToFavourites(w http.ResponseWriter, r *http.Request) {
+	response := dto.NoteResponse{}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	err := srv.DBClient.SetNoteFavourite(r.Context(), id, true)
+	if err != nil {
+		respondError(w, err, "Error adding note to favourites")
+		return
+	}
+
+	srv.publishNotes(r.Context(), events.ActionUpdated, id)
+	slog.Info("Changed |is_favourite| field to true")
+	response.Data = "Successfully added note to favourites"
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv NoteService) HandleRemoveNoteFromFavourites(w http.ResponseWriter, r *http.Request) {
+	response := dto.NoteResponse{}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	err := srv.DBClient.SetNoteFavourite(r.Context(), id, false)
+	if err != nil {
+		respondError(w, err, "Error removing note from favourites")
+		return
+	}
+
+	srv.publishNotes(r.Context(), events.ActionUpdated, id)
+	slog.Info("Changed |is_favourite| field to false")
+	response.Data = "Successfully removed note from favourites"
+	json.NewEncoder(w).Encode(response)
+}
+
 func (srv NoteService) HandleRestoreNoteFromTrash(w http.ResponseWriter, r *http.Request) {
 	response := dto.NoteResponse{}
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	err := srv.DBClient.RestoreNoteFromTrash(id)
+	err := srv.DBClient.RestoreNoteFromTrash(r.Context(), id)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error restoring note from trash"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error restoring note from trash")
 		return
 	}
 
+	srv.publishNotes(r.Context(), events.ActionRestored, id)
 	slog.Info("Changed |is_deleted| field to false")
 	response.Data = "Successfully removed note from trash"
 	json.NewEncoder(w).Encode(response)
@@ -461,22 +648,17 @@ func (srv NoteService) HandleRestoreNoteFromArchive(w http.ResponseWriter, r *ht
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	err := srv.DBClient.RestoreNoteFromArchive(id)
+	err := srv.DBClient.RestoreNoteFromArchive(r.Context(), id)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error restoring note from archive"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error restoring note from archive")
 		return
 	}
 
+	srv.publishNotes(r.Context(), events.ActionRestored, id)
 	slog.Info("Changed |is_archived| field to false")
 	response.Data = "Successfully removed note from archive"
 	json.NewEncoder(w).Encode(response)
@@ -487,22 +669,19 @@ func (srv NoteService) HandleDeleteNote(w http.ResponseWriter, r *http.Request)
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	res, err := srv.DBClient.DeleteNote(id)
+	owners := srv.noteOwners(r.Context(), id)
+
+	res, err := srv.DBClient.DeleteNote(r.Context(), id)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error deleting note in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error deleting note in db")
 		return
 	}
 
+	srv.publishOwned(events.ActionDeleted, []string{id}, owners)
 	slog.Info("Note deleted")
 	response.Data = res
 	json.NewEncoder(w).Encode(response)
@@ -514,49 +693,352 @@ func (srv NoteService) HandleRemoveTagFromNote(w http.ResponseWriter, r *http.Re
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&noteReq)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Wrong request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if !decodePartial(w, r, &noteReq) {
 		return
 	}
 
-	if noteReq.TagName == "" {
-		slog.Error("Empty tag_name field")
-		response.Error = "No tag name"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if noteReq.TagID == "" && noteReq.TagName == "" {
+		respondInvalid(w, "No tag id or name")
 		return
 	}
 
-	_, err = srv.HelperTagClient.GetTagByName(noteReq.TagName)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "wrong tag name"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	tag, err := srv.findTag(r.Context(), noteReq)
+	if errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrInvalidID) {
+		respondInvalid(w, "wrong tag")
+		return
+	} else if err != nil {
+		respondError(w, err, "Error finding tag")
 		return
 	}
 
-	res, err := srv.DBClient.RemoveTagFromNote(id, noteReq.TagName)
+	res, err := srv.DBClient.RemoveTagFromNote(r.Context(), id, tag.ID.Hex())
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error removing tag from note"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error removing tag from note")
 		return
 	}
 
+	srv.publishNotes(r.Context(), events.ActionUntagged, id)
 	slog.Info("Removed tag from notebook")
 	response.Data = res
 	json.NewEncoder(w).Encode(response)
 }
+
+func (srv NoteService) findTag(ctx context.Context, noteReq dto.NoteRequest) (model.Tag, error) {
+	if noteReq.TagID != "" {
+		return srv.HelperTagClient.GetTagByID(ctx, noteReq.TagID)
+	}
+
+	return srv.HelperTagClient.GetTagByName(ctx, noteReq.TagName)
+}
+
+func (srv NoteService) expandTags(ctx context.Context, notes []model.Note) ([]dto.NoteView, error) {
+	var tagIDs []string
+	seen := map[primitive.ObjectID]bool{}
+
+	for _, note := range notes {
+		for _, tagID := range note.Tags {
+			if !seen[tagID] {
+				seen[tagID] = true
+				tagIDs = append(tagIDs, tagID.Hex())
+			}
+		}
+	}
+
+	tags := map[primitive.ObjectID]model.Tag{}
+
+	if len(tagIDs) > 0 {
+		found, err := srv.HelperTagClient.GetTagsByIDs(ctx, tagIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tag := range found {
+			tags[tag.ID] = tag
+		}
+	}
+
+	var views []dto.NoteView
+
+	for _, note := range notes {
+		view := dto.NoteView{Note: note}
+
+		for _, tagID := range note.Tags {
+			tag, ok := tags[tagID]
+			if !ok {
+				continue
+			}
+			view.Tags = append(view.Tags, dto.NoteTag{ID: tag.ID, Name: tag.Name, Color: tag.Color})
+		}
+
+		views = append(views, view)
+	}
+
+	return views, nil
+}
+
+func validNoteStatus(status string) bool {
+	switch status {
+	case "", repository.NoteStatusActive, repository.NoteStatusArchived, repository.NoteStatusTrashed, repository.NoteStatusAll:
+		return true
+	}
+
+	return false
+}
+
+// buildTagExpr объединяет через AND все условия запроса: tags/tag_ids и all_of - все теги,
+// any_of - хотя бы один, none_of - ни одного, query - произвольное выражение.
+func buildTagExpr(noteReq dto.NoteTagsRequest) (tagquery.Expr, error) {
+	var and tagquery.And
+
+	for _, tagID := range noteReq.TagIDs {
+		and = append(and, &tagquery.Tag{ID: tagID})
+	}
+
+	for _, tagName := range append(noteReq.TagNames, noteReq.AllOf...) {
+		and = append(and, &tagquery.Tag{Name: tagName})
+	}
+
+	if len(noteReq.AnyOf) > 0 {
+		var or tagquery.Or
+		for _, tagName := range noteReq.AnyOf {
+			or = append(or, &tagquery.Tag{Name: tagName})
+		}
+		and = append(and, or)
+	}
+
+	if len(noteReq.NoneOf) > 0 {
+		var or tagquery.Or
+		for _, tagName := range noteReq.NoneOf {
+			or = append(or, &tagquery.Tag{Name: tagName})
+		}
+		and = append(and, tagquery.Not{Expr: or})
+	}
+
+	if noteReq.Query != "" {
+		expr, err := tagquery.Parse(noteReq.Query)
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, expr)
+	}
+
+	switch len(and) {
+	case 0:
+		return nil, nil
+	case 1:
+		return and[0], nil
+	default:
+		return and, nil
+	}
+}
+
+// resolveTagExpr заполняет IDs в листьях выражения. Несуществующий тег не совпадает ни с одной заметкой.
+func (srv NoteService) resolveTagExpr(ctx context.Context, expr tagquery.Expr, includeDescendants bool) error {
+	for _, leaf := range tagquery.Tags(expr) {
+		var tag model.Tag
+		var err error
+
+		if leaf.ID != "" {
+			tag, err = srv.HelperTagClient.GetTagByID(ctx, leaf.ID)
+		} else {
+			tag, err = srv.HelperTagClient.GetTagByName(ctx, leaf.Name)
+		}
+		if err != nil {
+			slog.Info("Unknown tag in filter", slog.String("tag_id", leaf.ID), slog.String("tag_name", leaf.Name))
+			leaf.IDs = []string{}
+			continue
+		}
+
+		leaf.IDs = []string{tag.ID.Hex()}
+
+		if includeDescendants {
+			descendants, err := srv.HelperTagClient.GetTagDescendants(ctx, tag.Name)
+			if err != nil {
+				return err
+			}
+
+			for _, descendant := range descendants {
+				leaf.IDs = append(leaf.IDs, descendant.ID.Hex())
+			}
+		}
+	}
+
+	return nil
+}
+
+// createNote ставит заметку в конец ее блокнота и сохраняет
+func (srv NoteService) createNote(ctx context.Context, note model.Note) (string, error) {
+	noteRank, err := srv.nextRank(ctx, note.NoteBookID)
+	if err != nil {
+		return "", err
+	}
+	note.Rank = noteRank
+
+	res, err := srv.DBClient.CreateNote(ctx, note)
+	if err != nil {
+		return "", err
+	}
+
+	srv.Events.Publish(events.Event{Kind: events.KindNote, Action: events.ActionCreated, ID: res, UserID: ownerOf(note)})
+	return res, nil
+}
+
+// createOwnNote создает заметку пользователя в блокноте и с тегами по ID, если они существуют
+func (srv NoteService) createOwnNote(ctx context.Context, userID string, note model.Note, noteBookID string, tagIDs []string) (model.Note, error) {
+	tags := uniqueIDs(tagIDs)
+
+	err := srv.checkPatchRefs(ctx, repository.NotePatch{NoteBookID: &noteBookID, Tags: &tags})
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	now := timezone.Now().String()
+	note.IsDeleted = new(bool)
+	note.IsArchived = new(bool)
+	note.CreatedAt = now
+	note.UpdatedAt = now
+
+	note.UserID, _ = primitive.ObjectIDFromHex(userID)
+	note.NoteBookID, _ = primitive.ObjectIDFromHex(noteBookID)
+	for _, tagID := range tags {
+		id, _ := primitive.ObjectIDFromHex(tagID)
+		note.Tags = append(note.Tags, id)
+	}
+
+	res, err := srv.createNote(ctx, note)
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	return srv.DBClient.GetNoteByID(ctx, res)
+}
+
+func (srv NoteService) deleteOwnNote(ctx context.Context, id, userID string) error {
+	note, err := srv.findOwnNote(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = srv.DBClient.DeleteNote(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	srv.Events.Publish(events.Event{Kind: events.KindNote, Action: events.ActionDeleted, ID: id, UserID: ownerOf(note)})
+	return nil
+}
+
+// findNotes - заметки пользователя и общие заметки по фильтру, теги задаются как в POST /notes/tag
+func (srv NoteService) findNotes(ctx context.Context, userID string, tags dto.NoteTagsRequest, filter repository.NoteFilter) ([]model.Note, error) {
+	tagExpr, err := buildTagExpr(tags)
+	if err != nil {
+		return nil, model.Validation("Wrong tag query: %s", err.Error())
+	}
+
+	if tagExpr != nil {
+		err = srv.resolveTagExpr(ctx, tagExpr, tags.IncludeDescendants)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	filter.Tags = tagExpr
+	notes, err := srv.DBClient.FindNotes(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := []model.Note{}
+	for _, note := range notes {
+		if owner := ownerOf(note); owner == "" || owner == userID {
+			visible = append(visible, note)
+		}
+	}
+
+	return visible, nil
+}
+
+// findOwnNote находит заметку пользователя или общую; чужая заметка не отличается от несуществующей
+func (srv NoteService) findOwnNote(ctx context.Context, id, userID string) (model.Note, error) {
+	note, err := srv.DBClient.GetNoteByID(ctx, id)
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	if owner := ownerOf(note); owner != "" && owner != userID {
+		return model.Note{}, errNoteNotFound
+	}
+
+	return note, nil
+}
+
+func (srv NoteService) nextRank(ctx context.Context, noteBookID primitive.ObjectID) (string, error) {
+	id := ""
+	if !noteBookID.IsZero() {
+		id = noteBookID.Hex()
+	}
+
+	last, err := srv.DBClient.GetLastNoteRank(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	return rank.After(last)
+}
+
+// applyTemplate заполняет пустые поля заметки из шаблона пользователя
+func (srv NoteService) applyTemplate(ctx context.Context, note *model.Note, template model.Template, userID string, now time.Time) error {
+	user, err := srv.HelperUserClient.GetProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	userName := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if userName == "" {
+		userName = user.Email
+	}
+
+	if note.Name == "" {
+		note.Name = renderTemplate(template.Name, now, userName)
+	}
+	if note.Text == "" {
+		note.Text = renderTemplate(template.Text, now, userName)
+	}
+	if note.Color == "" {
+		note.Color = template.Color
+	}
+	if note.NoteBookID.IsZero() {
+		note.NoteBookID = template.NoteBookID
+	}
+	note.Tags = template.Tags
+
+	return nil
+}
+
+func (srv NoteService) findOwnTemplate(ctx context.Context, templateID, userID string) (model.Template, error) {
+	template, err := srv.HelperTemplateClient.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return model.Template{}, err
+	}
+
+	if template.UserID.Hex() != userID {
+		return model.Template{}, errTemplateNotFound
+	}
+
+	return template, nil
+}
+
+func uniqueIDs(ids []string) []string {
+	unique := []string{}
+	for _, id := range ids {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
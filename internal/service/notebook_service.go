@@ -1,46 +1,43 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
 	"github.com/LoL-KeKovich/NoteVault/internal/repository"
 	"github.com/go-chi/chi"
 )
 
+var (
+	errNoteBookNotFound       = model.NotFound("Notebook not found")
+	errTargetNoteBookNotFound = model.Validation("Wrong target notebook id")
+	errNoteBookNotEmpty       = model.Conflict("Notebook is not empty")
+)
+
 type NoteBookService struct {
 	DBClient         repository.NoteBookRepo
 	HelperNoteClient repository.NoteRepo
+	TxClient         repository.UnitOfWork
+	Events           *events.Bus
 }
 
 func (srv NoteBookService) HandleCreateNoteBook(w http.ResponseWriter, r *http.Request) {
 	response := dto.NoteBookResponse{}
 	var noteBookReq dto.NoteBookRequest
 
-	err := json.NewDecoder(r.Body).Decode(&noteBookReq)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Wrong request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if !decodeRequest(w, r, &noteBookReq) {
 		return
 	}
 
-	noteBook := model.NoteBook{
-		Name:        noteBookReq.Name,
-		Description: noteBookReq.Description,
-		IsActive:    noteBookReq.IsActive,
-	}
-
-	res, err := srv.DBClient.CreateNoteBook(noteBook)
+	res, err := srv.createNoteBook(r.Context(), noteBookReq)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error inserting notebook in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error inserting notebook in db")
 		return
 	}
 
@@ -54,19 +51,13 @@ func (srv NoteBookService) HandleGetNoteBookByID(w http.ResponseWriter, r *http.
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	noteBook, err := srv.DBClient.GetNoteBookByID(id)
+	noteBook, err := srv.DBClient.GetNoteBookByID(r.Context(), id)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error finding notebook in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error finding notebook in db")
 		return
 	}
 
@@ -78,12 +69,9 @@ func (srv NoteBookService) HandleGetNoteBookByID(w http.ResponseWriter, r *http.
 func (srv NoteBookService) HandleGetNoteBooks(w http.ResponseWriter, r *http.Request) {
 	response := dto.NoteBookResponse{}
 
-	noteBooks, err := srv.DBClient.GetNoteBooks()
+	noteBooks, err := srv.DBClient.GetNoteBooks(r.Context())
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error finding notebooks in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error finding notebooks in db")
 		return
 	}
 
@@ -98,28 +86,17 @@ func (srv NoteBookService) HandleUpdateNoteBook(w http.ResponseWriter, r *http.R
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&noteBookReq)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Wrong request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if !decodePartial(w, r, &noteBookReq) {
 		return
 	}
 
-	res, err := srv.DBClient.UpdateNoteBook(id, noteBookReq.Name, noteBookReq.Description, noteBookReq.IsActive)
+	res, err := srv.updateNoteBook(r.Context(), id, noteBookReq)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error updating notebook in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error updating notebook in db")
 		return
 	}
 
@@ -133,32 +110,132 @@ func (srv NoteBookService) HandleDeleteNoteBook(w http.ResponseWriter, r *http.R
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	_, err := srv.HelperNoteClient.UnlinkNotesFromNoteBook(id)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error unlinking notes from notebook"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = dto.DeleteModeUnlink
+	}
+
+	targetID := r.URL.Query().Get("target_id")
+
+	switch mode {
+	case dto.DeleteModeUnlink, dto.DeleteModeTrash, dto.DeleteModeRestrict:
+	case dto.DeleteModeMove:
+		if targetID == "" || targetID == id {
+			respondInvalid(w, "Wrong target notebook id")
+			return
+		}
+	default:
+		respondInvalid(w, "Wrong delete mode")
 		return
 	}
 
-	res, err := srv.DBClient.DeleteNoteBook(id)
+	res, err := srv.deleteNoteBook(r.Context(), id, mode, targetID)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error deleting notebook in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error deleting notebook in db")
 		return
 	}
 
-	slog.Info("Notebook deleted")
+	slog.Info("Notebook deleted", slog.String("mode", mode))
 	response.Data = res
 	json.NewEncoder(w).Encode(response)
 }
+
+func (srv NoteBookService) createNoteBook(ctx context.Context, noteBookReq dto.NoteBookRequest) (string, error) {
+	noteBook := model.NoteBook{
+		Name:        noteBookReq.Name,
+		Description: noteBookReq.Description,
+		IsActive:    noteBookReq.IsActive,
+	}
+
+	res, err := srv.DBClient.CreateNoteBook(ctx, noteBook)
+	if err != nil {
+		return "", err
+	}
+
+	srv.Events.Publish(events.Event{Kind: events.KindNoteBook, Action: events.ActionCreated, ID: res})
+	return res, nil
+}
+
+func (srv NoteBookService) updateNoteBook(ctx context.Context, id string, noteBookReq dto.NoteBookRequest) (int, error) {
+	res, err := srv.DBClient.UpdateNoteBook(ctx, id, noteBookReq.Name, noteBookReq.Description, noteBookReq.IsActive)
+	if err != nil {
+		return 0, err
+	}
+
+	srv.Events.Publish(events.Event{Kind: events.KindNoteBook, Action: events.ActionUpdated, ID: id})
+	return res, nil
+}
+
+// deleteNoteBook удаляет блокнот и в той же транзакции отвязывает, переносит или отправляет в корзину его заметки
+func (srv NoteBookService) deleteNoteBook(ctx context.Context, id, mode, targetID string) (int, error) {
+	if mode == dto.DeleteModeMove && (targetID == "" || targetID == id) {
+		return 0, model.Validation("Wrong target notebook id")
+	}
+
+	//Заметки ищутся до удаления: после него их уже не связать с блокнотом
+	var notes []model.Note
+	if srv.Events.Active() && mode != dto.DeleteModeRestrict {
+		notes, _ = srv.HelperNoteClient.FindNotes(ctx, repository.NoteFilter{NoteBookID: id, Status: repository.NoteStatusAll})
+	}
+
+	var res int
+
+	err := srv.TxClient.Do(ctx, func(tx repository.Tx) error {
+		_, err := tx.NoteBooks.GetNoteBookByID(ctx, id)
+		if errors.Is(err, model.ErrNotFound) {
+			return errNoteBookNotFound
+		} else if err != nil {
+			return err
+		}
+
+		switch mode {
+		case dto.DeleteModeUnlink:
+			_, err = tx.Notes.UnlinkNotesFromNoteBook(ctx, id)
+		case dto.DeleteModeMove:
+			_, err = tx.NoteBooks.GetNoteBookByID(ctx, targetID)
+			if errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrInvalidID) {
+				return errTargetNoteBookNotFound
+			} else if err != nil {
+				return err
+			}
+			_, err = tx.Notes.MoveNotesToNoteBook(ctx, id, targetID)
+		case dto.DeleteModeTrash:
+			_, err = tx.Notes.TrashNotesFromNoteBook(ctx, id)
+		case dto.DeleteModeRestrict:
+			var count int
+			count, err = tx.Notes.CountNotesByNoteBookID(ctx, id)
+			if err == nil && count > 0 {
+				return errNoteBookNotEmpty
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		//Шаблоны не должны создавать заметки в удаленном блокноте
+		_, err = tx.Templates.UnlinkTemplatesFromNoteBook(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		res, err = tx.NoteBooks.DeleteNoteBook(ctx, id)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	srv.Events.Publish(events.Event{Kind: events.KindNoteBook, Action: events.ActionDeleted, ID: id})
+
+	action := events.ActionUpdated
+	if mode == dto.DeleteModeTrash {
+		action = events.ActionTrashed
+	}
+	publishNoteChanges(srv.Events, action, notes)
+
+	return res, nil
+}
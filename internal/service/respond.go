@@ -0,0 +1,165 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/lib/validate"
+	"google.golang.org/grpc/codes"
+)
+
+const maxRequestSize = 1 << 20
+
+// statusClientClosedRequest - нестандартный статус nginx для запросов, которые клиент отменил сам
+const statusClientClosedRequest = 499
+
+// errorStatuses - коды ошибок по видам для HTTP API, GraphQL и gRPC
+var errorStatuses = []struct {
+	kind   error
+	status int
+	code   string
+	rpc    codes.Code
+}{
+	{model.ErrInvalidID, http.StatusBadRequest, dto.ErrorCodeInvalidID, codes.InvalidArgument},
+	{model.ErrValidation, http.StatusBadRequest, dto.ErrorCodeValidation, codes.InvalidArgument},
+	{model.ErrUnauthorized, http.StatusUnauthorized, dto.ErrorCodeUnauthorized, codes.Unauthenticated},
+	{model.ErrNotFound, http.StatusNotFound, dto.ErrorCodeNotFound, codes.NotFound},
+	{model.ErrConflict, http.StatusConflict, dto.ErrorCodeConflict, codes.FailedPrecondition},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, dto.ErrorCodeTimeout, codes.DeadlineExceeded},
+	{context.Canceled, statusClientClosedRequest, dto.ErrorCodeCanceled, codes.Canceled},
+}
+
+// respondError отвечает клиенту ошибкой. Ошибки из model уходят со своим текстом и статусом,
+// остальные считаются внутренними: клиент получает 500 и message, а подробности остаются в логе
+func respondError(w http.ResponseWriter, err error, message string) {
+	slog.Error(err.Error())
+
+	status := http.StatusInternalServerError
+	response := dto.ErrorResponse{Error: message, Code: dto.ErrorCodeInternal}
+
+	var fieldErrs validate.Errors
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &fieldErrs):
+		status, response.Code = http.StatusUnprocessableEntity, dto.ErrorCodeInvalidFields
+		response.Error = "Validation failed"
+		for _, fe := range fieldErrs {
+			response.Fields = append(response.Fields, dto.FieldError{Field: fe.Field, Rule: fe.Rule, Message: fe.Message})
+		}
+	case errors.As(err, &maxBytesErr):
+		status, response.Code = http.StatusRequestEntityTooLarge, dto.ErrorCodeTooLarge
+		response.Error = "Request body is too large"
+	default:
+		for _, s := range errorStatuses {
+			if errors.Is(err, s.kind) {
+				status, response.Code = s.status, s.code
+				response.Error = s.kind.Error()
+				var modelErr *model.Error
+				if errors.As(err, &modelErr) {
+					response.Error = modelErr.Message
+				}
+				break
+			}
+		}
+	}
+
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// decodeRequest читает тело запроса в v и проверяет его правилами из тегов validate.
+// Неизвестные поля и тело больше maxRequestSize отклоняются. При ошибке ответ уже отправлен
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	return decode(w, r, v, validate.Struct)
+}
+
+// decodePartial - decodeRequest для обновлений, где пустое поле означает "не менять"
+func decodePartial(w http.ResponseWriter, r *http.Request, v any) bool {
+	return decode(w, r, v, validate.Partial)
+}
+
+// decodeMergePatch читает тело JSON Merge Patch (RFC 7396) в v и возвращает поля, переданные как null:
+// json.Unmarshal не отличает null от отсутствующего поля, а в merge patch null означает "очистить"
+func decodeMergePatch(w http.ResponseWriter, r *http.Request, v any) (map[string]bool, bool) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "" && mediaType != dto.MergePatchContentType && mediaType != "application/json" {
+		slog.Error("Unsupported media type", slog.String("content_type", mediaType))
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		json.NewEncoder(w).Encode(dto.ErrorResponse{Error: "Expected " + dto.MergePatchContentType, Code: dto.ErrorCodeMediaType})
+		return nil, false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		respondError(w, err, "Error reading request")
+		return nil, false
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(body, &fields)
+	if err != nil || fields == nil {
+		respondInvalid(w, "Wrong request: merge patch must be a JSON object")
+		return nil, false
+	}
+
+	nulls := map[string]bool{}
+	for name, value := range fields {
+		if string(value) == "null" {
+			nulls[name] = true
+		}
+	}
+
+	if !decodeBody(w, bytes.NewReader(body), v, validate.Partial) {
+		return nil, false
+	}
+
+	return nulls, true
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any, check func(any) error) bool {
+	return decodeBody(w, http.MaxBytesReader(w, r.Body, maxRequestSize), v, check)
+}
+
+func decodeBody(w http.ResponseWriter, body io.Reader, v any, check func(any) error) bool {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err == nil && decoder.More() {
+		err = errors.New("unexpected data after JSON body")
+	}
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondError(w, err, "")
+		} else {
+			respondInvalid(w, "Wrong request: "+err.Error())
+		}
+		return false
+	}
+
+	err = check(v)
+	if err != nil {
+		respondError(w, err, "")
+		return false
+	}
+
+	return true
+}
+
+// respondInvalid отвечает 400 на запрос, который не прошел проверку в обработчике
+func respondInvalid(w http.ResponseWriter, message string) {
+	respondError(w, model.Validation("%s", message), "")
+}
+
+func respondUnauthorized(w http.ResponseWriter) {
+	respondError(w, model.Unauthorized("Authorization required"), "")
+}
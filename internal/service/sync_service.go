@@ -0,0 +1,596 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/timezone"
+	"github.com/LoL-KeKovich/NoteVault/lib/validate"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// syncPageSize - сколько изменений отдается за один запрос; остальные клиент дочитывает по has_more
+const syncPageSize = 500
+
+// syncPushMu выстраивает отправки изменений в очередь, чтобы проверка ревизии и запись не перемежались
+// с другой отправкой. Изменения через остальные маршруты API идут мимо очереди и видны как конфликт
+// только после записи в журнал
+var syncPushMu sync.Mutex
+
+type SyncService struct {
+	DBClient  repository.SyncRepo
+	Recorder  *SyncRecorder
+	Notes     NoteService
+	NoteBooks NoteBookService
+	Tags      TagService
+}
+
+// Задержка повтора записи в журнал синхронизации после ошибки базы
+const (
+	syncRetryDelay    = 100 * time.Millisecond
+	syncMaxRetryDelay = 10 * time.Second
+)
+
+// SyncRecorder пишет журнал синхронизации в порядке событий шины. Record только ставит изменение
+// в очередь, поэтому Publish не ждет базу под блокировкой шины; записи делает одна фоновая горутина,
+// пока очередь не опустеет. Изменение, которое не удалось записать, не теряется: запись повторяется,
+// а Flush до ее успеха возвращает ошибку
+type SyncRecorder struct {
+	DBClient repository.SyncRepo
+
+	mu       sync.Mutex
+	queue    []model.SyncChange
+	queued   int64
+	written  int64
+	running  bool
+	err      error
+	progress chan struct{}
+}
+
+// Record - слушатель шины: каждое событие переносит сущность в конец журнала синхронизации
+func (r *SyncRecorder) Record(e events.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.queue = append(r.queue, model.SyncChange{
+		Kind:     e.Kind,
+		EntityID: e.ID,
+		UserID:   e.UserID,
+		Deleted:  e.Action == events.ActionDeleted,
+	})
+	r.queued++
+
+	if !r.running {
+		r.running = true
+		go r.drain()
+	}
+}
+
+func (r *SyncRecorder) drain() {
+	delay := syncRetryDelay
+
+	for {
+		r.mu.Lock()
+		if len(r.queue) == 0 {
+			r.running = false
+			r.mu.Unlock()
+			return
+		}
+		change := r.queue[0]
+		r.mu.Unlock()
+
+		_, err := r.DBClient.RecordChange(context.Background(), change)
+
+		r.mu.Lock()
+		r.err = err
+		if err == nil {
+			r.queue = r.queue[1:]
+			r.written++
+			delay = syncRetryDelay
+		}
+		r.notify()
+		r.mu.Unlock()
+
+		if err != nil {
+			slog.Error("Error recording sync change", slog.String("kind", change.Kind), slog.String("id", change.EntityID), slog.String("error", err.Error()))
+			time.Sleep(delay)
+			delay = min(delay*2, syncMaxRetryDelay)
+		}
+	}
+}
+
+// notify будит ожидающих Flush, вызывается под r.mu
+func (r *SyncRecorder) notify() {
+	if r.progress != nil {
+		close(r.progress)
+		r.progress = nil
+	}
+}
+
+// Flush ждет, пока в журнал запишутся все изменения, опубликованные до вызова. Ошибка записи
+// возвращается сразу, а не после всех повторов: журнал без изменения отдавать клиенту нельзя
+func (r *SyncRecorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	target := r.queued
+
+	for r.written < target {
+		if r.err != nil {
+			err := r.err
+			r.mu.Unlock()
+			return err
+		}
+
+		if r.progress == nil {
+			r.progress = make(chan struct{})
+		}
+		progress := r.progress
+		r.mu.Unlock()
+
+		select {
+		case <-progress:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		r.mu.Lock()
+	}
+
+	r.mu.Unlock()
+
+	return nil
+}
+
+// HandlePull отдает изменения после token. Без token или с token из другой базы отдаются все данные пользователя
+func (srv SyncService) HandlePull(w http.ResponseWriter, r *http.Request) {
+	response := dto.SyncResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	err := srv.Recorder.Flush(r.Context())
+	if err != nil {
+		respondError(w, err, "Error reading sync log")
+		return
+	}
+
+	last, err := srv.DBClient.GetLastSyncSeq(r.Context())
+	if err != nil {
+		respondError(w, err, "Error reading sync log")
+		return
+	}
+
+	var since int64
+	token := r.URL.Query().Get("token")
+	if token != "" {
+		since, err = strconv.ParseInt(token, 10, 64)
+		if err != nil || since < 0 {
+			respondInvalid(w, "Wrong sync token")
+			return
+		}
+	}
+
+	pull := dto.SyncPull{
+		Notes:     []dto.SyncNote{},
+		NoteBooks: []dto.SyncNoteBook{},
+		Tags:      []dto.SyncTag{},
+		Deleted:   []dto.SyncTombstone{},
+	}
+
+	if token == "" || since > last {
+		err = srv.snapshot(r.Context(), userID, last, &pull)
+	} else {
+		err = srv.delta(r.Context(), userID, since, &pull)
+	}
+	if err != nil {
+		respondError(w, err, "Error reading changes")
+		return
+	}
+
+	slog.Info("Sync pulled", slog.String("user_id", userID), slog.String("token", pull.Token), slog.Bool("full", pull.Full))
+	response.Data = pull
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandlePush применяет изменения клиента по порядку. Ошибка одного изменения не отменяет остальные:
+// результат каждого возвращается отдельно
+func (srv SyncService) HandlePush(w http.ResponseWriter, r *http.Request) {
+	response := dto.SyncResponse{}
+	var pushReq dto.SyncPushRequest
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	if !decodeRequest(w, r, &pushReq) {
+		return
+	}
+
+	syncPushMu.Lock()
+	defer syncPushMu.Unlock()
+
+	//Ревизии проверяются по журналу, поэтому в нем должны быть все изменения до отправки
+	err := srv.Recorder.Flush(r.Context())
+	if err != nil {
+		respondError(w, err, "Error reading sync log")
+		return
+	}
+
+	results := make([]dto.SyncResult, 0, len(pushReq.Changes))
+	for _, change := range pushReq.Changes {
+		results = append(results, srv.push(r.Context(), userID, change))
+	}
+
+	slog.Info("Sync pushed", slog.String("user_id", userID), slog.Int("changes", len(results)))
+	response.Data = results
+	json.NewEncoder(w).Encode(response)
+}
+
+// snapshot - все данные пользователя. Token читается до данных: изменения, сделанные во время чтения,
+// клиент получит еще раз при следующем запросе
+func (srv SyncService) snapshot(ctx context.Context, userID string, last int64, pull *dto.SyncPull) error {
+	pull.Token = strconv.FormatInt(last, 10)
+	pull.Full = true
+
+	changes, err := srv.DBClient.GetChanges(ctx, userID, 0, math.MaxInt)
+	if err != nil {
+		return err
+	}
+
+	revs := make(map[string]int64, len(changes))
+	for _, change := range changes {
+		revs[change.Kind+"/"+change.EntityID] = change.Seq
+	}
+
+	err = srv.Notes.DBClient.StreamNotes(ctx, userID, func(note model.Note) error {
+		pull.Notes = append(pull.Notes, dto.SyncNote{Note: note, Rev: revs[events.KindNote+"/"+note.ID.Hex()]})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	noteBooks, err := srv.NoteBooks.DBClient.GetNoteBooks(ctx)
+	if err != nil {
+		return err
+	}
+	for _, noteBook := range noteBooks {
+		pull.NoteBooks = append(pull.NoteBooks, dto.SyncNoteBook{NoteBook: noteBook, Rev: revs[events.KindNoteBook+"/"+noteBook.ID.Hex()]})
+	}
+
+	tags, err := srv.Tags.DBClient.GetTags(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		pull.Tags = append(pull.Tags, dto.SyncTag{Tag: tag, Rev: revs[events.KindTag+"/"+tag.ID.Hex()]})
+	}
+
+	return nil
+}
+
+// delta - сущности, измененные после since, в их текущем состоянии. Удаленные сущности приходят в deleted
+func (srv SyncService) delta(ctx context.Context, userID string, since int64, pull *dto.SyncPull) error {
+	changes, err := srv.DBClient.GetChanges(ctx, userID, since, syncPageSize+1)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) > syncPageSize {
+		changes = changes[:syncPageSize]
+		pull.HasMore = true
+	}
+
+	pull.Token = strconv.FormatInt(since, 10)
+
+	for _, change := range changes {
+		pull.Token = strconv.FormatInt(change.Seq, 10)
+
+		var entity dto.SyncResult
+
+		if !change.Deleted {
+			err = srv.load(ctx, change.Kind, change.EntityID, change.Seq, &entity)
+			if err != nil && !errors.Is(err, model.ErrNotFound) {
+				return err
+			}
+		}
+
+		switch {
+		case entity.Note != nil:
+			pull.Notes = append(pull.Notes, *entity.Note)
+		case entity.NoteBook != nil:
+			pull.NoteBooks = append(pull.NoteBooks, *entity.NoteBook)
+		case entity.Tag != nil:
+			pull.Tags = append(pull.Tags, *entity.Tag)
+		default:
+			pull.Deleted = append(pull.Deleted, dto.SyncTombstone{Kind: change.Kind, ID: change.EntityID, Rev: change.Seq})
+		}
+	}
+
+	return nil
+}
+
+// load кладет в result текущее состояние сущности
+func (srv SyncService) load(ctx context.Context, kind, id string, rev int64, result *dto.SyncResult) error {
+	switch kind {
+	case events.KindNote:
+		note, err := srv.Notes.DBClient.GetNoteByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		result.Note = &dto.SyncNote{Note: note, Rev: rev}
+	case events.KindNoteBook:
+		noteBook, err := srv.NoteBooks.DBClient.GetNoteBookByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		result.NoteBook = &dto.SyncNoteBook{NoteBook: noteBook, Rev: rev}
+	case events.KindTag:
+		tag, err := srv.Tags.DBClient.GetTagByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		result.Tag = &dto.SyncTag{Tag: tag, Rev: rev}
+	default:
+		return model.NotFound("Unknown kind %s", kind)
+	}
+
+	return nil
+}
+
+// push применяет одно изменение. Update и delete принимаются, только если с ревизии клиента сущность
+// на сервере не менялась; иначе клиент получает конфликт и текущее состояние сущности
+func (srv SyncService) push(ctx context.Context, userID string, change dto.SyncChange) dto.SyncResult {
+	result := dto.SyncResult{Kind: change.Kind, ID: change.ID, ClientID: change.ClientID, Status: dto.SyncStatusOK}
+
+	if !slices.Contains([]string{events.KindNote, events.KindNoteBook, events.KindTag}, change.Kind) {
+		result.Status, result.Error = syncStatus(model.Validation("Wrong kind"))
+		return result
+	}
+	if !slices.Contains([]string{dto.SyncOpCreate, dto.SyncOpUpdate, dto.SyncOpDelete}, change.Op) {
+		result.Status, result.Error = syncStatus(model.Validation("Wrong op"))
+		return result
+	}
+
+	if change.Op != dto.SyncOpCreate {
+		current, err := srv.DBClient.GetChange(ctx, change.Kind, change.ID)
+		if err != nil && !errors.Is(err, model.ErrNotFound) {
+			result.Status, result.Error = syncStatus(err)
+			return result
+		}
+
+		switch {
+		case current.UserID != "" && current.UserID != userID:
+			result.Status, result.Error = syncStatus(errNoteNotFound)
+			return result
+		case current.Deleted:
+			//Повторное удаление уже удаленной сущности не конфликтует
+			result.Rev, result.Deleted = current.Seq, true
+			if change.Op != dto.SyncOpDelete {
+				result.Status = dto.SyncStatusConflict
+			}
+			return result
+		case current.Seq > change.Rev:
+			result.Status, result.Rev = dto.SyncStatusConflict, current.Seq
+			err = srv.load(ctx, change.Kind, change.ID, current.Seq, &result)
+			if err != nil {
+				result.Status, result.Error = syncStatus(err)
+			}
+			return result
+		}
+	}
+
+	var id string
+	var err error
+
+	switch change.Kind {
+	case events.KindNote:
+		id, err = srv.pushNote(ctx, userID, change)
+	case events.KindNoteBook:
+		id, err = srv.pushNoteBook(ctx, change)
+	case events.KindTag:
+		id, err = srv.pushTag(ctx, change)
+	}
+	if err != nil {
+		result.Status, result.Error = syncStatus(err)
+		return result
+	}
+
+	result.ID = id
+	result.Deleted = change.Op == dto.SyncOpDelete
+
+	err = srv.Recorder.Flush(ctx)
+	if err != nil {
+		result.Status, result.Error = syncStatus(err)
+		return result
+	}
+
+	current, err := srv.DBClient.GetChange(ctx, change.Kind, id)
+	if err == nil {
+		result.Rev = current.Seq
+	}
+
+	return result
+}
+
+func (srv SyncService) pushNote(ctx context.Context, userID string, change dto.SyncChange) (string, error) {
+	notes := srv.Notes
+
+	if change.Op == dto.SyncOpDelete {
+		note, err := notes.findOwnNote(ctx, change.ID, userID)
+		if err != nil {
+			return "", err
+		}
+
+		_, err = notes.DBClient.DeleteNote(ctx, change.ID)
+		if err != nil {
+			return "", err
+		}
+
+		notes.Events.Publish(events.Event{Kind: events.KindNote, Action: events.ActionDeleted, ID: change.ID, UserID: ownerOf(note)})
+		return change.ID, nil
+	}
+
+	data := change.Note
+	if data == nil {
+		return "", model.Validation("No note data")
+	}
+
+	err := validate.Struct(data)
+	if err != nil {
+		return "", err
+	}
+
+	tags := []string{}
+	for _, tagID := range data.Tags {
+		if !slices.Contains(tags, tagID) {
+			tags = append(tags, tagID)
+		}
+	}
+
+	now := timezone.Now().String()
+	patch := repository.NotePatch{
+		Name:       &data.Name,
+		Text:       &data.Text,
+		Color:      &data.Color,
+		NoteBookID: &data.NoteBookID,
+		Tags:       &tags,
+		UpdatedAt:  now,
+	}
+
+	err = notes.checkPatchRefs(ctx, patch)
+	if err != nil {
+		return "", err
+	}
+
+	if change.Op == dto.SyncOpCreate {
+		return srv.createNote(ctx, userID, patch, data)
+	}
+
+	note, err := notes.findOwnNote(ctx, change.ID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	err = notes.updateNote(ctx, note, patch, noteFlags{
+		IsDeleted:   data.IsDeleted,
+		IsArchived:  data.IsArchived,
+		IsPinned:    data.IsPinned,
+		IsFavourite: data.IsFavourite,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return change.ID, nil
+}
+
+func (srv SyncService) createNote(ctx context.Context, userID string, patch repository.NotePatch, data *dto.SyncNoteData) (string, error) {
+	note := model.Note{
+		Name:        data.Name,
+		Text:        data.Text,
+		Color:       data.Color,
+		IsDeleted:   boolValue(data.IsDeleted),
+		IsArchived:  boolValue(data.IsArchived),
+		IsPinned:    data.IsPinned,
+		IsFavourite: data.IsFavourite,
+		CreatedAt:   patch.UpdatedAt,
+		UpdatedAt:   patch.UpdatedAt,
+	}
+
+	note.UserID, _ = primitive.ObjectIDFromHex(userID)
+
+	if data.NoteBookID != "" {
+		note.NoteBookID, _ = primitive.ObjectIDFromHex(data.NoteBookID)
+	}
+	for _, tagID := range *patch.Tags {
+		id, _ := primitive.ObjectIDFromHex(tagID)
+		note.Tags = append(note.Tags, id)
+	}
+
+	return srv.Notes.createNote(ctx, note)
+}
+
+func (srv SyncService) pushNoteBook(ctx context.Context, change dto.SyncChange) (string, error) {
+	if change.Op == dto.SyncOpDelete {
+		_, err := srv.NoteBooks.deleteNoteBook(ctx, change.ID, dto.DeleteModeUnlink, "")
+		return change.ID, err
+	}
+
+	if change.NoteBook == nil {
+		return "", model.Validation("No notebook data")
+	}
+
+	err := validate.Struct(change.NoteBook)
+	if err != nil {
+		return "", err
+	}
+
+	if change.Op == dto.SyncOpCreate {
+		return srv.NoteBooks.createNoteBook(ctx, *change.NoteBook)
+	}
+
+	_, err = srv.NoteBooks.updateNoteBook(ctx, change.ID, *change.NoteBook)
+	return change.ID, err
+}
+
+func (srv SyncService) pushTag(ctx context.Context, change dto.SyncChange) (string, error) {
+	if change.Op == dto.SyncOpDelete {
+		_, err := srv.Tags.deleteTag(ctx, change.ID)
+		return change.ID, err
+	}
+
+	if change.Tag == nil {
+		return "", model.Validation("No tag data")
+	}
+
+	err := validate.Struct(change.Tag)
+	if err != nil {
+		return "", err
+	}
+
+	if change.Op == dto.SyncOpCreate {
+		return srv.Tags.createTag(ctx, *change.Tag)
+	}
+
+	_, err = srv.Tags.updateTag(ctx, change.ID, *change.Tag)
+	return change.ID, err
+}
+
+// syncStatus переводит ошибку изменения в статус результата; внутренние ошибки остаются в логе
+func syncStatus(err error) (string, string) {
+	var fieldErrs validate.Errors
+
+	switch {
+	case errors.As(err, &fieldErrs), errors.Is(err, model.ErrValidation), errors.Is(err, model.ErrInvalidID):
+		return dto.SyncStatusInvalid, err.Error()
+	case errors.Is(err, model.ErrNotFound):
+		return dto.SyncStatusNotFound, err.Error()
+	case errors.Is(err, model.ErrConflict):
+		return dto.SyncStatusConflict, err.Error()
+	}
+
+	slog.Error("Error applying sync change", slog.String("error", err.Error()))
+	return dto.SyncStatusFailed, "Internal error"
+}
+
+func boolValue(b *bool) *bool {
+	value := b != nil && *b
+	return &value
+}
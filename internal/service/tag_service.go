@@ -1,45 +1,46 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
 	"github.com/LoL-KeKovich/NoteVault/internal/model"
 	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/lib/tagquery"
 	"github.com/go-chi/chi"
 )
 
+var (
+	errTagNotFound         = model.NotFound("Tag not found")
+	errTargetTagNotFound   = model.Validation("Wrong target tag id")
+	errWrongTagName        = model.Validation("Wrong tag name")
+	errMergeIntoDescendant = model.Validation("Cannot merge a tag into its descendant")
+)
+
 type TagService struct {
 	DBClient         repository.TagRepo
 	HelperNoteClient repository.NoteRepo
+	TxClient         repository.UnitOfWork
+	Events           *events.Bus
 }
 
 func (srv TagService) HandleCreateTag(w http.ResponseWriter, r *http.Request) {
 	response := dto.TagResponse{}
 	var tagReq dto.TagRequest
 
-	err := json.NewDecoder(r.Body).Decode(&tagReq)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Wrong request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if !decodeRequest(w, r, &tagReq) {
 		return
 	}
 
-	tag := model.Tag{
-		Name:  tagReq.Name,
-		Color: tagReq.Color,
-	}
-
-	res, err := srv.DBClient.CreateTag(tag)
+	res, err := srv.createTag(r.Context(), tagReq)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error inserting tag in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error inserting tag in db")
 		return
 	}
 
@@ -53,19 +54,13 @@ func (srv TagService) HandleGetTagByID(w http.ResponseWriter, r *http.Request) {
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	tag, err := srv.DBClient.GetTagByID(id)
+	tag, err := srv.DBClient.GetTagByID(r.Context(), id)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error finding tag in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error finding tag in db")
 		return
 	}
 
@@ -77,17 +72,32 @@ func (srv TagService) HandleGetTagByID(w http.ResponseWriter, r *http.Request) {
 func (srv TagService) HandleGetTags(w http.ResponseWriter, r *http.Request) {
 	response := dto.TagResponse{}
 
-	tags, err := srv.DBClient.GetTags()
+	tags, err := srv.DBClient.GetTags(r.Context())
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error finding tags in db"
-		w.WriteHeader(http.StatusInternalServerError)
+		respondError(w, err, "Error finding tags in db")
+		return
+	}
+
+	if r.URL.Query().Get("with_counts") != "true" {
+		slog.Info("Tags found")
+		response.Data = tags
 		json.NewEncoder(w).Encode(response)
 		return
 	}
 
-	slog.Info("Tags found")
-	response.Data = tags
+	counts, err := srv.HelperNoteClient.CountNotesByTags(r.Context())
+	if err != nil {
+		respondError(w, err, "Error counting notes by tags")
+		return
+	}
+
+	usage := make([]dto.TagUsage, 0, len(tags))
+	for _, tag := range tags {
+		usage = append(usage, dto.TagUsage{Tag: tag, NoteCount: counts[tag.ID.Hex()]})
+	}
+
+	slog.Info("Tags with counts found")
+	response.Data = usage
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -97,28 +107,17 @@ func (srv TagService) HandleUpdateTag(w http.ResponseWriter, r *http.Request) {
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&tagReq)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Wrong request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if !decodePartial(w, r, &tagReq) {
 		return
 	}
 
-	res, err := srv.DBClient.UpdateTag(id, tagReq.Name, tagReq.Color)
+	res, err := srv.updateTag(r.Context(), id, tagReq)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error updating tag in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error updating tag in db")
 		return
 	}
 
@@ -127,42 +126,48 @@ func (srv TagService) HandleUpdateTag(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-func (srv TagService) HandleDeleteTag(w http.ResponseWriter, r *http.Request) {
+func (srv TagService) HandleMergeTag(w http.ResponseWriter, r *http.Request) {
 	response := dto.TagResponse{}
+	var mergeReq dto.TagMergeRequest
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		slog.Error("Empty id field")
-		response.Error = "Wrong id"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respondInvalid(w, "Wrong id")
 		return
 	}
 
-	tag, err := srv.DBClient.GetTagByID(id)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Tag not found"
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(response)
+	if !decodeRequest(w, r, &mergeReq) {
 		return
 	}
 
-	_, err = srv.HelperNoteClient.UnlinkNotesFromTag(tag.Name)
+	if mergeReq.TargetID == "" || mergeReq.TargetID == id {
+		respondInvalid(w, "Wrong target tag id")
+		return
+	}
+
+	res, err := srv.mergeTag(r.Context(), id, mergeReq.TargetID)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error unlinking notes from tag"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error merging tags in db")
 		return
 	}
 
-	res, err := srv.DBClient.DeleteTag(id)
+	slog.Info("Tags merged", slog.String("from", id), slog.String("to", mergeReq.TargetID))
+	response.Data = res
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv TagService) HandleDeleteTag(w http.ResponseWriter, r *http.Request) {
+	response := dto.TagResponse{}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	res, err := srv.deleteTag(r.Context(), id)
 	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Error deleting tag in db"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Error deleting tag in db")
 		return
 	}
 
@@ -170,3 +175,216 @@ func (srv TagService) HandleDeleteTag(w http.ResponseWriter, r *http.Request) {
 	response.Data = res
 	json.NewEncoder(w).Encode(response)
 }
+
+func (srv TagService) createTag(ctx context.Context, tagReq dto.TagRequest) (string, error) {
+	if !model.ValidTagName(tagReq.Name) {
+		return "", errWrongTagName
+	}
+
+	tag := model.Tag{
+		Name:  tagReq.Name,
+		Color: tagReq.Color,
+	}
+
+	res, err := srv.DBClient.CreateTag(ctx, tag)
+	if err != nil {
+		return "", err
+	}
+
+	srv.Events.Publish(events.Event{Kind: events.KindTag, Action: events.ActionCreated, ID: res})
+	return res, nil
+}
+
+// updateTag меняет тег. Заметки ссылаются на тег по _id, поэтому переименование сразу видно во всех заметках.
+// Вместе с тегом переименовываются его потомки: "work/clientA" -> "job/clientA"
+func (srv TagService) updateTag(ctx context.Context, id string, tagReq dto.TagRequest) (int, error) {
+	if tagReq.Name != "" && !model.ValidTagName(tagReq.Name) {
+		return 0, errWrongTagName
+	}
+
+	var res int
+	var renamed []model.Tag
+
+	err := srv.TxClient.Do(ctx, func(tx repository.Tx) error {
+		tag, err := tx.Tags.GetTagByID(ctx, id)
+		if errors.Is(err, model.ErrNotFound) {
+			return errTagNotFound
+		} else if err != nil {
+			return err
+		}
+
+		res, err = tx.Tags.UpdateTag(ctx, id, tagReq.Name, tagReq.Color)
+		if err != nil {
+			return err
+		}
+
+		if tagReq.Name != "" && tagReq.Name != tag.Name {
+			renamed, err = tx.Tags.GetTagDescendants(ctx, tag.Name)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Tags.RenameTagDescendants(ctx, tag.Name, tagReq.Name)
+		}
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	srv.Events.Publish(events.Event{Kind: events.KindTag, Action: events.ActionUpdated, ID: id})
+	for _, tag := range renamed {
+		srv.Events.Publish(events.Event{Kind: events.KindTag, Action: events.ActionUpdated, ID: tag.ID.Hex()})
+	}
+
+	return res, nil
+}
+
+// mergeTag переносит заметки и шаблоны с тега на целевой и удаляет его. Потомки "source/child" переезжают
+// под целевой тег, а если там уже есть тег с таким именем - сливаются с ним так же
+func (srv TagService) mergeTag(ctx context.Context, id, targetID string) (int, error) {
+	var res int
+	var deleted, renamed []string
+	var notes []model.Note
+
+	err := srv.TxClient.Do(ctx, func(tx repository.Tx) error {
+		//Транзакция может повториться - собираем результат заново
+		res, deleted, renamed, notes = 0, nil, nil, nil
+
+		source, err := tx.Tags.GetTagByID(ctx, id)
+		if errors.Is(err, model.ErrNotFound) {
+			return errTagNotFound
+		} else if err != nil {
+			return err
+		}
+
+		target, err := tx.Tags.GetTagByID(ctx, targetID)
+		if errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrInvalidID) {
+			return errTargetTagNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(target.Name, source.Name+model.TagPathSeparator) {
+			return errMergeIntoDescendant
+		}
+
+		descendants, err := tx.Tags.GetTagDescendants(ctx, source.Name)
+		if err != nil {
+			return err
+		}
+
+		existing, err := tx.Tags.GetTagDescendants(ctx, target.Name)
+		if err != nil {
+			return err
+		}
+
+		//Сам тег и его потомки тоже могут лежать под целевым - они переименовываются, а не сливаются
+		merging := map[string]bool{source.Name: true}
+		for _, tag := range descendants {
+			merging[tag.Name] = true
+		}
+		byName := make(map[string]string, len(existing))
+		for _, tag := range existing {
+			if !merging[tag.Name] {
+				byName[tag.Name] = tag.ID.Hex()
+			}
+		}
+
+		merges := []struct{ from, to string }{{id, targetID}}
+		for _, tag := range descendants {
+			if to, ok := byName[target.Name+strings.TrimPrefix(tag.Name, source.Name)]; ok {
+				merges = append(merges, struct{ from, to string }{tag.ID.Hex(), to})
+			} else {
+				renamed = append(renamed, tag.ID.Hex())
+			}
+		}
+
+		for _, merge := range merges {
+			notes = append(notes, srv.taggedNotes(ctx, tx.Notes, merge.from)...)
+
+			n, err := tx.Notes.ReplaceTagInNotes(ctx, merge.from, merge.to)
+			if err != nil {
+				return err
+			}
+			res += n
+
+			_, err = tx.Templates.ReplaceTagInTemplates(ctx, merge.from, merge.to)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Tags.DeleteTag(ctx, merge.from)
+			if err != nil {
+				return err
+			}
+			deleted = append(deleted, merge.from)
+		}
+
+		_, err = tx.Tags.RenameTagDescendants(ctx, source.Name, target.Name)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range deleted {
+		srv.Events.Publish(events.Event{Kind: events.KindTag, Action: events.ActionDeleted, ID: id})
+	}
+	srv.Events.Publish(events.Event{Kind: events.KindTag, Action: events.ActionUpdated, ID: targetID})
+	for _, id := range renamed {
+		srv.Events.Publish(events.Event{Kind: events.KindTag, Action: events.ActionUpdated, ID: id})
+	}
+	publishNoteChanges(srv.Events, events.ActionUpdated, notes)
+
+	return res, nil
+}
+
+func (srv TagService) deleteTag(ctx context.Context, id string) (int, error) {
+	notes := srv.taggedNotes(ctx, srv.HelperNoteClient, id)
+
+	var res int
+
+	err := srv.TxClient.Do(ctx, func(tx repository.Tx) error {
+		_, err := tx.Tags.GetTagByID(ctx, id)
+		if errors.Is(err, model.ErrNotFound) {
+			return errTagNotFound
+		} else if err != nil {
+			return err
+		}
+
+		_, err = tx.Notes.UnlinkNotesFromTag(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Templates.UnlinkTemplatesFromTag(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		res, err = tx.Tags.DeleteTag(ctx, id)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	srv.Events.Publish(events.Event{Kind: events.KindTag, Action: events.ActionDeleted, ID: id})
+	publishNoteChanges(srv.Events, events.ActionUntagged, notes)
+
+	return res, nil
+}
+
+// taggedNotes находит заметки с тегом до его удаления или слияния, чтобы разослать события и по ним
+func (srv TagService) taggedNotes(ctx context.Context, noteRepo repository.NoteRepo, id string) []model.Note {
+	if !srv.Events.Active() {
+		return nil
+	}
+
+	notes, _ := noteRepo.FindNotes(ctx, repository.NoteFilter{
+		Tags:   &tagquery.Tag{ID: id, IDs: []string{id}},
+		Status: repository.NoteStatusAll,
+	})
+
+	return notes
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/go-chi/chi"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	errTemplateNotFound = model.NotFound("Template not found")
+	errWrongNoteBook    = errors.New("wrong notebook id")
+	errWrongTags        = errors.New("wrong tag ids")
+)
+
+type TemplateService struct {
+	DBClient             repository.TemplateRepo
+	HelperNoteBookClient repository.NoteBookRepo
+	HelperTagClient      repository.TagRepo
+}
+
+func (srv TemplateService) HandleCreateTemplate(w http.ResponseWriter, r *http.Request) {
+	response := dto.TemplateResponse{}
+	var templateReq dto.TemplateRequest
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	if !decodeRequest(w, r, &templateReq) {
+		return
+	}
+
+	err := srv.checkReferences(r.Context(), templateReq)
+	if err != nil {
+		respondInvalid(w, err.Error())
+		return
+	}
+
+	userDocId, _ := primitive.ObjectIDFromHex(userID)
+	noteBookDocId, _ := primitive.ObjectIDFromHex(templateReq.NoteBookID)
+
+	var tagDocIds []primitive.ObjectID
+	for _, tagID := range templateReq.TagIDs {
+		tagDocId, _ := primitive.ObjectIDFromHex(tagID)
+		tagDocIds = append(tagDocIds, tagDocId)
+	}
+
+	template := model.Template{
+		UserID:     userDocId,
+		Name:       templateReq.Name,
+		Text:       templateReq.Text,
+		Color:      templateReq.Color,
+		Tags:       tagDocIds,
+		NoteBookID: noteBookDocId,
+	}
+
+	res, err := srv.DBClient.CreateTemplate(r.Context(), template)
+	if err != nil {
+		respondError(w, err, "Error inserting template in db")
+		return
+	}
+
+	slog.Info("Created template", slog.String("_id", res))
+	response.Data = res
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv TemplateService) HandleGetTemplateByID(w http.ResponseWriter, r *http.Request) {
+	response := dto.TemplateResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	template, err := srv.findOwnTemplate(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, err, "Error finding template in db")
+		return
+	}
+
+	slog.Info("Template found")
+	response.Data = template
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv TemplateService) HandleGetTemplates(w http.ResponseWriter, r *http.Request) {
+	response := dto.TemplateResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	templates, err := srv.DBClient.GetTemplatesByUserID(r.Context(), userID)
+	if err != nil {
+		respondError(w, err, "Error finding templates in db")
+		return
+	}
+
+	slog.Info("Templates found")
+	response.Data = templates
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv TemplateService) HandleUpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	response := dto.TemplateResponse{}
+	var templateReq dto.TemplateRequest
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	if !decodePartial(w, r, &templateReq) {
+		return
+	}
+
+	_, err := srv.findOwnTemplate(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, err, "Error finding template in db")
+		return
+	}
+
+	err = srv.checkReferences(r.Context(), templateReq)
+	if err != nil {
+		respondInvalid(w, err.Error())
+		return
+	}
+
+	res, err := srv.DBClient.UpdateTemplate(r.Context(), id, templateReq.Name, templateReq.Text, templateReq.Color,
+		templateReq.NoteBookID, templateReq.TagIDs)
+	if err != nil {
+		respondError(w, err, "Error updating template in db")
+		return
+	}
+
+	slog.Info("Template updated")
+	response.Data = res
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv TemplateService) HandleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	response := dto.TemplateResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	_, err := srv.findOwnTemplate(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, err, "Error finding template in db")
+		return
+	}
+
+	res, err := srv.DBClient.DeleteTemplate(r.Context(), id)
+	if err != nil {
+		respondError(w, err, "Error deleting template in db")
+		return
+	}
+
+	slog.Info("Template deleted")
+	response.Data = res
+	json.NewEncoder(w).Encode(response)
+}
+
+// findOwnTemplate не отличает чужой шаблон от несуществующего
+func (srv TemplateService) findOwnTemplate(ctx context.Context, id, userID string) (model.Template, error) {
+	template, err := srv.DBClient.GetTemplateByID(ctx, id)
+	if err != nil {
+		return model.Template{}, err
+	}
+
+	if template.UserID.Hex() != userID {
+		return model.Template{}, errTemplateNotFound
+	}
+
+	return template, nil
+}
+
+func (srv TemplateService) checkReferences(ctx context.Context, templateReq dto.TemplateRequest) error {
+	if templateReq.NoteBookID != "" {
+		_, err := srv.HelperNoteBookClient.GetNoteBookByID(ctx, templateReq.NoteBookID)
+		if err != nil {
+			return errWrongNoteBook
+		}
+	}
+
+	if len(templateReq.TagIDs) > 0 {
+		tags, err := srv.HelperTagClient.GetTagsByIDs(ctx, templateReq.TagIDs)
+		if err != nil || len(tags) != len(templateReq.TagIDs) {
+			return errWrongTags
+		}
+	}
+
+	return nil
+}
+
+// renderTemplate подставляет в текст шаблона {{date}}, {{time}}, {{datetime}}, {{weekday}} и {{user}}
+func renderTemplate(text string, now time.Time, user string) string {
+	replacer := strings.NewReplacer(
+		"{{date}}", now.Format("2006-01-02"),
+		"{{time}}", now.Format("15:04"),
+		"{{datetime}}", now.Format("2006-01-02 15:04"),
+		"{{weekday}}", now.Weekday().String(),
+		"{{user}}", user,
+	)
+
+	return replacer.Replace(text)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -23,34 +24,25 @@ const (
 
 type UserService struct {
 	DBClient repository.UserRepo
+	TxClient repository.UnitOfWork
 }
 
 func (srv UserService) HandleRegisterUser(w http.ResponseWriter, r *http.Request) {
 	response := dto.RegisterResponse{}
 	var registerReq dto.RegisterRequest
 
-	err := json.NewDecoder(r.Body).Decode(&registerReq)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Wrong request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	if !decodeRequest(w, r, &registerReq) {
 		return
 	}
 
-	if registerReq.Email == "" || registerReq.Password == "" {
-		response.Error = "Email and password are required"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(registerReq.Password), bcrypt.DefaultCost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		//max=72 считает символы, а bcrypt - байты
+		respondInvalid(w, "Password is too long")
 		return
 	}
-
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(registerReq.Password), bcrypt.DefaultCost)
 	if err != nil {
-		slog.Error("Failed to hash password", slog.String("error", err.Error()))
-		response.Error = "Internal server error"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Internal server error")
 		return
 	}
 
@@ -61,12 +53,9 @@ func (srv UserService) HandleRegisterUser(w http.ResponseWriter, r *http.Request
 		LastName:     registerReq.LastName,
 	}
 
-	res, err := srv.DBClient.RegisterUser(user)
+	res, err := srv.DBClient.RegisterUser(r.Context(), user)
 	if err != nil {
-		slog.Error("Failed to create user", slog.String("error", err.Error()))
-		response.Error = "Failed to create user"
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
+		respondError(w, err, "Failed to create user")
 		return
 	}
 
@@ -79,44 +68,13 @@ func (srv UserService) HandleLoginUser(w http.ResponseWriter, r *http.Request) {
 	response := dto.LoginResponse{}
 	var loginReq dto.LoginRequest
 
-	err := json.NewDecoder(r.Body).Decode(&loginReq)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "Invalid request"
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
-		return
-	}
-
-	user, err := srv.DBClient.LoginUser(loginReq.Email)
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "User not found or wrong password"
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(response)
-		return
-	}
-
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(loginReq.Password))
-	if err != nil {
-		slog.Error(err.Error())
-		response.Error = "User not found or wrong password"
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(response)
+	if !decodeRequest(w, r, &loginReq) {
 		return
 	}
 
-	claims := jwt.MapClaims{
-		"user_id": user.ID.Hex(),
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	secretKey := []byte("placeholder_secret_key") //В будущем создать нормальный ключ в конфиге
-
-	tokenString, err := token.SignedString(secretKey)
+	user, tokenString, err := srv.login(r.Context(), loginReq.Email, loginReq.Password)
 	if err != nil {
-		slog.Error("Failed to generate JWT", slog.String("error", err.Error()))
-		w.WriteHeader(http.StatusInternalServerError)
+		respondError(w, err, "Failed to generate token")
 		return
 	}
 
@@ -140,15 +98,13 @@ func (srv UserService) HandleGetProfile(w http.ResponseWriter, r *http.Request)
 
 	userID, ok := r.Context().Value(userIDKey).(string)
 	if !ok || userID == "" {
-		slog.Error("UserID not found in context")
-		w.WriteHeader(http.StatusUnauthorized)
+		respondUnauthorized(w)
 		return
 	}
 
-	user, err := srv.DBClient.GetProfile(userID)
+	user, err := srv.DBClient.GetProfile(r.Context(), userID)
 	if err != nil {
-		slog.Error("Failed to get user", slog.String("error", err.Error()))
-		w.WriteHeader(http.StatusInternalServerError)
+		respondError(w, err, "Failed to get user")
 		return
 	}
 
@@ -157,38 +113,154 @@ func (srv UserService) HandleGetProfile(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
-func (srv UserService) AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cookie, err := r.Cookie("auth_token")
+func (srv UserService) HandleDeleteProfile(w http.ResponseWriter, r *http.Request) {
+	response := dto.LoginResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	var res int
+
+	err := srv.TxClient.Do(r.Context(), func(tx repository.Tx) error {
+		_, err := tx.Templates.DeleteTemplatesByUserID(r.Context(), userID)
 		if err != nil {
-			slog.Error("Cookie 'auth_token' not found", "error", err.Error())
-			w.WriteHeader(http.StatusUnauthorized)
-			return
+			return err
 		}
 
-		token, err := jwt.Parse(cookie.Value, func(token *jwt.Token) (interface{}, error) {
-			return []byte("placeholder_secret_key"), nil //В будущем создать нормальный ключ в конфиге
+		_, err = tx.Webhooks.DeleteWebhooksByUserID(r.Context(), userID)
+		if err != nil {
+			return err
+		}
+
+		//Заметки пользователя, включая записи журнала, удаляются вместе с напоминаниями; общие заметки остаются
+		var noteIDs []string
+		err = tx.Notes.StreamNotes(r.Context(), userID, func(note model.Note) error {
+			if note.UserID.Hex() == userID {
+				noteIDs = append(noteIDs, note.ID.Hex())
+			}
+			return nil
 		})
-		if err != nil || !token.Valid {
-			slog.Error("Invalid token", "error", err.Error())
-			w.WriteHeader(http.StatusUnauthorized)
-			return
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Reminders.DeleteRemindersByNotes(r.Context(), noteIDs)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Notes.DeleteNotesByUserID(r.Context(), userID)
+		if err != nil {
+			return err
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			slog.Error("Invalid token claims")
-			w.WriteHeader(http.StatusUnauthorized)
+		res, err = tx.Users.DeleteUser(r.Context(), userID)
+		return err
+	})
+	if err != nil {
+		respondError(w, err, "Failed to delete user")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     "auth_token",
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   false, //На случай https
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	slog.Info("User deleted", slog.String("_id", userID))
+	response.Data = res
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv UserService) AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := userIDFromCookie(r)
+		if err != nil {
+			slog.Error("Unauthorized request", "error", err.Error())
+			respondUnauthorized(w)
 			return
 		}
 
-		userID, ok := claims["user_id"].(string)
-		if !ok {
-			slog.Error("user_id is not a string", "type", fmt.Sprintf("%T", claims["user_id"]))
-			w.WriteHeader(http.StatusUnauthorized)
+		ctx := context.WithValue(r.Context(), userIDKey, userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// OptionalAuthMiddleware пропускает анонимные запросы, но кладет user_id в контекст, если токен валиден
+func (srv UserService) OptionalAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := userIDFromCookie(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
 			return
 		}
+
 		ctx := context.WithValue(r.Context(), userIDKey, userID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+func userIDFromCookie(r *http.Request) (string, error) {
+	cookie, err := r.Cookie("auth_token")
+	if err != nil {
+		return "", fmt.Errorf("cookie 'auth_token' not found: %v", err)
+	}
+
+	return parseToken(cookie.Value)
+}
+
+// login проверяет email и пароль и выдает токен; его принимают и cookie auth_token, и gRPC
+func (srv UserService) login(ctx context.Context, email, password string) (model.User, string, error) {
+	user, err := srv.DBClient.LoginUser(ctx, email)
+	if err != nil {
+		return model.User{}, "", model.Unauthorized("User not found or wrong password")
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	if err != nil {
+		return model.User{}, "", model.Unauthorized("User not found or wrong password")
+	}
+
+	claims := jwt.MapClaims{
+		"user_id": user.ID.Hex(),
+		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	secretKey := []byte("placeholder_secret_key") //В будущем создать нормальный ключ в конфиге
+
+	tokenString, err := token.SignedString(secretKey)
+	if err != nil {
+		return model.User{}, "", err
+	}
+
+	return user, tokenString, nil
+}
+
+func parseToken(tokenString string) (string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte("placeholder_secret_key"), nil //В будущем создать нормальный ключ в конфиге
+	})
+	if err != nil || !token.Valid {
+		return "", fmt.Errorf("invalid token: %v", err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", fmt.Errorf("invalid token claims")
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return "", fmt.Errorf("user_id is not a string: %T", claims["user_id"])
+	}
+
+	return userID, nil
+}
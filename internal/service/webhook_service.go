@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"github.com/LoL-KeKovich/NoteVault/internal/webhook"
+	"github.com/LoL-KeKovich/NoteVault/lib/timezone"
+	"github.com/LoL-KeKovich/NoteVault/lib/validate"
+	"github.com/go-chi/chi"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errWebhookNotFound = model.NotFound("Webhook not found")
+
+type WebhookService struct {
+	DBClient   repository.WebhookRepo
+	Dispatcher webhook.Dispatcher
+}
+
+func (srv WebhookService) HandleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	response := dto.WebhookResponse{}
+	var webhookReq dto.WebhookRequest
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	if !decodeRequest(w, r, &webhookReq) {
+		return
+	}
+
+	err := checkWebhookEvents(webhookReq.Events)
+	if err != nil {
+		respondError(w, err, "")
+		return
+	}
+
+	if webhookReq.Secret == "" {
+		webhookReq.Secret = newWebhookSecret()
+	}
+
+	userDocId, _ := primitive.ObjectIDFromHex(userID)
+
+	hook := model.Webhook{
+		UserID:    userDocId,
+		URL:       webhookReq.URL,
+		Secret:    webhookReq.Secret,
+		Events:    webhookReq.Events,
+		IsActive:  webhookReq.IsActive,
+		CreatedAt: timezone.Now().String(),
+	}
+
+	res, err := srv.DBClient.CreateWebhook(r.Context(), hook)
+	if err != nil {
+		respondError(w, err, "Error inserting webhook in db")
+		return
+	}
+
+	slog.Info("Created webhook", slog.String("_id", res))
+	response.Data = res
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv WebhookService) HandleGetWebhookByID(w http.ResponseWriter, r *http.Request) {
+	response := dto.WebhookResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	hook, err := srv.findOwnWebhook(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, err, "Error finding webhook in db")
+		return
+	}
+
+	slog.Info("Webhook found")
+	response.Data = hook
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv WebhookService) HandleGetWebhooks(w http.ResponseWriter, r *http.Request) {
+	response := dto.WebhookResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	webhooks, err := srv.DBClient.GetWebhooksByUserID(r.Context(), userID)
+	if err != nil {
+		respondError(w, err, "Error finding webhooks in db")
+		return
+	}
+
+	slog.Info("Webhooks found")
+	response.Data = webhooks
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv WebhookService) HandleUpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	response := dto.WebhookResponse{}
+	var webhookReq dto.WebhookRequest
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	if !decodePartial(w, r, &webhookReq) {
+		return
+	}
+
+	err := checkWebhookEvents(webhookReq.Events)
+	if err != nil {
+		respondError(w, err, "")
+		return
+	}
+
+	_, err = srv.findOwnWebhook(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, err, "Error finding webhook in db")
+		return
+	}
+
+	update := repository.WebhookUpdate{IsActive: webhookReq.IsActive}
+	if webhookReq.URL != "" {
+		update.URL = &webhookReq.URL
+	}
+	if webhookReq.Secret != "" {
+		update.Secret = &webhookReq.Secret
+	}
+	if webhookReq.Events != nil {
+		update.Events = &webhookReq.Events
+	}
+
+	res, err := srv.DBClient.UpdateWebhook(r.Context(), id, update)
+	if err != nil {
+		respondError(w, err, "Error updating webhook in db")
+		return
+	}
+
+	slog.Info("Webhook updated")
+	response.Data = res
+	json.NewEncoder(w).Encode(response)
+}
+
+func (srv WebhookService) HandleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	response := dto.WebhookResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	_, err := srv.findOwnWebhook(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, err, "Error finding webhook in db")
+		return
+	}
+
+	res, err := srv.DBClient.DeleteWebhook(r.Context(), id)
+	if err != nil {
+		respondError(w, err, "Error deleting webhook in db")
+		return
+	}
+
+	slog.Info("Webhook deleted")
+	response.Data = res
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleGetWebhookDeliveries отдает журнал доставок вебхука, новые попытки первыми
+func (srv WebhookService) HandleGetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	response := dto.WebhookResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	_, err := srv.findOwnWebhook(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, err, "Error finding webhook in db")
+		return
+	}
+
+	deliveries, err := srv.DBClient.GetWebhookDeliveries(r.Context(), id)
+	if err != nil {
+		respondError(w, err, "Error finding webhook deliveries in db")
+		return
+	}
+
+	slog.Info("Webhook deliveries found")
+	response.Data = deliveries
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleTestWebhook сразу отправляет на вебхук событие ping, без повторов, и возвращает результат попытки.
+// Выключенный вебхук и фильтр событий не мешают проверке
+func (srv WebhookService) HandleTestWebhook(w http.ResponseWriter, r *http.Request) {
+	response := dto.WebhookResponse{}
+
+	userID, ok := r.Context().Value(userIDKey).(string)
+	if !ok || userID == "" {
+		respondUnauthorized(w)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondInvalid(w, "Wrong id")
+		return
+	}
+
+	hook, err := srv.findOwnWebhook(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, err, "Error finding webhook in db")
+		return
+	}
+
+	payload := dto.WebhookPayload{
+		ID:        primitive.NewObjectID().Hex(),
+		Event:     webhook.EventPing,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	body, _ := json.Marshal(payload)
+
+	delivery, err := srv.Dispatcher.Send(r.Context(), hook, payload.ID, payload.Event, body, 1)
+	if err != nil {
+		respondError(w, err, "Error saving webhook delivery in db")
+		return
+	}
+
+	slog.Info("Webhook tested", slog.String("_id", id), slog.Bool("success", delivery.Success))
+	response.Data = delivery
+	json.NewEncoder(w).Encode(response)
+}
+
+// findOwnWebhook не отличает чужой вебхук от несуществующего
+func (srv WebhookService) findOwnWebhook(ctx context.Context, id, userID string) (model.Webhook, error) {
+	hook, err := srv.DBClient.GetWebhookByID(ctx, id)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	if hook.UserID.Hex() != userID {
+		return model.Webhook{}, errWebhookNotFound
+	}
+
+	return hook, nil
+}
+
+func checkWebhookEvents(names []string) error {
+	var errs validate.Errors
+	for _, name := range names {
+		if !slices.Contains(webhook.Events, name) {
+			errs = append(errs, validate.FieldError{Field: "events", Rule: "event", Message: "unknown event " + name})
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+
+	return nil
+}
+
+func newWebhookSecret() string {
+	secret := make([]byte, 32)
+	rand.Read(secret)
+
+	return hex.EncodeToString(secret)
+}
@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/internal/config"
+	"github.com/LoL-KeKovich/NoteVault/internal/dto"
+	"github.com/LoL-KeKovich/NoteVault/internal/events"
+	"github.com/LoL-KeKovich/NoteVault/internal/model"
+	"github.com/LoL-KeKovich/NoteVault/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	HeaderEvent     = "X-NoteVault-Event"
+	HeaderDelivery  = "X-NoteVault-Delivery"
+	HeaderSignature = "X-NoteVault-Signature"
+)
+
+// EventPing - пробное событие, которое отправляет POST /webhooks/{id}/test
+const EventPing = "ping"
+
+// Events - события заметок, на которые можно подписать вебхук
+var Events = []string{
+	events.KindNote + "." + events.ActionCreated,
+	events.KindNote + "." + events.ActionUpdated,
+	events.KindNote + "." + events.ActionTagged,
+	events.KindNote + "." + events.ActionUntagged,
+	events.KindNote + "." + events.ActionTrashed,
+	events.KindNote + "." + events.ActionArchived,
+	events.KindNote + "." + events.ActionRestored,
+	events.KindNote + "." + events.ActionDeleted,
+}
+
+// clientTimeout ограничивает запрос, если в конфигурации нет таймаута попытки
+const clientTimeout = 30 * time.Second
+
+var errForbiddenAddress = errors.New("webhook address is not allowed")
+
+// defaultClient - клиент для Dispatcher без Client, с проверкой адресов
+var defaultClient = NewClient(config.Webhooks{})
+
+// NewClient возвращает клиент для доставки. Он не следует редиректам: иначе POST превратится в GET
+// без тела. Без cfg.AllowPrivate адрес проверяется при подключении, уже после разрешения имени:
+// так DNS-имя, указывающее на 127.0.0.1, не обходит проверку. Прокси не используется по той же причине
+func NewClient(cfg config.Webhooks) *http.Client {
+	dialer := &net.Dialer{Timeout: clientTimeout}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if !cfg.AllowPrivate {
+		dialer.Control = checkAddress
+		transport.Proxy = nil
+	}
+	transport.DialContext = dialer.DialContext
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = clientTimeout
+	}
+
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// checkAddress запрещает подключение к loopback, частным, link-local и прочим немаршрутизируемым адресам
+func checkAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", errForbiddenAddress, ip)
+	}
+
+	return nil
+}
+
+// Dispatcher доставляет события заметок на адреса вебхуков их владельцев
+type Dispatcher struct {
+	Webhooks repository.WebhookRepo
+	Notes    repository.NoteRepo
+	Client   *http.Client
+	Config   config.Webhooks
+}
+
+// Sign возвращает значение заголовка X-NoteVault-Signature: HMAC-SHA256 тела с секретом вебхука
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Subscribed сообщает, нужно ли отправлять событие на вебхук
+func Subscribed(webhook model.Webhook, event string) bool {
+	if webhook.IsActive != nil && !*webhook.IsActive {
+		return false
+	}
+
+	return len(webhook.Events) == 0 || slices.Contains(webhook.Events, event)
+}
+
+// Handle - слушатель шины событий. Доставка идет в фоне, чтобы не задерживать Publish
+func (d Dispatcher) Handle(e events.Event) {
+	if e.Kind != events.KindNote || e.UserID == "" {
+		return
+	}
+
+	go d.dispatch(context.Background(), e)
+}
+
+func (d Dispatcher) dispatch(ctx context.Context, e events.Event) {
+	webhooks, err := d.Webhooks.GetWebhooksByUserID(ctx, e.UserID)
+	if err != nil {
+		slog.Error("Error finding webhooks", slog.String("error", err.Error()))
+		return
+	}
+
+	webhooks = slices.DeleteFunc(webhooks, func(webhook model.Webhook) bool { return !Subscribed(webhook, e.Type()) })
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload := dto.WebhookPayload{
+		Event:     e.Type(),
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		NoteID:    e.ID,
+	}
+
+	note, err := d.Notes.GetNoteByID(ctx, e.ID)
+	if err == nil {
+		payload.Note = &note
+	}
+
+	for _, webhook := range webhooks {
+		go d.deliver(ctx, webhook, payload)
+	}
+}
+
+// deliver повторяет неудачную доставку с экспоненциальной задержкой. Перед повтором вебхук перечитывается:
+// удаленный или выключенный вебхук больше не получает событие
+func (d Dispatcher) deliver(ctx context.Context, webhook model.Webhook, payload dto.WebhookPayload) {
+	payload.ID = primitive.NewObjectID().Hex()
+	body, _ := json.Marshal(payload)
+
+	delay := d.Config.RetryDelay
+	for attempt := 1; ; attempt++ {
+		delivery, err := d.Send(ctx, webhook, payload.ID, payload.Event, body, attempt)
+		if err != nil {
+			slog.Error("Error saving webhook delivery", slog.String("error", err.Error()))
+			return
+		}
+		if delivery.Success || attempt >= d.Config.MaxAttempts {
+			return
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+
+		webhook, err = d.Webhooks.GetWebhookByID(ctx, webhook.ID.Hex())
+		if err != nil || !Subscribed(webhook, payload.Event) {
+			return
+		}
+	}
+}
+
+// Send делает одну попытку доставки и пишет ее в журнал вебхука. Ошибка означает, что журнал записать
+// не удалось, например вебхук удален; неудачная доставка возвращается как delivery с Success=false
+func (d Dispatcher) Send(ctx context.Context, webhook model.Webhook, deliveryID, event string, body []byte, attempt int) (model.WebhookDelivery, error) {
+	delivery := model.WebhookDelivery{
+		ID:         primitive.NewObjectID(),
+		WebhookID:  webhook.ID,
+		DeliveryID: deliveryID,
+		Event:      event,
+		Attempt:    attempt,
+		Payload:    string(body),
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+
+	start := time.Now()
+	status, err := d.post(ctx, webhook, deliveryID, event, body)
+	delivery.DurationMs = int(time.Since(start).Milliseconds())
+	delivery.StatusCode = status
+	delivery.Success = err == nil && status >= 200 && status < 300
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	slog.Info("Webhook delivery",
+		slog.String("webhook_id", webhook.ID.Hex()), slog.String("event", event),
+		slog.Int("attempt", attempt), slog.Int("status", status), slog.Bool("success", delivery.Success))
+
+	return delivery, d.Webhooks.AddWebhookDelivery(ctx, delivery)
+}
+
+func (d Dispatcher) post(ctx context.Context, webhook model.Webhook, deliveryID, event string, body []byte) (int, error) {
+	if d.Config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Config.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "NoteVault-Webhook")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, deliveryID)
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, body))
+
+	client := d.Client
+	if client == nil {
+		client = defaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	//Тело ответа не нужно, но его дочитывание позволяет переиспользовать соединение
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
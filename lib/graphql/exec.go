@@ -0,0 +1,776 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+type Request struct {
+	Query         string
+	OperationName string
+	Variables     map[string]any
+}
+
+// Response - результат запроса. Data нет, если запрос не дошел до выполнения: не разобрался, не прошел
+// проверку по схеме или получил неверные переменные. Ошибки резолверов не мешают остальным полям:
+// поле с ошибкой становится null, а ошибка попадает в Errors с путем до поля.
+type Response struct {
+	Data     any     `json:"data"`
+	Errors   []Error `json:"errors,omitempty"`
+	executed bool
+}
+
+func (r Response) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Data   *any    `json:"data,omitempty"`
+		Errors []Error `json:"errors,omitempty"`
+	}{Errors: r.Errors}
+
+	if r.executed {
+		out.Data = &r.Data
+	}
+
+	return json.Marshal(out)
+}
+
+type Error struct {
+	Message    string         `json:"message"`
+	Locations  []Location     `json:"locations,omitempty"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+// Execute разбирает и выполняет запрос. Поля мутации выполняются по порядку, поэтому вторая мутация
+// в запросе видит результат первой.
+func Execute(ctx context.Context, schema *Schema, req Request) Response {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return errorResponse(err.Error())
+	}
+
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return errorResponse(err.Error())
+	}
+
+	root := schema.Query
+	switch op.Type {
+	case "mutation":
+		root = schema.Mutation
+	case "subscription":
+		root = nil
+	}
+	if root == nil {
+		return errorResponse(fmt.Sprintf("schema does not support %s operations", op.Type))
+	}
+
+	e := &executor{ctx: ctx, schema: schema, doc: doc}
+
+	e.vars, err = e.variables(op, req.Variables)
+	if err != nil {
+		return errorResponse(err.Error())
+	}
+
+	defined := map[string]bool{}
+	for _, def := range op.Variables {
+		defined[def.Name] = true
+	}
+
+	maxDepth, maxFields := schema.MaxDepth, schema.MaxFields
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if maxFields <= 0 {
+		maxFields = DefaultMaxFields
+	}
+
+	v := validator{
+		executor:  e,
+		defined:   defined,
+		visiting:  map[string]bool{},
+		validated: map[string]bool{},
+		costs:     map[string]cost{},
+		maxFields: maxFields,
+	}
+
+	size := v.measure(op.Selections)
+	if size.depth > maxDepth {
+		return errorResponse(fmt.Sprintf("query depth %d exceeds maximum depth %d", size.depth, maxDepth))
+	}
+	if size.fields > maxFields {
+		return errorResponse(fmt.Sprintf("query selects more than %d fields", maxFields))
+	}
+
+	v.selections(root, op.Selections)
+	if err := ctx.Err(); err != nil {
+		return errorResponse(err.Error())
+	}
+	if len(v.errors) > 0 {
+		return Response{Errors: v.errors}
+	}
+
+	resp := Response{executed: true}
+
+	if data := e.selectionSet(root, []node{{}}, op.Selections)[0]; data != nil {
+		resp.Data = data
+	}
+	resp.Errors = e.errors
+
+	return resp
+}
+
+func errorResponse(message string) Response {
+	return Response{Errors: []Error{{Message: message}}}
+}
+
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, fmt.Errorf("operationName is required for a document with several operations")
+		}
+		return doc.Operations[0], nil
+	}
+
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+type executor struct {
+	ctx    context.Context
+	schema *Schema
+	doc    *Document
+	vars   map[string]any
+	errors []Error
+}
+
+// node - родитель, для которого вычисляются поля, и путь до него в ответе.
+type node struct {
+	value any
+	path  []any
+}
+
+type item struct {
+	value any
+	path  []any
+	err   error
+}
+
+// variables проверяет переменные запроса по их типам из операции.
+func (e *executor) variables(op *Operation, values map[string]any) (map[string]any, error) {
+	vars := map[string]any{}
+
+	for _, def := range op.Variables {
+		t, err := e.typeRef(def.Type)
+		if err != nil {
+			return nil, fmt.Errorf("variable $%s: %w", def.Name, err)
+		}
+
+		value, ok := values[def.Name]
+		if !ok && def.Default != nil {
+			value, ok, err = e.literal(*def.Default)
+			if err != nil {
+				return nil, fmt.Errorf("variable $%s: %w", def.Name, err)
+			}
+		}
+		if !ok {
+			if _, nonNull := t.(*NonNull); nonNull {
+				return nil, fmt.Errorf("variable $%s of required type %s was not provided", def.Name, t)
+			}
+			continue
+		}
+
+		vars[def.Name], err = coerce(t, value)
+		if err != nil {
+			return nil, fmt.Errorf("variable $%s: %w", def.Name, err)
+		}
+	}
+
+	return vars, nil
+}
+
+func (e *executor) typeRef(ref TypeRef) (Type, error) {
+	var t Type
+
+	if ref.Elem != nil {
+		elem, err := e.typeRef(*ref.Elem)
+		if err != nil {
+			return nil, err
+		}
+		t = NewList(elem)
+	} else {
+		named, ok := e.schema.inputType(ref.Name)
+		if !ok {
+			return nil, fmt.Errorf("unknown input type %s", ref.Name)
+		}
+		t = named
+	}
+
+	if ref.NonNull {
+		t = NewNonNull(t)
+	}
+
+	return t, nil
+}
+
+// enumLiteral - значение перечисления, записанное в запросе без кавычек.
+type enumLiteral string
+
+// literal переводит значение из запроса в Go. ok = false - переменная не передана.
+func (e *executor) literal(v Value) (any, bool, error) {
+	switch v.Kind {
+	case ValueVariable:
+		value, ok := e.vars[v.Raw]
+		return value, ok, nil
+	case ValueInt:
+		n, err := strconv.ParseInt(v.Raw, 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid Int %s", v.Raw)
+		}
+		return float64(n), true, nil
+	case ValueFloat:
+		f, err := strconv.ParseFloat(v.Raw, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid Float %s", v.Raw)
+		}
+		return f, true, nil
+	case ValueString:
+		return v.Raw, true, nil
+	case ValueBoolean:
+		return v.Raw == "true", true, nil
+	case ValueNull:
+		return nil, true, nil
+	case ValueEnum:
+		return enumLiteral(v.Raw), true, nil
+	case ValueList:
+		list := make([]any, 0, len(v.List))
+		for _, elem := range v.List {
+			value, _, err := e.literal(elem)
+			if err != nil {
+				return nil, false, err
+			}
+			list = append(list, value)
+		}
+		return list, true, nil
+	case ValueObject:
+		obj := map[string]any{}
+		for _, field := range v.Fields {
+			value, ok, err := e.literal(field.Value)
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				obj[field.Name] = value
+			}
+		}
+		return obj, true, nil
+	}
+
+	return nil, false, fmt.Errorf("unsupported value")
+}
+
+// args проверяет аргументы поля и подставляет значения по умолчанию.
+func (e *executor) args(defs map[string]*Arg, nodes []Argument) (Args, error) {
+	args := Args{}
+
+	for _, arg := range nodes {
+		def := defs[arg.Name]
+
+		value, ok, err := e.literal(arg.Value)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", arg.Name, err)
+		}
+		if !ok {
+			continue
+		}
+
+		args[arg.Name], err = coerce(def.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", arg.Name, err)
+		}
+	}
+
+	for name, def := range defs {
+		if args.Has(name) {
+			continue
+		}
+		if def.Default != nil {
+			args[name] = def.Default
+			continue
+		}
+		if _, nonNull := def.Type.(*NonNull); nonNull {
+			return nil, fmt.Errorf("argument %q of required type %s was not provided", name, def.Type)
+		}
+	}
+
+	return args, nil
+}
+
+// coerce приводит значение аргумента или переменной к типу. Числа из JSON приходят как float64.
+func coerce(t Type, value any) (any, error) {
+	if nonNull, ok := t.(*NonNull); ok {
+		if value == nil {
+			return nil, fmt.Errorf("expected non-null %s", nonNull.Of)
+		}
+		return coerce(nonNull.Of, value)
+	}
+
+	if value == nil {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		list, ok := value.([]any)
+		if !ok {
+			//Одно значение на месте списка считается списком из одного элемента
+			list = []any{value}
+		}
+		out := make([]any, 0, len(list))
+		for i, elem := range list {
+			v, err := coerce(t.Of, elem)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case *InputObject:
+		//Переменные приходят уже приведенными, литералы - как map
+		obj, ok := value.(map[string]any)
+		if args, isArgs := value.(Args); isArgs {
+			obj, ok = args, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s must be an object", t.Name)
+		}
+		out := Args{}
+		for name, v := range obj {
+			def, ok := t.Fields[name]
+			if !ok {
+				return nil, fmt.Errorf("field %q is not defined by type %s", name, t.Name)
+			}
+			c, err := coerce(def.Type, v)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", name, err)
+			}
+			out[name] = c
+		}
+		for name, def := range t.Fields {
+			if out.Has(name) {
+				continue
+			}
+			if def.Default != nil {
+				out[name] = def.Default
+			} else if _, nonNull := def.Type.(*NonNull); nonNull {
+				return nil, fmt.Errorf("field %q of required type %s was not provided", name, def.Type)
+			}
+		}
+		return out, nil
+	case *Enum:
+		var s string
+		switch v := value.(type) {
+		case enumLiteral:
+			s = string(v)
+		case string:
+			s = v
+		}
+		for _, allowed := range t.Values {
+			if s != "" && s == allowed {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("value %v does not exist in %s enum", value, t.Name)
+	case *Scalar:
+		return coerceScalar(t, value)
+	}
+
+	return nil, fmt.Errorf("%s cannot be used as an input type", t)
+}
+
+func coerceScalar(t *Scalar, value any) (any, error) {
+	switch t {
+	case String:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case ID:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case int:
+			return strconv.Itoa(v), nil
+		case float64:
+			if v == math.Trunc(v) {
+				return strconv.FormatInt(int64(v), 10), nil
+			}
+		}
+	case Int:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case float64:
+			if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxInt32 {
+				return int(v), nil
+			}
+		}
+	case Float:
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	case Boolean:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	}
+
+	if s, ok := value.(enumLiteral); ok {
+		value = string(s)
+	}
+
+	return nil, fmt.Errorf("%s cannot represent %s", t.Name, describe(value))
+}
+
+func describe(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
+
+// skipped вычисляет директивы @skip и @include.
+func (e *executor) skipped(directives []Directive) bool {
+	for _, d := range directives {
+		if len(d.Args) == 0 {
+			continue
+		}
+
+		value, _, _ := e.literal(d.Args[0].Value)
+		cond, _ := value.(bool)
+
+		if d.Name == "skip" && cond || d.Name == "include" && !cond {
+			return true
+		}
+	}
+
+	return false
+}
+
+type fieldSet struct {
+	keys      []string
+	nodes     map[string][]*FieldNode
+	fragments map[string]bool
+}
+
+// collectFields раскрывает фрагменты и собирает поля с одинаковым именем в ответе. Фрагмент
+// раскрывается один раз на выборку, повторные ссылки на него ничего не добавляют.
+func (e *executor) collectFields(sels []Selection, set *fieldSet) {
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *FieldNode:
+			if e.skipped(sel.Directives) {
+				continue
+			}
+			key := sel.Key()
+			if _, ok := set.nodes[key]; !ok {
+				set.keys = append(set.keys, key)
+			}
+			set.nodes[key] = append(set.nodes[key], sel)
+		case *FragmentSpread:
+			if set.fragments[sel.Name] || e.skipped(sel.Directives) {
+				continue
+			}
+			set.fragments[sel.Name] = true
+			e.collectFields(e.doc.Fragments[sel.Name].Selections, set)
+		case *InlineFragment:
+			if e.skipped(sel.Directives) {
+				continue
+			}
+			e.collectFields(sel.Selections, set)
+		}
+	}
+}
+
+// selectionSet вычисляет поля объекта сразу для всех родителей. Родитель, у которого non-null поле
+// получило null, сам становится nil.
+func (e *executor) selectionSet(t *Object, parents []node, sels []Selection) []*orderedMap {
+	set := fieldSet{nodes: map[string][]*FieldNode{}, fragments: map[string]bool{}}
+	e.collectFields(sels, &set)
+
+	results := make([]*orderedMap, len(parents))
+	for i := range results {
+		results[i] = &orderedMap{values: map[string]any{}}
+	}
+
+	for _, key := range set.keys {
+		nodes := set.nodes[key]
+		field := nodes[0]
+
+		var live []int
+		for i := range parents {
+			if results[i] != nil {
+				live = append(live, i)
+			}
+		}
+		if len(live) == 0 {
+			break
+		}
+
+		if field.Name == "__typename" {
+			for _, i := range live {
+				results[i].set(key, t.Name)
+			}
+			continue
+		}
+
+		def := t.Fields[field.Name]
+
+		items := make([]item, len(live))
+		for j, i := range live {
+			items[j].path = appendPath(parents[i].path, key)
+		}
+
+		args, err := e.args(def.Args, field.Args)
+		switch {
+		case e.ctx.Err() != nil:
+			//Запрос отменен - резолверы уже не вызываются
+			for j := range items {
+				items[j].err = e.ctx.Err()
+			}
+		case err != nil:
+			for j := range items {
+				items[j].err = Error{Message: err.Error()}
+			}
+		case def.Batch != nil:
+			sources := make([]any, len(live))
+			for j, i := range live {
+				sources[j] = parents[i].value
+			}
+
+			values, err := def.Batch(BatchParams{Context: e.ctx, Sources: sources, Args: args})
+			if err == nil && len(values) != len(sources) {
+				err = fmt.Errorf("%s.%s: batch returned %d values for %d sources", t.Name, field.Name, len(values), len(sources))
+			}
+			for j := range items {
+				if err != nil {
+					items[j].err = err
+				} else {
+					items[j].value = values[j]
+				}
+			}
+		default:
+			for j, i := range live {
+				items[j].value, items[j].err = def.Resolve(Params{Context: e.ctx, Source: parents[i].value, Args: args})
+			}
+		}
+
+		values, _ := e.complete(def.Type, nodes, items)
+
+		_, nonNull := def.Type.(*NonNull)
+		for j, i := range live {
+			if nonNull && values[j] == nil {
+				results[i] = nil
+			} else {
+				results[i].set(key, values[j])
+			}
+		}
+	}
+
+	return results
+}
+
+// complete приводит значения резолверов к типу поля. failed - значение стало null из-за уже записанной ошибки.
+func (e *executor) complete(t Type, nodes []*FieldNode, items []item) ([]any, []bool) {
+	values := make([]any, len(items))
+	failed := make([]bool, len(items))
+
+	if nonNull, ok := t.(*NonNull); ok {
+		values, failed = e.complete(nonNull.Of, nodes, items)
+		for i := range items {
+			if values[i] == nil && !failed[i] {
+				e.addError(errors.New("cannot return null for non-nullable field"), items[i].path, nodes[0])
+				failed[i] = true
+			}
+		}
+		return values, failed
+	}
+
+	var present []int
+	for i, it := range items {
+		switch {
+		case it.err != nil:
+			e.addError(it.err, it.path, nodes[0])
+			failed[i] = true
+		case !isNil(it.value):
+			present = append(present, i)
+		}
+	}
+
+	switch t := t.(type) {
+	case *Scalar, *Enum:
+		for _, i := range present {
+			values[i] = reflect.Indirect(reflect.ValueOf(items[i].value)).Interface()
+		}
+	case *Object:
+		parents := make([]node, len(present))
+		for j, i := range present {
+			parents[j] = node{value: items[i].value, path: items[i].path}
+		}
+
+		var sels []Selection
+		for _, n := range nodes {
+			sels = append(sels, n.Selections...)
+		}
+
+		for j, obj := range e.selectionSet(t, parents, sels) {
+			if obj == nil {
+				failed[present[j]] = true
+			} else {
+				values[present[j]] = obj
+			}
+		}
+	case *List:
+		var elems []item
+		bounds := map[int][2]int{}
+
+		for _, i := range present {
+			rv := reflect.ValueOf(items[i].value)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				e.addError(fmt.Errorf("expected a list, got %T", items[i].value), items[i].path, nodes[0])
+				failed[i] = true
+				continue
+			}
+
+			start := len(elems)
+			for k := 0; k < rv.Len(); k++ {
+				elems = append(elems, item{value: rv.Index(k).Interface(), path: appendPath(items[i].path, k)})
+			}
+			bounds[i] = [2]int{start, len(elems)}
+		}
+
+		elemValues, _ := e.complete(t.Of, nodes, elems)
+		_, elemNonNull := t.Of.(*NonNull)
+
+		for _, i := range present {
+			b, ok := bounds[i]
+			if !ok {
+				continue
+			}
+
+			list := make([]any, 0, b[1]-b[0])
+			for k := b[0]; k < b[1]; k++ {
+				if elemNonNull && elemValues[k] == nil {
+					list = nil
+					break
+				}
+				list = append(list, elemValues[k])
+			}
+
+			if list == nil {
+				failed[i] = true
+			} else {
+				values[i] = list
+			}
+		}
+	}
+
+	return values, failed
+}
+
+func (e *executor) addError(err error, path []any, field *FieldNode) {
+	var gqlErr Error
+	if !errors.As(err, &gqlErr) {
+		if e.schema.FormatError != nil {
+			gqlErr = e.schema.FormatError(err)
+		} else {
+			gqlErr = Error{Message: err.Error()}
+		}
+	}
+
+	gqlErr.Path = path
+	gqlErr.Locations = []Location{{Line: field.Line, Column: field.Column}}
+	e.errors = append(e.errors, gqlErr)
+}
+
+func appendPath(path []any, key any) []any {
+	out := make([]any, len(path), len(path)+1)
+	copy(out, path)
+
+	return append(out, key)
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	//nil-срез - пустой список, а не null
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Interface:
+		return rv.IsNil()
+	}
+
+	return false
+}
+
+// orderedMap - объект ответа, поля которого идут в порядке запроса.
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func (m *orderedMap) set(key string, value any) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, _ := json.Marshal(key)
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
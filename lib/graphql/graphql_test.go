@@ -0,0 +1,350 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LoL-KeKovich/NoteVault/lib/graphql"
+)
+
+type book struct {
+	ID    string
+	Title string
+	Tags  []string
+}
+
+// testSchema - книги с тегами. batches считает вызовы Batch, чтобы проверить, что поле списка
+// вычисляется одним вызовом
+func testSchema(batches *int) *graphql.Schema {
+	books := []book{{ID: "1", Title: "Go", Tags: []string{"dev"}}, {ID: "2", Title: "Mongo"}}
+
+	bookType := &graphql.Object{Name: "Book", Fields: map[string]*graphql.Field{}}
+	bookType.Fields["id"] = &graphql.Field{
+		Type:    graphql.NewNonNull(graphql.ID),
+		Resolve: func(p graphql.Params) (any, error) { return p.Source.(book).ID, nil },
+	}
+	bookType.Fields["title"] = &graphql.Field{
+		Type: graphql.String,
+		Args: map[string]*graphql.Arg{"upper": {Type: graphql.Boolean, Default: false}},
+		Resolve: func(p graphql.Params) (any, error) {
+			if *p.Args.Bool("upper") {
+				return strings.ToUpper(p.Source.(book).Title), nil
+			}
+			return p.Source.(book).Title, nil
+		},
+	}
+	bookType.Fields["tags"] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+		Batch: func(p graphql.BatchParams) ([]any, error) {
+			*batches++
+			values := make([]any, len(p.Sources))
+			for i, src := range p.Sources {
+				values[i] = src.(book).Tags
+			}
+			return values, nil
+		},
+	}
+	bookType.Fields["broken"] = &graphql.Field{
+		Type:    graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.Params) (any, error) { return nil, errors.New("broken") },
+	}
+	bookType.Fields["self"] = &graphql.Field{
+		Type:    bookType,
+		Resolve: func(p graphql.Params) (any, error) { return p.Source, nil },
+	}
+
+	query := &graphql.Object{Name: "Query", Fields: map[string]*graphql.Field{
+		"books": {
+			Type: graphql.NewList(bookType),
+			Resolve: func(p graphql.Params) (any, error) {
+				list := make([]any, len(books))
+				for i, b := range books {
+					list[i] = b
+				}
+				return list, nil
+			},
+		},
+		"book": {
+			Type: bookType,
+			Args: map[string]*graphql.Arg{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: func(p graphql.Params) (any, error) {
+				for _, b := range books {
+					if b.ID == p.Args.String("id") {
+						return b, nil
+					}
+				}
+				return nil, nil
+			},
+		},
+	}}
+
+	return &graphql.Schema{Query: query}
+}
+
+func execute(t *testing.T, ctx context.Context, schema *graphql.Schema, query string, vars map[string]any) string {
+	t.Helper()
+
+	data, err := json.Marshal(graphql.Execute(ctx, schema, graphql.Request{Query: query, Variables: vars}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		vars  map[string]any
+		want  string
+	}{
+		{
+			name:  "fields and aliases",
+			query: `{ books { id name: title } }`,
+			want:  `{"data":{"books":[{"id":"1","name":"Go"},{"id":"2","name":"Mongo"}]}}`,
+		},
+		{
+			name:  "arguments and defaults",
+			query: `{ book(id: 2) { title upper: title(upper: true) } }`,
+			want:  `{"data":{"book":{"title":"Mongo","upper":"MONGO"}}}`,
+		},
+		{
+			name:  "variables",
+			query: `query ($id: ID!, $upper: Boolean = true) { book(id: $id) { title(upper: $upper) } }`,
+			vars:  map[string]any{"id": "1"},
+			want:  `{"data":{"book":{"title":"GO"}}}`,
+		},
+		{
+			name:  "fragments",
+			query: `{ book(id: "1") { ...A ... on Book { title } } } fragment A on Book { id ...A2 } fragment A2 on Book { tags }`,
+			want:  `{"data":{"book":{"id":"1","tags":["dev"],"title":"Go"}}}`,
+		},
+		{
+			name:  "skip and include",
+			query: `query ($no: Boolean!) { book(id: "1") { id @skip(if: true) title @include(if: $no) tags @include(if: true) } }`,
+			vars:  map[string]any{"no": false},
+			want:  `{"data":{"book":{"tags":["dev"]}}}`,
+		},
+		{
+			name:  "typename",
+			query: `{ book(id: "1") { __typename } }`,
+			want:  `{"data":{"book":{"__typename":"Book"}}}`,
+		},
+		{
+			name:  "non-null error nulls the parent",
+			query: `{ book(id: "1") { id broken } }`,
+			want:  `{"data":{"book":null},"errors":[{"message":"broken","locations":[{"line":1,"column":22}],"path":["book","broken"]}]}`,
+		},
+		{
+			name:  "missing required variable",
+			query: `query ($id: ID!) { book(id: $id) { id } }`,
+			want:  `{"errors":[{"message":"variable $id of required type ID! was not provided"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var batches int
+			got := execute(t, context.Background(), testSchema(&batches), tt.query, tt.vars)
+			if got != tt.want {
+				t.Fatalf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchCalledOncePerLevel(t *testing.T) {
+	var batches int
+	got := execute(t, context.Background(), testSchema(&batches), `{ books { tags } }`, nil)
+
+	if want := `{"data":{"books":[{"tags":["dev"]},{"tags":[]}]}}`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if batches != 1 {
+		t.Fatalf("batch called %d times, want 1", batches)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"unknown field", `{ books { isbn } }`, `cannot query field "isbn" on type "Book"`},
+		{"missing selection", `{ books }`, `field "books" of type "[Book]" must have a selection of subfields`},
+		{"selection on scalar", `{ books { id { x } } }`, `field "id" must not have a selection since type "ID!" has no subfields`},
+		{"unknown argument", `{ books { title(lower: true) } }`, `unknown argument "lower" on field "Book.title"`},
+		{"required argument", `{ book { id } }`, `field "Query.book" argument "id" of type ID! is required, but it was not provided`},
+		{"undefined variable", `{ book(id: $id) { id } }`, `variable "$id" is not defined`},
+		{"unknown directive", `{ books { id @defer } }`, `unknown directive "@defer"`},
+		{"conflicting aliases", `{ books { a: id a: title } }`, `fields "a" conflict because id and title are different fields`},
+		{"unknown fragment", `{ books { ...F } }`, `unknown fragment "F"`},
+		{"fragment cycle", `{ books { ...A } } fragment A on Book { ...B } fragment B on Book { ...A }`, `cannot spread fragment "A" within itself`},
+		{"fragment type", `{ books { ...F } } fragment F on Query { books { id } }`, `fragment "F" cannot be spread here as objects of type "Book" can never be of type "Query"`},
+		{"syntax", `{ books { id }`, `syntax error at 1:15: unexpected end of document`},
+		{"subscription", `subscription { books { id } }`, `schema does not support subscription operations`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var batches int
+			resp := graphql.Execute(context.Background(), testSchema(&batches), graphql.Request{Query: tt.query})
+
+			if resp.Data != nil {
+				t.Fatalf("data %v, want none", resp.Data)
+			}
+			if len(resp.Errors) == 0 || resp.Errors[0].Message != tt.want {
+				t.Fatalf("errors %v, want %q", resp.Errors, tt.want)
+			}
+		})
+	}
+}
+
+// TestFragmentValidatedOnce - ошибка во фрагменте, который раскрывается в нескольких местах, сообщается один раз
+func TestFragmentValidatedOnce(t *testing.T) {
+	var batches int
+	resp := graphql.Execute(context.Background(), testSchema(&batches), graphql.Request{
+		Query: `{ books { ...F self { ...F } } } fragment F on Book { isbn }`,
+	})
+
+	if len(resp.Errors) != 1 {
+		t.Fatalf("errors %v, want one", resp.Errors)
+	}
+}
+
+// fragmentChain строит n фрагментов, каждый из которых дважды раскрывает следующий: без кэша
+// обход такого запроса занимает 2^n шагов
+func fragmentChain(n int) string {
+	var b strings.Builder
+
+	b.WriteString(`{ books { ...F0 } }`)
+	for i := range n {
+		fmt.Fprintf(&b, " fragment F%d on Book { a: self { ...F%d } b: self { ...F%d } }", i, i+1, i+1)
+	}
+	fmt.Fprintf(&b, " fragment F%d on Book { id }", n)
+
+	return b.String()
+}
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema func(*graphql.Schema)
+		query  string
+		want   string
+	}{
+		{
+			name:  "exponential fragments",
+			query: fragmentChain(40),
+			want:  "query depth 42 exceeds maximum depth 12",
+		},
+		{
+			name:   "exponential fragments within depth",
+			schema: func(s *graphql.Schema) { s.MaxDepth = 100 },
+			query:  fragmentChain(40),
+			want:   "query selects more than 500 fields",
+		},
+		{
+			name:  "depth",
+			query: `{ books { self { self { self { self { self { self { self { self { self { self { self { id } } } } } } } } } } } } }`,
+			want:  "query depth 13 exceeds maximum depth 12",
+		},
+		{
+			name:   "fields",
+			schema: func(s *graphql.Schema) { s.MaxFields = 3 },
+			query:  `{ books { id title tags } }`,
+			want:   "query selects more than 3 fields",
+		},
+		{
+			name:  "nesting",
+			query: strings.Repeat("{ books ", 70) + strings.Repeat("}", 70),
+			want:  "syntax error at 1:513: nesting is deeper than 64",
+		},
+		{
+			name:  "nested values",
+			query: `{ book(id: ` + strings.Repeat("[", 70) + strings.Repeat("]", 70) + `) { id } }`,
+			want:  "syntax error at 1:75: nesting is deeper than 64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var batches int
+			schema := testSchema(&batches)
+			if tt.schema != nil {
+				tt.schema(schema)
+			}
+
+			start := time.Now()
+			resp := graphql.Execute(context.Background(), schema, graphql.Request{Query: tt.query})
+
+			if len(resp.Errors) != 1 || resp.Errors[0].Message != tt.want {
+				t.Fatalf("errors %v, want %q", resp.Errors, tt.want)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("rejected in %v", elapsed)
+			}
+		})
+	}
+}
+
+// TestDuplicateSpreads - один фрагмент, раскрытый дважды в выборке, вычисляется один раз
+func TestDuplicateSpreads(t *testing.T) {
+	var batches int
+	got := execute(t, context.Background(), testSchema(&batches), `{ book(id: "1") { ...F ...F } } fragment F on Book { tags }`, nil)
+
+	if want := `{"data":{"book":{"tags":["dev"]}}}`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if batches != 1 {
+		t.Fatalf("batch called %d times, want 1", batches)
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var batches int
+	got := execute(t, ctx, testSchema(&batches), `{ books { tags } }`, nil)
+
+	if want := `{"errors":[{"message":"context canceled"}]}`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if batches != 0 {
+		t.Fatalf("batch called %d times after cancel", batches)
+	}
+}
+
+// TestCancelledDuringExecution - поля после отмены не вычисляются и получают ошибку контекста
+func TestCancelledDuringExecution(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var batches int
+	schema := testSchema(&batches)
+	schema.Query.Fields["cancel"] = &graphql.Field{
+		Type: graphql.Boolean,
+		Resolve: func(p graphql.Params) (any, error) {
+			cancel()
+			return true, nil
+		},
+	}
+
+	got := execute(t, ctx, schema, `{ cancel books { tags } }`, nil)
+
+	want := `{"data":{"cancel":true,"books":null},"errors":[{"message":"context canceled","locations":[{"line":1,"column":10}],"path":["books"]}]}`
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+	if batches != 0 {
+		t.Fatalf("batch called %d times after cancel", batches)
+	}
+}
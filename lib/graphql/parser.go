@@ -0,0 +1,856 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document - разобранный запрос: операции и именованные фрагменты.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation - query или mutation. Анонимная операция имеет пустое Name.
+type Operation struct {
+	Type       string
+	Name       string
+	Variables  []VariableDef
+	Selections []Selection
+}
+
+type VariableDef struct {
+	Name    string
+	Type    TypeRef
+	Default *Value
+}
+
+// TypeRef - тип переменной из запроса, например [ID!]!.
+type TypeRef struct {
+	Name    string
+	Elem    *TypeRef
+	NonNull bool
+}
+
+func (t TypeRef) String() string {
+	s := t.Name
+	if t.Elem != nil {
+		s = "[" + t.Elem.String() + "]"
+	}
+	if t.NonNull {
+		s += "!"
+	}
+
+	return s
+}
+
+type Fragment struct {
+	Name       string
+	TypeCond   string
+	Selections []Selection
+}
+
+// Selection - *FieldNode, *FragmentSpread или *InlineFragment.
+type Selection interface {
+	selection()
+}
+
+type FieldNode struct {
+	Alias      string
+	Name       string
+	Args       []Argument
+	Directives []Directive
+	Selections []Selection
+	Line       int
+	Column     int
+}
+
+// Key - имя поля в ответе.
+func (f *FieldNode) Key() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+
+	return f.Name
+}
+
+type FragmentSpread struct {
+	Name       string
+	Directives []Directive
+}
+
+type InlineFragment struct {
+	TypeCond   string
+	Directives []Directive
+	Selections []Selection
+}
+
+func (*FieldNode) selection()      {}
+func (*FragmentSpread) selection() {}
+func (*InlineFragment) selection() {}
+
+type Argument struct {
+	Name  string
+	Value Value
+}
+
+type Directive struct {
+	Name string
+	Args []Argument
+}
+
+type ValueKind int
+
+const (
+	ValueVariable ValueKind = iota
+	ValueInt
+	ValueFloat
+	ValueString
+	ValueBoolean
+	ValueNull
+	ValueEnum
+	ValueList
+	ValueObject
+)
+
+// Value - литерал или переменная в аргументе. Raw - текст скалярного значения или имя переменной.
+type Value struct {
+	Kind   ValueKind
+	Raw    string
+	List   []Value
+	Fields []Argument
+}
+
+// Parse разбирает исполняемый документ GraphQL: операции и фрагменты без определений схемы.
+func Parse(query string) (*Document, error) {
+	p := parser{lexer: lexer{src: query, line: 1, col: 1}}
+
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: map[string]*Fragment{}}
+
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.tok.is(tokPunct, "{"):
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selections: selections})
+		case p.tok.is(tokName, "query"), p.tok.is(tokName, "mutation"), p.tok.is(tokName, "subscription"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.tok.is(tokName, "fragment"):
+			fragment, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.Fragments[fragment.Name]; ok {
+				return nil, fmt.Errorf("there can be only one fragment named %q", fragment.Name)
+			}
+			doc.Fragments[fragment.Name] = fragment
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("document has no operations")
+	}
+
+	return doc, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	line   int
+	column int
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+type lexer struct {
+	src       string
+	pos       int
+	line, col int
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+
+	tok := token{line: l.line, column: l.col}
+	if l.pos >= len(l.src) {
+		return tok, nil
+	}
+
+	c := l.src[l.pos]
+
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.forward(3)
+		tok.kind, tok.text = tokPunct, "..."
+	case strings.IndexByte("!$&()=:@[]{}|", c) >= 0:
+		l.forward(1)
+		tok.kind, tok.text = tokPunct, string(c)
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.forward(1)
+		}
+		tok.kind, tok.text = tokName, l.src[start:l.pos]
+	case c == '-' || isDigit(c):
+		return l.number(tok)
+	case c == '"':
+		return l.string(tok)
+	default:
+		r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+		return tok, fmt.Errorf("syntax error at %d:%d: unexpected character %q", tok.line, tok.column, r)
+	}
+
+	return tok, nil
+}
+
+// skipIgnored пропускает пробелы, запятые, переводы строк и комментарии.
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == '\n':
+			l.pos++
+			l.line++
+			l.col = 1
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			l.forward(1)
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.forward(1)
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\ufeff"):
+			l.pos += len("\ufeff")
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) forward(n int) {
+	l.pos += n
+	l.col += n
+}
+
+func (l *lexer) number(tok token) (token, error) {
+	start := l.pos
+	tok.kind = tokInt
+
+	if l.src[l.pos] == '-' {
+		l.forward(1)
+	}
+	digits := func() int {
+		n := 0
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.forward(1)
+			n++
+		}
+		return n
+	}
+
+	if digits() == 0 {
+		return tok, fmt.Errorf("syntax error at %d:%d: invalid number", tok.line, tok.column)
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		l.forward(1)
+		tok.kind = tokFloat
+		if digits() == 0 {
+			return tok, fmt.Errorf("syntax error at %d:%d: invalid number", tok.line, tok.column)
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		l.forward(1)
+		tok.kind = tokFloat
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.forward(1)
+		}
+		if digits() == 0 {
+			return tok, fmt.Errorf("syntax error at %d:%d: invalid number", tok.line, tok.column)
+		}
+	}
+
+	tok.text = l.src[start:l.pos]
+	return tok, nil
+}
+
+func (l *lexer) string(tok token) (token, error) {
+	tok.kind = tokString
+
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		return l.blockString(tok)
+	}
+
+	l.forward(1)
+
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) || l.src[l.pos] == '\n' {
+			return tok, fmt.Errorf("syntax error at %d:%d: unterminated string", tok.line, tok.column)
+		}
+
+		c := l.src[l.pos]
+		switch {
+		case c == '"':
+			l.forward(1)
+			tok.text = b.String()
+			return tok, nil
+		case c == '\\':
+			if l.pos+1 >= len(l.src) {
+				return tok, fmt.Errorf("syntax error at %d:%d: unterminated string", tok.line, tok.column)
+			}
+			esc := l.src[l.pos+1]
+			l.forward(2)
+			switch esc {
+			case '"', '\\', '/':
+				b.WriteByte(esc)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return tok, fmt.Errorf("syntax error at %d:%d: invalid unicode escape", l.line, l.col)
+				}
+				code, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return tok, fmt.Errorf("syntax error at %d:%d: invalid unicode escape", l.line, l.col)
+				}
+				l.forward(4)
+				b.WriteRune(rune(code))
+			default:
+				return tok, fmt.Errorf("syntax error at %d:%d: invalid escape \\%c", l.line, l.col, esc)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			b.WriteRune(r)
+			l.forward(size)
+		}
+	}
+}
+
+// blockString читает строку в тройных кавычках. Общий отступ строк не убирается.
+func (l *lexer) blockString(tok token) (token, error) {
+	l.forward(3)
+
+	end := strings.Index(l.src[l.pos:], `"""`)
+	if end < 0 {
+		return tok, fmt.Errorf("syntax error at %d:%d: unterminated string", tok.line, tok.column)
+	}
+
+	text := l.src[l.pos : l.pos+end]
+	for _, c := range text {
+		if c == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+	}
+	l.pos += end
+	l.forward(3)
+
+	tok.text = strings.ReplaceAll(text, `\"""`, `"""`)
+	return tok, nil
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// maxNesting ограничивает вложенность скобок в документе, чтобы разбор не уходил в глубокую рекурсию.
+const maxNesting = 64
+
+type parser struct {
+	lexer lexer
+	tok   token
+	depth int
+}
+
+// enter отмечает вход в скобки; парный leave вызывается через defer.
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxNesting {
+		return fmt.Errorf("syntax error at %d:%d: nesting is deeper than %d", p.tok.line, p.tok.column, maxNesting)
+	}
+
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokEOF {
+		return fmt.Errorf("syntax error at %d:%d: unexpected end of document", p.tok.line, p.tok.column)
+	}
+
+	return fmt.Errorf("syntax error at %d:%d: unexpected %q", p.tok.line, p.tok.column, p.tok.text)
+}
+
+// expect пропускает знак препинания text или возвращает ошибку.
+func (p *parser) expect(text string) error {
+	if !p.tok.is(tokPunct, text) {
+		return p.unexpected()
+	}
+
+	return p.advance()
+}
+
+// skip пропускает знак препинания text, если он следующий.
+func (p *parser) skip(text string) (bool, error) {
+	if !p.tok.is(tokPunct, text) {
+		return false, nil
+	}
+
+	return true, p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokName {
+		return "", p.unexpected()
+	}
+
+	name := p.tok.text
+	return name, p.advance()
+}
+
+func (p *parser) operation() (*Operation, error) {
+	op := &Operation{Type: p.tok.text}
+
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokName {
+		op.Name = p.tok.text
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok, err := p.skip("("); err != nil {
+		return nil, err
+	} else if ok {
+		for !p.tok.is(tokPunct, ")") {
+			def, err := p.variableDef()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = append(op.Variables, def)
+		}
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	//Директивы операций не поддерживаются, но разрешены синтаксисом
+	if _, err = p.directives(); err != nil {
+		return nil, err
+	}
+
+	op.Selections, err = p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+func (p *parser) variableDef() (VariableDef, error) {
+	var def VariableDef
+
+	err := p.expect("$")
+	if err != nil {
+		return def, err
+	}
+
+	def.Name, err = p.name()
+	if err != nil {
+		return def, err
+	}
+
+	if err = p.expect(":"); err != nil {
+		return def, err
+	}
+
+	def.Type, err = p.typeRef()
+	if err != nil {
+		return def, err
+	}
+
+	if ok, err := p.skip("="); err != nil {
+		return def, err
+	} else if ok {
+		value, err := p.value(true)
+		if err != nil {
+			return def, err
+		}
+		def.Default = &value
+	}
+
+	_, err = p.directives()
+	return def, err
+}
+
+func (p *parser) typeRef() (TypeRef, error) {
+	var t TypeRef
+
+	if err := p.enter(); err != nil {
+		return t, err
+	}
+	defer p.leave()
+
+	if ok, err := p.skip("["); err != nil {
+		return t, err
+	} else if ok {
+		elem, err := p.typeRef()
+		if err != nil {
+			return t, err
+		}
+		if err = p.expect("]"); err != nil {
+			return t, err
+		}
+		t.Elem = &elem
+	} else {
+		t.Name, err = p.name()
+		if err != nil {
+			return t, err
+		}
+	}
+
+	nonNull, err := p.skip("!")
+	t.NonNull = nonNull
+
+	return t, err
+}
+
+func (p *parser) fragment() (*Fragment, error) {
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	fragment := &Fragment{}
+
+	fragment.Name, err = p.name()
+	if err != nil {
+		return nil, err
+	}
+	if fragment.Name == "on" {
+		return nil, fmt.Errorf("syntax error: fragment cannot be named \"on\"")
+	}
+
+	if !p.tok.is(tokName, "on") {
+		return nil, p.unexpected()
+	}
+	if err = p.advance(); err != nil {
+		return nil, err
+	}
+
+	fragment.TypeCond, err = p.name()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = p.directives(); err != nil {
+		return nil, err
+	}
+
+	fragment.Selections, err = p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return fragment, nil
+}
+
+func (p *parser) selectionSet() ([]Selection, error) {
+	err := p.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	err = p.expect("{")
+	if err != nil {
+		return nil, err
+	}
+
+	var selections []Selection
+
+	for !p.tok.is(tokPunct, "}") {
+		var selection Selection
+
+		if p.tok.is(tokPunct, "...") {
+			selection, err = p.fragmentSelection()
+		} else {
+			selection, err = p.field()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		selections = append(selections, selection)
+	}
+
+	if len(selections) == 0 {
+		return nil, p.unexpected()
+	}
+
+	return selections, p.advance()
+}
+
+func (p *parser) fragmentSelection() (Selection, error) {
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokName && p.tok.text != "on" {
+		spread := &FragmentSpread{Name: p.tok.text}
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		spread.Directives, err = p.directives()
+		return spread, err
+	}
+
+	inline := &InlineFragment{}
+
+	if p.tok.is(tokName, "on") {
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		inline.TypeCond, err = p.name()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inline.Directives, err = p.directives()
+	if err != nil {
+		return nil, err
+	}
+
+	inline.Selections, err = p.selectionSet()
+	return inline, err
+}
+
+func (p *parser) field() (*FieldNode, error) {
+	field := &FieldNode{Line: p.tok.line, Column: p.tok.column}
+
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		field.Alias = name
+		name, err = p.name()
+		if err != nil {
+			return nil, err
+		}
+	}
+	field.Name = name
+
+	field.Args, err = p.arguments(false)
+	if err != nil {
+		return nil, err
+	}
+
+	field.Directives, err = p.directives()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.is(tokPunct, "{") {
+		field.Selections, err = p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return field, nil
+}
+
+func (p *parser) arguments(constant bool) ([]Argument, error) {
+	if ok, err := p.skip("("); err != nil || !ok {
+		return nil, err
+	}
+
+	var args []Argument
+
+	for !p.tok.is(tokPunct, ")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		value, err := p.value(constant)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, Argument{Name: name, Value: value})
+	}
+
+	if len(args) == 0 {
+		return nil, p.unexpected()
+	}
+
+	return args, p.advance()
+}
+
+func (p *parser) directives() ([]Directive, error) {
+	var directives []Directive
+
+	for p.tok.is(tokPunct, "@") {
+		err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+
+		var directive Directive
+
+		directive.Name, err = p.name()
+		if err != nil {
+			return nil, err
+		}
+
+		directive.Args, err = p.arguments(false)
+		if err != nil {
+			return nil, err
+		}
+
+		directives = append(directives, directive)
+	}
+
+	return directives, nil
+}
+
+// value разбирает значение аргумента. В значениях по умолчанию (constant) переменные запрещены.
+func (p *parser) value(constant bool) (Value, error) {
+	tok := p.tok
+
+	if err := p.enter(); err != nil {
+		return Value{}, err
+	}
+	defer p.leave()
+
+	switch {
+	case tok.is(tokPunct, "$") && !constant:
+		err := p.advance()
+		if err != nil {
+			return Value{}, err
+		}
+		name, err := p.name()
+		return Value{Kind: ValueVariable, Raw: name}, err
+	case tok.is(tokPunct, "["):
+		err := p.advance()
+		if err != nil {
+			return Value{}, err
+		}
+
+		list := Value{Kind: ValueList, List: []Value{}}
+		for !p.tok.is(tokPunct, "]") {
+			item, err := p.value(constant)
+			if err != nil {
+				return Value{}, err
+			}
+			list.List = append(list.List, item)
+		}
+
+		return list, p.advance()
+	case tok.is(tokPunct, "{"):
+		err := p.advance()
+		if err != nil {
+			return Value{}, err
+		}
+
+		object := Value{Kind: ValueObject}
+		for !p.tok.is(tokPunct, "}") {
+			name, err := p.name()
+			if err != nil {
+				return Value{}, err
+			}
+			if err = p.expect(":"); err != nil {
+				return Value{}, err
+			}
+			item, err := p.value(constant)
+			if err != nil {
+				return Value{}, err
+			}
+			object.Fields = append(object.Fields, Argument{Name: name, Value: item})
+		}
+
+		return object, p.advance()
+	case tok.kind == tokInt:
+		return Value{Kind: ValueInt, Raw: tok.text}, p.advance()
+	case tok.kind == tokFloat:
+		return Value{Kind: ValueFloat, Raw: tok.text}, p.advance()
+	case tok.kind == tokString:
+		return Value{Kind: ValueString, Raw: tok.text}, p.advance()
+	case tok.is(tokName, "true"), tok.is(tokName, "false"):
+		return Value{Kind: ValueBoolean, Raw: tok.text}, p.advance()
+	case tok.is(tokName, "null"):
+		return Value{Kind: ValueNull}, p.advance()
+	case tok.kind == tokName:
+		return Value{Kind: ValueEnum, Raw: tok.text}, p.advance()
+	}
+
+	return Value{}, p.unexpected()
+}
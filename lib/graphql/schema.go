@@ -0,0 +1,378 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Type - тип поля или аргумента: *Scalar, *Enum, *Object, *InputObject, *List или *NonNull.
+type Type interface {
+	String() string
+}
+
+// Scalar - встроенный скалярный тип. Значения полей отдаются в JSON как есть.
+type Scalar struct {
+	Name string
+}
+
+var (
+	String  = &Scalar{Name: "String"}
+	Int     = &Scalar{Name: "Int"}
+	Float   = &Scalar{Name: "Float"}
+	Boolean = &Scalar{Name: "Boolean"}
+	ID      = &Scalar{Name: "ID"}
+)
+
+// Enum - строковое значение из списка. В запросе пишется без кавычек, в переменных - строкой.
+type Enum struct {
+	Name   string
+	Values []string
+}
+
+// Object - тип с полями в ответе.
+type Object struct {
+	Name        string
+	Description string
+	Fields      map[string]*Field
+}
+
+// InputObject - тип аргумента-объекта. В резолвер приходит как Args.
+type InputObject struct {
+	Name        string
+	Description string
+	Fields      map[string]*Arg
+}
+
+type List struct {
+	Of Type
+}
+
+type NonNull struct {
+	Of Type
+}
+
+func (t *Scalar) String() string      { return t.Name }
+func (t *Enum) String() string        { return t.Name }
+func (t *Object) String() string      { return t.Name }
+func (t *InputObject) String() string { return t.Name }
+func (t *List) String() string        { return "[" + t.Of.String() + "]" }
+func (t *NonNull) String() string     { return t.Of.String() + "!" }
+
+// NewList и NewNonNull - сокращения для описания схемы.
+func NewList(of Type) *List {
+	return &List{Of: of}
+}
+
+func NewNonNull(of Type) *NonNull {
+	return &NonNull{Of: of}
+}
+
+// Field - поле объекта. Resolve вычисляет поле для одного родителя. Batch вычисляет поле сразу для всех
+// родителей на одном уровне ответа и возвращает значения в том же порядке: так список заметок с блокнотами
+// и тегами читается несколькими запросами к базе, а не запросом на каждую заметку.
+type Field struct {
+	Type        Type
+	Description string
+	Args        map[string]*Arg
+	Resolve     func(p Params) (any, error)
+	Batch       func(p BatchParams) ([]any, error)
+}
+
+// Arg - аргумент поля или поле InputObject. Default подставляется, если аргумент не передан.
+type Arg struct {
+	Type        Type
+	Description string
+	Default     any
+}
+
+type Params struct {
+	Context context.Context
+	Source  any
+	Args    Args
+}
+
+type BatchParams struct {
+	Context context.Context
+	Sources []any
+	Args    Args
+}
+
+// Args - значения аргументов после проверки типов: string, int, float64, bool, []any, Args или nil.
+// Аргумент, переданный как null, есть в Args со значением nil, непереданный - отсутствует.
+type Args map[string]any
+
+func (a Args) Has(name string) bool {
+	_, ok := a[name]
+	return ok
+}
+
+func (a Args) String(name string) string {
+	s, _ := a[name].(string)
+	return s
+}
+
+// StringPtr - nil, если аргумент не передан; пустая строка, если передан null.
+func (a Args) StringPtr(name string) *string {
+	if !a.Has(name) {
+		return nil
+	}
+
+	s := a.String(name)
+	return &s
+}
+
+func (a Args) Int(name string) int {
+	n, _ := a[name].(int)
+	return n
+}
+
+// Bool - nil, если аргумент не передан или передан null.
+func (a Args) Bool(name string) *bool {
+	b, ok := a[name].(bool)
+	if !ok {
+		return nil
+	}
+
+	return &b
+}
+
+func (a Args) Strings(name string) []string {
+	list, _ := a[name].([]any)
+
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+
+	return strs
+}
+
+func (a Args) Object(name string) Args {
+	obj, _ := a[name].(Args)
+	return obj
+}
+
+// Ограничения запроса по умолчанию.
+const (
+	DefaultMaxDepth  = 12
+	DefaultMaxFields = 500
+)
+
+// Schema - корневые типы. FormatError превращает ошибку резолвера в ошибку ответа, например чтобы
+// скрыть внутренние ошибки и добавить код в extensions; без него клиент получает текст ошибки.
+// MaxDepth и MaxFields ограничивают вложенность выборки и число полей в ней после раскрытия фрагментов,
+// 0 - DefaultMaxDepth и DefaultMaxFields.
+type Schema struct {
+	Query       *Object
+	Mutation    *Object
+	FormatError func(error) Error
+	MaxDepth    int
+	MaxFields   int
+
+	inputs map[string]Type
+}
+
+// inputType находит именованный тип для переменной запроса.
+func (s *Schema) inputType(name string) (Type, bool) {
+	if s.inputs == nil {
+		s.inputs = map[string]Type{}
+		for _, scalar := range []*Scalar{String, Int, Float, Boolean, ID} {
+			s.inputs[scalar.Name] = scalar
+		}
+
+		seen := map[*Object]bool{}
+		var walkObject func(*Object)
+		var walkArg func(Type)
+
+		walkArg = func(t Type) {
+			switch t := t.(type) {
+			case *NonNull:
+				walkArg(t.Of)
+			case *List:
+				walkArg(t.Of)
+			case *Enum:
+				s.inputs[t.Name] = t
+			case *InputObject:
+				if _, ok := s.inputs[t.Name]; ok {
+					return
+				}
+				s.inputs[t.Name] = t
+				for _, arg := range t.Fields {
+					walkArg(arg.Type)
+				}
+			}
+		}
+
+		walkObject = func(obj *Object) {
+			if obj == nil || seen[obj] {
+				return
+			}
+			seen[obj] = true
+
+			for _, field := range obj.Fields {
+				for _, arg := range field.Args {
+					walkArg(arg.Type)
+				}
+				if next, ok := namedType(field.Type).(*Object); ok {
+					walkObject(next)
+				}
+			}
+		}
+
+		walkObject(s.Query)
+		walkObject(s.Mutation)
+	}
+
+	t, ok := s.inputs[name]
+	return t, ok
+}
+
+// SDL описывает схему на языке схем GraphQL, чтобы ее можно было показать клиентам.
+func (s *Schema) SDL() string {
+	var objects []*Object
+	var inputs []*InputObject
+	var enums []*Enum
+	seen := map[string]bool{}
+
+	var walk func(Type)
+	walk = func(t Type) {
+		switch t := t.(type) {
+		case *NonNull:
+			walk(t.Of)
+		case *List:
+			walk(t.Of)
+		case *Enum:
+			if !seen[t.Name] {
+				seen[t.Name] = true
+				enums = append(enums, t)
+			}
+		case *InputObject:
+			if !seen[t.Name] {
+				seen[t.Name] = true
+				inputs = append(inputs, t)
+				for _, arg := range t.Fields {
+					walk(arg.Type)
+				}
+			}
+		case *Object:
+			if t != nil && !seen[t.Name] {
+				seen[t.Name] = true
+				objects = append(objects, t)
+				for _, field := range t.Fields {
+					walk(field.Type)
+					for _, arg := range field.Args {
+						walk(arg.Type)
+					}
+				}
+			}
+		}
+	}
+
+	walk(s.Query)
+	if s.Mutation != nil {
+		walk(s.Mutation)
+	}
+
+	var b strings.Builder
+
+	b.WriteString("schema {\n  query: " + s.Query.Name + "\n")
+	if s.Mutation != nil {
+		b.WriteString("  mutation: " + s.Mutation.Name + "\n")
+	}
+	b.WriteString("}\n")
+
+	for _, obj := range objects {
+		b.WriteString("\n" + description(obj.Description, ""))
+		b.WriteString("type " + obj.Name + " {\n")
+		for _, name := range sortedKeys(obj.Fields) {
+			field := obj.Fields[name]
+			b.WriteString(description(field.Description, "  "))
+			b.WriteString("  " + name + sdlArgs(field.Args) + ": " + field.Type.String() + "\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, input := range inputs {
+		b.WriteString("\n" + description(input.Description, ""))
+		b.WriteString("input " + input.Name + " {\n")
+		for _, name := range sortedKeys(input.Fields) {
+			arg := input.Fields[name]
+			b.WriteString(description(arg.Description, "  "))
+			b.WriteString("  " + name + ": " + arg.Type.String() + sdlDefault(arg) + "\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, enum := range enums {
+		b.WriteString("\nenum " + enum.Name + " {\n")
+		for _, value := range enum.Values {
+			b.WriteString("  " + value + "\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	return b.String()
+}
+
+func sdlArgs(args map[string]*Arg) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, name := range sortedKeys(args) {
+		parts = append(parts, name+": "+args[name].Type.String()+sdlDefault(args[name]))
+	}
+
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+func sdlDefault(arg *Arg) string {
+	switch v := arg.Default.(type) {
+	case nil:
+		return ""
+	case string:
+		if _, ok := namedType(arg.Type).(*Enum); ok {
+			return " = " + v
+		}
+		return fmt.Sprintf(" = %q", v)
+	default:
+		return fmt.Sprintf(" = %v", v)
+	}
+}
+
+func description(text, indent string) string {
+	if text == "" {
+		return ""
+	}
+
+	return indent + `"""` + text + `"""` + "\n"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// namedType снимает обертки List и NonNull.
+func namedType(t Type) Type {
+	for {
+		switch wrapped := t.(type) {
+		case *NonNull:
+			t = wrapped.Of
+		case *List:
+			t = wrapped.Of
+		default:
+			return t
+		}
+	}
+}
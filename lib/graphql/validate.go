@@ -0,0 +1,209 @@
+package graphql
+
+import "fmt"
+
+// validator проверяет запрос по схеме до выполнения, чтобы опечатка в одном поле не выполнила
+// половину мутаций.
+type validator struct {
+	*executor
+	defined   map[string]bool
+	visiting  map[string]bool
+	validated map[string]bool
+	costs     map[string]cost
+	maxFields int
+	errors    []Error
+}
+
+// cost - размер выборки после раскрытия фрагментов: число полей и глубина вложенности.
+type cost struct {
+	fields int
+	depth  int
+}
+
+// measure считает размер выборки. Размер фрагмента считается один раз, поэтому цепочка фрагментов,
+// каждый из которых дважды раскрывает следующий, не требует экспоненциального обхода. Число полей
+// не растет выше maxFields+1, чтобы не переполниться.
+func (v *validator) measure(sels []Selection) cost {
+	var c cost
+
+	for _, sel := range sels {
+		var sub cost
+
+		switch sel := sel.(type) {
+		case *FieldNode:
+			sub = v.measure(sel.Selections)
+			sub.fields++
+			sub.depth++
+		case *FragmentSpread:
+			sub = v.fragmentCost(sel.Name)
+		case *InlineFragment:
+			sub = v.measure(sel.Selections)
+		}
+
+		c.fields = min(c.fields+sub.fields, v.maxFields+1)
+		c.depth = max(c.depth, sub.depth)
+	}
+
+	return c
+}
+
+func (v *validator) fragmentCost(name string) cost {
+	if c, ok := v.costs[name]; ok {
+		return c
+	}
+
+	fragment, ok := v.doc.Fragments[name]
+	if !ok || v.visiting[name] {
+		//Неизвестный фрагмент и цикл - ошибки проверки, размер здесь не важен
+		return cost{}
+	}
+
+	v.visiting[name] = true
+	c := v.measure(fragment.Selections)
+	delete(v.visiting, name)
+
+	v.costs[name] = c
+
+	return c
+}
+
+func (v *validator) errorf(field *FieldNode, format string, args ...any) {
+	err := Error{Message: fmt.Sprintf(format, args...)}
+	if field != nil {
+		err.Locations = []Location{{Line: field.Line, Column: field.Column}}
+	}
+
+	v.errors = append(v.errors, err)
+}
+
+func (v *validator) selections(t *Object, sels []Selection) {
+	if v.ctx.Err() != nil {
+		return
+	}
+
+	keys := map[string]string{}
+
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *FieldNode:
+			v.directives(sel, sel.Directives)
+
+			if name, ok := keys[sel.Key()]; ok && name != sel.Name {
+				v.errorf(sel, "fields %q conflict because %s and %s are different fields", sel.Key(), name, sel.Name)
+			}
+			keys[sel.Key()] = sel.Name
+
+			v.field(t, sel)
+		case *FragmentSpread:
+			v.directives(nil, sel.Directives)
+
+			fragment, ok := v.doc.Fragments[sel.Name]
+			if !ok {
+				v.errorf(nil, "unknown fragment %q", sel.Name)
+				continue
+			}
+			if fragment.TypeCond != t.Name {
+				v.errorf(nil, "fragment %q cannot be spread here as objects of type %q can never be of type %q", sel.Name, t.Name, fragment.TypeCond)
+				continue
+			}
+			if v.validated[sel.Name] {
+				continue
+			}
+			if v.visiting[sel.Name] {
+				v.errorf(nil, "cannot spread fragment %q within itself", sel.Name)
+				continue
+			}
+
+			//Тип фрагмента уже совпал с t, поэтому тело достаточно проверить один раз
+			v.visiting[sel.Name] = true
+			v.selections(t, fragment.Selections)
+			delete(v.visiting, sel.Name)
+			v.validated[sel.Name] = true
+		case *InlineFragment:
+			v.directives(nil, sel.Directives)
+
+			if sel.TypeCond != "" && sel.TypeCond != t.Name {
+				v.errorf(nil, "fragment cannot be spread here as objects of type %q can never be of type %q", t.Name, sel.TypeCond)
+				continue
+			}
+			v.selections(t, sel.Selections)
+		}
+	}
+}
+
+func (v *validator) field(t *Object, field *FieldNode) {
+	if field.Name == "__typename" {
+		if len(field.Selections) > 0 {
+			v.errorf(field, "field \"__typename\" must not have a selection since type \"String\" has no subfields")
+		}
+		return
+	}
+
+	def, ok := t.Fields[field.Name]
+	if !ok {
+		v.errorf(field, "cannot query field %q on type %q", field.Name, t.Name)
+		return
+	}
+
+	provided := map[string]bool{}
+	for _, arg := range field.Args {
+		provided[arg.Name] = true
+		if _, ok := def.Args[arg.Name]; !ok {
+			v.errorf(field, "unknown argument %q on field \"%s.%s\"", arg.Name, t.Name, field.Name)
+		}
+		v.variables(field, arg.Value)
+	}
+
+	for name, arg := range def.Args {
+		if _, nonNull := arg.Type.(*NonNull); nonNull && arg.Default == nil && !provided[name] {
+			v.errorf(field, "field \"%s.%s\" argument %q of type %s is required, but it was not provided", t.Name, field.Name, name, arg.Type)
+		}
+	}
+
+	if obj, ok := namedType(def.Type).(*Object); ok {
+		if len(field.Selections) == 0 {
+			v.errorf(field, "field %q of type %q must have a selection of subfields", field.Name, def.Type)
+			return
+		}
+		v.selections(obj, field.Selections)
+	} else if len(field.Selections) > 0 {
+		v.errorf(field, "field %q must not have a selection since type %q has no subfields", field.Name, def.Type)
+	}
+}
+
+// directives пропускает только @skip и @include с обязательным аргументом if.
+func (v *validator) directives(field *FieldNode, directives []Directive) {
+	for _, d := range directives {
+		if d.Name != "skip" && d.Name != "include" {
+			v.errorf(field, "unknown directive \"@%s\"", d.Name)
+			continue
+		}
+		if len(d.Args) != 1 || d.Args[0].Name != "if" {
+			v.errorf(field, "directive \"@%s\" requires a single argument \"if\" of type Boolean!", d.Name)
+			continue
+		}
+
+		v.variables(field, d.Args[0].Value)
+		if d.Args[0].Value.Kind != ValueBoolean && d.Args[0].Value.Kind != ValueVariable {
+			v.errorf(field, "directive \"@%s\" argument \"if\" must be Boolean", d.Name)
+		}
+	}
+}
+
+// variables проверяет, что все переменные в значении объявлены в операции.
+func (v *validator) variables(field *FieldNode, value Value) {
+	switch value.Kind {
+	case ValueVariable:
+		if !v.defined[value.Raw] {
+			v.errorf(field, "variable \"$%s\" is not defined", value.Raw)
+		}
+	case ValueList:
+		for _, elem := range value.List {
+			v.variables(field, elem)
+		}
+	case ValueObject:
+		for _, f := range value.Fields {
+			v.variables(field, f.Value)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package rank
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Ключи сортировки сравниваются как обычные строки. Между любыми двумя ключами
+// всегда есть место для нового, поэтому перемещение заметки меняет только ее ключ.
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Between возвращает ключ строго между a и b. Пустой a - начало списка, пустой b - конец.
+func Between(a, b string) (string, error) {
+	if err := validate(a); err != nil {
+		return "", err
+	}
+	if err := validate(b); err != nil {
+		return "", err
+	}
+	if b != "" && a >= b {
+		return "", fmt.Errorf("rank %q is not less than %q", a, b)
+	}
+
+	return midpoint(a, b), nil
+}
+
+// After возвращает короткий ключ больше a - для добавления в конец списка.
+func After(a string) (string, error) {
+	if err := validate(a); err != nil {
+		return "", err
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, a[i])
+		if d < len(digits)-1 {
+			return a[:i] + string(digits[d+1]), nil
+		}
+	}
+
+	return a + string(digits[len(digits)/2]), nil
+}
+
+func midpoint(a, b string) string {
+	if b != "" {
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(digits, a[0])
+	}
+
+	digitB := len(digits)
+	if b != "" {
+		digitB = strings.IndexByte(digits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(digits[(digitA+digitB+1)/2])
+	}
+
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+
+	return string(digits[digitA]) + midpoint(rest, "")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+
+	return digits[0]
+}
+
+func validate(key string) error {
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return fmt.Errorf("wrong rank %q", key)
+		}
+	}
+
+	if strings.HasSuffix(key, digits[:1]) {
+		return fmt.Errorf("wrong rank %q", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package rank_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/LoL-KeKovich/NoteVault/lib/rank"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"empty list", "", ""},
+		{"before first", "", "V"},
+		{"after last", "V", ""},
+		{"wide gap", "1", "z"},
+		{"adjacent digits", "V", "W"},
+		{"prefix", "V", "V1"},
+		{"longer lower bound", "Vz", "W"},
+		{"shared prefix", "abc", "abd"},
+		{"tight gap", "V", "V01"},
+		{"before smallest", "", "01"},
+		{"after largest", "zzz", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := rank.Between(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Between(%q, %q): %v", tt.a, tt.b, err)
+			}
+			checkBetween(t, tt.a, tt.b, key)
+		})
+	}
+}
+
+func TestBetweenErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"equal", "V", "V"},
+		{"reversed", "W", "V"},
+		{"bad digit", "V-", ""},
+		{"trailing zero", "V0", ""},
+		{"bad upper bound", "", "!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if key, err := rank.Between(tt.a, tt.b); err == nil {
+				t.Fatalf("Between(%q, %q) = %q, want error", tt.a, tt.b, key)
+			}
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	key := ""
+	for i := 0; i < 1000; i++ {
+		next, err := rank.After(key)
+		if err != nil {
+			t.Fatalf("After(%q): %v", key, err)
+		}
+		if next <= key {
+			t.Fatalf("After(%q) = %q, want greater", key, next)
+		}
+		key = next
+	}
+
+	if _, err := rank.After("V0"); err == nil {
+		t.Fatal("After accepted a key with a trailing zero")
+	}
+}
+
+// TestBetweenRepeated вставляет ключи в одно и то же место, пока зазор не станет совсем узким
+func TestBetweenRepeated(t *testing.T) {
+	low, high := "V", "W"
+	for i := 0; i < 200; i++ {
+		key, err := rank.Between(low, high)
+		if err != nil {
+			t.Fatalf("step %d: Between(%q, %q): %v", i, low, high, err)
+		}
+		checkBetween(t, low, high, key)
+
+		if i%2 == 0 {
+			high = key
+		} else {
+			low = key
+		}
+	}
+}
+
+func FuzzBetween(f *testing.F) {
+	f.Add("", "")
+	f.Add("V", "W")
+	f.Add("V", "V01")
+	f.Add("zz", "")
+	f.Add("", "01")
+
+	f.Fuzz(func(t *testing.T, a, b string) {
+		if !valid(a) || !valid(b) || (b != "" && a >= b) {
+			if _, err := rank.Between(a, b); err == nil {
+				t.Fatalf("Between(%q, %q) accepted wrong bounds", a, b)
+			}
+			return
+		}
+
+		key, err := rank.Between(a, b)
+		if err != nil {
+			t.Fatalf("Between(%q, %q): %v", a, b, err)
+		}
+		checkBetween(t, a, b, key)
+
+		next, err := rank.After(key)
+		if err != nil {
+			t.Fatalf("After(%q): %v", key, err)
+		}
+		if next <= key || !valid(next) {
+			t.Fatalf("After(%q) = %q", key, next)
+		}
+	})
+}
+
+func checkBetween(t *testing.T, a, b, key string) {
+	t.Helper()
+
+	if key <= a || (b != "" && key >= b) {
+		t.Fatalf("Between(%q, %q) = %q, not strictly between", a, b, key)
+	}
+	if !valid(key) {
+		t.Fatalf("Between(%q, %q) = %q, not a valid key", a, b, key)
+	}
+}
+
+// valid повторяет правила ключа: цифры из алфавита и без "0" в конце
+func valid(key string) bool {
+	const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+
+	return !strings.HasSuffix(key, "0")
+}
@@ -0,0 +1,264 @@
+package tagquery
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Expr - узел логического выражения над тегами заметки.
+type Expr interface {
+	expr()
+}
+
+// Tag истинно, если у заметки есть хотя бы один тег из IDs.
+// Name и ID заполняются при разборе запроса, IDs - при поиске тегов в базе.
+type Tag struct {
+	Name string
+	ID   string
+	IDs  []string
+}
+
+type And []Expr
+
+type Or []Expr
+
+type Not struct {
+	Expr Expr
+}
+
+func (*Tag) expr() {}
+func (And) expr()  {}
+func (Or) expr()   {}
+func (Not) expr()  {}
+
+// Tags возвращает все листья выражения.
+func Tags(e Expr) []*Tag {
+	var tags []*Tag
+
+	switch v := e.(type) {
+	case *Tag:
+		tags = append(tags, v)
+	case And:
+		for _, sub := range v {
+			tags = append(tags, Tags(sub)...)
+		}
+	case Or:
+		for _, sub := range v {
+			tags = append(tags, Tags(sub)...)
+		}
+	case Not:
+		tags = append(tags, Tags(v.Expr)...)
+	}
+
+	return tags
+}
+
+// maxDepth ограничивает вложенность скобок и NOT: глубокие выражения дальше не разобрать ни рекурсией, ни базой
+const maxDepth = 32
+
+// Parse разбирает выражение вида `(work OR personal) AND NOT draft`.
+// Операторы AND, OR, NOT не зависят от регистра, AND между соседними тегами можно опустить.
+// Имена с пробелами, скобками или совпадающие с операторами берутся в кавычки: "to do".
+func Parse(input string) (Expr, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenTag
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case r == '"':
+			start := i
+			i++
+			var sb strings.Builder
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated quote at position %d", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenTag, text: sb.String(), pos: start})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+				i++
+			}
+			word := string(runes[start:i])
+
+			kind := tokenTag
+			switch strings.ToUpper(word) {
+			case "AND":
+				kind = tokenAnd
+			case "OR":
+				kind = tokenOr
+			case "NOT":
+				kind = tokenNot
+			}
+			tokens = append(tokens, token{kind: kind, text: word, pos: start})
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, text: "end of query", pos: len(runes)})
+
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	or := Or{left}
+	for p.peek().kind == tokenOr {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, right)
+	}
+
+	if len(or) == 1 {
+		return left, nil
+	}
+
+	return or, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	and := And{left}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenTag, tokenNot, tokenLParen:
+		default:
+			if len(and) == 1 {
+				return left, nil
+			}
+			return and, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, right)
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	tok := p.next()
+
+	if tok.kind == tokenNot || tok.kind == tokenLParen {
+		p.depth++
+		defer func() { p.depth-- }()
+
+		if p.depth > maxDepth {
+			return nil, fmt.Errorf("query is nested deeper than %d levels at position %d", maxDepth, tok.pos)
+		}
+	}
+
+	switch tok.kind {
+	case tokenNot:
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: e}, nil
+	case tokenLParen:
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ')' at position %d, got %q", closing.pos, closing.text)
+		}
+		return e, nil
+	case tokenTag:
+		if tok.text == "" {
+			return nil, fmt.Errorf("empty tag name at position %d", tok.pos)
+		}
+		return &Tag{Name: tok.text}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+}
@@ -0,0 +1,147 @@
+package tagquery_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/LoL-KeKovich/NoteVault/lib/tagquery"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"single tag", "work", "work"},
+		{"or", "work OR home", "(work OR home)"},
+		{"and", "work AND home", "(work AND home)"},
+		{"implicit and", "work home draft", "(work AND home AND draft)"},
+		{"and before or", "a OR b AND c", "(a OR (b AND c))"},
+		{"implicit and before or", "a b OR c", "((a AND b) OR c)"},
+		{"parentheses", "(a OR b) AND c", "((a OR b) AND c)"},
+		{"not", "NOT draft", "NOT draft"},
+		{"not binds tighter than and", "NOT a AND b", "(NOT a AND b)"},
+		{"not of group", "NOT (a OR b)", "NOT (a OR b)"},
+		{"double not", "NOT NOT a", "NOT NOT a"},
+		{"case insensitive operators", "a or b and not c", "(a OR (b AND NOT c))"},
+		{"implicit and with not", "work NOT draft", "(work AND NOT draft)"},
+		{"quoted name", `"to do" OR work`, "(to do OR work)"},
+		{"quoted operator", `"AND" OR "not"`, "(AND OR not)"},
+		{"escaped quote", `"say \"hi\""`, `say "hi"`},
+		{"escaped backslash", `"a\\b"`, `a\b`},
+		{"quoted parentheses", `"(x)"`, "(x)"},
+		{"hierarchical name", "work/clientA", "work/clientA"},
+		{"extra spaces", "  ( a\tOR  b )  ", "(a OR b)"},
+		{"unicode", "работа OR дом", "(работа OR дом)"},
+		{"nested groups", "((a))", "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := tagquery.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			if got := format(e); got != tt.want {
+				t.Fatalf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", `unexpected "end of query" at position 0`},
+		{"only spaces", "   ", `unexpected "end of query" at position 3`},
+		{"dangling or", "a OR", `unexpected "end of query" at position 4`},
+		{"leading and", "AND a", `unexpected "AND" at position 0`},
+		{"double operator", "a OR OR b", `unexpected "OR" at position 5`},
+		{"dangling not", "a NOT", `unexpected "end of query" at position 5`},
+		{"unclosed parenthesis", "(a OR b", `expected ')' at position 7, got "end of query"`},
+		{"extra parenthesis", "a)", `unexpected ")" at position 1`},
+		{"empty group", "()", `unexpected ")" at position 1`},
+		{"unterminated quote", `a "b`, "unterminated quote at position 2"},
+		{"empty quoted name", `""`, "empty tag name at position 0"},
+		{"rune positions", `"тег" )`, `unexpected ")" at position 6`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := tagquery.Parse(tt.input)
+			if err == nil {
+				t.Fatalf("Parse(%q) = %s, want error", tt.input, format(e))
+			}
+			if err.Error() != tt.want {
+				t.Fatalf("Parse(%q) error %q, want %q", tt.input, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDepth(t *testing.T) {
+	if _, err := tagquery.Parse(strings.Repeat("(", 32) + "a" + strings.Repeat(")", 32)); err != nil {
+		t.Fatalf("32 nested groups: %v", err)
+	}
+	if _, err := tagquery.Parse(strings.Repeat("NOT ", 32) + "a"); err != nil {
+		t.Fatalf("32 nested NOT: %v", err)
+	}
+
+	tests := []string{
+		strings.Repeat("(", 33) + "a" + strings.Repeat(")", 33),
+		strings.Repeat("NOT ", 33) + "a",
+		strings.Repeat("(NOT ", 17) + "a" + strings.Repeat(")", 17),
+		strings.Repeat("(", 200000) + "a" + strings.Repeat(")", 200000),
+	}
+
+	for _, input := range tests {
+		_, err := tagquery.Parse(input)
+		if err == nil || !strings.Contains(err.Error(), "nested deeper than 32 levels") {
+			t.Fatalf("Parse(%.40q...): %v, want depth error", input, err)
+		}
+	}
+}
+
+func TestTags(t *testing.T) {
+	e, err := tagquery.Parse("(a OR NOT b) c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, tag := range tagquery.Tags(e) {
+		names = append(names, tag.Name)
+	}
+	if got := strings.Join(names, ","); got != "a,b,c" {
+		t.Fatalf("Tags = %s, want a,b,c", got)
+	}
+}
+
+// format печатает выражение с явными скобками вокруг каждого AND и OR
+func format(e tagquery.Expr) string {
+	switch v := e.(type) {
+	case *tagquery.Tag:
+		return v.Name
+	case tagquery.And:
+		return join(v, " AND ")
+	case tagquery.Or:
+		return join(v, " OR ")
+	case tagquery.Not:
+		return "NOT " + format(v.Expr)
+	}
+
+	return "?"
+}
+
+func join(exprs []tagquery.Expr, sep string) string {
+	parts := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		parts = append(parts, format(e))
+	}
+
+	return "(" + strings.Join(parts, sep) + ")"
+}
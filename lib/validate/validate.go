@@ -0,0 +1,220 @@
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError - нарушенное правило одного поля. Field - имя поля в JSON.
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + ": " + fe.Message
+	}
+
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Struct проверяет поля структуры по тегу validate, например `validate:"required,max=200"`.
+// Правила через запятую:
+//
+//	required               - строка не пустая (без учета пробелов), срез не пустой, указатель не nil
+//	required_without=field - required, если поле field (имя в JSON) не задано
+//	min=N, max=N           - длина строки в символах, длина среза или значение числа
+//	color                  - цвет в формате #rgb или #rrggbb
+//	email                  - адрес электронной почты
+//	url                    - абсолютный адрес http или https
+//
+// Пустые значения проверяются только правилами required, остальные их пропускают
+func Struct(v any) error {
+	return check(v, false)
+}
+
+// Partial проверяет структуру частичного обновления: незаданные поля не меняются, поэтому required пропускается
+func Partial(v any) error {
+	return check(v, true)
+}
+
+func check(v any, partial bool) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: %T is not a struct", v))
+	}
+
+	var errs Errors
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+
+		tag := field.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+
+		name := jsonName(field)
+		value := rv.Field(i)
+
+		//Правила формата проверяют значение под указателем, nil-указатель проверяет только required
+		target := value
+		if target.Kind() == reflect.Pointer && !target.IsNil() {
+			target = target.Elem()
+		}
+
+		for _, rule := range strings.Split(tag, ",") {
+			rule, param, _ := strings.Cut(rule, "=")
+
+			if target.Kind() == reflect.Pointer && rule != "required" && rule != "required_without" {
+				continue
+			}
+
+			message := ""
+			switch rule {
+			case "required":
+				if !partial && isEmpty(value) {
+					message = "is required"
+				}
+			case "required_without":
+				if !partial && isEmpty(value) && isEmpty(fieldByJSONName(rv, param)) {
+					message = "is required when " + param + " is not set"
+				}
+			case "min":
+				if !isEmpty(target) && size(target) < atoi(param) {
+					message = "must be at least " + param + lengthUnit(target)
+				}
+			case "max":
+				if size(target) > atoi(param) {
+					message = "must be at most " + param + lengthUnit(target)
+				}
+			case "color":
+				if s := target.String(); s != "" && !isColor(s) {
+					message = "must be a hex color like #1e90ff"
+				}
+			case "email":
+				if s := target.String(); s != "" && !isEmail(s) {
+					message = "must be a valid email address"
+				}
+			case "url":
+				if s := target.String(); s != "" && !isURL(s) {
+					message = "must be an absolute http or https URL"
+				}
+			default:
+				panic("validate: unknown rule " + rule)
+			}
+
+			if message != "" {
+				errs = append(errs, FieldError{Field: name, Rule: rule, Message: message})
+				break
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+
+	return name
+}
+
+func fieldByJSONName(rv reflect.Value, name string) reflect.Value {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		if jsonName(rt.Field(i)) == name {
+			return rv.Field(i)
+		}
+	}
+
+	panic("validate: unknown field " + name)
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	default:
+		return value.IsZero()
+	}
+}
+
+func size(value reflect.Value) int {
+	switch value.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(value.String())
+	case reflect.Slice, reflect.Map:
+		return value.Len()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(value.Int())
+	default:
+		panic("validate: min and max are not supported for " + value.Kind().String())
+	}
+}
+
+func lengthUnit(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Map:
+		return " items"
+	default:
+		return ""
+	}
+}
+
+func atoi(param string) int {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		panic("validate: wrong rule parameter " + param)
+	}
+
+	return n
+}
+
+func isColor(s string) bool {
+	if len(s) != 4 && len(s) != 7 || s[0] != '#' {
+		return false
+	}
+
+	for _, c := range s[1:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}